@@ -0,0 +1,4708 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        (unknown)
+// source: api/liteserver/liteserver.proto
+
+package liteserver
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type BlockIDExt struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Workchain int32  `protobuf:"varint,1,opt,name=workchain,proto3" json:"workchain,omitempty"`
+	Shard     int64  `protobuf:"varint,2,opt,name=shard,proto3" json:"shard,omitempty"`
+	Seqno     uint32 `protobuf:"varint,3,opt,name=seqno,proto3" json:"seqno,omitempty"`
+	RootHash  []byte `protobuf:"bytes,4,opt,name=root_hash,json=rootHash,proto3" json:"root_hash,omitempty"`
+	FileHash  []byte `protobuf:"bytes,5,opt,name=file_hash,json=fileHash,proto3" json:"file_hash,omitempty"`
+}
+
+func (x *BlockIDExt) Reset() {
+	*x = BlockIDExt{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlockIDExt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockIDExt) ProtoMessage() {}
+
+func (x *BlockIDExt) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockIDExt.ProtoReflect.Descriptor instead.
+func (*BlockIDExt) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *BlockIDExt) GetWorkchain() int32 {
+	if x != nil {
+		return x.Workchain
+	}
+	return 0
+}
+
+func (x *BlockIDExt) GetShard() int64 {
+	if x != nil {
+		return x.Shard
+	}
+	return 0
+}
+
+func (x *BlockIDExt) GetSeqno() uint32 {
+	if x != nil {
+		return x.Seqno
+	}
+	return 0
+}
+
+func (x *BlockIDExt) GetRootHash() []byte {
+	if x != nil {
+		return x.RootHash
+	}
+	return nil
+}
+
+func (x *BlockIDExt) GetFileHash() []byte {
+	if x != nil {
+		return x.FileHash
+	}
+	return nil
+}
+
+type ZeroStateIDExt struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Workchain int32  `protobuf:"varint,1,opt,name=workchain,proto3" json:"workchain,omitempty"`
+	RootHash  []byte `protobuf:"bytes,2,opt,name=root_hash,json=rootHash,proto3" json:"root_hash,omitempty"`
+	FileHash  []byte `protobuf:"bytes,3,opt,name=file_hash,json=fileHash,proto3" json:"file_hash,omitempty"`
+}
+
+func (x *ZeroStateIDExt) Reset() {
+	*x = ZeroStateIDExt{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ZeroStateIDExt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZeroStateIDExt) ProtoMessage() {}
+
+func (x *ZeroStateIDExt) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZeroStateIDExt.ProtoReflect.Descriptor instead.
+func (*ZeroStateIDExt) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ZeroStateIDExt) GetWorkchain() int32 {
+	if x != nil {
+		return x.Workchain
+	}
+	return 0
+}
+
+func (x *ZeroStateIDExt) GetRootHash() []byte {
+	if x != nil {
+		return x.RootHash
+	}
+	return nil
+}
+
+func (x *ZeroStateIDExt) GetFileHash() []byte {
+	if x != nil {
+		return x.FileHash
+	}
+	return nil
+}
+
+type AccountID struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Workchain int32  `protobuf:"varint,1,opt,name=workchain,proto3" json:"workchain,omitempty"`
+	Id        []byte `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *AccountID) Reset() {
+	*x = AccountID{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AccountID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountID) ProtoMessage() {}
+
+func (x *AccountID) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountID.ProtoReflect.Descriptor instead.
+func (*AccountID) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AccountID) GetWorkchain() int32 {
+	if x != nil {
+		return x.Workchain
+	}
+	return 0
+}
+
+func (x *AccountID) GetId() []byte {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+type WaitMasterchainSeqno struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Seqno     uint32 `protobuf:"varint,1,opt,name=seqno,proto3" json:"seqno,omitempty"`
+	TimeoutMs uint32 `protobuf:"varint,2,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`
+}
+
+func (x *WaitMasterchainSeqno) Reset() {
+	*x = WaitMasterchainSeqno{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WaitMasterchainSeqno) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WaitMasterchainSeqno) ProtoMessage() {}
+
+func (x *WaitMasterchainSeqno) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WaitMasterchainSeqno.ProtoReflect.Descriptor instead.
+func (*WaitMasterchainSeqno) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *WaitMasterchainSeqno) GetSeqno() uint32 {
+	if x != nil {
+		return x.Seqno
+	}
+	return 0
+}
+
+func (x *WaitMasterchainSeqno) GetTimeoutMs() uint32 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+type GetMasterchainInfoRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Wait *WaitMasterchainSeqno `protobuf:"bytes,15,opt,name=wait,proto3" json:"wait,omitempty"`
+}
+
+func (x *GetMasterchainInfoRequest) Reset() {
+	*x = GetMasterchainInfoRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetMasterchainInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMasterchainInfoRequest) ProtoMessage() {}
+
+func (x *GetMasterchainInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMasterchainInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetMasterchainInfoRequest) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetMasterchainInfoRequest) GetWait() *WaitMasterchainSeqno {
+	if x != nil {
+		return x.Wait
+	}
+	return nil
+}
+
+type MasterchainInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Last          *BlockIDExt     `protobuf:"bytes,1,opt,name=last,proto3" json:"last,omitempty"`
+	StateRootHash []byte          `protobuf:"bytes,2,opt,name=state_root_hash,json=stateRootHash,proto3" json:"state_root_hash,omitempty"`
+	Init          *ZeroStateIDExt `protobuf:"bytes,3,opt,name=init,proto3" json:"init,omitempty"`
+}
+
+func (x *MasterchainInfo) Reset() {
+	*x = MasterchainInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MasterchainInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MasterchainInfo) ProtoMessage() {}
+
+func (x *MasterchainInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MasterchainInfo.ProtoReflect.Descriptor instead.
+func (*MasterchainInfo) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *MasterchainInfo) GetLast() *BlockIDExt {
+	if x != nil {
+		return x.Last
+	}
+	return nil
+}
+
+func (x *MasterchainInfo) GetStateRootHash() []byte {
+	if x != nil {
+		return x.StateRootHash
+	}
+	return nil
+}
+
+func (x *MasterchainInfo) GetInit() *ZeroStateIDExt {
+	if x != nil {
+		return x.Init
+	}
+	return nil
+}
+
+type GetMasterchainInfoExtRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Mode uint32                `protobuf:"varint,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	Wait *WaitMasterchainSeqno `protobuf:"bytes,15,opt,name=wait,proto3" json:"wait,omitempty"`
+}
+
+func (x *GetMasterchainInfoExtRequest) Reset() {
+	*x = GetMasterchainInfoExtRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetMasterchainInfoExtRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMasterchainInfoExtRequest) ProtoMessage() {}
+
+func (x *GetMasterchainInfoExtRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMasterchainInfoExtRequest.ProtoReflect.Descriptor instead.
+func (*GetMasterchainInfoExtRequest) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetMasterchainInfoExtRequest) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *GetMasterchainInfoExtRequest) GetWait() *WaitMasterchainSeqno {
+	if x != nil {
+		return x.Wait
+	}
+	return nil
+}
+
+type MasterchainInfoExt struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Mode          uint32          `protobuf:"varint,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	Version       int32           `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Capabilities  int64           `protobuf:"varint,3,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
+	Last          *BlockIDExt     `protobuf:"bytes,4,opt,name=last,proto3" json:"last,omitempty"`
+	LastUtime     uint32          `protobuf:"varint,5,opt,name=last_utime,json=lastUtime,proto3" json:"last_utime,omitempty"`
+	Now           uint32          `protobuf:"varint,6,opt,name=now,proto3" json:"now,omitempty"`
+	StateRootHash []byte          `protobuf:"bytes,7,opt,name=state_root_hash,json=stateRootHash,proto3" json:"state_root_hash,omitempty"`
+	Init          *ZeroStateIDExt `protobuf:"bytes,8,opt,name=init,proto3" json:"init,omitempty"`
+}
+
+func (x *MasterchainInfoExt) Reset() {
+	*x = MasterchainInfoExt{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MasterchainInfoExt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MasterchainInfoExt) ProtoMessage() {}
+
+func (x *MasterchainInfoExt) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MasterchainInfoExt.ProtoReflect.Descriptor instead.
+func (*MasterchainInfoExt) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *MasterchainInfoExt) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *MasterchainInfoExt) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *MasterchainInfoExt) GetCapabilities() int64 {
+	if x != nil {
+		return x.Capabilities
+	}
+	return 0
+}
+
+func (x *MasterchainInfoExt) GetLast() *BlockIDExt {
+	if x != nil {
+		return x.Last
+	}
+	return nil
+}
+
+func (x *MasterchainInfoExt) GetLastUtime() uint32 {
+	if x != nil {
+		return x.LastUtime
+	}
+	return 0
+}
+
+func (x *MasterchainInfoExt) GetNow() uint32 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+func (x *MasterchainInfoExt) GetStateRootHash() []byte {
+	if x != nil {
+		return x.StateRootHash
+	}
+	return nil
+}
+
+func (x *MasterchainInfoExt) GetInit() *ZeroStateIDExt {
+	if x != nil {
+		return x.Init
+	}
+	return nil
+}
+
+type GetTimeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Wait *WaitMasterchainSeqno `protobuf:"bytes,15,opt,name=wait,proto3" json:"wait,omitempty"`
+}
+
+func (x *GetTimeRequest) Reset() {
+	*x = GetTimeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTimeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTimeRequest) ProtoMessage() {}
+
+func (x *GetTimeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTimeRequest.ProtoReflect.Descriptor instead.
+func (*GetTimeRequest) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetTimeRequest) GetWait() *WaitMasterchainSeqno {
+	if x != nil {
+		return x.Wait
+	}
+	return nil
+}
+
+type CurrentTime struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Now uint32 `protobuf:"varint,1,opt,name=now,proto3" json:"now,omitempty"`
+}
+
+func (x *CurrentTime) Reset() {
+	*x = CurrentTime{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CurrentTime) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CurrentTime) ProtoMessage() {}
+
+func (x *CurrentTime) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CurrentTime.ProtoReflect.Descriptor instead.
+func (*CurrentTime) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CurrentTime) GetNow() uint32 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+type GetVersionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Wait *WaitMasterchainSeqno `protobuf:"bytes,15,opt,name=wait,proto3" json:"wait,omitempty"`
+}
+
+func (x *GetVersionRequest) Reset() {
+	*x = GetVersionRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetVersionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVersionRequest) ProtoMessage() {}
+
+func (x *GetVersionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVersionRequest.ProtoReflect.Descriptor instead.
+func (*GetVersionRequest) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetVersionRequest) GetWait() *WaitMasterchainSeqno {
+	if x != nil {
+		return x.Wait
+	}
+	return nil
+}
+
+type Version struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Mode         uint32 `protobuf:"varint,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	Version      int32  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Capabilities int64  `protobuf:"varint,3,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
+	Now          uint32 `protobuf:"varint,4,opt,name=now,proto3" json:"now,omitempty"`
+}
+
+func (x *Version) Reset() {
+	*x = Version{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Version) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Version) ProtoMessage() {}
+
+func (x *Version) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Version.ProtoReflect.Descriptor instead.
+func (*Version) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Version) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *Version) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Version) GetCapabilities() int64 {
+	if x != nil {
+		return x.Capabilities
+	}
+	return 0
+}
+
+func (x *Version) GetNow() uint32 {
+	if x != nil {
+		return x.Now
+	}
+	return 0
+}
+
+type GetBlockRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id   *BlockIDExt           `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Wait *WaitMasterchainSeqno `protobuf:"bytes,15,opt,name=wait,proto3" json:"wait,omitempty"`
+}
+
+func (x *GetBlockRequest) Reset() {
+	*x = GetBlockRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetBlockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBlockRequest) ProtoMessage() {}
+
+func (x *GetBlockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBlockRequest.ProtoReflect.Descriptor instead.
+func (*GetBlockRequest) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetBlockRequest) GetId() *BlockIDExt {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *GetBlockRequest) GetWait() *WaitMasterchainSeqno {
+	if x != nil {
+		return x.Wait
+	}
+	return nil
+}
+
+type BlockData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id   *BlockIDExt `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Data []byte      `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *BlockData) Reset() {
+	*x = BlockData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlockData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockData) ProtoMessage() {}
+
+func (x *BlockData) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockData.ProtoReflect.Descriptor instead.
+func (*BlockData) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *BlockData) GetId() *BlockIDExt {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *BlockData) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type LookupBlockRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Mode      uint32                `protobuf:"varint,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	Workchain int32                 `protobuf:"varint,2,opt,name=workchain,proto3" json:"workchain,omitempty"`
+	Shard     int64                 `protobuf:"varint,3,opt,name=shard,proto3" json:"shard,omitempty"`
+	Seqno     uint32                `protobuf:"varint,4,opt,name=seqno,proto3" json:"seqno,omitempty"`
+	Lt        uint64                `protobuf:"varint,5,opt,name=lt,proto3" json:"lt,omitempty"`
+	Utime     uint32                `protobuf:"varint,6,opt,name=utime,proto3" json:"utime,omitempty"`
+	Wait      *WaitMasterchainSeqno `protobuf:"bytes,15,opt,name=wait,proto3" json:"wait,omitempty"`
+}
+
+func (x *LookupBlockRequest) Reset() {
+	*x = LookupBlockRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LookupBlockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupBlockRequest) ProtoMessage() {}
+
+func (x *LookupBlockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupBlockRequest.ProtoReflect.Descriptor instead.
+func (*LookupBlockRequest) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *LookupBlockRequest) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *LookupBlockRequest) GetWorkchain() int32 {
+	if x != nil {
+		return x.Workchain
+	}
+	return 0
+}
+
+func (x *LookupBlockRequest) GetShard() int64 {
+	if x != nil {
+		return x.Shard
+	}
+	return 0
+}
+
+func (x *LookupBlockRequest) GetSeqno() uint32 {
+	if x != nil {
+		return x.Seqno
+	}
+	return 0
+}
+
+func (x *LookupBlockRequest) GetLt() uint64 {
+	if x != nil {
+		return x.Lt
+	}
+	return 0
+}
+
+func (x *LookupBlockRequest) GetUtime() uint32 {
+	if x != nil {
+		return x.Utime
+	}
+	return 0
+}
+
+func (x *LookupBlockRequest) GetWait() *WaitMasterchainSeqno {
+	if x != nil {
+		return x.Wait
+	}
+	return nil
+}
+
+type BlockHeader struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id          *BlockIDExt `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Mode        uint32      `protobuf:"varint,2,opt,name=mode,proto3" json:"mode,omitempty"`
+	HeaderProof []byte      `protobuf:"bytes,3,opt,name=header_proof,json=headerProof,proto3" json:"header_proof,omitempty"`
+}
+
+func (x *BlockHeader) Reset() {
+	*x = BlockHeader{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlockHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockHeader) ProtoMessage() {}
+
+func (x *BlockHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockHeader.ProtoReflect.Descriptor instead.
+func (*BlockHeader) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *BlockHeader) GetId() *BlockIDExt {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *BlockHeader) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *BlockHeader) GetHeaderProof() []byte {
+	if x != nil {
+		return x.HeaderProof
+	}
+	return nil
+}
+
+type GetAccountStateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id      *BlockIDExt           `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Account *AccountID            `protobuf:"bytes,2,opt,name=account,proto3" json:"account,omitempty"`
+	Wait    *WaitMasterchainSeqno `protobuf:"bytes,15,opt,name=wait,proto3" json:"wait,omitempty"`
+}
+
+func (x *GetAccountStateRequest) Reset() {
+	*x = GetAccountStateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetAccountStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAccountStateRequest) ProtoMessage() {}
+
+func (x *GetAccountStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAccountStateRequest.ProtoReflect.Descriptor instead.
+func (*GetAccountStateRequest) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetAccountStateRequest) GetId() *BlockIDExt {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *GetAccountStateRequest) GetAccount() *AccountID {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+func (x *GetAccountStateRequest) GetWait() *WaitMasterchainSeqno {
+	if x != nil {
+		return x.Wait
+	}
+	return nil
+}
+
+type AccountState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         *BlockIDExt `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Shard      *BlockIDExt `protobuf:"bytes,2,opt,name=shard,proto3" json:"shard,omitempty"`
+	ShardProof []byte      `protobuf:"bytes,3,opt,name=shard_proof,json=shardProof,proto3" json:"shard_proof,omitempty"`
+	Proof      []byte      `protobuf:"bytes,4,opt,name=proof,proto3" json:"proof,omitempty"`
+	State      []byte      `protobuf:"bytes,5,opt,name=state,proto3" json:"state,omitempty"`
+}
+
+func (x *AccountState) Reset() {
+	*x = AccountState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AccountState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountState) ProtoMessage() {}
+
+func (x *AccountState) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountState.ProtoReflect.Descriptor instead.
+func (*AccountState) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *AccountState) GetId() *BlockIDExt {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *AccountState) GetShard() *BlockIDExt {
+	if x != nil {
+		return x.Shard
+	}
+	return nil
+}
+
+func (x *AccountState) GetShardProof() []byte {
+	if x != nil {
+		return x.ShardProof
+	}
+	return nil
+}
+
+func (x *AccountState) GetProof() []byte {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+func (x *AccountState) GetState() []byte {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+type RunSmcMethodRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Mode     uint32                `protobuf:"varint,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	Id       *BlockIDExt           `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Account  *AccountID            `protobuf:"bytes,3,opt,name=account,proto3" json:"account,omitempty"`
+	MethodId uint64                `protobuf:"varint,4,opt,name=method_id,json=methodId,proto3" json:"method_id,omitempty"`
+	Params   []byte                `protobuf:"bytes,5,opt,name=params,proto3" json:"params,omitempty"`
+	Wait     *WaitMasterchainSeqno `protobuf:"bytes,15,opt,name=wait,proto3" json:"wait,omitempty"`
+}
+
+func (x *RunSmcMethodRequest) Reset() {
+	*x = RunSmcMethodRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RunSmcMethodRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunSmcMethodRequest) ProtoMessage() {}
+
+func (x *RunSmcMethodRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunSmcMethodRequest.ProtoReflect.Descriptor instead.
+func (*RunSmcMethodRequest) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RunSmcMethodRequest) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *RunSmcMethodRequest) GetId() *BlockIDExt {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *RunSmcMethodRequest) GetAccount() *AccountID {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+func (x *RunSmcMethodRequest) GetMethodId() uint64 {
+	if x != nil {
+		return x.MethodId
+	}
+	return 0
+}
+
+func (x *RunSmcMethodRequest) GetParams() []byte {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *RunSmcMethodRequest) GetWait() *WaitMasterchainSeqno {
+	if x != nil {
+		return x.Wait
+	}
+	return nil
+}
+
+type RunMethodResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Mode       uint32      `protobuf:"varint,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	Id         *BlockIDExt `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	ShardBlock *BlockIDExt `protobuf:"bytes,3,opt,name=shard_block,json=shardBlock,proto3" json:"shard_block,omitempty"`
+	ShardProof []byte      `protobuf:"bytes,4,opt,name=shard_proof,json=shardProof,proto3" json:"shard_proof,omitempty"`
+	Proof      []byte      `protobuf:"bytes,5,opt,name=proof,proto3" json:"proof,omitempty"`
+	StateProof []byte      `protobuf:"bytes,6,opt,name=state_proof,json=stateProof,proto3" json:"state_proof,omitempty"`
+	InitC7     []byte      `protobuf:"bytes,7,opt,name=init_c7,json=initC7,proto3" json:"init_c7,omitempty"`
+	LibExtras  []byte      `protobuf:"bytes,8,opt,name=lib_extras,json=libExtras,proto3" json:"lib_extras,omitempty"`
+	ExitCode   int32       `protobuf:"varint,9,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	Result     []byte      `protobuf:"bytes,10,opt,name=result,proto3" json:"result,omitempty"`
+}
+
+func (x *RunMethodResult) Reset() {
+	*x = RunMethodResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RunMethodResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunMethodResult) ProtoMessage() {}
+
+func (x *RunMethodResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunMethodResult.ProtoReflect.Descriptor instead.
+func (*RunMethodResult) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RunMethodResult) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *RunMethodResult) GetId() *BlockIDExt {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *RunMethodResult) GetShardBlock() *BlockIDExt {
+	if x != nil {
+		return x.ShardBlock
+	}
+	return nil
+}
+
+func (x *RunMethodResult) GetShardProof() []byte {
+	if x != nil {
+		return x.ShardProof
+	}
+	return nil
+}
+
+func (x *RunMethodResult) GetProof() []byte {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+func (x *RunMethodResult) GetStateProof() []byte {
+	if x != nil {
+		return x.StateProof
+	}
+	return nil
+}
+
+func (x *RunMethodResult) GetInitC7() []byte {
+	if x != nil {
+		return x.InitC7
+	}
+	return nil
+}
+
+func (x *RunMethodResult) GetLibExtras() []byte {
+	if x != nil {
+		return x.LibExtras
+	}
+	return nil
+}
+
+func (x *RunMethodResult) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *RunMethodResult) GetResult() []byte {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type GetOneTransactionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id      *BlockIDExt           `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Account *AccountID            `protobuf:"bytes,2,opt,name=account,proto3" json:"account,omitempty"`
+	Lt      int64                 `protobuf:"varint,3,opt,name=lt,proto3" json:"lt,omitempty"`
+	Wait    *WaitMasterchainSeqno `protobuf:"bytes,15,opt,name=wait,proto3" json:"wait,omitempty"`
+}
+
+func (x *GetOneTransactionRequest) Reset() {
+	*x = GetOneTransactionRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetOneTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOneTransactionRequest) ProtoMessage() {}
+
+func (x *GetOneTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOneTransactionRequest.ProtoReflect.Descriptor instead.
+func (*GetOneTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetOneTransactionRequest) GetId() *BlockIDExt {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *GetOneTransactionRequest) GetAccount() *AccountID {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+func (x *GetOneTransactionRequest) GetLt() int64 {
+	if x != nil {
+		return x.Lt
+	}
+	return 0
+}
+
+func (x *GetOneTransactionRequest) GetWait() *WaitMasterchainSeqno {
+	if x != nil {
+		return x.Wait
+	}
+	return nil
+}
+
+type TransactionInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id          *BlockIDExt `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Proof       []byte      `protobuf:"bytes,2,opt,name=proof,proto3" json:"proof,omitempty"`
+	Transaction []byte      `protobuf:"bytes,3,opt,name=transaction,proto3" json:"transaction,omitempty"`
+}
+
+func (x *TransactionInfo) Reset() {
+	*x = TransactionInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransactionInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionInfo) ProtoMessage() {}
+
+func (x *TransactionInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionInfo.ProtoReflect.Descriptor instead.
+func (*TransactionInfo) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *TransactionInfo) GetId() *BlockIDExt {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *TransactionInfo) GetProof() []byte {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+func (x *TransactionInfo) GetTransaction() []byte {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+type GetLibrariesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hashes [][]byte              `protobuf:"bytes,1,rep,name=hashes,proto3" json:"hashes,omitempty"`
+	Wait   *WaitMasterchainSeqno `protobuf:"bytes,15,opt,name=wait,proto3" json:"wait,omitempty"`
+}
+
+func (x *GetLibrariesRequest) Reset() {
+	*x = GetLibrariesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetLibrariesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLibrariesRequest) ProtoMessage() {}
+
+func (x *GetLibrariesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLibrariesRequest.ProtoReflect.Descriptor instead.
+func (*GetLibrariesRequest) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetLibrariesRequest) GetHashes() [][]byte {
+	if x != nil {
+		return x.Hashes
+	}
+	return nil
+}
+
+func (x *GetLibrariesRequest) GetWait() *WaitMasterchainSeqno {
+	if x != nil {
+		return x.Wait
+	}
+	return nil
+}
+
+type LibraryEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hash []byte `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *LibraryEntry) Reset() {
+	*x = LibraryEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LibraryEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LibraryEntry) ProtoMessage() {}
+
+func (x *LibraryEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LibraryEntry.ProtoReflect.Descriptor instead.
+func (*LibraryEntry) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *LibraryEntry) GetHash() []byte {
+	if x != nil {
+		return x.Hash
+	}
+	return nil
+}
+
+func (x *LibraryEntry) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type LibraryResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result []*LibraryEntry `protobuf:"bytes,1,rep,name=result,proto3" json:"result,omitempty"`
+}
+
+func (x *LibraryResult) Reset() {
+	*x = LibraryResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LibraryResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LibraryResult) ProtoMessage() {}
+
+func (x *LibraryResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LibraryResult.ProtoReflect.Descriptor instead.
+func (*LibraryResult) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *LibraryResult) GetResult() []*LibraryEntry {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type SendMessageRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Body []byte                `protobuf:"bytes,1,opt,name=body,proto3" json:"body,omitempty"`
+	Wait *WaitMasterchainSeqno `protobuf:"bytes,15,opt,name=wait,proto3" json:"wait,omitempty"`
+}
+
+func (x *SendMessageRequest) Reset() {
+	*x = SendMessageRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SendMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendMessageRequest) ProtoMessage() {}
+
+func (x *SendMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendMessageRequest.ProtoReflect.Descriptor instead.
+func (*SendMessageRequest) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *SendMessageRequest) GetBody() []byte {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+func (x *SendMessageRequest) GetWait() *WaitMasterchainSeqno {
+	if x != nil {
+		return x.Wait
+	}
+	return nil
+}
+
+type SendMessageStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status int32 `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+}
+
+func (x *SendMessageStatus) Reset() {
+	*x = SendMessageStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SendMessageStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendMessageStatus) ProtoMessage() {}
+
+func (x *SendMessageStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendMessageStatus.ProtoReflect.Descriptor instead.
+func (*SendMessageStatus) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SendMessageStatus) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+type GetTransactionsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Limit   uint32                `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Account *AccountID            `protobuf:"bytes,2,opt,name=account,proto3" json:"account,omitempty"`
+	Lt      int64                 `protobuf:"varint,3,opt,name=lt,proto3" json:"lt,omitempty"`
+	Hash    []byte                `protobuf:"bytes,4,opt,name=hash,proto3" json:"hash,omitempty"`
+	Wait    *WaitMasterchainSeqno `protobuf:"bytes,15,opt,name=wait,proto3" json:"wait,omitempty"`
+}
+
+func (x *GetTransactionsRequest) Reset() {
+	*x = GetTransactionsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTransactionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTransactionsRequest) ProtoMessage() {}
+
+func (x *GetTransactionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTransactionsRequest.ProtoReflect.Descriptor instead.
+func (*GetTransactionsRequest) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetTransactionsRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetTransactionsRequest) GetAccount() *AccountID {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+func (x *GetTransactionsRequest) GetLt() int64 {
+	if x != nil {
+		return x.Lt
+	}
+	return 0
+}
+
+func (x *GetTransactionsRequest) GetHash() []byte {
+	if x != nil {
+		return x.Hash
+	}
+	return nil
+}
+
+func (x *GetTransactionsRequest) GetWait() *WaitMasterchainSeqno {
+	if x != nil {
+		return x.Wait
+	}
+	return nil
+}
+
+type TransactionList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ids          []*BlockIDExt `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	Transactions []byte        `protobuf:"bytes,2,opt,name=transactions,proto3" json:"transactions,omitempty"`
+}
+
+func (x *TransactionList) Reset() {
+	*x = TransactionList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransactionList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionList) ProtoMessage() {}
+
+func (x *TransactionList) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionList.ProtoReflect.Descriptor instead.
+func (*TransactionList) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *TransactionList) GetIds() []*BlockIDExt {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *TransactionList) GetTransactions() []byte {
+	if x != nil {
+		return x.Transactions
+	}
+	return nil
+}
+
+type GetBlockHeaderRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Mode      uint32                `protobuf:"varint,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	Workchain int32                 `protobuf:"varint,2,opt,name=workchain,proto3" json:"workchain,omitempty"`
+	Shard     int64                 `protobuf:"varint,3,opt,name=shard,proto3" json:"shard,omitempty"`
+	Seqno     uint32                `protobuf:"varint,4,opt,name=seqno,proto3" json:"seqno,omitempty"`
+	Wait      *WaitMasterchainSeqno `protobuf:"bytes,15,opt,name=wait,proto3" json:"wait,omitempty"`
+}
+
+func (x *GetBlockHeaderRequest) Reset() {
+	*x = GetBlockHeaderRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetBlockHeaderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBlockHeaderRequest) ProtoMessage() {}
+
+func (x *GetBlockHeaderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBlockHeaderRequest.ProtoReflect.Descriptor instead.
+func (*GetBlockHeaderRequest) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *GetBlockHeaderRequest) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *GetBlockHeaderRequest) GetWorkchain() int32 {
+	if x != nil {
+		return x.Workchain
+	}
+	return 0
+}
+
+func (x *GetBlockHeaderRequest) GetShard() int64 {
+	if x != nil {
+		return x.Shard
+	}
+	return 0
+}
+
+func (x *GetBlockHeaderRequest) GetSeqno() uint32 {
+	if x != nil {
+		return x.Seqno
+	}
+	return 0
+}
+
+func (x *GetBlockHeaderRequest) GetWait() *WaitMasterchainSeqno {
+	if x != nil {
+		return x.Wait
+	}
+	return nil
+}
+
+type GetConfigAllRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Mode uint32                `protobuf:"varint,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	Id   *BlockIDExt           `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Wait *WaitMasterchainSeqno `protobuf:"bytes,15,opt,name=wait,proto3" json:"wait,omitempty"`
+}
+
+func (x *GetConfigAllRequest) Reset() {
+	*x = GetConfigAllRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetConfigAllRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConfigAllRequest) ProtoMessage() {}
+
+func (x *GetConfigAllRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConfigAllRequest.ProtoReflect.Descriptor instead.
+func (*GetConfigAllRequest) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *GetConfigAllRequest) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *GetConfigAllRequest) GetId() *BlockIDExt {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *GetConfigAllRequest) GetWait() *WaitMasterchainSeqno {
+	if x != nil {
+		return x.Wait
+	}
+	return nil
+}
+
+type GetConfigParamsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Mode   uint32                `protobuf:"varint,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	Id     *BlockIDExt           `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Params []int32               `protobuf:"varint,3,rep,packed,name=params,proto3" json:"params,omitempty"`
+	Wait   *WaitMasterchainSeqno `protobuf:"bytes,15,opt,name=wait,proto3" json:"wait,omitempty"`
+}
+
+func (x *GetConfigParamsRequest) Reset() {
+	*x = GetConfigParamsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetConfigParamsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConfigParamsRequest) ProtoMessage() {}
+
+func (x *GetConfigParamsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConfigParamsRequest.ProtoReflect.Descriptor instead.
+func (*GetConfigParamsRequest) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *GetConfigParamsRequest) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *GetConfigParamsRequest) GetId() *BlockIDExt {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *GetConfigParamsRequest) GetParams() []int32 {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *GetConfigParamsRequest) GetWait() *WaitMasterchainSeqno {
+	if x != nil {
+		return x.Wait
+	}
+	return nil
+}
+
+type ConfigInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Mode        uint32      `protobuf:"varint,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	Id          *BlockIDExt `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	StateProof  []byte      `protobuf:"bytes,3,opt,name=state_proof,json=stateProof,proto3" json:"state_proof,omitempty"`
+	ConfigProof []byte      `protobuf:"bytes,4,opt,name=config_proof,json=configProof,proto3" json:"config_proof,omitempty"`
+}
+
+func (x *ConfigInfo) Reset() {
+	*x = ConfigInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConfigInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigInfo) ProtoMessage() {}
+
+func (x *ConfigInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigInfo.ProtoReflect.Descriptor instead.
+func (*ConfigInfo) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ConfigInfo) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *ConfigInfo) GetId() *BlockIDExt {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *ConfigInfo) GetStateProof() []byte {
+	if x != nil {
+		return x.StateProof
+	}
+	return nil
+}
+
+func (x *ConfigInfo) GetConfigProof() []byte {
+	if x != nil {
+		return x.ConfigProof
+	}
+	return nil
+}
+
+type GetAllShardsInfoRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id   *BlockIDExt           `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Wait *WaitMasterchainSeqno `protobuf:"bytes,15,opt,name=wait,proto3" json:"wait,omitempty"`
+}
+
+func (x *GetAllShardsInfoRequest) Reset() {
+	*x = GetAllShardsInfoRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetAllShardsInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAllShardsInfoRequest) ProtoMessage() {}
+
+func (x *GetAllShardsInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAllShardsInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetAllShardsInfoRequest) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetAllShardsInfoRequest) GetId() *BlockIDExt {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *GetAllShardsInfoRequest) GetWait() *WaitMasterchainSeqno {
+	if x != nil {
+		return x.Wait
+	}
+	return nil
+}
+
+type AllShardsInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id    *BlockIDExt `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Proof []byte      `protobuf:"bytes,2,opt,name=proof,proto3" json:"proof,omitempty"`
+	Data  []byte      `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *AllShardsInfo) Reset() {
+	*x = AllShardsInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AllShardsInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AllShardsInfo) ProtoMessage() {}
+
+func (x *AllShardsInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AllShardsInfo.ProtoReflect.Descriptor instead.
+func (*AllShardsInfo) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *AllShardsInfo) GetId() *BlockIDExt {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *AllShardsInfo) GetProof() []byte {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+func (x *AllShardsInfo) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type GetShardInfoRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id        *BlockIDExt           `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Workchain int32                 `protobuf:"varint,2,opt,name=workchain,proto3" json:"workchain,omitempty"`
+	Shard     int64                 `protobuf:"varint,3,opt,name=shard,proto3" json:"shard,omitempty"`
+	Exact     bool                  `protobuf:"varint,4,opt,name=exact,proto3" json:"exact,omitempty"`
+	Wait      *WaitMasterchainSeqno `protobuf:"bytes,15,opt,name=wait,proto3" json:"wait,omitempty"`
+}
+
+func (x *GetShardInfoRequest) Reset() {
+	*x = GetShardInfoRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetShardInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetShardInfoRequest) ProtoMessage() {}
+
+func (x *GetShardInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetShardInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetShardInfoRequest) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GetShardInfoRequest) GetId() *BlockIDExt {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *GetShardInfoRequest) GetWorkchain() int32 {
+	if x != nil {
+		return x.Workchain
+	}
+	return 0
+}
+
+func (x *GetShardInfoRequest) GetShard() int64 {
+	if x != nil {
+		return x.Shard
+	}
+	return 0
+}
+
+func (x *GetShardInfoRequest) GetExact() bool {
+	if x != nil {
+		return x.Exact
+	}
+	return false
+}
+
+func (x *GetShardInfoRequest) GetWait() *WaitMasterchainSeqno {
+	if x != nil {
+		return x.Wait
+	}
+	return nil
+}
+
+type ShardInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         *BlockIDExt `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ShardBlock *BlockIDExt `protobuf:"bytes,2,opt,name=shard_block,json=shardBlock,proto3" json:"shard_block,omitempty"`
+	ShardProof []byte      `protobuf:"bytes,3,opt,name=shard_proof,json=shardProof,proto3" json:"shard_proof,omitempty"`
+	ShardDescr []byte      `protobuf:"bytes,4,opt,name=shard_descr,json=shardDescr,proto3" json:"shard_descr,omitempty"`
+}
+
+func (x *ShardInfo) Reset() {
+	*x = ShardInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShardInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShardInfo) ProtoMessage() {}
+
+func (x *ShardInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShardInfo.ProtoReflect.Descriptor instead.
+func (*ShardInfo) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ShardInfo) GetId() *BlockIDExt {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *ShardInfo) GetShardBlock() *BlockIDExt {
+	if x != nil {
+		return x.ShardBlock
+	}
+	return nil
+}
+
+func (x *ShardInfo) GetShardProof() []byte {
+	if x != nil {
+		return x.ShardProof
+	}
+	return nil
+}
+
+func (x *ShardInfo) GetShardDescr() []byte {
+	if x != nil {
+		return x.ShardDescr
+	}
+	return nil
+}
+
+type TransactionID3 struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Account []byte `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	Lt      uint64 `protobuf:"varint,2,opt,name=lt,proto3" json:"lt,omitempty"`
+}
+
+func (x *TransactionID3) Reset() {
+	*x = TransactionID3{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransactionID3) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionID3) ProtoMessage() {}
+
+func (x *TransactionID3) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionID3.ProtoReflect.Descriptor instead.
+func (*TransactionID3) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *TransactionID3) GetAccount() []byte {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+func (x *TransactionID3) GetLt() uint64 {
+	if x != nil {
+		return x.Lt
+	}
+	return 0
+}
+
+type ListBlockTransactionsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id           *BlockIDExt           `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Mode         uint32                `protobuf:"varint,2,opt,name=mode,proto3" json:"mode,omitempty"`
+	Count        uint32                `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	After        *TransactionID3       `protobuf:"bytes,4,opt,name=after,proto3" json:"after,omitempty"`
+	ReverseOrder bool                  `protobuf:"varint,5,opt,name=reverse_order,json=reverseOrder,proto3" json:"reverse_order,omitempty"`
+	WantProof    bool                  `protobuf:"varint,6,opt,name=want_proof,json=wantProof,proto3" json:"want_proof,omitempty"`
+	Wait         *WaitMasterchainSeqno `protobuf:"bytes,15,opt,name=wait,proto3" json:"wait,omitempty"`
+}
+
+func (x *ListBlockTransactionsRequest) Reset() {
+	*x = ListBlockTransactionsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListBlockTransactionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlockTransactionsRequest) ProtoMessage() {}
+
+func (x *ListBlockTransactionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBlockTransactionsRequest.ProtoReflect.Descriptor instead.
+func (*ListBlockTransactionsRequest) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ListBlockTransactionsRequest) GetId() *BlockIDExt {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *ListBlockTransactionsRequest) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *ListBlockTransactionsRequest) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ListBlockTransactionsRequest) GetAfter() *TransactionID3 {
+	if x != nil {
+		return x.After
+	}
+	return nil
+}
+
+func (x *ListBlockTransactionsRequest) GetReverseOrder() bool {
+	if x != nil {
+		return x.ReverseOrder
+	}
+	return false
+}
+
+func (x *ListBlockTransactionsRequest) GetWantProof() bool {
+	if x != nil {
+		return x.WantProof
+	}
+	return false
+}
+
+func (x *ListBlockTransactionsRequest) GetWait() *WaitMasterchainSeqno {
+	if x != nil {
+		return x.Wait
+	}
+	return nil
+}
+
+type TransactionID struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Mode    uint32 `protobuf:"varint,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	Account []byte `protobuf:"bytes,2,opt,name=account,proto3" json:"account,omitempty"`
+	Lt      uint64 `protobuf:"varint,3,opt,name=lt,proto3" json:"lt,omitempty"`
+	Hash    []byte `protobuf:"bytes,4,opt,name=hash,proto3" json:"hash,omitempty"`
+}
+
+func (x *TransactionID) Reset() {
+	*x = TransactionID{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransactionID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionID) ProtoMessage() {}
+
+func (x *TransactionID) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionID.ProtoReflect.Descriptor instead.
+func (*TransactionID) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *TransactionID) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *TransactionID) GetAccount() []byte {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+func (x *TransactionID) GetLt() uint64 {
+	if x != nil {
+		return x.Lt
+	}
+	return 0
+}
+
+func (x *TransactionID) GetHash() []byte {
+	if x != nil {
+		return x.Hash
+	}
+	return nil
+}
+
+type BlockTransactions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         *BlockIDExt      `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ReqCount   int32            `protobuf:"varint,2,opt,name=req_count,json=reqCount,proto3" json:"req_count,omitempty"`
+	Incomplete bool             `protobuf:"varint,3,opt,name=incomplete,proto3" json:"incomplete,omitempty"`
+	Ids        []*TransactionID `protobuf:"bytes,4,rep,name=ids,proto3" json:"ids,omitempty"`
+	Proof      []byte           `protobuf:"bytes,5,opt,name=proof,proto3" json:"proof,omitempty"`
+}
+
+func (x *BlockTransactions) Reset() {
+	*x = BlockTransactions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlockTransactions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockTransactions) ProtoMessage() {}
+
+func (x *BlockTransactions) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockTransactions.ProtoReflect.Descriptor instead.
+func (*BlockTransactions) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *BlockTransactions) GetId() *BlockIDExt {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *BlockTransactions) GetReqCount() int32 {
+	if x != nil {
+		return x.ReqCount
+	}
+	return 0
+}
+
+func (x *BlockTransactions) GetIncomplete() bool {
+	if x != nil {
+		return x.Incomplete
+	}
+	return false
+}
+
+func (x *BlockTransactions) GetIds() []*TransactionID {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *BlockTransactions) GetProof() []byte {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+type BlockTransactionsExt struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id           *BlockIDExt `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ReqCount     int32       `protobuf:"varint,2,opt,name=req_count,json=reqCount,proto3" json:"req_count,omitempty"`
+	Incomplete   bool        `protobuf:"varint,3,opt,name=incomplete,proto3" json:"incomplete,omitempty"`
+	Transactions []byte      `protobuf:"bytes,4,opt,name=transactions,proto3" json:"transactions,omitempty"`
+	Proof        []byte      `protobuf:"bytes,5,opt,name=proof,proto3" json:"proof,omitempty"`
+}
+
+func (x *BlockTransactionsExt) Reset() {
+	*x = BlockTransactionsExt{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlockTransactionsExt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockTransactionsExt) ProtoMessage() {}
+
+func (x *BlockTransactionsExt) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockTransactionsExt.ProtoReflect.Descriptor instead.
+func (*BlockTransactionsExt) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *BlockTransactionsExt) GetId() *BlockIDExt {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *BlockTransactionsExt) GetReqCount() int32 {
+	if x != nil {
+		return x.ReqCount
+	}
+	return 0
+}
+
+func (x *BlockTransactionsExt) GetIncomplete() bool {
+	if x != nil {
+		return x.Incomplete
+	}
+	return false
+}
+
+func (x *BlockTransactionsExt) GetTransactions() []byte {
+	if x != nil {
+		return x.Transactions
+	}
+	return nil
+}
+
+func (x *BlockTransactionsExt) GetProof() []byte {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+type GetBlockProofRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Mode        uint32                `protobuf:"varint,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	KnownBlock  *BlockIDExt           `protobuf:"bytes,2,opt,name=known_block,json=knownBlock,proto3" json:"known_block,omitempty"`
+	TargetBlock *BlockIDExt           `protobuf:"bytes,3,opt,name=target_block,json=targetBlock,proto3" json:"target_block,omitempty"`
+	Wait        *WaitMasterchainSeqno `protobuf:"bytes,15,opt,name=wait,proto3" json:"wait,omitempty"`
+}
+
+func (x *GetBlockProofRequest) Reset() {
+	*x = GetBlockProofRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetBlockProofRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBlockProofRequest) ProtoMessage() {}
+
+func (x *GetBlockProofRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBlockProofRequest.ProtoReflect.Descriptor instead.
+func (*GetBlockProofRequest) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *GetBlockProofRequest) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *GetBlockProofRequest) GetKnownBlock() *BlockIDExt {
+	if x != nil {
+		return x.KnownBlock
+	}
+	return nil
+}
+
+func (x *GetBlockProofRequest) GetTargetBlock() *BlockIDExt {
+	if x != nil {
+		return x.TargetBlock
+	}
+	return nil
+}
+
+func (x *GetBlockProofRequest) GetWait() *WaitMasterchainSeqno {
+	if x != nil {
+		return x.Wait
+	}
+	return nil
+}
+
+type Signature struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NodeIdShort []byte `protobuf:"bytes,1,opt,name=node_id_short,json=nodeIdShort,proto3" json:"node_id_short,omitempty"`
+	Signature   []byte `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (x *Signature) Reset() {
+	*x = Signature{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Signature) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Signature) ProtoMessage() {}
+
+func (x *Signature) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Signature.ProtoReflect.Descriptor instead.
+func (*Signature) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *Signature) GetNodeIdShort() []byte {
+	if x != nil {
+		return x.NodeIdShort
+	}
+	return nil
+}
+
+func (x *Signature) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+type SignatureSet struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ValidatorSetHash int32        `protobuf:"varint,1,opt,name=validator_set_hash,json=validatorSetHash,proto3" json:"validator_set_hash,omitempty"`
+	CatchainSeqno    int32        `protobuf:"varint,2,opt,name=catchain_seqno,json=catchainSeqno,proto3" json:"catchain_seqno,omitempty"`
+	Signatures       []*Signature `protobuf:"bytes,3,rep,name=signatures,proto3" json:"signatures,omitempty"`
+}
+
+func (x *SignatureSet) Reset() {
+	*x = SignatureSet{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignatureSet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignatureSet) ProtoMessage() {}
+
+func (x *SignatureSet) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignatureSet.ProtoReflect.Descriptor instead.
+func (*SignatureSet) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *SignatureSet) GetValidatorSetHash() int32 {
+	if x != nil {
+		return x.ValidatorSetHash
+	}
+	return 0
+}
+
+func (x *SignatureSet) GetCatchainSeqno() int32 {
+	if x != nil {
+		return x.CatchainSeqno
+	}
+	return 0
+}
+
+func (x *SignatureSet) GetSignatures() []*Signature {
+	if x != nil {
+		return x.Signatures
+	}
+	return nil
+}
+
+type BlockLinkBackward struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ToKeyBlock bool        `protobuf:"varint,1,opt,name=to_key_block,json=toKeyBlock,proto3" json:"to_key_block,omitempty"`
+	From       *BlockIDExt `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To         *BlockIDExt `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	DestProof  []byte      `protobuf:"bytes,4,opt,name=dest_proof,json=destProof,proto3" json:"dest_proof,omitempty"`
+	Proof      []byte      `protobuf:"bytes,5,opt,name=proof,proto3" json:"proof,omitempty"`
+	StateProof []byte      `protobuf:"bytes,6,opt,name=state_proof,json=stateProof,proto3" json:"state_proof,omitempty"`
+}
+
+func (x *BlockLinkBackward) Reset() {
+	*x = BlockLinkBackward{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlockLinkBackward) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockLinkBackward) ProtoMessage() {}
+
+func (x *BlockLinkBackward) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockLinkBackward.ProtoReflect.Descriptor instead.
+func (*BlockLinkBackward) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *BlockLinkBackward) GetToKeyBlock() bool {
+	if x != nil {
+		return x.ToKeyBlock
+	}
+	return false
+}
+
+func (x *BlockLinkBackward) GetFrom() *BlockIDExt {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *BlockLinkBackward) GetTo() *BlockIDExt {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *BlockLinkBackward) GetDestProof() []byte {
+	if x != nil {
+		return x.DestProof
+	}
+	return nil
+}
+
+func (x *BlockLinkBackward) GetProof() []byte {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+func (x *BlockLinkBackward) GetStateProof() []byte {
+	if x != nil {
+		return x.StateProof
+	}
+	return nil
+}
+
+type BlockLinkForward struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ToKeyBlock  bool          `protobuf:"varint,1,opt,name=to_key_block,json=toKeyBlock,proto3" json:"to_key_block,omitempty"`
+	From        *BlockIDExt   `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To          *BlockIDExt   `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	DestProof   []byte        `protobuf:"bytes,4,opt,name=dest_proof,json=destProof,proto3" json:"dest_proof,omitempty"`
+	ConfigProof []byte        `protobuf:"bytes,5,opt,name=config_proof,json=configProof,proto3" json:"config_proof,omitempty"`
+	Signatures  *SignatureSet `protobuf:"bytes,6,opt,name=signatures,proto3" json:"signatures,omitempty"`
+}
+
+func (x *BlockLinkForward) Reset() {
+	*x = BlockLinkForward{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[46]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlockLinkForward) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockLinkForward) ProtoMessage() {}
+
+func (x *BlockLinkForward) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[46]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockLinkForward.ProtoReflect.Descriptor instead.
+func (*BlockLinkForward) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *BlockLinkForward) GetToKeyBlock() bool {
+	if x != nil {
+		return x.ToKeyBlock
+	}
+	return false
+}
+
+func (x *BlockLinkForward) GetFrom() *BlockIDExt {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *BlockLinkForward) GetTo() *BlockIDExt {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *BlockLinkForward) GetDestProof() []byte {
+	if x != nil {
+		return x.DestProof
+	}
+	return nil
+}
+
+func (x *BlockLinkForward) GetConfigProof() []byte {
+	if x != nil {
+		return x.ConfigProof
+	}
+	return nil
+}
+
+func (x *BlockLinkForward) GetSignatures() *SignatureSet {
+	if x != nil {
+		return x.Signatures
+	}
+	return nil
+}
+
+type BlockLink struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Link:
+	//	*BlockLink_Back
+	//	*BlockLink_Forward
+	Link isBlockLink_Link `protobuf_oneof:"link"`
+}
+
+func (x *BlockLink) Reset() {
+	*x = BlockLink{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlockLink) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockLink) ProtoMessage() {}
+
+func (x *BlockLink) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockLink.ProtoReflect.Descriptor instead.
+func (*BlockLink) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{47}
+}
+
+func (m *BlockLink) GetLink() isBlockLink_Link {
+	if m != nil {
+		return m.Link
+	}
+	return nil
+}
+
+func (x *BlockLink) GetBack() *BlockLinkBackward {
+	if x, ok := x.GetLink().(*BlockLink_Back); ok {
+		return x.Back
+	}
+	return nil
+}
+
+func (x *BlockLink) GetForward() *BlockLinkForward {
+	if x, ok := x.GetLink().(*BlockLink_Forward); ok {
+		return x.Forward
+	}
+	return nil
+}
+
+type isBlockLink_Link interface {
+	isBlockLink_Link()
+}
+
+type BlockLink_Back struct {
+	Back *BlockLinkBackward `protobuf:"bytes,1,opt,name=back,proto3,oneof"`
+}
+
+type BlockLink_Forward struct {
+	Forward *BlockLinkForward `protobuf:"bytes,2,opt,name=forward,proto3,oneof"`
+}
+
+func (*BlockLink_Back) isBlockLink_Link() {}
+
+func (*BlockLink_Forward) isBlockLink_Link() {}
+
+type PartialBlockProof struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Complete bool         `protobuf:"varint,1,opt,name=complete,proto3" json:"complete,omitempty"`
+	From     *BlockIDExt  `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To       *BlockIDExt  `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	Steps    []*BlockLink `protobuf:"bytes,4,rep,name=steps,proto3" json:"steps,omitempty"`
+}
+
+func (x *PartialBlockProof) Reset() {
+	*x = PartialBlockProof{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_liteserver_liteserver_proto_msgTypes[48]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PartialBlockProof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PartialBlockProof) ProtoMessage() {}
+
+func (x *PartialBlockProof) ProtoReflect() protoreflect.Message {
+	mi := &file_api_liteserver_liteserver_proto_msgTypes[48]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PartialBlockProof.ProtoReflect.Descriptor instead.
+func (*PartialBlockProof) Descriptor() ([]byte, []int) {
+	return file_api_liteserver_liteserver_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *PartialBlockProof) GetComplete() bool {
+	if x != nil {
+		return x.Complete
+	}
+	return false
+}
+
+func (x *PartialBlockProof) GetFrom() *BlockIDExt {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *PartialBlockProof) GetTo() *BlockIDExt {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *PartialBlockProof) GetSteps() []*BlockLink {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+var File_api_liteserver_liteserver_proto protoreflect.FileDescriptor
+
+var file_api_liteserver_liteserver_proto_rawDesc = []byte{
+	0x0a, 0x1f, 0x61, 0x70, 0x69, 0x2f, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x2f, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x0d, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x22, 0x90, 0x01, 0x0a, 0x0a, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x44, 0x45, 0x78, 0x74, 0x12,
+	0x1c, 0x0a, 0x09, 0x77, 0x6f, 0x72, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x09, 0x77, 0x6f, 0x72, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x12, 0x14, 0x0a,
+	0x05, 0x73, 0x68, 0x61, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x73, 0x68,
+	0x61, 0x72, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x65, 0x71, 0x6e, 0x6f, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x05, 0x73, 0x65, 0x71, 0x6e, 0x6f, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x6f, 0x6f,
+	0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x72, 0x6f,
+	0x6f, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x68,
+	0x61, 0x73, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x48,
+	0x61, 0x73, 0x68, 0x22, 0x68, 0x0a, 0x0e, 0x5a, 0x65, 0x72, 0x6f, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x49, 0x44, 0x45, 0x78, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x77, 0x6f, 0x72, 0x6b, 0x63, 0x68, 0x61,
+	0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x77, 0x6f, 0x72, 0x6b, 0x63, 0x68,
+	0x61, 0x69, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x72, 0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73, 0x68,
+	0x12, 0x1b, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x48, 0x61, 0x73, 0x68, 0x22, 0x39, 0x0a,
+	0x09, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x77, 0x6f,
+	0x72, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x77,
+	0x6f, 0x72, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x69, 0x64, 0x22, 0x4b, 0x0a, 0x14, 0x57, 0x61, 0x69, 0x74,
+	0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x53, 0x65, 0x71, 0x6e, 0x6f,
+	0x12, 0x14, 0x0a, 0x05, 0x73, 0x65, 0x71, 0x6e, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x05, 0x73, 0x65, 0x71, 0x6e, 0x6f, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75,
+	0x74, 0x5f, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x6f, 0x75, 0x74, 0x4d, 0x73, 0x22, 0x54, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x73, 0x74,
+	0x65, 0x72, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x37, 0x0a, 0x04, 0x77, 0x61, 0x69, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x23, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x57, 0x61, 0x69, 0x74, 0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x63, 0x68, 0x61, 0x69, 0x6e,
+	0x53, 0x65, 0x71, 0x6e, 0x6f, 0x52, 0x04, 0x77, 0x61, 0x69, 0x74, 0x22, 0x9b, 0x01, 0x0a, 0x0f,
+	0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x12,
+	0x2d, 0x0a, 0x04, 0x6c, 0x61, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x49, 0x44, 0x45, 0x78, 0x74, 0x52, 0x04, 0x6c, 0x61, 0x73, 0x74, 0x12, 0x26,
+	0x0a, 0x0f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x68, 0x61, 0x73,
+	0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x73, 0x74, 0x61, 0x74, 0x65, 0x52, 0x6f,
+	0x6f, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x31, 0x0a, 0x04, 0x69, 0x6e, 0x69, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x5a, 0x65, 0x72, 0x6f, 0x53, 0x74, 0x61, 0x74, 0x65, 0x49, 0x44,
+	0x45, 0x78, 0x74, 0x52, 0x04, 0x69, 0x6e, 0x69, 0x74, 0x22, 0x6b, 0x0a, 0x1c, 0x47, 0x65, 0x74,
+	0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x45,
+	0x78, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x37, 0x0a,
+	0x04, 0x77, 0x61, 0x69, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x6c, 0x69,
+	0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x69, 0x74,
+	0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x53, 0x65, 0x71, 0x6e, 0x6f,
+	0x52, 0x04, 0x77, 0x61, 0x69, 0x74, 0x22, 0xa1, 0x02, 0x0a, 0x12, 0x4d, 0x61, 0x73, 0x74, 0x65,
+	0x72, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x45, 0x78, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x6d, 0x6f, 0x64,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x0c, 0x63,
+	0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0c, 0x63, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12,
+	0x2d, 0x0a, 0x04, 0x6c, 0x61, 0x73, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x49, 0x44, 0x45, 0x78, 0x74, 0x52, 0x04, 0x6c, 0x61, 0x73, 0x74, 0x12, 0x1d,
+	0x0a, 0x0a, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x75, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x55, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x10, 0x0a,
+	0x03, 0x6e, 0x6f, 0x77, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x6e, 0x6f, 0x77, 0x12,
+	0x26, 0x0a, 0x0f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x68, 0x61,
+	0x73, 0x68, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x73, 0x74, 0x61, 0x74, 0x65, 0x52,
+	0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x31, 0x0a, 0x04, 0x69, 0x6e, 0x69, 0x74, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x5a, 0x65, 0x72, 0x6f, 0x53, 0x74, 0x61, 0x74, 0x65, 0x49,
+	0x44, 0x45, 0x78, 0x74, 0x52, 0x04, 0x69, 0x6e, 0x69, 0x74, 0x22, 0x49, 0x0a, 0x0e, 0x47, 0x65,
+	0x74, 0x54, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x37, 0x0a, 0x04,
+	0x77, 0x61, 0x69, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x6c, 0x69, 0x74,
+	0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x69, 0x74, 0x4d,
+	0x61, 0x73, 0x74, 0x65, 0x72, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x53, 0x65, 0x71, 0x6e, 0x6f, 0x52,
+	0x04, 0x77, 0x61, 0x69, 0x74, 0x22, 0x1f, 0x0a, 0x0b, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74,
+	0x54, 0x69, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6e, 0x6f, 0x77, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x03, 0x6e, 0x6f, 0x77, 0x22, 0x4c, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x37, 0x0a, 0x04, 0x77,
+	0x61, 0x69, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x6c, 0x69, 0x74, 0x65,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x69, 0x74, 0x4d, 0x61,
+	0x73, 0x74, 0x65, 0x72, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x53, 0x65, 0x71, 0x6e, 0x6f, 0x52, 0x04,
+	0x77, 0x61, 0x69, 0x74, 0x22, 0x6d, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x6d,
+	0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a,
+	0x0c, 0x63, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0c, 0x63, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65,
+	0x73, 0x12, 0x10, 0x0a, 0x03, 0x6e, 0x6f, 0x77, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03,
+	0x6e, 0x6f, 0x77, 0x22, 0x75, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x44, 0x45, 0x78, 0x74, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x37, 0x0a, 0x04, 0x77, 0x61, 0x69, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x23, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x57, 0x61, 0x69, 0x74, 0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x53,
+	0x65, 0x71, 0x6e, 0x6f, 0x52, 0x04, 0x77, 0x61, 0x69, 0x74, 0x22, 0x4a, 0x0a, 0x09, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x44, 0x61, 0x74, 0x61, 0x12, 0x29, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x44, 0x45, 0x78, 0x74, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0xd1, 0x01, 0x0a, 0x12, 0x4c, 0x6f, 0x6f, 0x6b, 0x75,
+	0x70, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x6d, 0x6f, 0x64,
+	0x65, 0x12, 0x1c, 0x0a, 0x09, 0x77, 0x6f, 0x72, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x77, 0x6f, 0x72, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x12,
+	0x14, 0x0a, 0x05, 0x73, 0x68, 0x61, 0x72, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05,
+	0x73, 0x68, 0x61, 0x72, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x65, 0x71, 0x6e, 0x6f, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x73, 0x65, 0x71, 0x6e, 0x6f, 0x12, 0x0e, 0x0a, 0x02, 0x6c,
+	0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x6c, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x75,
+	0x74, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x75, 0x74, 0x69, 0x6d,
+	0x65, 0x12, 0x37, 0x0a, 0x04, 0x77, 0x61, 0x69, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x23, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x57, 0x61, 0x69, 0x74, 0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x53,
+	0x65, 0x71, 0x6e, 0x6f, 0x52, 0x04, 0x77, 0x61, 0x69, 0x74, 0x22, 0x6f, 0x0a, 0x0b, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x29, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x44, 0x45, 0x78, 0x74,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x68, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b,
+	0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x22, 0xb0, 0x01, 0x0a, 0x16,
+	0x47, 0x65, 0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x44, 0x45, 0x78, 0x74, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x32, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49, 0x44, 0x52, 0x07, 0x61, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x37, 0x0a, 0x04, 0x77, 0x61, 0x69, 0x74, 0x18, 0x0f, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x69, 0x74, 0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x63, 0x68,
+	0x61, 0x69, 0x6e, 0x53, 0x65, 0x71, 0x6e, 0x6f, 0x52, 0x04, 0x77, 0x61, 0x69, 0x74, 0x22, 0xb7,
+	0x01, 0x0a, 0x0c, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12,
+	0x29, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69,
+	0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x49, 0x44, 0x45, 0x78, 0x74, 0x52, 0x02, 0x69, 0x64, 0x12, 0x2f, 0x0a, 0x05, 0x73, 0x68,
+	0x61, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49,
+	0x44, 0x45, 0x78, 0x74, 0x52, 0x05, 0x73, 0x68, 0x61, 0x72, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x73,
+	0x68, 0x61, 0x72, 0x64, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0a, 0x73, 0x68, 0x61, 0x72, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x14, 0x0a, 0x05,
+	0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x70, 0x72, 0x6f,
+	0x6f, 0x66, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0xf6, 0x01, 0x0a, 0x13, 0x52, 0x75, 0x6e,
+	0x53, 0x6d, 0x63, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04,
+	0x6d, 0x6f, 0x64, 0x65, 0x12, 0x29, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x44, 0x45, 0x78, 0x74, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x32, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x18, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49, 0x44, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x5f, 0x69, 0x64,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x49, 0x64,
+	0x12, 0x16, 0x0a, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x37, 0x0a, 0x04, 0x77, 0x61, 0x69, 0x74,
+	0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x69, 0x74, 0x4d, 0x61, 0x73, 0x74, 0x65,
+	0x72, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x53, 0x65, 0x71, 0x6e, 0x6f, 0x52, 0x04, 0x77, 0x61, 0x69,
+	0x74, 0x22, 0xd1, 0x02, 0x0a, 0x0f, 0x52, 0x75, 0x6e, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x29, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x44, 0x45, 0x78, 0x74,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x3a, 0x0a, 0x0b, 0x73, 0x68, 0x61, 0x72, 0x64, 0x5f, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49,
+	0x44, 0x45, 0x78, 0x74, 0x52, 0x0a, 0x73, 0x68, 0x61, 0x72, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x68, 0x61, 0x72, 0x64, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x73, 0x68, 0x61, 0x72, 0x64, 0x50, 0x72, 0x6f, 0x6f,
+	0x66, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x17, 0x0a, 0x07, 0x69, 0x6e, 0x69, 0x74,
+	0x5f, 0x63, 0x37, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x69, 0x6e, 0x69, 0x74, 0x43,
+	0x37, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x69, 0x62, 0x5f, 0x65, 0x78, 0x74, 0x72, 0x61, 0x73, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x6c, 0x69, 0x62, 0x45, 0x78, 0x74, 0x72, 0x61, 0x73,
+	0x12, 0x1b, 0x0a, 0x09, 0x65, 0x78, 0x69, 0x74, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x08, 0x65, 0x78, 0x69, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0xc2, 0x01, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x4f, 0x6e, 0x65,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x29, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42,
+	0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x44, 0x45, 0x78, 0x74, 0x52, 0x02, 0x69, 0x64, 0x12, 0x32, 0x0a,
+	0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18,
+	0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x41,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49, 0x44, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x0e, 0x0a, 0x02, 0x6c, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x6c,
+	0x74, 0x12, 0x37, 0x0a, 0x04, 0x77, 0x61, 0x69, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x23, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x57, 0x61, 0x69, 0x74, 0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x53,
+	0x65, 0x71, 0x6e, 0x6f, 0x52, 0x04, 0x77, 0x61, 0x69, 0x74, 0x22, 0x74, 0x0a, 0x0f, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x29, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49,
+	0x44, 0x45, 0x78, 0x74, 0x52, 0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x6f,
+	0x66, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x20,
+	0x0a, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x22, 0x66, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x4c, 0x69, 0x62, 0x72, 0x61, 0x72, 0x69, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x12,
+	0x37, 0x0a, 0x04, 0x77, 0x61, 0x69, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e,
+	0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61,
+	0x69, 0x74, 0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x53, 0x65, 0x71,
+	0x6e, 0x6f, 0x52, 0x04, 0x77, 0x61, 0x69, 0x74, 0x22, 0x36, 0x0a, 0x0c, 0x4c, 0x69, 0x62, 0x72,
+	0x61, 0x72, 0x79, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x12, 0x0a, 0x04,
+	0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61,
+	0x22, 0x44, 0x0a, 0x0d, 0x4c, 0x69, 0x62, 0x72, 0x61, 0x72, 0x79, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x12, 0x33, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1b, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x4c, 0x69, 0x62, 0x72, 0x61, 0x72, 0x79, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x61, 0x0a, 0x12, 0x53, 0x65, 0x6e, 0x64, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x62, 0x6f, 0x64, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79,
+	0x12, 0x37, 0x0a, 0x04, 0x77, 0x61, 0x69, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23,
+	0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x57,
+	0x61, 0x69, 0x74, 0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x53, 0x65,
+	0x71, 0x6e, 0x6f, 0x52, 0x04, 0x77, 0x61, 0x69, 0x74, 0x22, 0x2b, 0x0a, 0x11, 0x53, 0x65, 0x6e,
+	0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16,
+	0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0xbf, 0x01, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x32, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x49, 0x44, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x6c,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x6c, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x68,
+	0x61, 0x73, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12,
+	0x37, 0x0a, 0x04, 0x77, 0x61, 0x69, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e,
+	0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61,
+	0x69, 0x74, 0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x53, 0x65, 0x71,
+	0x6e, 0x6f, 0x52, 0x04, 0x77, 0x61, 0x69, 0x74, 0x22, 0x62, 0x0a, 0x0f, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x03, 0x69,
+	0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x44,
+	0x45, 0x78, 0x74, 0x52, 0x03, 0x69, 0x64, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x74, 0x72, 0x61, 0x6e,
+	0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xae, 0x01, 0x0a,
+	0x15, 0x47, 0x65, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x77, 0x6f,
+	0x72, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x77,
+	0x6f, 0x72, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x68, 0x61, 0x72,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x73, 0x68, 0x61, 0x72, 0x64, 0x12, 0x14,
+	0x0a, 0x05, 0x73, 0x65, 0x71, 0x6e, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x73,
+	0x65, 0x71, 0x6e, 0x6f, 0x12, 0x37, 0x0a, 0x04, 0x77, 0x61, 0x69, 0x74, 0x18, 0x0f, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x23, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x57, 0x61, 0x69, 0x74, 0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x63, 0x68, 0x61,
+	0x69, 0x6e, 0x53, 0x65, 0x71, 0x6e, 0x6f, 0x52, 0x04, 0x77, 0x61, 0x69, 0x74, 0x22, 0x8d, 0x01,
+	0x0a, 0x13, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x41, 0x6c, 0x6c, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x29, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x44, 0x45, 0x78, 0x74,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x37, 0x0a, 0x04, 0x77, 0x61, 0x69, 0x74, 0x18, 0x0f, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x23, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x57, 0x61, 0x69, 0x74, 0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x63, 0x68, 0x61,
+	0x69, 0x6e, 0x53, 0x65, 0x71, 0x6e, 0x6f, 0x52, 0x04, 0x77, 0x61, 0x69, 0x74, 0x22, 0xa8, 0x01,
+	0x0a, 0x16, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x50, 0x61, 0x72, 0x61, 0x6d,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x29, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x44,
+	0x45, 0x78, 0x74, 0x52, 0x02, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x05, 0x52, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12,
+	0x37, 0x0a, 0x04, 0x77, 0x61, 0x69, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e,
+	0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61,
+	0x69, 0x74, 0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x53, 0x65, 0x71,
+	0x6e, 0x6f, 0x52, 0x04, 0x77, 0x61, 0x69, 0x74, 0x22, 0x8f, 0x01, 0x0a, 0x0a, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x29, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x44, 0x45,
+	0x78, 0x74, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f,
+	0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x22, 0x7d, 0x0a, 0x17, 0x47, 0x65,
+	0x74, 0x41, 0x6c, 0x6c, 0x53, 0x68, 0x61, 0x72, 0x64, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x44, 0x45, 0x78, 0x74, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x37, 0x0a, 0x04, 0x77, 0x61, 0x69, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23,
+	0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x57,
+	0x61, 0x69, 0x74, 0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x53, 0x65,
+	0x71, 0x6e, 0x6f, 0x52, 0x04, 0x77, 0x61, 0x69, 0x74, 0x22, 0x64, 0x0a, 0x0d, 0x41, 0x6c, 0x6c,
+	0x53, 0x68, 0x61, 0x72, 0x64, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x29, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x44, 0x45, 0x78,
+	0x74, 0x52, 0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x12, 0x0a, 0x04, 0x64,
+	0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22,
+	0xc3, 0x01, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x53, 0x68, 0x61, 0x72, 0x64, 0x49, 0x6e, 0x66, 0x6f,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x44, 0x45, 0x78, 0x74, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x77, 0x6f, 0x72, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x77, 0x6f, 0x72, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e,
+	0x12, 0x14, 0x0a, 0x05, 0x73, 0x68, 0x61, 0x72, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x05, 0x73, 0x68, 0x61, 0x72, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x78, 0x61, 0x63, 0x74, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x65, 0x78, 0x61, 0x63, 0x74, 0x12, 0x37, 0x0a, 0x04,
+	0x77, 0x61, 0x69, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x6c, 0x69, 0x74,
+	0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x69, 0x74, 0x4d,
+	0x61, 0x73, 0x74, 0x65, 0x72, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x53, 0x65, 0x71, 0x6e, 0x6f, 0x52,
+	0x04, 0x77, 0x61, 0x69, 0x74, 0x22, 0xb4, 0x01, 0x0a, 0x09, 0x53, 0x68, 0x61, 0x72, 0x64, 0x49,
+	0x6e, 0x66, 0x6f, 0x12, 0x29, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x44, 0x45, 0x78, 0x74, 0x52, 0x02, 0x69, 0x64, 0x12, 0x3a,
+	0x0a, 0x0b, 0x73, 0x68, 0x61, 0x72, 0x64, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x44, 0x45, 0x78, 0x74, 0x52, 0x0a,
+	0x73, 0x68, 0x61, 0x72, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x68,
+	0x61, 0x72, 0x64, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x0a, 0x73, 0x68, 0x61, 0x72, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x1f, 0x0a, 0x0b, 0x73,
+	0x68, 0x61, 0x72, 0x64, 0x5f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0a, 0x73, 0x68, 0x61, 0x72, 0x64, 0x44, 0x65, 0x73, 0x63, 0x72, 0x22, 0x3a, 0x0a, 0x0e,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x33, 0x12, 0x18,
+	0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x6c, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x6c, 0x74, 0x22, 0xa5, 0x02, 0x0a, 0x1c, 0x4c, 0x69, 0x73,
+	0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x44, 0x45, 0x78, 0x74,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x33,
+	0x0a, 0x05, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e,
+	0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x33, 0x52, 0x05, 0x61, 0x66,
+	0x74, 0x65, 0x72, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x65, 0x76, 0x65, 0x72, 0x73, 0x65, 0x5f, 0x6f,
+	0x72, 0x64, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x72, 0x65, 0x76, 0x65,
+	0x72, 0x73, 0x65, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x77, 0x61, 0x6e, 0x74,
+	0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x77, 0x61,
+	0x6e, 0x74, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x37, 0x0a, 0x04, 0x77, 0x61, 0x69, 0x74, 0x18,
+	0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x69, 0x74, 0x4d, 0x61, 0x73, 0x74, 0x65, 0x72,
+	0x63, 0x68, 0x61, 0x69, 0x6e, 0x53, 0x65, 0x71, 0x6e, 0x6f, 0x52, 0x04, 0x77, 0x61, 0x69, 0x74,
+	0x22, 0x61, 0x0a, 0x0d, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49,
+	0x44, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12,
+	0x0e, 0x0a, 0x02, 0x6c, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x6c, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x68,
+	0x61, 0x73, 0x68, 0x22, 0xc1, 0x01, 0x0a, 0x11, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x29, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x44, 0x45, 0x78, 0x74,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x65, 0x71, 0x5f, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x72, 0x65, 0x71, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74,
+	0x65, 0x12, 0x2e, 0x0a, 0x03, 0x69, 0x64, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c,
+	0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x52, 0x03, 0x69, 0x64,
+	0x73, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x22, 0xb8, 0x01, 0x0a, 0x14, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x78, 0x74,
+	0x12, 0x29, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c,
+	0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f,
+	0x63, 0x6b, 0x49, 0x44, 0x45, 0x78, 0x74, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x72,
+	0x65, 0x71, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08,
+	0x72, 0x65, 0x71, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x69, 0x6e, 0x63, 0x6f,
+	0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x69, 0x6e,
+	0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x74, 0x72, 0x61, 0x6e,
+	0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x70, 0x72, 0x6f,
+	0x6f, 0x66, 0x22, 0xdd, 0x01, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6d,
+	0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12,
+	0x3a, 0x0a, 0x0b, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x44, 0x45, 0x78, 0x74, 0x52,
+	0x0a, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x3c, 0x0a, 0x0c, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x44, 0x45, 0x78, 0x74, 0x52, 0x0b, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x37, 0x0a, 0x04, 0x77, 0x61, 0x69,
+	0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x69, 0x74, 0x4d, 0x61, 0x73, 0x74,
+	0x65, 0x72, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x53, 0x65, 0x71, 0x6e, 0x6f, 0x52, 0x04, 0x77, 0x61,
+	0x69, 0x74, 0x22, 0x4d, 0x0a, 0x09, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12,
+	0x22, 0x0a, 0x0d, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x5f, 0x73, 0x68, 0x6f, 0x72, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x53, 0x68,
+	0x6f, 0x72, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x22, 0x9d, 0x01, 0x0a, 0x0c, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x53,
+	0x65, 0x74, 0x12, 0x2c, 0x0a, 0x12, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x5f,
+	0x73, 0x65, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x10,
+	0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x53, 0x65, 0x74, 0x48, 0x61, 0x73, 0x68,
+	0x12, 0x25, 0x0a, 0x0e, 0x63, 0x61, 0x74, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x5f, 0x73, 0x65, 0x71,
+	0x6e, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x63, 0x61, 0x74, 0x63, 0x68, 0x61,
+	0x69, 0x6e, 0x53, 0x65, 0x71, 0x6e, 0x6f, 0x12, 0x38, 0x0a, 0x0a, 0x73, 0x69, 0x67, 0x6e, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6c, 0x69,
+	0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x0a, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x73, 0x22, 0xe5, 0x01, 0x0a, 0x11, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x4c, 0x69, 0x6e, 0x6b, 0x42,
+	0x61, 0x63, 0x6b, 0x77, 0x61, 0x72, 0x64, 0x12, 0x20, 0x0a, 0x0c, 0x74, 0x6f, 0x5f, 0x6b, 0x65,
+	0x79, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x74,
+	0x6f, 0x4b, 0x65, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x2d, 0x0a, 0x04, 0x66, 0x72, 0x6f,
+	0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x44, 0x45,
+	0x78, 0x74, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x29, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x44, 0x45, 0x78, 0x74, 0x52,
+	0x02, 0x74, 0x6f, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x65, 0x73, 0x74, 0x5f, 0x70, 0x72, 0x6f, 0x6f,
+	0x66, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x64, 0x65, 0x73, 0x74, 0x50, 0x72, 0x6f,
+	0x6f, 0x66, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x22, 0x8d, 0x02, 0x0a, 0x10, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x4c, 0x69, 0x6e, 0x6b, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x12, 0x20,
+	0x0a, 0x0c, 0x74, 0x6f, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x74, 0x6f, 0x4b, 0x65, 0x79, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x12, 0x2d, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42,
+	0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x44, 0x45, 0x78, 0x74, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12,
+	0x29, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69,
+	0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x49, 0x44, 0x45, 0x78, 0x74, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x65,
+	0x73, 0x74, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09,
+	0x64, 0x65, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x0b, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x3b, 0x0a, 0x0a,
+	0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1b, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x53, 0x65, 0x74, 0x52, 0x0a, 0x73,
+	0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x22, 0x88, 0x01, 0x0a, 0x09, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x4c, 0x69, 0x6e, 0x6b, 0x12, 0x36, 0x0a, 0x04, 0x62, 0x61, 0x63, 0x6b, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x4c, 0x69, 0x6e, 0x6b, 0x42,
+	0x61, 0x63, 0x6b, 0x77, 0x61, 0x72, 0x64, 0x48, 0x00, 0x52, 0x04, 0x62, 0x61, 0x63, 0x6b, 0x12,
+	0x3b, 0x0a, 0x07, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1f, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x4c, 0x69, 0x6e, 0x6b, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72,
+	0x64, 0x48, 0x00, 0x52, 0x07, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x42, 0x06, 0x0a, 0x04,
+	0x6c, 0x69, 0x6e, 0x6b, 0x22, 0xb9, 0x01, 0x0a, 0x11, 0x50, 0x61, 0x72, 0x74, 0x69, 0x61, 0x6c,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x6f,
+	0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x63, 0x6f,
+	0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x2d, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x44, 0x45, 0x78, 0x74, 0x52,
+	0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x29, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x44, 0x45, 0x78, 0x74, 0x52, 0x02, 0x74, 0x6f,
+	0x12, 0x2e, 0x0a, 0x05, 0x73, 0x74, 0x65, 0x70, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x4c, 0x69, 0x6e, 0x6b, 0x52, 0x05, 0x73, 0x74, 0x65, 0x70, 0x73,
+	0x32, 0xce, 0x0d, 0x0a, 0x0a, 0x4c, 0x69, 0x74, 0x65, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12,
+	0x5e, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x63, 0x68, 0x61, 0x69,
+	0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x28, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x63,
+	0x68, 0x61, 0x69, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1e, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x12,
+	0x67, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x63, 0x68, 0x61, 0x69,
+	0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x45, 0x78, 0x74, 0x12, 0x2b, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x73, 0x74,
+	0x65, 0x72, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x45, 0x78, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x63, 0x68, 0x61, 0x69,
+	0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x45, 0x78, 0x74, 0x12, 0x44, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x54,
+	0x69, 0x6d, 0x65, 0x12, 0x1d, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x46,
+	0x0a, 0x0a, 0x47, 0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x20, 0x2e, 0x6c,
+	0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16,
+	0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x44, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x42, 0x6c, 0x6f,
+	0x63, 0x6b, 0x12, 0x1e, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x18, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x61, 0x74, 0x61, 0x12, 0x4c, 0x0a, 0x0b,
+	0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x21, 0x2e, 0x6c, 0x69,
+	0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x6f, 0x6b,
+	0x75, 0x70, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a,
+	0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42,
+	0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x55, 0x0a, 0x0f, 0x47, 0x65,
+	0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x25, 0x2e,
+	0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65,
+	0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x12, 0x52, 0x0a, 0x0c, 0x52, 0x75, 0x6e, 0x53, 0x6d, 0x63, 0x4d, 0x65, 0x74, 0x68, 0x6f,
+	0x64, 0x12, 0x22, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x52, 0x75, 0x6e, 0x53, 0x6d, 0x63, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x75, 0x6e, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x5c, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x4f, 0x6e, 0x65, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x27, 0x2e, 0x6c, 0x69, 0x74,
+	0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x4f, 0x6e,
+	0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49,
+	0x6e, 0x66, 0x6f, 0x12, 0x50, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x4c, 0x69, 0x62, 0x72, 0x61, 0x72,
+	0x69, 0x65, 0x73, 0x12, 0x22, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x4c, 0x69, 0x62, 0x72, 0x61, 0x72, 0x69, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x62, 0x72, 0x61, 0x72, 0x79, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x52, 0x0a, 0x0b, 0x53, 0x65, 0x6e, 0x64, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x12, 0x21, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x58, 0x0a, 0x0f, 0x47, 0x65, 0x74,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x25, 0x2e, 0x6c,
+	0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4c,
+	0x69, 0x73, 0x74, 0x12, 0x52, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48,
+	0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x24, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x6c, 0x69,
+	0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x4d, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x41, 0x6c, 0x6c, 0x12, 0x22, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x41, 0x6c, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x6c, 0x69,
+	0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x53, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x25, 0x2e, 0x6c, 0x69, 0x74, 0x65,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x19, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x58, 0x0a, 0x10, 0x47,
+	0x65, 0x74, 0x41, 0x6c, 0x6c, 0x53, 0x68, 0x61, 0x72, 0x64, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x12,
+	0x26, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x47, 0x65, 0x74, 0x41, 0x6c, 0x6c, 0x53, 0x68, 0x61, 0x72, 0x64, 0x73, 0x49, 0x6e, 0x66, 0x6f,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x6c, 0x6c, 0x53, 0x68, 0x61, 0x72, 0x64,
+	0x73, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x4c, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x53, 0x68, 0x61, 0x72,
+	0x64, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x22, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x68, 0x61, 0x72, 0x64, 0x49, 0x6e,
+	0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x6c, 0x69, 0x74, 0x65,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x68, 0x61, 0x72, 0x64, 0x49,
+	0x6e, 0x66, 0x6f, 0x12, 0x66, 0x0a, 0x15, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x2b, 0x2e, 0x6c,
+	0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x6c, 0x69, 0x74, 0x65,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x6c, 0x0a, 0x18, 0x4c,
+	0x69, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x45, 0x78, 0x74, 0x12, 0x2b, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x78, 0x74, 0x12, 0x56, 0x0a, 0x0d, 0x47, 0x65, 0x74,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x23, 0x2e, 0x6c, 0x69, 0x74,
+	0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x20, 0x2e, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x50, 0x61, 0x72, 0x74, 0x69, 0x61, 0x6c, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x50, 0x72, 0x6f, 0x6f,
+	0x66, 0x42, 0x3d, 0x5a, 0x3b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x78, 0x73, 0x73, 0x6e, 0x69, 0x63, 0x6b, 0x2f, 0x74, 0x6f, 0x6e, 0x75, 0x74, 0x69, 0x6c, 0x73,
+	0x2d, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2d, 0x70, 0x72, 0x6f, 0x78,
+	0x79, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x6c, 0x69, 0x74, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_api_liteserver_liteserver_proto_rawDescOnce sync.Once
+	file_api_liteserver_liteserver_proto_rawDescData = file_api_liteserver_liteserver_proto_rawDesc
+)
+
+func file_api_liteserver_liteserver_proto_rawDescGZIP() []byte {
+	file_api_liteserver_liteserver_proto_rawDescOnce.Do(func() {
+		file_api_liteserver_liteserver_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_liteserver_liteserver_proto_rawDescData)
+	})
+	return file_api_liteserver_liteserver_proto_rawDescData
+}
+
+var file_api_liteserver_liteserver_proto_msgTypes = make([]protoimpl.MessageInfo, 49)
+var file_api_liteserver_liteserver_proto_goTypes = []interface{}{
+	(*BlockIDExt)(nil),                   // 0: liteserver.v1.BlockIDExt
+	(*ZeroStateIDExt)(nil),               // 1: liteserver.v1.ZeroStateIDExt
+	(*AccountID)(nil),                    // 2: liteserver.v1.AccountID
+	(*WaitMasterchainSeqno)(nil),         // 3: liteserver.v1.WaitMasterchainSeqno
+	(*GetMasterchainInfoRequest)(nil),    // 4: liteserver.v1.GetMasterchainInfoRequest
+	(*MasterchainInfo)(nil),              // 5: liteserver.v1.MasterchainInfo
+	(*GetMasterchainInfoExtRequest)(nil), // 6: liteserver.v1.GetMasterchainInfoExtRequest
+	(*MasterchainInfoExt)(nil),           // 7: liteserver.v1.MasterchainInfoExt
+	(*GetTimeRequest)(nil),               // 8: liteserver.v1.GetTimeRequest
+	(*CurrentTime)(nil),                  // 9: liteserver.v1.CurrentTime
+	(*GetVersionRequest)(nil),            // 10: liteserver.v1.GetVersionRequest
+	(*Version)(nil),                      // 11: liteserver.v1.Version
+	(*GetBlockRequest)(nil),              // 12: liteserver.v1.GetBlockRequest
+	(*BlockData)(nil),                    // 13: liteserver.v1.BlockData
+	(*LookupBlockRequest)(nil),           // 14: liteserver.v1.LookupBlockRequest
+	(*BlockHeader)(nil),                  // 15: liteserver.v1.BlockHeader
+	(*GetAccountStateRequest)(nil),       // 16: liteserver.v1.GetAccountStateRequest
+	(*AccountState)(nil),                 // 17: liteserver.v1.AccountState
+	(*RunSmcMethodRequest)(nil),          // 18: liteserver.v1.RunSmcMethodRequest
+	(*RunMethodResult)(nil),              // 19: liteserver.v1.RunMethodResult
+	(*GetOneTransactionRequest)(nil),     // 20: liteserver.v1.GetOneTransactionRequest
+	(*TransactionInfo)(nil),              // 21: liteserver.v1.TransactionInfo
+	(*GetLibrariesRequest)(nil),          // 22: liteserver.v1.GetLibrariesRequest
+	(*LibraryEntry)(nil),                 // 23: liteserver.v1.LibraryEntry
+	(*LibraryResult)(nil),                // 24: liteserver.v1.LibraryResult
+	(*SendMessageRequest)(nil),           // 25: liteserver.v1.SendMessageRequest
+	(*SendMessageStatus)(nil),            // 26: liteserver.v1.SendMessageStatus
+	(*GetTransactionsRequest)(nil),       // 27: liteserver.v1.GetTransactionsRequest
+	(*TransactionList)(nil),              // 28: liteserver.v1.TransactionList
+	(*GetBlockHeaderRequest)(nil),        // 29: liteserver.v1.GetBlockHeaderRequest
+	(*GetConfigAllRequest)(nil),          // 30: liteserver.v1.GetConfigAllRequest
+	(*GetConfigParamsRequest)(nil),       // 31: liteserver.v1.GetConfigParamsRequest
+	(*ConfigInfo)(nil),                   // 32: liteserver.v1.ConfigInfo
+	(*GetAllShardsInfoRequest)(nil),      // 33: liteserver.v1.GetAllShardsInfoRequest
+	(*AllShardsInfo)(nil),                // 34: liteserver.v1.AllShardsInfo
+	(*GetShardInfoRequest)(nil),          // 35: liteserver.v1.GetShardInfoRequest
+	(*ShardInfo)(nil),                    // 36: liteserver.v1.ShardInfo
+	(*TransactionID3)(nil),               // 37: liteserver.v1.TransactionID3
+	(*ListBlockTransactionsRequest)(nil), // 38: liteserver.v1.ListBlockTransactionsRequest
+	(*TransactionID)(nil),                // 39: liteserver.v1.TransactionID
+	(*BlockTransactions)(nil),            // 40: liteserver.v1.BlockTransactions
+	(*BlockTransactionsExt)(nil),         // 41: liteserver.v1.BlockTransactionsExt
+	(*GetBlockProofRequest)(nil),         // 42: liteserver.v1.GetBlockProofRequest
+	(*Signature)(nil),                    // 43: liteserver.v1.Signature
+	(*SignatureSet)(nil),                 // 44: liteserver.v1.SignatureSet
+	(*BlockLinkBackward)(nil),            // 45: liteserver.v1.BlockLinkBackward
+	(*BlockLinkForward)(nil),             // 46: liteserver.v1.BlockLinkForward
+	(*BlockLink)(nil),                    // 47: liteserver.v1.BlockLink
+	(*PartialBlockProof)(nil),            // 48: liteserver.v1.PartialBlockProof
+}
+var file_api_liteserver_liteserver_proto_depIdxs = []int32{
+	3,  // 0: liteserver.v1.GetMasterchainInfoRequest.wait:type_name -> liteserver.v1.WaitMasterchainSeqno
+	0,  // 1: liteserver.v1.MasterchainInfo.last:type_name -> liteserver.v1.BlockIDExt
+	1,  // 2: liteserver.v1.MasterchainInfo.init:type_name -> liteserver.v1.ZeroStateIDExt
+	3,  // 3: liteserver.v1.GetMasterchainInfoExtRequest.wait:type_name -> liteserver.v1.WaitMasterchainSeqno
+	0,  // 4: liteserver.v1.MasterchainInfoExt.last:type_name -> liteserver.v1.BlockIDExt
+	1,  // 5: liteserver.v1.MasterchainInfoExt.init:type_name -> liteserver.v1.ZeroStateIDExt
+	3,  // 6: liteserver.v1.GetTimeRequest.wait:type_name -> liteserver.v1.WaitMasterchainSeqno
+	3,  // 7: liteserver.v1.GetVersionRequest.wait:type_name -> liteserver.v1.WaitMasterchainSeqno
+	0,  // 8: liteserver.v1.GetBlockRequest.id:type_name -> liteserver.v1.BlockIDExt
+	3,  // 9: liteserver.v1.GetBlockRequest.wait:type_name -> liteserver.v1.WaitMasterchainSeqno
+	0,  // 10: liteserver.v1.BlockData.id:type_name -> liteserver.v1.BlockIDExt
+	3,  // 11: liteserver.v1.LookupBlockRequest.wait:type_name -> liteserver.v1.WaitMasterchainSeqno
+	0,  // 12: liteserver.v1.BlockHeader.id:type_name -> liteserver.v1.BlockIDExt
+	0,  // 13: liteserver.v1.GetAccountStateRequest.id:type_name -> liteserver.v1.BlockIDExt
+	2,  // 14: liteserver.v1.GetAccountStateRequest.account:type_name -> liteserver.v1.AccountID
+	3,  // 15: liteserver.v1.GetAccountStateRequest.wait:type_name -> liteserver.v1.WaitMasterchainSeqno
+	0,  // 16: liteserver.v1.AccountState.id:type_name -> liteserver.v1.BlockIDExt
+	0,  // 17: liteserver.v1.AccountState.shard:type_name -> liteserver.v1.BlockIDExt
+	0,  // 18: liteserver.v1.RunSmcMethodRequest.id:type_name -> liteserver.v1.BlockIDExt
+	2,  // 19: liteserver.v1.RunSmcMethodRequest.account:type_name -> liteserver.v1.AccountID
+	3,  // 20: liteserver.v1.RunSmcMethodRequest.wait:type_name -> liteserver.v1.WaitMasterchainSeqno
+	0,  // 21: liteserver.v1.RunMethodResult.id:type_name -> liteserver.v1.BlockIDExt
+	0,  // 22: liteserver.v1.RunMethodResult.shard_block:type_name -> liteserver.v1.BlockIDExt
+	0,  // 23: liteserver.v1.GetOneTransactionRequest.id:type_name -> liteserver.v1.BlockIDExt
+	2,  // 24: liteserver.v1.GetOneTransactionRequest.account:type_name -> liteserver.v1.AccountID
+	3,  // 25: liteserver.v1.GetOneTransactionRequest.wait:type_name -> liteserver.v1.WaitMasterchainSeqno
+	0,  // 26: liteserver.v1.TransactionInfo.id:type_name -> liteserver.v1.BlockIDExt
+	3,  // 27: liteserver.v1.GetLibrariesRequest.wait:type_name -> liteserver.v1.WaitMasterchainSeqno
+	23, // 28: liteserver.v1.LibraryResult.result:type_name -> liteserver.v1.LibraryEntry
+	3,  // 29: liteserver.v1.SendMessageRequest.wait:type_name -> liteserver.v1.WaitMasterchainSeqno
+	2,  // 30: liteserver.v1.GetTransactionsRequest.account:type_name -> liteserver.v1.AccountID
+	3,  // 31: liteserver.v1.GetTransactionsRequest.wait:type_name -> liteserver.v1.WaitMasterchainSeqno
+	0,  // 32: liteserver.v1.TransactionList.ids:type_name -> liteserver.v1.BlockIDExt
+	3,  // 33: liteserver.v1.GetBlockHeaderRequest.wait:type_name -> liteserver.v1.WaitMasterchainSeqno
+	0,  // 34: liteserver.v1.GetConfigAllRequest.id:type_name -> liteserver.v1.BlockIDExt
+	3,  // 35: liteserver.v1.GetConfigAllRequest.wait:type_name -> liteserver.v1.WaitMasterchainSeqno
+	0,  // 36: liteserver.v1.GetConfigParamsRequest.id:type_name -> liteserver.v1.BlockIDExt
+	3,  // 37: liteserver.v1.GetConfigParamsRequest.wait:type_name -> liteserver.v1.WaitMasterchainSeqno
+	0,  // 38: liteserver.v1.ConfigInfo.id:type_name -> liteserver.v1.BlockIDExt
+	0,  // 39: liteserver.v1.GetAllShardsInfoRequest.id:type_name -> liteserver.v1.BlockIDExt
+	3,  // 40: liteserver.v1.GetAllShardsInfoRequest.wait:type_name -> liteserver.v1.WaitMasterchainSeqno
+	0,  // 41: liteserver.v1.AllShardsInfo.id:type_name -> liteserver.v1.BlockIDExt
+	0,  // 42: liteserver.v1.GetShardInfoRequest.id:type_name -> liteserver.v1.BlockIDExt
+	3,  // 43: liteserver.v1.GetShardInfoRequest.wait:type_name -> liteserver.v1.WaitMasterchainSeqno
+	0,  // 44: liteserver.v1.ShardInfo.id:type_name -> liteserver.v1.BlockIDExt
+	0,  // 45: liteserver.v1.ShardInfo.shard_block:type_name -> liteserver.v1.BlockIDExt
+	0,  // 46: liteserver.v1.ListBlockTransactionsRequest.id:type_name -> liteserver.v1.BlockIDExt
+	37, // 47: liteserver.v1.ListBlockTransactionsRequest.after:type_name -> liteserver.v1.TransactionID3
+	3,  // 48: liteserver.v1.ListBlockTransactionsRequest.wait:type_name -> liteserver.v1.WaitMasterchainSeqno
+	0,  // 49: liteserver.v1.BlockTransactions.id:type_name -> liteserver.v1.BlockIDExt
+	39, // 50: liteserver.v1.BlockTransactions.ids:type_name -> liteserver.v1.TransactionID
+	0,  // 51: liteserver.v1.BlockTransactionsExt.id:type_name -> liteserver.v1.BlockIDExt
+	0,  // 52: liteserver.v1.GetBlockProofRequest.known_block:type_name -> liteserver.v1.BlockIDExt
+	0,  // 53: liteserver.v1.GetBlockProofRequest.target_block:type_name -> liteserver.v1.BlockIDExt
+	3,  // 54: liteserver.v1.GetBlockProofRequest.wait:type_name -> liteserver.v1.WaitMasterchainSeqno
+	43, // 55: liteserver.v1.SignatureSet.signatures:type_name -> liteserver.v1.Signature
+	0,  // 56: liteserver.v1.BlockLinkBackward.from:type_name -> liteserver.v1.BlockIDExt
+	0,  // 57: liteserver.v1.BlockLinkBackward.to:type_name -> liteserver.v1.BlockIDExt
+	0,  // 58: liteserver.v1.BlockLinkForward.from:type_name -> liteserver.v1.BlockIDExt
+	0,  // 59: liteserver.v1.BlockLinkForward.to:type_name -> liteserver.v1.BlockIDExt
+	44, // 60: liteserver.v1.BlockLinkForward.signatures:type_name -> liteserver.v1.SignatureSet
+	45, // 61: liteserver.v1.BlockLink.back:type_name -> liteserver.v1.BlockLinkBackward
+	46, // 62: liteserver.v1.BlockLink.forward:type_name -> liteserver.v1.BlockLinkForward
+	0,  // 63: liteserver.v1.PartialBlockProof.from:type_name -> liteserver.v1.BlockIDExt
+	0,  // 64: liteserver.v1.PartialBlockProof.to:type_name -> liteserver.v1.BlockIDExt
+	47, // 65: liteserver.v1.PartialBlockProof.steps:type_name -> liteserver.v1.BlockLink
+	4,  // 66: liteserver.v1.LiteServer.GetMasterchainInfo:input_type -> liteserver.v1.GetMasterchainInfoRequest
+	6,  // 67: liteserver.v1.LiteServer.GetMasterchainInfoExt:input_type -> liteserver.v1.GetMasterchainInfoExtRequest
+	8,  // 68: liteserver.v1.LiteServer.GetTime:input_type -> liteserver.v1.GetTimeRequest
+	10, // 69: liteserver.v1.LiteServer.GetVersion:input_type -> liteserver.v1.GetVersionRequest
+	12, // 70: liteserver.v1.LiteServer.GetBlock:input_type -> liteserver.v1.GetBlockRequest
+	14, // 71: liteserver.v1.LiteServer.LookupBlock:input_type -> liteserver.v1.LookupBlockRequest
+	16, // 72: liteserver.v1.LiteServer.GetAccountState:input_type -> liteserver.v1.GetAccountStateRequest
+	18, // 73: liteserver.v1.LiteServer.RunSmcMethod:input_type -> liteserver.v1.RunSmcMethodRequest
+	20, // 74: liteserver.v1.LiteServer.GetOneTransaction:input_type -> liteserver.v1.GetOneTransactionRequest
+	22, // 75: liteserver.v1.LiteServer.GetLibraries:input_type -> liteserver.v1.GetLibrariesRequest
+	25, // 76: liteserver.v1.LiteServer.SendMessage:input_type -> liteserver.v1.SendMessageRequest
+	27, // 77: liteserver.v1.LiteServer.GetTransactions:input_type -> liteserver.v1.GetTransactionsRequest
+	29, // 78: liteserver.v1.LiteServer.GetBlockHeader:input_type -> liteserver.v1.GetBlockHeaderRequest
+	30, // 79: liteserver.v1.LiteServer.GetConfigAll:input_type -> liteserver.v1.GetConfigAllRequest
+	31, // 80: liteserver.v1.LiteServer.GetConfigParams:input_type -> liteserver.v1.GetConfigParamsRequest
+	33, // 81: liteserver.v1.LiteServer.GetAllShardsInfo:input_type -> liteserver.v1.GetAllShardsInfoRequest
+	35, // 82: liteserver.v1.LiteServer.GetShardInfo:input_type -> liteserver.v1.GetShardInfoRequest
+	38, // 83: liteserver.v1.LiteServer.ListBlockTransactions:input_type -> liteserver.v1.ListBlockTransactionsRequest
+	38, // 84: liteserver.v1.LiteServer.ListBlockTransactionsExt:input_type -> liteserver.v1.ListBlockTransactionsRequest
+	42, // 85: liteserver.v1.LiteServer.GetBlockProof:input_type -> liteserver.v1.GetBlockProofRequest
+	5,  // 86: liteserver.v1.LiteServer.GetMasterchainInfo:output_type -> liteserver.v1.MasterchainInfo
+	7,  // 87: liteserver.v1.LiteServer.GetMasterchainInfoExt:output_type -> liteserver.v1.MasterchainInfoExt
+	9,  // 88: liteserver.v1.LiteServer.GetTime:output_type -> liteserver.v1.CurrentTime
+	11, // 89: liteserver.v1.LiteServer.GetVersion:output_type -> liteserver.v1.Version
+	13, // 90: liteserver.v1.LiteServer.GetBlock:output_type -> liteserver.v1.BlockData
+	15, // 91: liteserver.v1.LiteServer.LookupBlock:output_type -> liteserver.v1.BlockHeader
+	17, // 92: liteserver.v1.LiteServer.GetAccountState:output_type -> liteserver.v1.AccountState
+	19, // 93: liteserver.v1.LiteServer.RunSmcMethod:output_type -> liteserver.v1.RunMethodResult
+	21, // 94: liteserver.v1.LiteServer.GetOneTransaction:output_type -> liteserver.v1.TransactionInfo
+	24, // 95: liteserver.v1.LiteServer.GetLibraries:output_type -> liteserver.v1.LibraryResult
+	26, // 96: liteserver.v1.LiteServer.SendMessage:output_type -> liteserver.v1.SendMessageStatus
+	28, // 97: liteserver.v1.LiteServer.GetTransactions:output_type -> liteserver.v1.TransactionList
+	15, // 98: liteserver.v1.LiteServer.GetBlockHeader:output_type -> liteserver.v1.BlockHeader
+	32, // 99: liteserver.v1.LiteServer.GetConfigAll:output_type -> liteserver.v1.ConfigInfo
+	32, // 100: liteserver.v1.LiteServer.GetConfigParams:output_type -> liteserver.v1.ConfigInfo
+	34, // 101: liteserver.v1.LiteServer.GetAllShardsInfo:output_type -> liteserver.v1.AllShardsInfo
+	36, // 102: liteserver.v1.LiteServer.GetShardInfo:output_type -> liteserver.v1.ShardInfo
+	40, // 103: liteserver.v1.LiteServer.ListBlockTransactions:output_type -> liteserver.v1.BlockTransactions
+	41, // 104: liteserver.v1.LiteServer.ListBlockTransactionsExt:output_type -> liteserver.v1.BlockTransactionsExt
+	48, // 105: liteserver.v1.LiteServer.GetBlockProof:output_type -> liteserver.v1.PartialBlockProof
+	86, // [86:106] is the sub-list for method output_type
+	66, // [66:86] is the sub-list for method input_type
+	66, // [66:66] is the sub-list for extension type_name
+	66, // [66:66] is the sub-list for extension extendee
+	0,  // [0:66] is the sub-list for field type_name
+}
+
+func init() { file_api_liteserver_liteserver_proto_init() }
+func file_api_liteserver_liteserver_proto_init() {
+	if File_api_liteserver_liteserver_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_api_liteserver_liteserver_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlockIDExt); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ZeroStateIDExt); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AccountID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WaitMasterchainSeqno); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetMasterchainInfoRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MasterchainInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetMasterchainInfoExtRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MasterchainInfoExt); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetTimeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CurrentTime); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetVersionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Version); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetBlockRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlockData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LookupBlockRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlockHeader); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetAccountStateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AccountState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunSmcMethodRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunMethodResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetOneTransactionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransactionInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetLibrariesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LibraryEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LibraryResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SendMessageRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SendMessageStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetTransactionsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransactionList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetBlockHeaderRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetConfigAllRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetConfigParamsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConfigInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetAllShardsInfoRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AllShardsInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetShardInfoRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShardInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransactionID3); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListBlockTransactionsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransactionID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlockTransactions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlockTransactionsExt); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetBlockProofRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Signature); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignatureSet); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlockLinkBackward); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlockLinkForward); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlockLink); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_liteserver_liteserver_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PartialBlockProof); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_api_liteserver_liteserver_proto_msgTypes[47].OneofWrappers = []interface{}{
+		(*BlockLink_Back)(nil),
+		(*BlockLink_Forward)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_api_liteserver_liteserver_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   49,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_liteserver_liteserver_proto_goTypes,
+		DependencyIndexes: file_api_liteserver_liteserver_proto_depIdxs,
+		MessageInfos:      file_api_liteserver_liteserver_proto_msgTypes,
+	}.Build()
+	File_api_liteserver_liteserver_proto = out.File
+	file_api_liteserver_liteserver_proto_rawDesc = nil
+	file_api_liteserver_liteserver_proto_goTypes = nil
+	file_api_liteserver_liteserver_proto_depIdxs = nil
+}
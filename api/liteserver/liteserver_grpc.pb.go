@@ -0,0 +1,812 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: api/liteserver/liteserver.proto
+
+package liteserver
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	LiteServer_GetMasterchainInfo_FullMethodName       = "/liteserver.v1.LiteServer/GetMasterchainInfo"
+	LiteServer_GetMasterchainInfoExt_FullMethodName    = "/liteserver.v1.LiteServer/GetMasterchainInfoExt"
+	LiteServer_GetTime_FullMethodName                  = "/liteserver.v1.LiteServer/GetTime"
+	LiteServer_GetVersion_FullMethodName               = "/liteserver.v1.LiteServer/GetVersion"
+	LiteServer_GetBlock_FullMethodName                 = "/liteserver.v1.LiteServer/GetBlock"
+	LiteServer_LookupBlock_FullMethodName              = "/liteserver.v1.LiteServer/LookupBlock"
+	LiteServer_GetAccountState_FullMethodName          = "/liteserver.v1.LiteServer/GetAccountState"
+	LiteServer_RunSmcMethod_FullMethodName             = "/liteserver.v1.LiteServer/RunSmcMethod"
+	LiteServer_GetOneTransaction_FullMethodName        = "/liteserver.v1.LiteServer/GetOneTransaction"
+	LiteServer_GetLibraries_FullMethodName             = "/liteserver.v1.LiteServer/GetLibraries"
+	LiteServer_SendMessage_FullMethodName              = "/liteserver.v1.LiteServer/SendMessage"
+	LiteServer_GetTransactions_FullMethodName          = "/liteserver.v1.LiteServer/GetTransactions"
+	LiteServer_GetBlockHeader_FullMethodName           = "/liteserver.v1.LiteServer/GetBlockHeader"
+	LiteServer_GetConfigAll_FullMethodName             = "/liteserver.v1.LiteServer/GetConfigAll"
+	LiteServer_GetConfigParams_FullMethodName          = "/liteserver.v1.LiteServer/GetConfigParams"
+	LiteServer_GetAllShardsInfo_FullMethodName         = "/liteserver.v1.LiteServer/GetAllShardsInfo"
+	LiteServer_GetShardInfo_FullMethodName             = "/liteserver.v1.LiteServer/GetShardInfo"
+	LiteServer_ListBlockTransactions_FullMethodName    = "/liteserver.v1.LiteServer/ListBlockTransactions"
+	LiteServer_ListBlockTransactionsExt_FullMethodName = "/liteserver.v1.LiteServer/ListBlockTransactionsExt"
+	LiteServer_GetBlockProof_FullMethodName            = "/liteserver.v1.LiteServer/GetBlockProof"
+)
+
+// LiteServerClient is the client API for LiteServer service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type LiteServerClient interface {
+	GetMasterchainInfo(ctx context.Context, in *GetMasterchainInfoRequest, opts ...grpc.CallOption) (*MasterchainInfo, error)
+	GetMasterchainInfoExt(ctx context.Context, in *GetMasterchainInfoExtRequest, opts ...grpc.CallOption) (*MasterchainInfoExt, error)
+	GetTime(ctx context.Context, in *GetTimeRequest, opts ...grpc.CallOption) (*CurrentTime, error)
+	GetVersion(ctx context.Context, in *GetVersionRequest, opts ...grpc.CallOption) (*Version, error)
+	GetBlock(ctx context.Context, in *GetBlockRequest, opts ...grpc.CallOption) (*BlockData, error)
+	LookupBlock(ctx context.Context, in *LookupBlockRequest, opts ...grpc.CallOption) (*BlockHeader, error)
+	GetAccountState(ctx context.Context, in *GetAccountStateRequest, opts ...grpc.CallOption) (*AccountState, error)
+	RunSmcMethod(ctx context.Context, in *RunSmcMethodRequest, opts ...grpc.CallOption) (*RunMethodResult, error)
+	GetOneTransaction(ctx context.Context, in *GetOneTransactionRequest, opts ...grpc.CallOption) (*TransactionInfo, error)
+	GetLibraries(ctx context.Context, in *GetLibrariesRequest, opts ...grpc.CallOption) (*LibraryResult, error)
+	SendMessage(ctx context.Context, in *SendMessageRequest, opts ...grpc.CallOption) (*SendMessageStatus, error)
+	GetTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (*TransactionList, error)
+	GetBlockHeader(ctx context.Context, in *GetBlockHeaderRequest, opts ...grpc.CallOption) (*BlockHeader, error)
+	GetConfigAll(ctx context.Context, in *GetConfigAllRequest, opts ...grpc.CallOption) (*ConfigInfo, error)
+	GetConfigParams(ctx context.Context, in *GetConfigParamsRequest, opts ...grpc.CallOption) (*ConfigInfo, error)
+	GetAllShardsInfo(ctx context.Context, in *GetAllShardsInfoRequest, opts ...grpc.CallOption) (*AllShardsInfo, error)
+	GetShardInfo(ctx context.Context, in *GetShardInfoRequest, opts ...grpc.CallOption) (*ShardInfo, error)
+	ListBlockTransactions(ctx context.Context, in *ListBlockTransactionsRequest, opts ...grpc.CallOption) (*BlockTransactions, error)
+	ListBlockTransactionsExt(ctx context.Context, in *ListBlockTransactionsRequest, opts ...grpc.CallOption) (*BlockTransactionsExt, error)
+	GetBlockProof(ctx context.Context, in *GetBlockProofRequest, opts ...grpc.CallOption) (*PartialBlockProof, error)
+}
+
+type liteServerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLiteServerClient(cc grpc.ClientConnInterface) LiteServerClient {
+	return &liteServerClient{cc}
+}
+
+func (c *liteServerClient) GetMasterchainInfo(ctx context.Context, in *GetMasterchainInfoRequest, opts ...grpc.CallOption) (*MasterchainInfo, error) {
+	out := new(MasterchainInfo)
+	err := c.cc.Invoke(ctx, LiteServer_GetMasterchainInfo_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *liteServerClient) GetMasterchainInfoExt(ctx context.Context, in *GetMasterchainInfoExtRequest, opts ...grpc.CallOption) (*MasterchainInfoExt, error) {
+	out := new(MasterchainInfoExt)
+	err := c.cc.Invoke(ctx, LiteServer_GetMasterchainInfoExt_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *liteServerClient) GetTime(ctx context.Context, in *GetTimeRequest, opts ...grpc.CallOption) (*CurrentTime, error) {
+	out := new(CurrentTime)
+	err := c.cc.Invoke(ctx, LiteServer_GetTime_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *liteServerClient) GetVersion(ctx context.Context, in *GetVersionRequest, opts ...grpc.CallOption) (*Version, error) {
+	out := new(Version)
+	err := c.cc.Invoke(ctx, LiteServer_GetVersion_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *liteServerClient) GetBlock(ctx context.Context, in *GetBlockRequest, opts ...grpc.CallOption) (*BlockData, error) {
+	out := new(BlockData)
+	err := c.cc.Invoke(ctx, LiteServer_GetBlock_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *liteServerClient) LookupBlock(ctx context.Context, in *LookupBlockRequest, opts ...grpc.CallOption) (*BlockHeader, error) {
+	out := new(BlockHeader)
+	err := c.cc.Invoke(ctx, LiteServer_LookupBlock_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *liteServerClient) GetAccountState(ctx context.Context, in *GetAccountStateRequest, opts ...grpc.CallOption) (*AccountState, error) {
+	out := new(AccountState)
+	err := c.cc.Invoke(ctx, LiteServer_GetAccountState_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *liteServerClient) RunSmcMethod(ctx context.Context, in *RunSmcMethodRequest, opts ...grpc.CallOption) (*RunMethodResult, error) {
+	out := new(RunMethodResult)
+	err := c.cc.Invoke(ctx, LiteServer_RunSmcMethod_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *liteServerClient) GetOneTransaction(ctx context.Context, in *GetOneTransactionRequest, opts ...grpc.CallOption) (*TransactionInfo, error) {
+	out := new(TransactionInfo)
+	err := c.cc.Invoke(ctx, LiteServer_GetOneTransaction_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *liteServerClient) GetLibraries(ctx context.Context, in *GetLibrariesRequest, opts ...grpc.CallOption) (*LibraryResult, error) {
+	out := new(LibraryResult)
+	err := c.cc.Invoke(ctx, LiteServer_GetLibraries_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *liteServerClient) SendMessage(ctx context.Context, in *SendMessageRequest, opts ...grpc.CallOption) (*SendMessageStatus, error) {
+	out := new(SendMessageStatus)
+	err := c.cc.Invoke(ctx, LiteServer_SendMessage_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *liteServerClient) GetTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (*TransactionList, error) {
+	out := new(TransactionList)
+	err := c.cc.Invoke(ctx, LiteServer_GetTransactions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *liteServerClient) GetBlockHeader(ctx context.Context, in *GetBlockHeaderRequest, opts ...grpc.CallOption) (*BlockHeader, error) {
+	out := new(BlockHeader)
+	err := c.cc.Invoke(ctx, LiteServer_GetBlockHeader_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *liteServerClient) GetConfigAll(ctx context.Context, in *GetConfigAllRequest, opts ...grpc.CallOption) (*ConfigInfo, error) {
+	out := new(ConfigInfo)
+	err := c.cc.Invoke(ctx, LiteServer_GetConfigAll_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *liteServerClient) GetConfigParams(ctx context.Context, in *GetConfigParamsRequest, opts ...grpc.CallOption) (*ConfigInfo, error) {
+	out := new(ConfigInfo)
+	err := c.cc.Invoke(ctx, LiteServer_GetConfigParams_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *liteServerClient) GetAllShardsInfo(ctx context.Context, in *GetAllShardsInfoRequest, opts ...grpc.CallOption) (*AllShardsInfo, error) {
+	out := new(AllShardsInfo)
+	err := c.cc.Invoke(ctx, LiteServer_GetAllShardsInfo_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *liteServerClient) GetShardInfo(ctx context.Context, in *GetShardInfoRequest, opts ...grpc.CallOption) (*ShardInfo, error) {
+	out := new(ShardInfo)
+	err := c.cc.Invoke(ctx, LiteServer_GetShardInfo_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *liteServerClient) ListBlockTransactions(ctx context.Context, in *ListBlockTransactionsRequest, opts ...grpc.CallOption) (*BlockTransactions, error) {
+	out := new(BlockTransactions)
+	err := c.cc.Invoke(ctx, LiteServer_ListBlockTransactions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *liteServerClient) ListBlockTransactionsExt(ctx context.Context, in *ListBlockTransactionsRequest, opts ...grpc.CallOption) (*BlockTransactionsExt, error) {
+	out := new(BlockTransactionsExt)
+	err := c.cc.Invoke(ctx, LiteServer_ListBlockTransactionsExt_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *liteServerClient) GetBlockProof(ctx context.Context, in *GetBlockProofRequest, opts ...grpc.CallOption) (*PartialBlockProof, error) {
+	out := new(PartialBlockProof)
+	err := c.cc.Invoke(ctx, LiteServer_GetBlockProof_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LiteServerServer is the server API for LiteServer service.
+// All implementations must embed UnimplementedLiteServerServer
+// for forward compatibility
+type LiteServerServer interface {
+	GetMasterchainInfo(context.Context, *GetMasterchainInfoRequest) (*MasterchainInfo, error)
+	GetMasterchainInfoExt(context.Context, *GetMasterchainInfoExtRequest) (*MasterchainInfoExt, error)
+	GetTime(context.Context, *GetTimeRequest) (*CurrentTime, error)
+	GetVersion(context.Context, *GetVersionRequest) (*Version, error)
+	GetBlock(context.Context, *GetBlockRequest) (*BlockData, error)
+	LookupBlock(context.Context, *LookupBlockRequest) (*BlockHeader, error)
+	GetAccountState(context.Context, *GetAccountStateRequest) (*AccountState, error)
+	RunSmcMethod(context.Context, *RunSmcMethodRequest) (*RunMethodResult, error)
+	GetOneTransaction(context.Context, *GetOneTransactionRequest) (*TransactionInfo, error)
+	GetLibraries(context.Context, *GetLibrariesRequest) (*LibraryResult, error)
+	SendMessage(context.Context, *SendMessageRequest) (*SendMessageStatus, error)
+	GetTransactions(context.Context, *GetTransactionsRequest) (*TransactionList, error)
+	GetBlockHeader(context.Context, *GetBlockHeaderRequest) (*BlockHeader, error)
+	GetConfigAll(context.Context, *GetConfigAllRequest) (*ConfigInfo, error)
+	GetConfigParams(context.Context, *GetConfigParamsRequest) (*ConfigInfo, error)
+	GetAllShardsInfo(context.Context, *GetAllShardsInfoRequest) (*AllShardsInfo, error)
+	GetShardInfo(context.Context, *GetShardInfoRequest) (*ShardInfo, error)
+	ListBlockTransactions(context.Context, *ListBlockTransactionsRequest) (*BlockTransactions, error)
+	ListBlockTransactionsExt(context.Context, *ListBlockTransactionsRequest) (*BlockTransactionsExt, error)
+	GetBlockProof(context.Context, *GetBlockProofRequest) (*PartialBlockProof, error)
+	mustEmbedUnimplementedLiteServerServer()
+}
+
+// UnimplementedLiteServerServer must be embedded to have forward compatible implementations.
+type UnimplementedLiteServerServer struct {
+}
+
+func (UnimplementedLiteServerServer) GetMasterchainInfo(context.Context, *GetMasterchainInfoRequest) (*MasterchainInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMasterchainInfo not implemented")
+}
+func (UnimplementedLiteServerServer) GetMasterchainInfoExt(context.Context, *GetMasterchainInfoExtRequest) (*MasterchainInfoExt, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMasterchainInfoExt not implemented")
+}
+func (UnimplementedLiteServerServer) GetTime(context.Context, *GetTimeRequest) (*CurrentTime, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTime not implemented")
+}
+func (UnimplementedLiteServerServer) GetVersion(context.Context, *GetVersionRequest) (*Version, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVersion not implemented")
+}
+func (UnimplementedLiteServerServer) GetBlock(context.Context, *GetBlockRequest) (*BlockData, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlock not implemented")
+}
+func (UnimplementedLiteServerServer) LookupBlock(context.Context, *LookupBlockRequest) (*BlockHeader, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LookupBlock not implemented")
+}
+func (UnimplementedLiteServerServer) GetAccountState(context.Context, *GetAccountStateRequest) (*AccountState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountState not implemented")
+}
+func (UnimplementedLiteServerServer) RunSmcMethod(context.Context, *RunSmcMethodRequest) (*RunMethodResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunSmcMethod not implemented")
+}
+func (UnimplementedLiteServerServer) GetOneTransaction(context.Context, *GetOneTransactionRequest) (*TransactionInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOneTransaction not implemented")
+}
+func (UnimplementedLiteServerServer) GetLibraries(context.Context, *GetLibrariesRequest) (*LibraryResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLibraries not implemented")
+}
+func (UnimplementedLiteServerServer) SendMessage(context.Context, *SendMessageRequest) (*SendMessageStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendMessage not implemented")
+}
+func (UnimplementedLiteServerServer) GetTransactions(context.Context, *GetTransactionsRequest) (*TransactionList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransactions not implemented")
+}
+func (UnimplementedLiteServerServer) GetBlockHeader(context.Context, *GetBlockHeaderRequest) (*BlockHeader, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlockHeader not implemented")
+}
+func (UnimplementedLiteServerServer) GetConfigAll(context.Context, *GetConfigAllRequest) (*ConfigInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConfigAll not implemented")
+}
+func (UnimplementedLiteServerServer) GetConfigParams(context.Context, *GetConfigParamsRequest) (*ConfigInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConfigParams not implemented")
+}
+func (UnimplementedLiteServerServer) GetAllShardsInfo(context.Context, *GetAllShardsInfoRequest) (*AllShardsInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAllShardsInfo not implemented")
+}
+func (UnimplementedLiteServerServer) GetShardInfo(context.Context, *GetShardInfoRequest) (*ShardInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetShardInfo not implemented")
+}
+func (UnimplementedLiteServerServer) ListBlockTransactions(context.Context, *ListBlockTransactionsRequest) (*BlockTransactions, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBlockTransactions not implemented")
+}
+func (UnimplementedLiteServerServer) ListBlockTransactionsExt(context.Context, *ListBlockTransactionsRequest) (*BlockTransactionsExt, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBlockTransactionsExt not implemented")
+}
+func (UnimplementedLiteServerServer) GetBlockProof(context.Context, *GetBlockProofRequest) (*PartialBlockProof, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlockProof not implemented")
+}
+func (UnimplementedLiteServerServer) mustEmbedUnimplementedLiteServerServer() {}
+
+// UnsafeLiteServerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LiteServerServer will
+// result in compilation errors.
+type UnsafeLiteServerServer interface {
+	mustEmbedUnimplementedLiteServerServer()
+}
+
+func RegisterLiteServerServer(s grpc.ServiceRegistrar, srv LiteServerServer) {
+	s.RegisterService(&LiteServer_ServiceDesc, srv)
+}
+
+func _LiteServer_GetMasterchainInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMasterchainInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LiteServerServer).GetMasterchainInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LiteServer_GetMasterchainInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LiteServerServer).GetMasterchainInfo(ctx, req.(*GetMasterchainInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LiteServer_GetMasterchainInfoExt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMasterchainInfoExtRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LiteServerServer).GetMasterchainInfoExt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LiteServer_GetMasterchainInfoExt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LiteServerServer).GetMasterchainInfoExt(ctx, req.(*GetMasterchainInfoExtRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LiteServer_GetTime_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTimeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LiteServerServer).GetTime(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LiteServer_GetTime_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LiteServerServer).GetTime(ctx, req.(*GetTimeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LiteServer_GetVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LiteServerServer).GetVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LiteServer_GetVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LiteServerServer).GetVersion(ctx, req.(*GetVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LiteServer_GetBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LiteServerServer).GetBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LiteServer_GetBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LiteServerServer).GetBlock(ctx, req.(*GetBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LiteServer_LookupBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LiteServerServer).LookupBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LiteServer_LookupBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LiteServerServer).LookupBlock(ctx, req.(*LookupBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LiteServer_GetAccountState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LiteServerServer).GetAccountState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LiteServer_GetAccountState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LiteServerServer).GetAccountState(ctx, req.(*GetAccountStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LiteServer_RunSmcMethod_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RunSmcMethodRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LiteServerServer).RunSmcMethod(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LiteServer_RunSmcMethod_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LiteServerServer).RunSmcMethod(ctx, req.(*RunSmcMethodRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LiteServer_GetOneTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOneTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LiteServerServer).GetOneTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LiteServer_GetOneTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LiteServerServer).GetOneTransaction(ctx, req.(*GetOneTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LiteServer_GetLibraries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLibrariesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LiteServerServer).GetLibraries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LiteServer_GetLibraries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LiteServerServer).GetLibraries(ctx, req.(*GetLibrariesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LiteServer_SendMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LiteServerServer).SendMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LiteServer_SendMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LiteServerServer).SendMessage(ctx, req.(*SendMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LiteServer_GetTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LiteServerServer).GetTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LiteServer_GetTransactions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LiteServerServer).GetTransactions(ctx, req.(*GetTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LiteServer_GetBlockHeader_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockHeaderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LiteServerServer).GetBlockHeader(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LiteServer_GetBlockHeader_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LiteServerServer).GetBlockHeader(ctx, req.(*GetBlockHeaderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LiteServer_GetConfigAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConfigAllRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LiteServerServer).GetConfigAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LiteServer_GetConfigAll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LiteServerServer).GetConfigAll(ctx, req.(*GetConfigAllRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LiteServer_GetConfigParams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConfigParamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LiteServerServer).GetConfigParams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LiteServer_GetConfigParams_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LiteServerServer).GetConfigParams(ctx, req.(*GetConfigParamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LiteServer_GetAllShardsInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAllShardsInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LiteServerServer).GetAllShardsInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LiteServer_GetAllShardsInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LiteServerServer).GetAllShardsInfo(ctx, req.(*GetAllShardsInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LiteServer_GetShardInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetShardInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LiteServerServer).GetShardInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LiteServer_GetShardInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LiteServerServer).GetShardInfo(ctx, req.(*GetShardInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LiteServer_ListBlockTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBlockTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LiteServerServer).ListBlockTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LiteServer_ListBlockTransactions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LiteServerServer).ListBlockTransactions(ctx, req.(*ListBlockTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LiteServer_ListBlockTransactionsExt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBlockTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LiteServerServer).ListBlockTransactionsExt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LiteServer_ListBlockTransactionsExt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LiteServerServer).ListBlockTransactionsExt(ctx, req.(*ListBlockTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LiteServer_GetBlockProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LiteServerServer).GetBlockProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LiteServer_GetBlockProof_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LiteServerServer).GetBlockProof(ctx, req.(*GetBlockProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// LiteServer_ServiceDesc is the grpc.ServiceDesc for LiteServer service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LiteServer_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "liteserver.v1.LiteServer",
+	HandlerType: (*LiteServerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetMasterchainInfo",
+			Handler:    _LiteServer_GetMasterchainInfo_Handler,
+		},
+		{
+			MethodName: "GetMasterchainInfoExt",
+			Handler:    _LiteServer_GetMasterchainInfoExt_Handler,
+		},
+		{
+			MethodName: "GetTime",
+			Handler:    _LiteServer_GetTime_Handler,
+		},
+		{
+			MethodName: "GetVersion",
+			Handler:    _LiteServer_GetVersion_Handler,
+		},
+		{
+			MethodName: "GetBlock",
+			Handler:    _LiteServer_GetBlock_Handler,
+		},
+		{
+			MethodName: "LookupBlock",
+			Handler:    _LiteServer_LookupBlock_Handler,
+		},
+		{
+			MethodName: "GetAccountState",
+			Handler:    _LiteServer_GetAccountState_Handler,
+		},
+		{
+			MethodName: "RunSmcMethod",
+			Handler:    _LiteServer_RunSmcMethod_Handler,
+		},
+		{
+			MethodName: "GetOneTransaction",
+			Handler:    _LiteServer_GetOneTransaction_Handler,
+		},
+		{
+			MethodName: "GetLibraries",
+			Handler:    _LiteServer_GetLibraries_Handler,
+		},
+		{
+			MethodName: "SendMessage",
+			Handler:    _LiteServer_SendMessage_Handler,
+		},
+		{
+			MethodName: "GetTransactions",
+			Handler:    _LiteServer_GetTransactions_Handler,
+		},
+		{
+			MethodName: "GetBlockHeader",
+			Handler:    _LiteServer_GetBlockHeader_Handler,
+		},
+		{
+			MethodName: "GetConfigAll",
+			Handler:    _LiteServer_GetConfigAll_Handler,
+		},
+		{
+			MethodName: "GetConfigParams",
+			Handler:    _LiteServer_GetConfigParams_Handler,
+		},
+		{
+			MethodName: "GetAllShardsInfo",
+			Handler:    _LiteServer_GetAllShardsInfo_Handler,
+		},
+		{
+			MethodName: "GetShardInfo",
+			Handler:    _LiteServer_GetShardInfo_Handler,
+		},
+		{
+			MethodName: "ListBlockTransactions",
+			Handler:    _LiteServer_ListBlockTransactions_Handler,
+		},
+		{
+			MethodName: "ListBlockTransactionsExt",
+			Handler:    _LiteServer_ListBlockTransactionsExt_Handler,
+		},
+		{
+			MethodName: "GetBlockProof",
+			Handler:    _LiteServer_GetBlockProof_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/liteserver/liteserver.proto",
+}
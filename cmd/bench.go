@@ -0,0 +1,277 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/liteclient"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"math/rand"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// elector, exists in every network
+const benchDefaultAccount = "Ef8zMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzM0vF"
+
+type benchResult struct {
+	latencies map[string][]time.Duration
+	errors    map[string]int
+}
+
+// benchQuery is one kind of query in mix, it is picked with probability proportional to weight
+type benchQuery struct {
+	name    string
+	weight  int
+	payload tl.Serializable
+}
+
+// runBench sends queries to proxy from parallel workers during duration and prints throughput
+// and latency percentiles, address and key are taken from config when not passed
+func runBench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	configPath := fs.String("config", defaultConfigPath, "path to config, used when addr or key are not passed")
+	addr := fs.String("addr", "", "address of proxy, default is listen address from config")
+	key := fs.String("key", "", "base64 public key of proxy, default is key of the first client from config")
+	connections := fs.Int("connections", 4, "number of connections to proxy")
+	concurrency := fs.Int("concurrency", 16, "number of parallel workers")
+	duration := fs.Duration("duration", 10*time.Second, "duration of benchmark")
+	query := fs.String("query", "masterchain", "mix of queries to send as name[:weight] list separated by comma, "+
+		"names are masterchain, account and method, for example masterchain:1,account:3,method:2")
+	account := fs.String("account", benchDefaultAccount, "account for account and method queries")
+	method := fs.String("method", "active_election_id", "get method without arguments for method query")
+	_ = fs.Parse(args)
+
+	if err := proxyEndpoint(*configPath, addr, key); err != nil {
+		return err
+	}
+
+	if *connections <= 0 || *concurrency <= 0 {
+		return fmt.Errorf("connections and concurrency should be positive")
+	}
+
+	client := liteclient.NewConnectionPool()
+	defer client.Stop()
+
+	for i := 0; i < *connections; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err := client.AddConnection(ctx, *addr, *key)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to connect to %s: %w", *addr, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	block, err := ton.NewAPIClient(client).GetMasterchainInfo(ctx)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to get masterchain info: %w", err)
+	}
+
+	acc, err := address.ParseAddr(*account)
+	if err != nil {
+		return fmt.Errorf("invalid account: %w", err)
+	}
+
+	mix, err := parseBenchMix(*query, block, acc, *method)
+	if err != nil {
+		return err
+	}
+	totalWeight := 0
+	for _, q := range mix {
+		totalWeight += q.weight
+	}
+
+	fmt.Printf("benchmarking %s with %s queries, %d workers, %d connections, %s\n",
+		*addr, *query, *concurrency, *connections, duration.String())
+
+	results := make([]*benchResult, *concurrency)
+	deadline := time.Now().Add(*duration)
+
+	var wg sync.WaitGroup
+	for i := range results {
+		res := &benchResult{latencies: map[string][]time.Duration{}, errors: map[string]int{}}
+		results[i] = res
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for time.Now().Before(deadline) {
+				q := pickBenchQuery(mix, totalWeight)
+				start := time.Now()
+
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				var resp tl.Serializable
+				err := client.QueryLiteserver(ctx, q.payload, &resp)
+				cancel()
+
+				if err == nil {
+					if ls, ok := resp.(ton.LSError); ok {
+						err = ls
+					}
+				}
+				if err != nil {
+					res.errors[q.name+": "+err.Error()]++
+					continue
+				}
+				res.latencies[q.name] = append(res.latencies[q.name], time.Since(start))
+			}
+		}()
+	}
+	wg.Wait()
+
+	var latencies []time.Duration
+	byQuery := map[string][]time.Duration{}
+	errors := map[string]int{}
+	failed := 0
+	for _, res := range results {
+		for name, list := range res.latencies {
+			latencies = append(latencies, list...)
+			byQuery[name] = append(byQuery[name], list...)
+		}
+		for msg, num := range res.errors {
+			errors[msg] += num
+			failed += num
+		}
+	}
+
+	fmt.Printf("requests: %d ok, %d failed, %.1f rps\n", len(latencies), failed, float64(len(latencies))/duration.Seconds())
+	printLatencies("latency", latencies)
+	if len(mix) > 1 {
+		for _, q := range mix {
+			printLatencies(fmt.Sprintf("%s latency (%d ok)", q.name, len(byQuery[q.name])), byQuery[q.name])
+		}
+	}
+	for msg, num := range errors {
+		fmt.Printf("error %q: %d\n", msg, num)
+	}
+	return nil
+}
+
+// proxyEndpoint fills address and public key of proxy from config when they are not passed
+func proxyEndpoint(configPath string, addr, key *string) error {
+	if *addr != "" && *key != "" {
+		return nil
+	}
+
+	if _, err := os.Stat(configPath); err != nil {
+		return fmt.Errorf("addr and key are not passed and config cannot be read: %w", err)
+	}
+
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if *addr == "" {
+		*addr = localAddr(cfg.ListenAddr)
+	}
+	if *key == "" {
+		if len(cfg.Clients) == 0 || len(cfg.Clients[0].PrivateKey) != ed25519.SeedSize {
+			return fmt.Errorf("no valid client keys in config")
+		}
+		pub := ed25519.NewKeyFromSeed(cfg.Clients[0].PrivateKey).Public().(ed25519.PublicKey)
+		*key = base64.StdEncoding.EncodeToString(pub)
+	}
+	return nil
+}
+
+// parseBenchMix builds queries from list like masterchain:1,account:3, all queries are for the same block,
+// so they measure cached path of proxy
+func parseBenchMix(mix string, block *ton.BlockIDExt, acc *address.Address, method string) ([]*benchQuery, error) {
+	accID := ton.AccountID{Workchain: acc.Workchain(), ID: acc.Data()}
+
+	var list []*benchQuery
+	for _, item := range strings.Split(mix, ",") {
+		name, weightStr, hasWeight := strings.Cut(strings.TrimSpace(item), ":")
+		q := &benchQuery{name: name, weight: 1}
+		if hasWeight {
+			w, err := strconv.Atoi(weightStr)
+			if err != nil || w <= 0 {
+				return nil, fmt.Errorf("invalid weight of query %s", name)
+			}
+			q.weight = w
+		}
+
+		switch name {
+		case "masterchain":
+			q.payload = ton.GetMasterchainInf{}
+		case "account":
+			q.payload = ton.GetAccountState{ID: block, Account: accID}
+		case "method":
+			params, err := (&tlb.Stack{}).ToCell()
+			if err != nil {
+				return nil, err
+			}
+			q.payload = ton.RunSmcMethod{
+				Mode:     1 << 2,
+				ID:       block,
+				Account:  accID,
+				MethodID: tlb.MethodNameHash(method),
+				Params:   params,
+			}
+		default:
+			return nil, fmt.Errorf("unknown query %s", name)
+		}
+		list = append(list, q)
+	}
+	return list, nil
+}
+
+func pickBenchQuery(mix []*benchQuery, totalWeight int) *benchQuery {
+	if len(mix) == 1 {
+		return mix[0]
+	}
+
+	n := rand.Intn(totalWeight)
+	for _, q := range mix {
+		if n < q.weight {
+			return q
+		}
+		n -= q.weight
+	}
+	return mix[len(mix)-1]
+}
+
+func printLatencies(title string, latencies []time.Duration) {
+	if len(latencies) == 0 {
+		return
+	}
+
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+	fmt.Printf("%s: p50 %s, p90 %s, p99 %s, max %s\n", title,
+		percentile(latencies, 50), percentile(latencies, 90), percentile(latencies, 99), latencies[len(latencies)-1])
+}
+
+// percentile of sorted list
+func percentile(list []time.Duration, p int) time.Duration {
+	return list[(len(list)-1)*p/100].Round(time.Microsecond)
+}
+
+// localAddr replaces unspecified host of listen address with loopback
+func localAddr(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, port)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/xssnick/tonutils-go/liteclient"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"github.com/xssnick/tonutils-liteserver-proxy/internal/server"
+	"os"
+	"time"
+)
+
+// runCheckConfig parses config and checks that each static backend is reachable
+// and belongs to configured network, non-zero exit code is returned when something is wrong
+func runCheckConfig(args []string) error {
+	fs := flag.NewFlagSet("check-config", flag.ExitOnError)
+	configPath := fs.String("config", defaultConfigPath, "path to config, json or yaml by extension")
+	timeout := fs.Duration("timeout", 5*time.Second, "timeout of connection and request to each backend")
+	_ = fs.Parse(args)
+
+	// load would create example config, but here we check existing one
+	if _, err := os.Stat(*configPath); err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse config: %w", err)
+	}
+	fmt.Println("config syntax: ok")
+
+	if err = cfg.Validate(); err != nil {
+		var verr *config.ValidationError
+		if !errors.As(err, &verr) {
+			return err
+		}
+
+		for _, f := range verr.Fields {
+			fmt.Println("invalid field", f.Error())
+		}
+		return fmt.Errorf("config has %d invalid fields", len(verr.Fields))
+	}
+	fmt.Println("config fields: ok")
+
+	zeroState, err := server.NetworkZeroState(cfg.Network, cfg.ZeroState)
+	if err != nil {
+		return fmt.Errorf("invalid network: %w", err)
+	}
+
+	if cfg.GlobalConfigURL != "" {
+		fmt.Println("backends from global config are discovered on start and not checked")
+	}
+
+	failed := 0
+	for _, backend := range cfg.Backends {
+		name := backend.Addr
+		if backend.Name != "" {
+			name = backend.Name + " (" + backend.Addr + ")"
+		}
+
+		inf, took, err := checkBackend(backend, *timeout)
+		if err == nil && zeroState != nil && !bytes.Equal(inf.Init.RootHash, zeroState.RootHash) {
+			err = fmt.Errorf("backend is from another network")
+		}
+		if err != nil {
+			failed++
+			fmt.Printf("backend %s: failed: %s\n", name, err.Error())
+			continue
+		}
+
+		// the first backend defines network when it is not configured
+		if zeroState == nil {
+			zeroState = inf.Init
+		}
+		fmt.Printf("backend %s: ok, master seqno %d, took %s\n", name, inf.Last.SeqNo, took.Round(time.Millisecond).String())
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d backends are not available", failed, len(cfg.Backends))
+	}
+	return nil
+}
+
+func checkBackend(backend config.BackendLiteserver, timeout time.Duration) (*ton.MasterchainInfo, time.Duration, error) {
+	if len(backend.Key) != ed25519.PublicKeySize {
+		return nil, 0, fmt.Errorf("key should be %d bytes", ed25519.PublicKeySize)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	start := time.Now()
+
+	client := liteclient.NewConnectionPool()
+	defer client.Stop()
+
+	if err := client.AddConnection(ctx, backend.Addr, base64.StdEncoding.EncodeToString(backend.Key)); err != nil {
+		return nil, 0, fmt.Errorf("failed to connect: %w", err)
+	}
+
+	var resp tl.Serializable
+	if err := client.QueryLiteserver(ctx, ton.GetMasterchainInf{}, &resp); err != nil {
+		return nil, 0, fmt.Errorf("failed to get masterchain info: %w", err)
+	}
+
+	switch t := resp.(type) {
+	case ton.MasterchainInfo:
+		return &t, time.Since(start), nil
+	case ton.LSError:
+		return nil, 0, t
+	}
+	return nil, 0, fmt.Errorf("unexpected response %T", resp)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"github.com/xssnick/tonutils-liteserver-proxy/internal/server"
+	"os"
+)
+
+// runClientConfig prints liteservers config for client keys, ready to be passed to customers
+func runClientConfig(args []string) error {
+	fs := flag.NewFlagSet("client-config", flag.ExitOnError)
+	configPath := fs.String("config", defaultConfigPath, "path to config, json or yaml by extension")
+	name := fs.String("name", "", "name of client key, configs of all keys by name are printed when empty")
+	addr := fs.String("addr", "", "public ipv4:port of proxy, default is PublicAddr or ListenAddr from config")
+	_ = fs.Parse(args)
+
+	if _, err := os.Stat(*configPath); err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	configs, err := server.ClientConfigs(cfg, *addr)
+	if err != nil {
+		return err
+	}
+
+	var res any = configs
+	if *name != "" {
+		c, ok := configs[*name]
+		if !ok {
+			return fmt.Errorf("no client key with name %s", *name)
+		}
+		res = c
+	}
+
+	data, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
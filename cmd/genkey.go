@@ -0,0 +1,23 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"flag"
+	"fmt"
+)
+
+// runGenKey prints new client keypair, private key is a seed in the same format as PrivateKey of client config
+func runGenKey(args []string) error {
+	fs := flag.NewFlagSet("genkey", flag.ExitOnError)
+	_ = fs.Parse(args)
+
+	pub, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		return fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	fmt.Println("private_key:", base64.StdEncoding.EncodeToString(private.Seed()))
+	fmt.Println("public_key: ", base64.StdEncoding.EncodeToString(pub))
+	return nil
+}
@@ -1,83 +1,84 @@
 package main
 
 import (
-	"crypto/ed25519"
-	"encoding/base64"
-	"flag"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"fmt"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/xssnick/tonutils-go/liteclient"
 	_ "github.com/xssnick/tonutils-go/ton"
-	"github.com/xssnick/tonutils-liteserver-proxy/config"
-	"github.com/xssnick/tonutils-liteserver-proxy/internal/server"
-	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
-	"net/http"
-	"time"
+	"github.com/xssnick/tonutils-liteserver-proxy/internal/logging"
+	"os"
+	"strings"
 )
 
-var (
-	Verbosity = flag.Int("verbosity", 2, "3 = debug, 2 = info, 1 = warn, 0 = error")
-)
+const defaultConfigPath = "ls-proxy-config.json"
 
-func main() {
-	flag.Parse()
-	liteclient.Logger = func(v ...any) {}
+const usage = `usage: liteserver [command] [flags]
 
-	log.Logger = zerolog.New(zerolog.NewConsoleWriter()).With().Timestamp().Logger().Level(zerolog.InfoLevel)
+commands:
+  serve         run proxy, default when command is not specified
+  check-config  validate config and connectivity of backends
+  genkey        generate client keypair
+  client-config print liteservers config for client keys
+  bench         run load benchmark against running proxy
+  replay        send captured queries to running proxy and compare responses
 
-	switch *Verbosity {
-	case 3:
-		log.Logger = log.Logger.Level(zerolog.DebugLevel).With().Logger()
-	case 2:
-		log.Logger = log.Logger.Level(zerolog.InfoLevel).With().Logger()
-	case 1:
-		log.Logger = log.Logger.Level(zerolog.WarnLevel).With().Logger()
-	case 0:
-		log.Logger = log.Logger.Level(zerolog.ErrorLevel).With().Logger()
-	}
-
-	cfg, err := config.LoadConfig("ls-proxy-config.json")
-	if err != nil {
-		log.Fatal().Err(err).Msg("failed to load config")
-		return
-	}
+run 'liteserver <command> -h' to see flags of command
+`
 
-	metrics.InitMetrics(cfg.MetricsNamespace, "tonutils_ls_proxy")
+func main() {
+	liteclient.Logger = func(v ...any) {}
 
-	if len(cfg.Backends) == 0 {
-		log.Fatal().Msg("no backends specified")
+	// flags without command are passed to serve, to keep old way of running working
+	cmd, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		cmd, args = args[0], args[1:]
 	}
 
-	for i, clientConfig := range cfg.Clients {
-		key := ed25519.NewKeyFromSeed(clientConfig.PrivateKey)
-		log.Info().Int("i", i).Str("pub_key", base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))).Msg("liteserver initialized")
+	var err error
+	switch cmd {
+	case "serve":
+		err = runServe(args)
+	case "check-config":
+		err = runCheckConfig(args)
+	case "genkey":
+		err = runGenKey(args)
+	case "client-config":
+		err = runClientConfig(args)
+	case "bench":
+		err = runBench(args)
+	case "replay":
+		err = runReplay(args)
+	case "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %s\n\n%s", cmd, usage)
+		os.Exit(2)
 	}
 
-	blc, err := server.NewBackendBalancer(cfg.Backends, server.BalancerType(cfg.BalancerType))
 	if err != nil {
-		log.Fatal().Err(err).Msg("failed to init backend balancer")
-		return
-	}
-
-	var cache *server.BlockCache
-	if !cfg.DisableEmulationAndCache {
-		cache = server.NewBlockCache(cfg.CacheConfig, blc)
+		fmt.Fprintln(os.Stderr, "error:", err.Error())
+		os.Exit(1)
 	}
+}
 
-	go func() {
-		http.Handle("/metrics", promhttp.Handler())
-		if err := http.ListenAndServe(cfg.MetricsAddr, nil); err != nil {
-			log.Fatal().Err(err).Msg("listen metrics failed")
-		}
-	}()
+func setupLogger(verbosity int) {
+	// level is applied globally, so it can be changed at runtime via admin api
+	log.Logger = zerolog.New(zerolog.NewConsoleWriter()).With().Timestamp().Logger().Hook(logging.ModuleHook{})
 
-	log.Info().Str("addr", cfg.ListenAddr).Msg("listening tcp")
-	proxy := server.NewProxyBalancer(cfg.Clients, blc, cache,
-		cfg.DisableEmulationAndCache, int(cfg.MaxConnectionsPerIP), time.Duration(cfg.MaxKeepAliveSeconds)*time.Second,
-		int(cfg.ResponseGeneralCacheSize))
-	if err = proxy.Listen(cfg.ListenAddr); err != nil {
-		log.Fatal().Err(err).Msg("listen failed")
-		return
+	switch verbosity {
+	case 3:
+		logging.SetLevel(zerolog.DebugLevel)
+	case 2:
+		logging.SetLevel(zerolog.InfoLevel)
+	case 1:
+		logging.SetLevel(zerolog.WarnLevel)
+	case 0:
+		logging.SetLevel(zerolog.ErrorLevel)
+	default:
+		logging.SetLevel(zerolog.InfoLevel)
 	}
+
+	// loggers of queries are passed with context, others fall back to global
+	zerolog.DefaultContextLogger = &log.Logger
 }
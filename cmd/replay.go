@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/xssnick/tonutils-go/liteclient"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-liteserver-proxy/internal/server"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+type replayStats struct {
+	sent       map[string]int
+	mismatches map[string]int
+	errors     map[string]int
+	mx         sync.Mutex
+}
+
+// runReplay sends queries from capture file to proxy and compares responses with captured ones,
+// so changes of cache and emulation can be checked against real traffic
+func runReplay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	file := fs.String("file", "", "capture file written by proxy with Capture.Path")
+	configPath := fs.String("config", defaultConfigPath, "path to config, used when addr or key are not passed")
+	addr := fs.String("addr", "", "address of proxy, default is listen address from config")
+	key := fs.String("key", "", "base64 public key of proxy, default is key of the first client from config")
+	concurrency := fs.Int("concurrency", 8, "number of parallel workers")
+	limit := fs.Int("limit", 0, "max number of queries to replay, 0 is all")
+	verbose := fs.Bool("v", false, "print every mismatched query")
+	_ = fs.Parse(args)
+
+	if *file == "" {
+		return fmt.Errorf("capture file is required")
+	}
+	if *concurrency <= 0 {
+		return fmt.Errorf("concurrency should be positive")
+	}
+	if err := proxyEndpoint(*configPath, addr, key); err != nil {
+		return err
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return fmt.Errorf("failed to open capture file: %w", err)
+	}
+	defer f.Close()
+
+	client := liteclient.NewConnectionPool()
+	defer client.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	err = client.AddConnection(ctx, *addr, *key)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", *addr, err)
+	}
+
+	stats := &replayStats{
+		sent:       map[string]int{},
+		mismatches: map[string]int{},
+		errors:     map[string]int{},
+	}
+
+	queue := make(chan *server.CapturedQuery, *concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < *concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for rec := range queue {
+				stats.replay(client, rec, *verbose)
+			}
+		}()
+	}
+
+	start := time.Now()
+	total := 0
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64<<10), 64<<20)
+	for sc.Scan() && (*limit == 0 || total < *limit) {
+		var rec server.CapturedQuery
+		if err = json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			close(queue)
+			wg.Wait()
+			return fmt.Errorf("invalid capture record %d: %w", total+1, err)
+		}
+		queue <- &rec
+		total++
+	}
+	close(queue)
+	wg.Wait()
+
+	if err = sc.Err(); err != nil {
+		return fmt.Errorf("failed to read capture file: %w", err)
+	}
+
+	fmt.Printf("replayed %d queries to %s in %s\n", total, *addr, time.Since(start).Round(time.Millisecond))
+
+	types := make([]string, 0, len(stats.sent))
+	for typ := range stats.sent {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	for _, typ := range types {
+		fmt.Printf("%s: %d sent, %d mismatched, %d failed\n", typ, stats.sent[typ], stats.mismatches[typ], stats.errors[typ])
+	}
+	return nil
+}
+
+func (st *replayStats) replay(client *liteclient.ConnectionPool, rec *server.CapturedQuery, verbose bool) {
+	query, err := server.ParseLiteQuery(rec.Query)
+	if err != nil {
+		st.count("invalid", st.sent)
+		st.count("invalid", st.errors)
+		return
+	}
+	typ := reflect.TypeOf(query).String()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	var resp tl.Serializable
+	err = client.QueryLiteserver(ctx, query, &resp)
+	cancel()
+
+	st.count(typ, st.sent)
+	if err != nil {
+		st.count(typ, st.errors)
+		if verbose {
+			fmt.Printf("%s failed: %s\n", typ, err.Error())
+		}
+		return
+	}
+
+	data, err := tl.Serialize(resp, true)
+	if err != nil || !bytes.Equal(data, rec.Response) {
+		st.count(typ, st.mismatches)
+		if verbose {
+			fmt.Printf("%s mismatch, key %s, captured at %s\n", typ, rec.Key, time.Unix(rec.Time, 0).Format(time.RFC3339))
+		}
+	}
+}
+
+func (st *replayStats) count(typ string, m map[string]int) {
+	st.mx.Lock()
+	m[typ]++
+	st.mx.Unlock()
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"github.com/xssnick/tonutils-liteserver-proxy/proxy"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	verbosity := fs.Int("verbosity", 2, "3 = debug, 2 = info, 1 = warn, 0 = error")
+	configPath := fs.String("config", defaultConfigPath, "path to config, json or yaml by extension, example is created when it is not exists")
+	_ = fs.Parse(args)
+
+	setupLogger(*verbosity)
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load config")
+		return nil
+	}
+
+	p := proxy.NewProxy(cfg)
+	if err = p.Start(); err != nil {
+		log.Fatal().Err(err).Msg("failed to start proxy")
+		return nil
+	}
+
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGHUP)
+		for range sig {
+			newCfg, err := config.LoadConfig(*configPath)
+			if err != nil {
+				log.Error().Err(err).Msg("failed to reload config")
+				continue
+			}
+
+			if err = p.SetMethodOverrides(newCfg.MethodOverrides); err != nil {
+				log.Error().Err(err).Msg("failed to reload method overrides")
+			}
+
+			if err = p.ReloadBackends(newCfg.Backends); err != nil {
+				log.Error().Err(err).Msg("failed to reload backends")
+				continue
+			}
+			log.Info().Msg("backends reloaded")
+		}
+	}()
+
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGUSR2)
+		for range sig {
+			log.Info().Msg("upgrading, starting new process")
+			if err := p.Upgrade(); err != nil {
+				log.Error().Err(err).Msg("failed to upgrade")
+				continue
+			}
+			log.Info().Msg("upgrade completed, new process accepts clients")
+		}
+	}()
+
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+		<-sig
+
+		log.Info().Msg("stopping")
+		p.Stop()
+	}()
+
+	if err = p.Wait(); err != nil {
+		log.Fatal().Err(err).Msg("proxy failed")
+	}
+	return nil
+}
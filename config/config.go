@@ -6,14 +6,39 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"gopkg.in/yaml.v3"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 type BackendLiteserver struct {
+	// Name - identifies backend in metrics, logs and on reload, should be unique, address is used when empty
 	Name string
 	Addr string
 	Key  []byte
+	// Weight - share of traffic for weighted balancer, 0 is treated as 1
+	Weight uint64
+	// Tags - kinds of requests backend serves: fast, archive, full-state; untagged backend is fast
+	Tags []string
+	// Connections - number of parallel connections to backend, requests are distributed by round-robin, 0 is treated as 1
+	Connections uint32
+	// Tier - preference group, like 0 for local dc, 1 for the same region and 2 for remote ones,
+	// queries go to the lowest tier which has healthy and not saturated backends
+	Tier uint32
+	// MaxInFlight - backend is saturated with this number of queries in flight, 0 is unlimited
+	MaxInFlight uint32
+	// Proxy - backend is another instance of proxy, client wait for master block is forwarded to it
+	// together with query, because its cache can be behind ours
+	Proxy bool
+}
+
+// NameOrAddr returns name of backend, or its address when name is not set
+func (b BackendLiteserver) NameOrAddr() string {
+	if b.Name != "" {
+		return b.Name
+	}
+	return b.Addr
 }
 
 type ClientConfig struct {
@@ -22,6 +47,24 @@ type ClientConfig struct {
 	CapacityPerIP  int64
 	CapacityPerKey int64
 	CoolingPerSec  float64
+	// Priority - class of key when queries wait for processing under load: platinum, standard or free,
+	// higher classes get free slots first and free tier is shed first, empty is standard
+	Priority string
+	// MaxInFlight - queries of key processed at the same time from all connections, exceeding ones are rejected, 0 disables
+	MaxInFlight int64
+	// Mode - overrides global mode for key: "proxy" disables emulation and caches like DisableEmulationAndCache,
+	// "raw" sends all queries to backend as is, so answers are byte-identical to liteserver ones, empty uses global mode
+	Mode string
+	// GasCapacity - gas of emulated get methods key can spend at once, refilled by GasPerSec,
+	// separate from requests limits, 0 disables
+	GasCapacity int64
+	GasPerSec   float64
+	// BandwidthQuotaMB - megabytes of responses key can receive during BandwidthPeriodSeconds (a day when 0),
+	// queries are rejected until the next period when it is used up, 0 disables
+	BandwidthQuotaMB       uint64
+	BandwidthPeriodSeconds uint32
+	// MaxWaitMasterMs - overrides global MaxWaitMasterMs for key, 0 uses global one
+	MaxWaitMasterMs uint32
 }
 
 type CacheConfig struct {
@@ -30,6 +73,387 @@ type CacheConfig struct {
 	MaxCachedLibraries             uint32
 	MaxMasterBlockSeqnoDiffToCache uint32
 	MaxShardBlockSeqnoDiffToCache  uint32
+	// Store - second level cache of blocks, account states, libraries and transactions:
+	// none, memory, redis, disk or layered (memory + disk)
+	Store  string
+	Memory MemoryCacheConfig
+	Redis  RedisCacheConfig
+	Disk   DiskCacheConfig
+	// StoreLimits - per object class settings of the second level store only, zero values fall back to store defaults,
+	// in-process caches are limited by MaxCachedAccountsPerBlock, MaxCachedLibraries, seqno diffs and MemoryBudgetMB
+	StoreLimits CacheStoreLimitsConfig
+	// NegativeTTLMs - how long to remember backend errors with NegativeErrorCodes, 0 disables negative caching
+	NegativeTTLMs      uint32
+	NegativeMaxEntries uint32
+	NegativeErrorCodes []int32
+	Warmup             CacheWarmupConfig
+	// DisableProofVerification - cache backend responses without checking their merkle proofs
+	DisableProofVerification bool
+	// DisableSignatureVerification - accept new master blocks as the latest without checking validator signatures
+	DisableSignatureVerification bool
+	// TrustedBlock - master block to start signature verification from, usually init block of global config,
+	// when not set the first fetched master block is trusted
+	TrustedBlock TrustedBlockConfig
+	// TTLJitterPercent - ttl of stored objects is randomly shifted by up to this percent,
+	// so objects cached together are not expiring in the same second
+	TTLJitterPercent uint32
+	// StaleSeconds - expired objects of memory and disk stores which are requested during this window
+	// are still served and kept for one more ttl, so hot objects are not refetched from backends
+	StaleSeconds uint32
+	// TrackShardStates - carry cached account states of basechain shards to new shard blocks,
+	// proofs of accounts without transactions are rebuilt from state updates of blocks instead of fetching each account
+	TrackShardStates bool
+	// MemoryBudgetMB - resident memory limit of process, when exceeded objects of in-process caches
+	// (blocks, accounts, libraries, general and negative answers) and of memory store are evicted
+	// starting from the biggest classes, 0 disables budget and only per class limits are used
+	MemoryBudgetMB uint64
+	// StaleIfErrorSeconds - when all backends are down, master info is answered from the last cached block,
+	// account states and get methods only for requested blocks kept in memory, not older than this window, 0 disables
+	StaleIfErrorSeconds uint32
+	// BlockIndexPath - file of persistent index of ingested blocks, lookups by seqno and lt of blocks
+	// seen before restart are answered from it, disabled when empty
+	BlockIndexPath string
+	// BlockIndexMaxBlocks - number of the latest blocks of all shards kept in block index, older ones are dropped
+	// and file is compacted when they take more than half of it, 0 keeps all blocks
+	BlockIndexMaxBlocks uint32
+	// TxIndexMaxTransactions - number of recent transactions of ingested blocks kept in memory by account,
+	// getTransactions is answered from them when the whole requested chain is indexed, 0 disables index
+	TxIndexMaxTransactions uint32
+	// S3 - object storage tier for history below Store, so only hot data is kept in memory and on disk
+	S3 S3CacheConfig
+	// Watchlist - accounts which states are fetched at every new master block, can be managed with admin api
+	Watchlist WatchlistConfig
+	// PubSub - redis channel which proxy instances announce new master blocks and cache purges through,
+	// so all of them follow the same head, with redis Store each block is fetched from backends once
+	PubSub PubSubConfig
+	// PeerCache - sibling proxies which are asked for objects missing in Store before backends,
+	// they answer only from their own store, so peers should be trusted the same as Store
+	PeerCache PeerCacheConfig
+}
+
+type PeerCacheConfig struct {
+	Peers []CachePeerConfig
+	// TimeoutMs - how long to wait for answers of peers, after that object is fetched from backends
+	TimeoutMs uint32
+	// Classes - cache classes asked from peers, blocks, account states and transactions when empty
+	Classes []string
+}
+
+type CachePeerConfig struct {
+	// Addr - liteserver address of sibling proxy
+	Addr string
+	// Key - public key of one of its clients
+	Key []byte
+}
+
+type PubSubConfig struct {
+	// Addr - redis to publish through, pub/sub is disabled when empty
+	Addr     string
+	Username string
+	Password string
+	DB       int
+	Channel  string
+	// LeaderElection - only instance holding redis lock fetches new master blocks from backends, others wait
+	// for its announces and read blocks from shared redis Store, when leader is silent they fetch blocks themselves
+	LeaderElection bool
+	LeaderLockKey  string
+	// LeaderTTLSeconds - lock expiration, followers wait for announce of leader the same time
+	LeaderTTLSeconds uint32
+}
+
+type WatchlistConfig struct {
+	// Accounts - user-friendly addresses of accounts to keep fresh
+	Accounts []string
+	// MaxAccounts - limit of watched accounts including added with admin api, 1000 when 0
+	MaxAccounts uint32
+	// Concurrency - states fetched in parallel for each block, 16 when 0
+	Concurrency uint32
+	// Methods - names of get methods without arguments to run for every new state of watched accounts,
+	// runSmcMethod of them is answered with precomputed results
+	Methods []string
+}
+
+type TrustedBlockConfig struct {
+	Workchain int32
+	Shard     int64
+	SeqNo     uint32
+	RootHash  []byte
+	FileHash  []byte
+}
+
+type CacheWarmupConfig struct {
+	// MasterBlocks - number of recent master blocks to prefetch, limited by MaxMasterBlockSeqnoDiffToCache
+	MasterBlocks uint32
+	// Accounts - user-friendly addresses of accounts to prefetch states of
+	Accounts       []string
+	TimeoutSeconds uint32
+}
+
+type CacheStoreClassConfig struct {
+	TTLSeconds uint32
+	// MaxEntries and MaxBytes are applied by memory store
+	MaxEntries uint32
+	MaxBytes   uint64
+}
+
+type CacheStoreLimitsConfig struct {
+	MasterBlocks  CacheStoreClassConfig
+	ShardBlocks   CacheStoreClassConfig
+	AccountStates CacheStoreClassConfig
+	Libraries     CacheStoreClassConfig
+	Transactions  CacheStoreClassConfig
+}
+
+type MemoryCacheConfig struct {
+	MaxEntries uint32
+	TTLSeconds uint32
+}
+
+type DiskCacheConfig struct {
+	Path       string
+	TTLSeconds uint32
+	// MaxSizeMB - least recently used objects are removed during compaction when exceeded, 0 is unlimited
+	MaxSizeMB                 uint64
+	CompactionIntervalSeconds uint32
+}
+
+type S3CacheConfig struct {
+	// Endpoint - url of s3 compatible storage, like https://s3.eu-central-1.amazonaws.com
+	Endpoint string
+	Region   string
+	// Bucket - objects of Classes are uploaded there and read when missing in faster store, s3 tier is disabled when empty
+	Bucket    string
+	KeyPrefix string
+	AccessKey string
+	SecretKey string
+	// Classes - cache classes kept in s3, blocks, account states and transactions when empty
+	Classes []string
+	// UploadWorkers - number of parallel uploads, 0 is 4
+	UploadWorkers uint32
+}
+
+type RedisCacheConfig struct {
+	Addr     string
+	Username string
+	Password string
+	DB       int
+	// KeyPrefix - prefix of cache keys, required, purge deletes all keys with it
+	KeyPrefix  string
+	TTLSeconds uint32
+}
+
+type BackendHealthCheckConfig struct {
+	// IntervalSeconds - how often to probe backends, 0 disables active checks
+	IntervalSeconds uint32
+	TimeoutSeconds  uint32
+	// MaxSeqnoLag - backend is evicted when its last master seqno is behind the best backend more than this
+	MaxSeqnoLag     uint32
+	FailuresToEvict uint32
+}
+
+type RetryConfig struct {
+	// BudgetRatio - allowed retries per request, for example 0.1 allows retrying 10% of requests, 0 disables retries
+	BudgetRatio      float64
+	MinRetriesPerSec float64
+	AttemptTimeoutMs uint32
+}
+
+type RoutingConfig struct {
+	// MethodTags - overrides of backend tag for request types, for example "ton.GetTransactions": "archive"
+	MethodTags map[string]string
+	// requests for master blocks older than top seqno minus this diff are sent to archive backends
+	ArchiveMasterSeqnoDiff uint32
+	// lookups by time older than this are sent to archive backends
+	ArchiveAfterSeconds uint32
+	// AccountHashing - uncached queries of account states, get methods and transactions go to backend chosen
+	// by consistent hash of address, so internal caches of backends see stable subsets of accounts
+	AccountHashing bool
+}
+
+type HandshakeLimitConfig struct {
+	// PerIPPerSec - allowed rate of new connections from one ip, checked before handshake, 0 disables limit
+	PerIPPerSec float64
+	Burst       int64
+}
+
+type QoSConfig struct {
+	// MaxConcurrentQueries - queries processed at once by all clients, others wait by priority of client key, 0 disables
+	MaxConcurrentQueries uint32
+	// MaxQueued - waiting queries, when exceeded queries of the lowest priority are rejected first
+	MaxQueued uint32
+	MaxWaitMs uint32
+}
+
+type LimitExemptionsConfig struct {
+	// Networks - ips or cidrs of clients which are not limited, like monitoring probes or internal indexers
+	Networks []string
+	// Keys - names of client keys which are not limited
+	Keys []string
+}
+
+type ErrorReportingConfig struct {
+	// SentryDSN - dsn of sentry project to report panics, emulation failures and repeated backend errors to
+	SentryDSN string
+	// WebhookURL - url to POST the same reports as json, can be used together with sentry
+	WebhookURL  string
+	Environment string
+	// MinIntervalSeconds - the same error is reported not more often than this, 60 when 0
+	MinIntervalSeconds uint32
+	// BackendFailuresToReport - failed queries of backend in a row to report it, 10 when 0
+	BackendFailuresToReport uint32
+}
+
+type CaptureConfig struct {
+	// Path - file to append captured queries to as json lines, capture is disabled when empty
+	Path string
+	// SamplePercent - percent of client queries to capture
+	SamplePercent float64
+	// MaxSizeMB - capture is stopped when file reaches this size, 0 is unlimited
+	MaxSizeMB uint64
+}
+
+type ShadowConfig struct {
+	// Backends - pool which receives copies of read queries, for example nodes of new version, disabled when empty
+	Backends []BackendLiteserver
+	// Percent - percent of read queries to mirror
+	Percent float64
+	// MaxInFlight - mirrored queries over this number are dropped, 0 is 64
+	MaxInFlight uint32
+	// TimeoutSeconds - timeout of mirrored query, 0 is 10 seconds
+	TimeoutSeconds uint32
+}
+
+type CanaryConfig struct {
+	// Percent - percent of locally answered get methods and account states to check against backend, 0 disables checks
+	Percent float64
+	// MaxInFlight - checks over this number are dropped, 0 is 16
+	MaxInFlight uint32
+}
+
+type ChaosRule struct {
+	// Keys - names of client keys rule applies to, all keys when empty
+	Keys []string
+	// Methods - query types like ton.RunSmcMethod rule applies to, all types when empty
+	Methods []string
+	// Percent - percent of matching queries to inject fault into
+	Percent float64
+	// LatencyMs - delay before answer, random delay up to LatencyJitterMs is added
+	LatencyMs       uint32
+	LatencyJitterMs uint32
+	// Fault - 429 or 502 to answer with error, truncate to cut answer in half, only latency when empty
+	Fault string
+}
+
+type SelfTestConfig struct {
+	// IntervalSeconds - how often proxy connects to itself through public endpoint as a client, 0 disables self test
+	IntervalSeconds uint32
+	// Addr - endpoint to connect to, PublicAddr or ListenAddr when empty
+	Addr string
+	// Key - name of client key to connect with, the first one when empty
+	Key            string
+	TimeoutSeconds uint32
+}
+
+type AnalyticsConfig struct {
+	// IntervalSeconds - period of aggregation, statistics are exported at its end, 60 when 0
+	IntervalSeconds uint32
+	// TopAccounts - number of the most requested accounts exported for each period
+	TopAccounts uint32
+	// ClickHouseURL - http interface of clickhouse, like http://localhost:8123/?database=default, rows are inserted as JSONEachRow
+	ClickHouseURL      string
+	ClickHouseTable    string
+	ClickHouseUser     string
+	ClickHousePassword string
+	// Dir - directory to write json lines file of each period to, for loading into clickhouse, duckdb or conversion to parquet
+	Dir string
+}
+
+type BillingConfig struct {
+	// Type - webhook, file, kafka or nats, usage of each key is sent there every interval, disabled when empty
+	Type string
+	// IntervalSeconds - period of usage aggregation, 60 when 0
+	IntervalSeconds uint32
+	WebhookURL      string
+	// WebhookToken - sent as bearer authorization to webhook
+	WebhookToken string
+	// Path - file to append events to as json lines
+	Path  string
+	Kafka KafkaStreamConfig
+	NATS  NATSStreamConfig
+}
+
+type LimiterStateConfig struct {
+	// Path - file to keep usage of per key limits, gas budgets and bandwidth quotas in, disabled when empty,
+	// it is locked with Path.lock, so processes sharing it during handoff merge their usage instead of overwriting
+	Path string
+	// SaveIntervalSeconds - how often state is saved, it is saved on shutdown too, 10 when 0
+	SaveIntervalSeconds uint32
+}
+
+type ZeroStateConfig struct {
+	RootHash []byte
+	FileHash []byte
+}
+
+type QuorumConfig struct {
+	// Size - number of backends critical queries are sent to, answers are cross-checked, 0 or 1 disables quorum
+	Size uint32
+	// Methods - request types to cross-check, master info, block lookups and account states when empty
+	Methods         []string
+	EvictOnMismatch bool
+	// DistrustSeconds - how long evicted on mismatch backend stays out of rotation, it is checked again after that, 0 is 300
+	DistrustSeconds uint32
+}
+
+type EventStreamConfig struct {
+	// Type - kafka or nats, every new block is published there, disabled when empty
+	Type string
+	// IncludeBlockData - add block boc to events
+	IncludeBlockData bool
+	Kafka            KafkaStreamConfig
+	NATS             NATSStreamConfig
+}
+
+type KafkaStreamConfig struct {
+	Brokers []string
+	Topic   string
+}
+
+type NATSStreamConfig struct {
+	URL     string
+	Subject string
+}
+
+type DHTConfig struct {
+	// Enabled - announce PublicAddr in ton dht for each client key, so clients can find proxy by adnl id of key
+	Enabled bool
+	// GlobalConfigURL - ton global config with dht nodes
+	GlobalConfigURL string
+	// TTLSeconds - how long announced address is valid, it is re-announced every IntervalSeconds
+	TTLSeconds      uint32
+	IntervalSeconds uint32
+}
+
+type ListenerConfig struct {
+	Addr string
+	// Clients - names of client keys accepted on this listener, all keys when empty
+	Clients []string
+}
+
+type HandoffConfig struct {
+	// Enabled - liteserver sockets are owned by proxy and passed to new process on upgrade (SIGUSR2)
+	Enabled bool
+	// DrainSeconds - how long old process serves already connected clients after upgrade, 0 disconnects them at once
+	DrainSeconds uint32
+	// ReadyTimeoutSeconds - upgrade is aborted when new process is not started during this time
+	ReadyTimeoutSeconds uint32
+}
+
+type OTLPConfig struct {
+	// Endpoint - collector url to push metrics, for example http://127.0.0.1:4318/v1/metrics, disabled when empty
+	Endpoint        string
+	Headers         map[string]string
+	IntervalSeconds uint32
 }
 
 type Config struct {
@@ -43,7 +467,145 @@ type Config struct {
 	MaxConnectionsPerIP      uint32
 	MaxKeepAliveSeconds      uint32
 	ResponseGeneralCacheSize uint32
-	BalancerType             string
+	BalancerType             string // fail_over, round_robin, latency or weighted
+	// ReadinessMaxMasterLagSeconds - /readyz fails when last cached master block is older, 0 to not check
+	ReadinessMaxMasterLagSeconds uint32
+	ExposeRequestIDInErrors      bool
+	// AdminAddr - listen address of operator API, disabled when empty
+	AdminAddr  string
+	AdminToken string
+	// MetricsTypeLabelsAllowlist - message types allowed as metrics labels, others are reported as 'other',
+	// built-in list of known types is used when empty, "*" allows all
+	MetricsTypeLabelsAllowlist []string
+	OTLPMetrics                OTLPConfig
+	BackendHealthCheck         BackendHealthCheckConfig
+	// HedgeDelayMs - delay after which read query is duplicated to another backend, 0 to disable
+	HedgeDelayMs uint32
+	Retry        RetryConfig
+	Routing      RoutingConfig
+	// GlobalConfigURL - when set, liteservers from this ton global config are added as backends
+	GlobalConfigURL            string
+	GlobalConfigRefreshSeconds uint32
+	GlobalConfigBackendTags    []string
+	Quorum                     QuorumConfig
+	// Network - mainnet, testnet or custom with ZeroState, backends are checked to be from this network at start,
+	// when empty, backends are only checked to agree with each other
+	Network   string
+	ZeroState ZeroStateConfig
+	// MaxQuerySizeBytes - larger client queries are rejected, 0 is unlimited
+	MaxQuerySizeBytes uint32
+	HandshakeLimit    HandshakeLimitConfig
+	// MaxResponseSizeBytes - larger responses are replaced with error, 0 is unlimited
+	MaxResponseSizeBytes uint32
+	// GRPCAddr - listen address of gRPC api with liteserver methods, disabled when empty
+	GRPCAddr string
+	// GRPCToken - bearer token required from gRPC clients, it is required unless GRPCInsecure is set
+	GRPCToken string
+	// GRPCInsecure - allows to serve gRPC api without token, only for networks where all clients are trusted
+	GRPCInsecure bool
+	// GRPCClient - name of client from Clients, its rate limits, bandwidth quota and in-flight limit apply
+	// to gRPC queries, which are accounted as its queries, required when GRPCAddr is set
+	GRPCClient string
+	// SubscriptionsAddr - listen address of websocket subscriptions api, disabled when empty, requires cache
+	SubscriptionsAddr  string
+	SubscriptionsToken string
+	// WebSocketListenAddr - listen address for liteserver protocol tunneled over websocket, disabled when empty
+	WebSocketListenAddr string
+	// WebSocketTrustForwardedFor - take client ip for limits from X-Forwarded-For, enable only behind load balancer
+	WebSocketTrustForwardedFor bool
+	EventStream                EventStreamConfig
+	// HelperAddr - listen address of http api with jetton and nft data, disabled when empty
+	HelperAddr  string
+	HelperToken string
+	// PublicAddr - ipv4:port which clients connect to, used in generated client configs, ListenAddr is used when empty
+	PublicAddr string
+	DHT        DHTConfig
+	// Listeners - additional liteserver listen addresses, for example internal network or localhost,
+	// each can accept only part of client keys
+	Listeners []ListenerConfig
+	// IPv6LimitPrefix - prefix length by which ipv6 clients are aggregated for per ip limits,
+	// 64 when 0, 128 limits each address separately
+	IPv6LimitPrefix uint32
+	Handoff         HandoffConfig
+	// RawPassthrough - queries which are never answered locally (all of them with DisableEmulationAndCache)
+	// are sent to backend without general and negative caches, which saves hashing of queries on pure proxy deployments,
+	// queries are decoded only to be validated and routed, bytes of client are forwarded to backend unchanged
+	RawPassthrough bool
+	// MethodOverrides - handling of query types by names like ton.RunSmcMethod, "raw" always sends query to backend
+	// as is, "local" always uses emulation and caches even in raw passthrough mode, reloaded on SIGHUP
+	MethodOverrides map[string]string
+	// CoalesceBackendQueries - identical read queries in flight at the same time are sent to backend once
+	CoalesceBackendQueries bool
+	// ConnectionWorkers - max queries of one client connection processed at the same time, 64 when 0,
+	// others wait in queue of ConnectionQueueSize (1024 when 0), queries are rejected when it is full
+	ConnectionWorkers   uint32
+	ConnectionQueueSize uint32
+	// ReusePortListeners - number of sockets opened with SO_REUSEPORT for each adnl listener, each one has its own
+	// accept loop, 0 or 1 listens single socket
+	ReusePortListeners uint32
+	QoS                QoSConfig
+	// FairBackendSlots - backend queries in flight of all client keys, when all slots are busy
+	// keys get free ones in turn, so one key cannot starve others, 0 disables
+	FairBackendSlots uint32
+	// LimitExemptions - clients exempt from all rate and concurrency limits, their queries are counted in separate metric
+	LimitExemptions LimitExemptionsConfig
+	// StickyConsistency - master info answered to client connection never goes back to older block than it has got,
+	// answers of lagging backends are requeried waiting for that block, so client state does not go backwards
+	StickyConsistency bool
+	// ErrorReporting - reporting of errors to sentry or webhook, disabled when both are empty
+	ErrorReporting ErrorReportingConfig
+	// Capture - recording of sampled client queries and responses, to replay them with replay command
+	Capture CaptureConfig
+	// Shadow - mirroring of read queries to second backend pool, answers are compared in background and reported in metrics
+	Shadow ShadowConfig
+	// Canary - background checks of emulated and cached answers against backends, divergences are reported in metrics
+	Canary CanaryConfig
+	// Chaos - fault injection for testing of clients, never use it in production, the first matching rule is applied
+	Chaos []ChaosRule
+	// SelfTest - periodic queries to proxy through its public endpoint, results are exported in metrics
+	SelfTest SelfTestConfig
+	// Analytics - periodic export of aggregated query statistics to clickhouse and files
+	Analytics AnalyticsConfig
+	// Billing - periodic export of usage of each client key, for integration with billing systems
+	Billing BillingConfig
+	// LimiterState - persistence of rate limits and quotas of keys, so they are not reset by restart
+	LimiterState LimiterStateConfig
+	// SystemGettersCacheSize - number of get method results of elector and config contracts kept for recent
+	// master blocks, they are polled by validator tooling constantly, 0 disables
+	SystemGettersCacheSize uint32
+	// Upstream - proxy which pushes new blocks to this one, so edge proxies don't each poll origin backends
+	Upstream UpstreamConfig
+	// BackendDNSRefreshSeconds - how often hostnames of backends are resolved again, backend is reconnected
+	// when its address is changed, unhealthy backends are resolved every 5 seconds, 0 disables
+	BackendDNSRefreshSeconds uint32
+	// SharedLimits - per key and per ip rate limits kept in redis, so they are shared by all instances of cluster
+	SharedLimits SharedLimitsConfig
+	// MaxWaitMasterMs - the longest wait for master block client can ask in query, longer timeouts are lowered to it,
+	// so clients cannot hold connection workers with long waits, 0 is unlimited.
+	// Timeout in waitMasterchainSeqno of client is milliseconds, as liteserver reads it, versions before
+	// chained proxy support read it as seconds, clients which relied on that wait 1000 times shorter now
+	MaxWaitMasterMs uint32
+}
+
+type UpstreamConfig struct {
+	// URL - downstream endpoint of subscriptions api of upstream proxy, like ws://origin:8082/downstream,
+	// disabled when empty
+	URL   string
+	Token string
+	// TimeoutSeconds - how long to wait for pushed master block, after that backends are polled until upstream is back
+	TimeoutSeconds uint32
+}
+
+type SharedLimitsConfig struct {
+	// Addr - redis to keep limits in, 3.2 or newer, limits are local to instance when empty
+	Addr     string
+	Username string
+	Password string
+	DB       int
+	// KeyPrefix - prefix of redis keys of limits, instances sharing limits should have the same one
+	KeyPrefix string
+	// TimeoutMs - how long to wait for redis, local limits are applied when it is slower or unavailable, 50 when 0
+	TimeoutMs uint32
 }
 
 func LoadConfig(path string) (*Config, error) {
@@ -81,6 +643,50 @@ func LoadConfig(path string) (*Config, error) {
 				MaxCachedLibraries:             8192,
 				MaxMasterBlockSeqnoDiffToCache: 60,
 				MaxShardBlockSeqnoDiffToCache:  60,
+				Store:                          "memory",
+				Memory: MemoryCacheConfig{
+					MaxEntries: 100000,
+					TTLSeconds: 3600,
+				},
+				Redis: RedisCacheConfig{
+					KeyPrefix:  "ls-proxy:",
+					TTLSeconds: 86400,
+				},
+				Disk: DiskCacheConfig{
+					Path:                      "./cache",
+					TTLSeconds:                7 * 86400,
+					MaxSizeMB:                 10240,
+					CompactionIntervalSeconds: 300,
+				},
+				StoreLimits: CacheStoreLimitsConfig{
+					MasterBlocks:  CacheStoreClassConfig{MaxEntries: 2000, MaxBytes: 512 << 20},
+					ShardBlocks:   CacheStoreClassConfig{MaxEntries: 10000, MaxBytes: 1 << 30},
+					AccountStates: CacheStoreClassConfig{TTLSeconds: 600, MaxEntries: 100000, MaxBytes: 512 << 20},
+					Libraries:     CacheStoreClassConfig{MaxEntries: 8192, MaxBytes: 256 << 20},
+					Transactions:  CacheStoreClassConfig{MaxEntries: 100000, MaxBytes: 256 << 20},
+				},
+				NegativeTTLMs:      1500,
+				NegativeMaxEntries: 10000,
+				NegativeErrorCodes: []int32{651, 404},
+				Warmup: CacheWarmupConfig{
+					MasterBlocks:   10,
+					TimeoutSeconds: 60,
+				},
+				TTLJitterPercent:    10,
+				StaleSeconds:        60,
+				BlockIndexMaxBlocks: 1000000,
+				Watchlist: WatchlistConfig{
+					MaxAccounts: 1000,
+					Concurrency: 16,
+				},
+				PubSub: PubSubConfig{
+					Channel:          "ls-proxy:events",
+					LeaderLockKey:    "ls-proxy:leader",
+					LeaderTTLSeconds: 10,
+				},
+				PeerCache: PeerCacheConfig{
+					TimeoutMs: 300,
+				},
 			},
 			Clients: []ClientConfig{
 				{
@@ -89,17 +695,112 @@ func LoadConfig(path string) (*Config, error) {
 					CapacityPerIP:  100,
 					CapacityPerKey: 0,
 					CoolingPerSec:  20,
+					Priority:       "standard",
 				},
 			},
 			Backends: []BackendLiteserver{
 				{
-					Addr: "5.9.10.47:19949",
-					Key:  exampleKey,
+					Addr:        "5.9.10.47:19949",
+					Key:         exampleKey,
+					Weight:      1,
+					Connections: 1,
 				},
 			},
-			MaxConnectionsPerIP:      20,
-			MaxKeepAliveSeconds:      60,
-			ResponseGeneralCacheSize: 2048,
+			MaxConnectionsPerIP:          20,
+			MaxKeepAliveSeconds:          60,
+			ResponseGeneralCacheSize:     2048,
+			ReadinessMaxMasterLagSeconds: 60,
+			OTLPMetrics: OTLPConfig{
+				IntervalSeconds: 15,
+			},
+			BackendHealthCheck: BackendHealthCheckConfig{
+				IntervalSeconds: 5,
+				TimeoutSeconds:  3,
+				MaxSeqnoLag:     3,
+				FailuresToEvict: 3,
+			},
+			Retry: RetryConfig{
+				BudgetRatio:      0.1,
+				MinRetriesPerSec: 5,
+				AttemptTimeoutMs: 3000,
+			},
+			Routing: RoutingConfig{
+				ArchiveMasterSeqnoDiff: 17280,
+				ArchiveAfterSeconds:    86400,
+			},
+			Network:           "mainnet",
+			MaxQuerySizeBytes: 1 << 20,
+			HandshakeLimit: HandshakeLimitConfig{
+				PerIPPerSec: 5,
+				Burst:       20,
+			},
+			MaxResponseSizeBytes: 8 << 20,
+			EventStream: EventStreamConfig{
+				Kafka: KafkaStreamConfig{
+					Brokers: []string{"127.0.0.1:9092"},
+					Topic:   "ton-blocks",
+				},
+				NATS: NATSStreamConfig{
+					URL:     "nats://127.0.0.1:4222",
+					Subject: "ton.blocks",
+				},
+			},
+			DHT: DHTConfig{
+				GlobalConfigURL: "https://ton.org/global.config.json",
+				TTLSeconds:      3600,
+				IntervalSeconds: 600,
+			},
+			IPv6LimitPrefix: 64,
+			Handoff: HandoffConfig{
+				DrainSeconds:        300,
+				ReadyTimeoutSeconds: 120,
+			},
+			ConnectionWorkers:   64,
+			ConnectionQueueSize: 1024,
+			QoS: QoSConfig{
+				MaxQueued: 10000,
+				MaxWaitMs: 5000,
+			},
+			ErrorReporting: ErrorReportingConfig{
+				MinIntervalSeconds:      60,
+				BackendFailuresToReport: 10,
+			},
+			Capture: CaptureConfig{
+				SamplePercent: 1,
+				MaxSizeMB:     1024,
+			},
+			Shadow: ShadowConfig{
+				Percent:        10,
+				MaxInFlight:    64,
+				TimeoutSeconds: 10,
+			},
+			Canary: CanaryConfig{
+				MaxInFlight: 16,
+			},
+			SelfTest: SelfTestConfig{
+				TimeoutSeconds: 10,
+			},
+			Analytics: AnalyticsConfig{
+				IntervalSeconds: 60,
+				TopAccounts:     100,
+				ClickHouseTable: "proxy_queries",
+			},
+			Billing: BillingConfig{
+				IntervalSeconds: 60,
+			},
+			LimiterState: LimiterStateConfig{
+				SaveIntervalSeconds: 10,
+			},
+			SystemGettersCacheSize: 1024,
+			Upstream: UpstreamConfig{
+				TimeoutSeconds: 15,
+			},
+			BackendDNSRefreshSeconds: 60,
+			SharedLimits: SharedLimitsConfig{
+				KeyPrefix: "ls-proxy:limits:",
+				TimeoutMs: 50,
+			},
+			MaxWaitMasterMs: 10000,
 		}
 
 		err = SaveConfig(cfg, path)
@@ -107,6 +808,10 @@ func LoadConfig(path string) (*Config, error) {
 			return nil, fmt.Errorf("failed to save config: %w", err)
 		}
 
+		// overrides are applied after save, to not write secrets to file
+		if err = ApplyEnv(cfg); err != nil {
+			return nil, err
+		}
 		return cfg, nil
 	} else if err == nil {
 		data, err := os.ReadFile(path)
@@ -114,27 +819,116 @@ func LoadConfig(path string) (*Config, error) {
 			return nil, fmt.Errorf("failed to read config: %w", err)
 		}
 
+		if isYAML(path) {
+			if data, err = yamlToJSON(data); err != nil {
+				return nil, fmt.Errorf("failed to parse yaml config: %w", err)
+			}
+		}
+
 		var cfg Config
 		err = json.Unmarshal(data, &cfg)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse config: %w", err)
 		}
 
+		if err = ApplyEnv(&cfg); err != nil {
+			return nil, err
+		}
 		return &cfg, nil
 	}
 
 	return nil, err
 }
 
+// SaveConfig writes config as json, or as yaml when path has .yaml or .yml extension
 func SaveConfig(cfg *Config, path string) error {
 	data, err := json.MarshalIndent(cfg, "", "\t")
 	if err != nil {
 		return err
 	}
 
+	if isYAML(path) {
+		if data, err = jsonToYAML(data); err != nil {
+			return err
+		}
+	}
+
 	err = os.WriteFile(path, data, 0766)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+func isYAML(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// yamlToJSON converts yaml document to json, so yaml config has the same field names
+// and formats as json one, bytes are base64 strings
+func yamlToJSON(data []byte) ([]byte, error) {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	doc, err := jsonCompatible(doc)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+func jsonCompatible(v any) (any, error) {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			conv, err := jsonCompatible(val)
+			if err != nil {
+				return nil, err
+			}
+			t[k] = conv
+		}
+		return t, nil
+	case map[any]any:
+		m := make(map[string]any, len(t))
+		for k, val := range t {
+			conv, err := jsonCompatible(val)
+			if err != nil {
+				return nil, err
+			}
+			m[fmt.Sprint(k)] = conv
+		}
+		return m, nil
+	case []any:
+		for i, val := range t {
+			conv, err := jsonCompatible(val)
+			if err != nil {
+				return nil, err
+			}
+			t[i] = conv
+		}
+		return t, nil
+	}
+	return v, nil
+}
+
+// jsonToYAML converts json to yaml document with the same order of fields
+func jsonToYAML(data []byte) ([]byte, error) {
+	// json is a subset of yaml, so it is parsed keeping the order, then written in block style
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	blockStyle(&node)
+
+	return yaml.Marshal(&node)
+}
+
+func blockStyle(node *yaml.Node) {
+	node.Style &^= yaml.FlowStyle | yaml.DoubleQuotedStyle
+	for _, n := range node.Content {
+		blockStyle(n)
+	}
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// EnvPrefix - prefix of environment variables which override config fields,
+// name of field is converted to upper snake case and joined with parents, for example
+// LS_PROXY_ADMIN_TOKEN, LS_PROXY_CACHE_CONFIG_REDIS_PASSWORD, LS_PROXY_CLIENTS_0_PRIVATE_KEY
+const EnvPrefix = "LS_PROXY_"
+
+// ApplyEnv overrides config fields with values of environment variables, bytes are passed as base64,
+// lists as comma separated values, only existing elements of lists of objects can be overridden
+func ApplyEnv(cfg *Config) error {
+	return applyEnv(reflect.ValueOf(cfg).Elem(), strings.TrimSuffix(EnvPrefix, "_"))
+}
+
+func applyEnv(v reflect.Value, name string) error {
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			if err := applyEnv(v.Field(i), name+"_"+envName(t.Field(i).Name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Struct {
+			for i := 0; i < v.Len(); i++ {
+				if err := applyEnv(v.Index(i), name+"_"+strconv.Itoa(i)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case reflect.Map:
+		// maps have arbitrary keys, they are configured only in file
+		return nil
+	}
+
+	val, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+
+	if err := setEnvValue(v, val); err != nil {
+		return fmt.Errorf("invalid value of %s: %w", name, err)
+	}
+	return nil
+}
+
+func setEnvValue(v reflect.Value, val string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(val)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(val, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(val, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(val, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			data, err := base64.StdEncoding.DecodeString(val)
+			if err != nil {
+				return err
+			}
+			v.SetBytes(data)
+			return nil
+		}
+
+		var parts []string
+		if val != "" {
+			parts = strings.Split(val, ",")
+		}
+
+		list := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setEnvValue(list.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+		v.Set(list)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type().String())
+	}
+	return nil
+}
+
+// envName converts field name to upper snake case, abbreviations are kept together: GRPCAddr -> GRPC_ADDR
+func envName(field string) string {
+	runes := []rune(field)
+
+	var sb strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+				(unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				sb.WriteByte('_')
+			}
+		}
+		sb.WriteRune(unicode.ToUpper(r))
+	}
+	return sb.String()
+}
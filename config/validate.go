@@ -0,0 +1,505 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// FieldError describes invalid value of config field, Field is a path like Clients[0].PrivateKey
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationError contains all problems found in config
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	list := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		list = append(list, f.Error())
+	}
+	return "invalid config: " + strings.Join(list, "; ")
+}
+
+type validator struct {
+	errs []FieldError
+}
+
+func (v *validator) add(field, format string, args ...any) {
+	v.errs = append(v.errs, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) oneOf(field, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.add(field, "unknown value %q, should be one of: %s", value, strings.Join(allowed, ", "))
+}
+
+func (v *validator) hashes(field string, rootHash, fileHash []byte) {
+	if len(rootHash) != 32 {
+		v.add(field+".RootHash", "should be 32 bytes, got %d", len(rootHash))
+	}
+	if len(fileHash) != 32 {
+		v.add(field+".FileHash", "should be 32 bytes, got %d", len(fileHash))
+	}
+}
+
+// Validate checks the whole config, so all problems are reported at once before start,
+// *ValidationError is returned with each invalid field
+func (c *Config) Validate() error {
+	var v validator
+
+	c.validateListeners(&v)
+
+	if len(c.Clients) == 0 {
+		v.add("Clients", "at least one client key is required")
+	}
+	clientNames := map[string]int{}
+	clientKeys := map[string]int{}
+	for i, client := range c.Clients {
+		field := fmt.Sprintf("Clients[%d]", i)
+		if len(client.PrivateKey) != ed25519.SeedSize {
+			v.add(field+".PrivateKey", "should be %d bytes seed, got %d bytes", ed25519.SeedSize, len(client.PrivateKey))
+		} else if j, ok := clientKeys[string(client.PrivateKey)]; ok {
+			v.add(field+".PrivateKey", "same key as Clients[%d]", j)
+		} else {
+			clientKeys[string(client.PrivateKey)] = i
+		}
+
+		if j, ok := clientNames[client.Name]; ok {
+			v.add(field+".Name", "duplicate name %q, same as Clients[%d], metrics of clients would be mixed", client.Name, j)
+		} else {
+			clientNames[client.Name] = i
+		}
+
+		if client.CapacityPerIP < 0 {
+			v.add(field+".CapacityPerIP", "should not be negative, 0 disables limit")
+		}
+		if client.CapacityPerKey < 0 {
+			v.add(field+".CapacityPerKey", "should not be negative, 0 disables limit")
+		}
+		if (client.CapacityPerIP > 0 || client.CapacityPerKey > 0) && client.CoolingPerSec <= 0 {
+			v.add(field+".CoolingPerSec", "should be positive when capacity is set, otherwise requests are blocked after capacity is used")
+		}
+		if client.MaxInFlight < 0 {
+			v.add(field+".MaxInFlight", "should not be negative, 0 disables limit")
+		}
+		v.oneOf(field+".Priority", client.Priority, "", "platinum", "standard", "free")
+		v.oneOf(field+".Mode", client.Mode, "", "proxy", "raw")
+		if client.GasCapacity < 0 {
+			v.add(field+".GasCapacity", "should not be negative, 0 disables limit")
+		}
+		if client.GasCapacity > 0 && client.GasPerSec <= 0 {
+			v.add(field+".GasPerSec", "should be positive when gas capacity is set, otherwise get methods are blocked after capacity is used")
+		}
+	}
+
+	if len(c.Backends) == 0 && c.GlobalConfigURL == "" {
+		v.add("Backends", "at least one backend or GlobalConfigURL is required")
+	}
+	backendNames := map[string]int{}
+	backendAddrs := map[string]int{}
+	for i, backend := range c.Backends {
+		field := fmt.Sprintf("Backends[%d]", i)
+		if _, _, err := net.SplitHostPort(backend.Addr); err != nil {
+			v.add(field+".Addr", "should be host:port, got %q", backend.Addr)
+		} else if j, ok := backendAddrs[backend.Addr]; ok {
+			v.add(field+".Addr", "duplicate address, same as Backends[%d], use Connections to add parallel connections", j)
+		} else {
+			backendAddrs[backend.Addr] = i
+		}
+
+		if len(backend.Key) != ed25519.PublicKeySize {
+			v.add(field+".Key", "should be %d bytes public key, got %d bytes", ed25519.PublicKeySize, len(backend.Key))
+		}
+
+		// unnamed backend is identified by address, so it should not match name of another one
+		if name := backend.NameOrAddr(); name != "" {
+			if j, ok := backendNames[name]; ok {
+				v.add(field+".Name", "duplicate name %q, same as Backends[%d], name defaults to address", name, j)
+			} else {
+				backendNames[name] = i
+			}
+		}
+
+		for k, tag := range backend.Tags {
+			v.oneOf(fmt.Sprintf("%s.Tags[%d]", field, k), tag, "fast", "archive", "full-state")
+		}
+	}
+
+	v.oneOf("BalancerType", c.BalancerType, "fail_over", "round_robin", "latency", "weighted")
+
+	if c.Quorum.Size > 1 && c.GlobalConfigURL == "" && int(c.Quorum.Size) > len(c.Backends) {
+		v.add("Quorum.Size", "is %d but only %d backends are configured", c.Quorum.Size, len(c.Backends))
+	}
+
+	v.oneOf("Network", c.Network, "", "mainnet", "testnet", "custom")
+	if c.Network == "custom" {
+		v.hashes("ZeroState", c.ZeroState.RootHash, c.ZeroState.FileHash)
+	}
+
+	if !c.DisableEmulationAndCache {
+		c.validateCache(&v)
+	}
+
+	v.oneOf("EventStream.Type", c.EventStream.Type, "", "none", "kafka", "nats")
+	switch c.EventStream.Type {
+	case "kafka":
+		if len(c.EventStream.Kafka.Brokers) == 0 {
+			v.add("EventStream.Kafka.Brokers", "at least one broker is required")
+		}
+		if c.EventStream.Kafka.Topic == "" {
+			v.add("EventStream.Kafka.Topic", "is required")
+		}
+	case "nats":
+		if c.EventStream.NATS.URL == "" {
+			v.add("EventStream.NATS.URL", "is required")
+		}
+		if c.EventStream.NATS.Subject == "" {
+			v.add("EventStream.NATS.Subject", "is required")
+		}
+	}
+	if c.EventStream.Type != "" && c.EventStream.Type != "none" && c.DisableEmulationAndCache {
+		v.add("EventStream.Type", "requires emulation and cache to be enabled")
+	}
+	if c.SubscriptionsAddr != "" && c.DisableEmulationAndCache {
+		v.add("SubscriptionsAddr", "requires emulation and cache to be enabled")
+	}
+	if c.Upstream.URL != "" {
+		if u, err := url.Parse(c.Upstream.URL); err != nil || (u.Scheme != "ws" && u.Scheme != "wss") || u.Host == "" {
+			v.add("Upstream.URL", "should be ws or wss url, got %q", c.Upstream.URL)
+		}
+		if c.DisableEmulationAndCache {
+			v.add("Upstream.URL", "requires emulation and cache to be enabled")
+		}
+	}
+
+	if c.SharedLimits.Addr != "" && c.SharedLimits.KeyPrefix == "" {
+		v.add("SharedLimits.KeyPrefix", "is required when Addr is set, otherwise keys can clash with other data")
+	}
+
+	if c.PublicAddr != "" {
+		host, _, err := net.SplitHostPort(c.PublicAddr)
+		if ip := net.ParseIP(host); err != nil || ip == nil || ip.To4() == nil || ip.IsUnspecified() {
+			v.add("PublicAddr", "should be public ipv4:port, got %q", c.PublicAddr)
+		}
+	}
+
+	if c.DHT.Enabled {
+		if c.DHT.GlobalConfigURL == "" {
+			v.add("DHT.GlobalConfigURL", "is required to announce in dht")
+		}
+		if c.PublicAddr == "" {
+			host, _, _ := net.SplitHostPort(c.ListenAddr)
+			if ip := net.ParseIP(host); ip == nil || ip.To4() == nil || ip.IsUnspecified() {
+				v.add("PublicAddr", "is required to announce in dht when ListenAddr is not public ipv4")
+			}
+		}
+	}
+
+	if c.Retry.BudgetRatio < 0 {
+		v.add("Retry.BudgetRatio", "should not be negative")
+	}
+	if c.HandshakeLimit.PerIPPerSec < 0 {
+		v.add("HandshakeLimit.PerIPPerSec", "should not be negative")
+	}
+	if c.HandshakeLimit.Burst < 0 {
+		v.add("HandshakeLimit.Burst", "should not be negative")
+	}
+	for method, mode := range c.MethodOverrides {
+		v.oneOf("MethodOverrides."+method, mode, "raw", "local")
+	}
+	if c.ReusePortListeners > 1 && c.Handoff.Enabled {
+		v.add("ReusePortListeners", "cannot be used with Handoff, sockets with SO_REUSEPORT are not passed to new process")
+	}
+	if c.IPv6LimitPrefix > 128 {
+		v.add("IPv6LimitPrefix", "should be from 0 to 128, got %d", c.IPv6LimitPrefix)
+	}
+	for i, backend := range c.Shadow.Backends {
+		field := fmt.Sprintf("Shadow.Backends[%d]", i)
+		if _, _, err := net.SplitHostPort(backend.Addr); err != nil {
+			v.add(field+".Addr", "should be host:port, got %q", backend.Addr)
+		}
+		if len(backend.Key) != ed25519.PublicKeySize {
+			v.add(field+".Key", "should be %d bytes public key, got %d bytes", ed25519.PublicKeySize, len(backend.Key))
+		}
+	}
+	if c.Shadow.Percent < 0 || c.Shadow.Percent > 100 {
+		v.add("Shadow.Percent", "should be from 0 to 100, got %v", c.Shadow.Percent)
+	}
+	for i, rule := range c.Chaos {
+		field := fmt.Sprintf("Chaos[%d]", i)
+		v.oneOf(field+".Fault", rule.Fault, "", "429", "502", "truncate")
+		if rule.Percent <= 0 || rule.Percent > 100 {
+			v.add(field+".Percent", "should be from 0 to 100, got %v", rule.Percent)
+		}
+		for j, name := range rule.Keys {
+			if _, ok := clientNames[name]; !ok {
+				v.add(fmt.Sprintf("%s.Keys[%d]", field, j), "unknown client %q, should be name from Clients", name)
+			}
+		}
+	}
+	if c.SelfTest.Key != "" {
+		if _, ok := clientNames[c.SelfTest.Key]; !ok {
+			v.add("SelfTest.Key", "unknown client %q, should be name from Clients", c.SelfTest.Key)
+		}
+	}
+	if c.Canary.Percent < 0 || c.Canary.Percent > 100 {
+		v.add("Canary.Percent", "should be from 0 to 100, got %v", c.Canary.Percent)
+	}
+	v.oneOf("Billing.Type", c.Billing.Type, "", "none", "webhook", "file", "kafka", "nats")
+	switch c.Billing.Type {
+	case "webhook":
+		if u, err := url.Parse(c.Billing.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			v.add("Billing.WebhookURL", "should be http or https url")
+		}
+	case "file":
+		if c.Billing.Path == "" {
+			v.add("Billing.Path", "is required")
+		}
+	case "kafka":
+		if len(c.Billing.Kafka.Brokers) == 0 {
+			v.add("Billing.Kafka.Brokers", "at least one broker is required")
+		}
+		if c.Billing.Kafka.Topic == "" {
+			v.add("Billing.Kafka.Topic", "is required")
+		}
+	case "nats":
+		if c.Billing.NATS.URL == "" {
+			v.add("Billing.NATS.URL", "is required")
+		}
+		if c.Billing.NATS.Subject == "" {
+			v.add("Billing.NATS.Subject", "is required")
+		}
+	}
+	if c.Analytics.ClickHouseURL != "" {
+		if u, err := url.Parse(c.Analytics.ClickHouseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			v.add("Analytics.ClickHouseURL", "should be http or https url of clickhouse")
+		}
+	}
+	if c.Capture.SamplePercent < 0 || c.Capture.SamplePercent > 100 {
+		v.add("Capture.SamplePercent", "should be from 0 to 100, got %v", c.Capture.SamplePercent)
+	}
+	if c.ErrorReporting.SentryDSN != "" {
+		if u, err := url.Parse(c.ErrorReporting.SentryDSN); err != nil || u.User == nil || strings.Trim(u.Path, "/") == "" {
+			v.add("ErrorReporting.SentryDSN", "should be like https://key@host/project")
+		}
+	}
+	if c.ErrorReporting.WebhookURL != "" {
+		if u, err := url.Parse(c.ErrorReporting.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			v.add("ErrorReporting.WebhookURL", "should be http or https url")
+		}
+	}
+	for i, n := range c.LimitExemptions.Networks {
+		if _, _, err := net.ParseCIDR(n); err != nil && net.ParseIP(n) == nil {
+			v.add(fmt.Sprintf("LimitExemptions.Networks[%d]", i), "should be ip or cidr, got %q", n)
+		}
+	}
+	for i, name := range c.LimitExemptions.Keys {
+		if _, ok := clientNames[name]; !ok {
+			v.add(fmt.Sprintf("LimitExemptions.Keys[%d]", i), "unknown client %q, should be name from Clients", name)
+		}
+	}
+
+	if c.GRPCAddr != "" {
+		if c.GRPCToken == "" && !c.GRPCInsecure {
+			v.add("GRPCToken", "is required when GRPCAddr is set, set GRPCInsecure to serve gRPC api without it")
+		}
+		if c.GRPCClient == "" {
+			v.add("GRPCClient", "is required when GRPCAddr is set, its limits apply to gRPC queries")
+		} else if _, ok := clientNames[c.GRPCClient]; !ok {
+			v.add("GRPCClient", "unknown client %q, should be name from Clients", c.GRPCClient)
+		}
+	}
+
+	if len(v.errs) > 0 {
+		return &ValidationError{Fields: v.errs}
+	}
+	return nil
+}
+
+func (c *Config) validateCache(v *validator) {
+	cc := c.CacheConfig
+
+	v.oneOf("CacheConfig.Store", cc.Store, "", "none", "memory", "redis", "disk", "layered")
+	switch cc.Store {
+	case "redis":
+		if cc.Redis.Addr == "" {
+			v.add("CacheConfig.Redis.Addr", "is required for redis store")
+		}
+		if cc.Redis.KeyPrefix == "" {
+			v.add("CacheConfig.Redis.KeyPrefix", "is required for redis store, purge deletes all keys with it")
+		}
+	case "disk", "layered":
+		if cc.Disk.Path == "" {
+			v.add("CacheConfig.Disk.Path", "is required for %s store", cc.Store)
+		}
+	}
+
+	if cc.Watchlist.MaxAccounts > 0 && len(cc.Watchlist.Accounts) > int(cc.Watchlist.MaxAccounts) {
+		v.add("CacheConfig.Watchlist.Accounts", "has %d accounts, more than MaxAccounts %d", len(cc.Watchlist.Accounts), cc.Watchlist.MaxAccounts)
+	}
+
+	if cc.S3.Bucket != "" {
+		if u, err := url.Parse(cc.S3.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			v.add("CacheConfig.S3.Endpoint", "should be http or https url, got %q", cc.S3.Endpoint)
+		}
+		if cc.S3.AccessKey == "" || cc.S3.SecretKey == "" {
+			v.add("CacheConfig.S3", "AccessKey and SecretKey are required")
+		}
+		for i, class := range cc.S3.Classes {
+			v.oneOf(fmt.Sprintf("CacheConfig.S3.Classes[%d]", i), class, "master_blocks", "shard_blocks", "accounts", "libraries", "transactions")
+		}
+	}
+
+	if cc.PubSub.Addr != "" && cc.PubSub.Channel == "" {
+		v.add("CacheConfig.PubSub.Channel", "is required when Addr is set")
+	}
+	if cc.PubSub.LeaderElection {
+		if cc.PubSub.Addr == "" {
+			v.add("CacheConfig.PubSub.Addr", "is required for leader election")
+		}
+		if cc.PubSub.LeaderLockKey == "" {
+			v.add("CacheConfig.PubSub.LeaderLockKey", "is required for leader election")
+		}
+	}
+
+	for i, peer := range cc.PeerCache.Peers {
+		field := fmt.Sprintf("CacheConfig.PeerCache.Peers[%d]", i)
+		if _, _, err := net.SplitHostPort(peer.Addr); err != nil {
+			v.add(field+".Addr", "should be host:port, got %q", peer.Addr)
+		}
+		if len(peer.Key) != ed25519.PublicKeySize {
+			v.add(field+".Key", "should be %d bytes public key, got %d bytes", ed25519.PublicKeySize, len(peer.Key))
+		}
+	}
+	for i, class := range cc.PeerCache.Classes {
+		v.oneOf(fmt.Sprintf("CacheConfig.PeerCache.Classes[%d]", i), class, "master_blocks", "shard_blocks", "accounts", "libraries", "transactions")
+	}
+
+	if cc.MemoryBudgetMB > 0 && c.DisableEmulationAndCache {
+		v.add("CacheConfig.MemoryBudgetMB", "requires emulation and cache to be enabled, otherwise there is nothing to evict")
+	}
+	if cc.TTLJitterPercent > 100 {
+		v.add("CacheConfig.TTLJitterPercent", "should be from 0 to 100, got %d", cc.TTLJitterPercent)
+	}
+
+	if len(cc.TrustedBlock.RootHash) > 0 || len(cc.TrustedBlock.FileHash) > 0 {
+		v.hashes("CacheConfig.TrustedBlock", cc.TrustedBlock.RootHash, cc.TrustedBlock.FileHash)
+		if cc.TrustedBlock.Workchain != -1 {
+			v.add("CacheConfig.TrustedBlock.Workchain", "should be -1, trusted block is from masterchain")
+		}
+	}
+}
+
+// validateListeners checks addresses of all listeners, and that they are not overlapping
+func (c *Config) validateListeners(v *validator) {
+	type listener struct {
+		field string
+		host  string
+		port  int
+	}
+
+	if c.ListenAddr == "" {
+		v.add("ListenAddr", "is required")
+	}
+
+	type address struct {
+		field string
+		addr  string
+	}
+
+	var list []listener
+	addrs := []address{
+		{"ListenAddr", c.ListenAddr},
+		{"MetricsAddr", c.MetricsAddr},
+		{"AdminAddr", c.AdminAddr},
+		{"GRPCAddr", c.GRPCAddr},
+		{"SubscriptionsAddr", c.SubscriptionsAddr},
+		{"HelperAddr", c.HelperAddr},
+		{"WebSocketListenAddr", c.WebSocketListenAddr},
+	}
+
+	clients := map[string]bool{}
+	for _, client := range c.Clients {
+		clients[client.Name] = true
+	}
+	for i, l := range c.Listeners {
+		field := fmt.Sprintf("Listeners[%d]", i)
+		if l.Addr == "" {
+			v.add(field+".Addr", "is required")
+		}
+		addrs = append(addrs, address{field + ".Addr", l.Addr})
+
+		for k, name := range l.Clients {
+			if !clients[name] {
+				v.add(fmt.Sprintf("%s.Clients[%d]", field, k), "unknown client %q, should be name from Clients", name)
+			}
+		}
+	}
+
+	for _, l := range addrs {
+		if l.addr == "" {
+			continue
+		}
+
+		host, portStr, err := net.SplitHostPort(l.addr)
+		if err != nil {
+			v.add(l.field, "should be host:port, got %q", l.addr)
+			continue
+		}
+		if host != "" && net.ParseIP(host) == nil {
+			if _, err = net.LookupHost(host); err != nil {
+				v.add(l.field, "host %q cannot be resolved", host)
+				continue
+			}
+		}
+
+		port, err := strconv.Atoi(portStr)
+		if err != nil || port < 0 || port > 65535 {
+			v.add(l.field, "invalid port %q", portStr)
+			continue
+		}
+		if port == 0 {
+			// random port cannot be reached by clients and scrapers which are configured in advance
+			v.add(l.field, "port should be set, 0 selects random port")
+			continue
+		}
+
+		for _, other := range list {
+			if other.port == port && hostsOverlap(other.host, host) {
+				v.add(l.field, "overlaps with %s, both listen %s", other.field, l.addr)
+				break
+			}
+		}
+		list = append(list, listener{field: l.field, host: host, port: port})
+	}
+}
+
+// hostsOverlap reports if listeners on the same port would conflict,
+// unspecified host listens all interfaces so it conflicts with any other
+func hostsOverlap(a, b string) bool {
+	unspecified := func(h string) bool {
+		ip := net.ParseIP(h)
+		return h == "" || (ip != nil && ip.IsUnspecified())
+	}
+	// the same ip can be written differently, like ::1 and 0:0:0:0:0:0:0:1
+	if ipA, ipB := net.ParseIP(a), net.ParseIP(b); ipA != nil && ipA.Equal(ipB) {
+		return true
+	}
+	return a == b || unspecified(a) || unspecified(b)
+}
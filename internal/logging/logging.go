@@ -0,0 +1,105 @@
+package logging
+
+import (
+	"github.com/rs/zerolog"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	level   atomic.Int32
+	modules atomic.Pointer[map[string]bool]
+	mx      sync.Mutex
+)
+
+// ModuleHook filters debug events by module when debug is enabled only for some of them, it should be
+// added to the root logger. Module is a name of source file without extension, like cache or backend,
+// or a name of its package directory, like server, to enable the whole package.
+type ModuleHook struct{}
+
+func (ModuleHook) Run(e *zerolog.Event, lvl zerolog.Level, _ string) {
+	if lvl >= zerolog.Level(level.Load()) {
+		return
+	}
+
+	list := modules.Load()
+	if list == nil || !callerEnabled(*list) {
+		e.Discard()
+	}
+}
+
+func callerEnabled(list map[string]bool) bool {
+	var pcs [16]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, "github.com/rs/zerolog") {
+			dir, file := filepath.Split(f.File)
+			return list[strings.TrimSuffix(file, ".go")] || list[filepath.Base(dir)]
+		}
+		if !more {
+			return false
+		}
+	}
+}
+
+// SetLevel changes level of all logs, debug events of modules with enabled debug are logged anyway
+func SetLevel(lvl zerolog.Level) {
+	mx.Lock()
+	defer mx.Unlock()
+
+	level.Store(int32(lvl))
+	apply()
+}
+
+// Level returns level set for all logs
+func Level() zerolog.Level {
+	return zerolog.Level(level.Load())
+}
+
+// SetDebugModules enables debug logs only for listed modules, empty list disables them
+func SetDebugModules(list []string) {
+	mx.Lock()
+	defer mx.Unlock()
+
+	if len(list) == 0 {
+		modules.Store(nil)
+	} else {
+		m := map[string]bool{}
+		for _, name := range list {
+			m[name] = true
+		}
+		modules.Store(&m)
+	}
+	apply()
+}
+
+// DebugModules returns modules with enabled debug logs
+func DebugModules() []string {
+	list := modules.Load()
+	if list == nil {
+		return []string{}
+	}
+
+	res := make([]string, 0, len(*list))
+	for name := range *list {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// apply lowers global level to debug when some modules need it, events of other modules are discarded by hook,
+// otherwise events below level are not even created
+func apply() {
+	lvl := zerolog.Level(level.Load())
+	if modules.Load() != nil && lvl > zerolog.DebugLevel {
+		lvl = zerolog.DebugLevel
+	}
+	zerolog.SetGlobalLevel(lvl)
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+)
+
+const cacheClassShardProofs CacheClass = "shard_proofs"
+
+// reuseShardAccountState builds account state at master block from the state remembered for the account's shard,
+// it is possible when shard has not advanced since, only proof of shard in the master block is fetched,
+// and it is shared by all accounts of the shard
+func (c *BlockCache) reuseShardAccountState(ctx context.Context, masterID *ton.BlockIDExt, addr *address.Address) *ton.AccountState {
+	shard := c.accountShardAt(masterID, addr)
+	if shard == nil {
+		return nil
+	}
+
+	c.mx.RLock()
+	var states *lru.ARCCache
+	if si := c.shardBlocks[getShardKey(shard.Workchain, shard.Shard)]; si != nil && si.lastBlock.Equals(shard) {
+		states = si.accountStates
+	}
+	c.mx.RUnlock()
+
+	if states == nil {
+		return nil
+	}
+
+	v, ok := states.Get(addr.String())
+	if !ok {
+		return nil
+	}
+	prev := v.(*ton.AccountState)
+
+	proof, err := c.getShardProof(ctx, masterID, shard)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("failed to get shard proof for cached account state")
+		return nil
+	}
+
+	return &ton.AccountState{
+		ID:         masterID,
+		Shard:      prev.Shard,
+		ShardProof: proof,
+		Proof:      prev.Proof,
+		State:      prev.State,
+	}
+}
+
+func (c *BlockCache) rememberShardAccountState(addr *address.Address, account *ton.AccountState) {
+	if account.Shard == nil || account.Shard.Workchain == -1 || c.config.MaxCachedAccountsPerBlock == 0 {
+		return
+	}
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	si := c.shardBlocks[getShardKey(account.Shard.Workchain, account.Shard.Shard)]
+	if si == nil || !si.lastBlock.Equals(account.Shard) {
+		// we keep states only for the last shard block
+		return
+	}
+
+	if si.accountStates == nil {
+		states, err := lru.NewARC(int(c.config.MaxCachedAccountsPerBlock))
+		if err != nil {
+			return
+		}
+		si.accountStates = states
+	}
+	si.accountStates.Add(addr.String(), account)
+}
+
+func (c *BlockCache) accountShardAt(masterID *ton.BlockIDExt, addr *address.Address) *ton.BlockIDExt {
+	c.mx.RLock()
+	mb := c.masterBlocks[masterID.SeqNo]
+	c.mx.RUnlock()
+
+	if mb == nil {
+		return nil
+	}
+
+	mb.mx.RLock()
+	defer mb.mx.RUnlock()
+
+	if mb.Block.ID == nil || !mb.Block.ID.Equals(masterID) {
+		return nil
+	}
+
+	for _, shard := range mb.Shards {
+		if shard.Workchain == addr.Workchain() && shardContainsAccount(shard.Shard, addr.Data()) {
+			return shard
+		}
+	}
+	return nil
+}
+
+func (c *BlockCache) getShardProof(ctx context.Context, masterID, shard *ton.BlockIDExt) ([]*cell.Cell, error) {
+	key := hex.EncodeToString(masterID.RootHash) + ":" + getShardKey(shard.Workchain, shard.Shard)
+	if v, ok := c.shardProofs.Get(key); ok {
+		return v.([]*cell.Cell), nil
+	}
+
+	v, err := c.coalesce(ctx, cacheClassShardProofs, key, func(ctx context.Context) (any, error) {
+		proof, err := getShardProof(ctx, c.balancer.GetClient(), masterID, shard)
+		if err != nil {
+			return nil, err
+		}
+
+		if !c.config.DisableProofVerification {
+			if err = verifyShardProof(masterID, shard, proof); err != nil {
+				log.Ctx(ctx).Warn().Err(err).Uint32("seqno", masterID.SeqNo).Msg("backend returned invalid shard proof")
+				return nil, fmt.Errorf("backend returned invalid proof: %w", err)
+			}
+		}
+		return proof, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	proof := v.([]*cell.Cell)
+	c.shardProofs.Add(key, proof)
+	return proof, nil
+}
+
+func getShardProof(ctx context.Context, client ton.LiteClient, masterID, shard *ton.BlockIDExt) ([]*cell.Cell, error) {
+	var resp tl.Serializable
+	err := client.QueryLiteserver(ctx, ton.GetShardInfo{
+		ID:        masterID,
+		Workchain: shard.Workchain,
+		Shard:     shard.Shard,
+		Exact:     true,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := resp.(type) {
+	case ton.ShardInfo:
+		if !t.ID.Equals(masterID) || !t.ShardBlock.Equals(shard) {
+			return nil, fmt.Errorf("response with incorrect block")
+		}
+		return t.ShardProof, nil
+	case ton.LSError:
+		return nil, t
+	}
+	return nil, fmt.Errorf("unexpected response")
+}
@@ -0,0 +1,472 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"github.com/xssnick/tonutils-liteserver-proxy/internal/logging"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+type AdminAPI struct {
+	proxy *ProxyBalancer
+	cache *BlockCache
+	cfg   *config.Config
+	token string
+	mux   *http.ServeMux
+}
+
+type ConnectionRateLimit struct {
+	IPRemaining  int64 `json:"ip_remaining"`
+	IPCapacity   int64 `json:"ip_capacity"`
+	KeyRemaining int64 `json:"key_remaining"`
+	KeyCapacity  int64 `json:"key_capacity"`
+	KeyInFlight  int64 `json:"key_in_flight"`
+	// bytes sent to key in the current period of bandwidth quota
+	KeyBandwidthUsed  uint64 `json:"key_bandwidth_used,omitempty"`
+	KeyBandwidthQuota uint64 `json:"key_bandwidth_quota,omitempty"`
+}
+
+type ConnectionInfo struct {
+	IP            string               `json:"ip"`
+	Port          uint16               `json:"port"`
+	KeyName       string               `json:"key_name"`
+	ConnectedAt   int64                `json:"connected_at"`
+	LastRequestAt int64                `json:"last_request_at"`
+	Requests      uint64               `json:"requests"`
+	RateLimit     *ConnectionRateLimit `json:"rate_limit,omitempty"`
+}
+
+type BackendInfo struct {
+	Name       string   `json:"name"`
+	Addr       string   `json:"addr"`
+	Weight     uint64   `json:"weight"`
+	Tags       []string `json:"tags"`
+	Tier       uint32   `json:"tier"`
+	Discovered bool     `json:"discovered"`
+	Healthy    bool     `json:"healthy"`
+	InFlight   int64    `json:"in_flight"`
+}
+
+// NewAdminAPI creates http handler for operator endpoints, when AdminToken is not empty
+// it is required to be passed as bearer authorization
+func NewAdminAPI(proxy *ProxyBalancer, cache *BlockCache, cfg *config.Config) *AdminAPI {
+	a := &AdminAPI{
+		proxy: proxy,
+		cache: cache,
+		cfg:   cfg,
+		token: cfg.AdminToken,
+		mux:   http.NewServeMux(),
+	}
+	a.mux.HandleFunc("/connections", a.handleConnections)
+	a.mux.HandleFunc("/connections/kick", a.handleKick)
+	a.mux.HandleFunc("/bans", a.handleBans)
+	a.mux.HandleFunc("/backends", a.handleBackends)
+	a.mux.HandleFunc("/cache/purge", a.handleCachePurge)
+	a.mux.HandleFunc("/client-config", a.handleClientConfig)
+	a.mux.HandleFunc("/log", a.handleLog)
+	a.mux.HandleFunc("/watchlist", a.handleWatchlist)
+
+	return a
+}
+
+func (a *AdminAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if a.token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+a.token)) != 1 {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return
+	}
+	a.mux.ServeHTTP(w, r)
+}
+
+func (a *AdminAPI) handleConnections(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	writeJSON(w, http.StatusOK, a.proxy.Connections())
+}
+
+// handleKick disconnects clients by ip, with port parameter only one connection is disconnected
+func (a *AdminAPI) handleKick(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	q := r.URL.Query()
+	if q.Get("ip") == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "ip is required"})
+		return
+	}
+
+	var port uint64
+	if q.Get("port") != "" {
+		var err error
+		if port, err = strconv.ParseUint(q.Get("port"), 10, 16); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid port"})
+			return
+		}
+	}
+
+	n := a.proxy.Kick(q.Get("ip"), uint16(port))
+	if n == 0 {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "no such connection"})
+		return
+	}
+	log.Info().Str("ip", q.Get("ip")).Uint64("port", port).Int("connections", n).Msg("clients kicked via admin api")
+	writeJSON(w, http.StatusOK, map[string]int{"kicked": n})
+}
+
+// handleBans lists active bans, bans ip or key for ttl seconds (an hour by default) with POST,
+// and removes ban with DELETE, ip or key parameter is required for both
+func (a *AdminAPI) handleBans(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		writeJSON(w, http.StatusOK, a.proxy.Bans())
+		return
+	}
+
+	q := r.URL.Query()
+	kind, value := BanKindIP, q.Get("ip")
+	if value == "" {
+		kind, value = BanKindKey, q.Get("key")
+	}
+	if value == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "ip or key is required"})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		if kind == BanKindIP && net.ParseIP(strings.Trim(value, "[]")) == nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid ip"})
+			return
+		}
+		if kind == BanKindKey {
+			if _, ok := a.proxy.keysByName[value]; !ok {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": "no client key with this name"})
+				return
+			}
+		}
+
+		ttl := time.Hour
+		if q.Get("ttl") != "" {
+			sec, err := strconv.ParseUint(q.Get("ttl"), 10, 32)
+			if err != nil || sec == 0 {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "ttl should be positive number of seconds"})
+				return
+			}
+			ttl = time.Duration(sec) * time.Second
+		}
+
+		n := a.proxy.Ban(kind, value, ttl)
+		log.Info().Str(kind, value).Dur("ttl", ttl).Int("kicked", n).Msg("client banned via admin api")
+		writeJSON(w, http.StatusOK, map[string]any{"status": "banned", "kicked": n})
+	case http.MethodDelete:
+		if !a.proxy.Unban(kind, value) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "no such ban"})
+			return
+		}
+		log.Info().Str(kind, value).Msg("client unbanned via admin api")
+		writeJSON(w, http.StatusOK, map[string]string{"status": "unbanned"})
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+func (a *AdminAPI) handleBackends(w http.ResponseWriter, r *http.Request) {
+	blc, ok := a.proxy.backendBalancer.(*BackendBalancer)
+	if !ok {
+		writeJSON(w, http.StatusNotImplemented, map[string]string{"error": "backends are managed by custom balancer"})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		list := blc.Backends()
+		res := make([]BackendInfo, 0, len(list))
+		for _, b := range list {
+			res = append(res, BackendInfo{
+				Name:       b.Name,
+				Addr:       b.Addr,
+				Weight:     b.Weight,
+				Tags:       b.Tags,
+				Tier:       b.Tier,
+				Discovered: b.discovered,
+				Healthy:    b.IsHealthy(),
+				InFlight:   atomic.LoadInt64(&b.inFlight),
+			})
+		}
+		writeJSON(w, http.StatusOK, res)
+	case http.MethodPost:
+		var cfg config.BackendLiteserver
+		if err := json.NewDecoder(io.LimitReader(r.Body, 1<<16)).Decode(&cfg); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid body: " + err.Error()})
+			return
+		}
+		if cfg.Name == "" || cfg.Addr == "" || len(cfg.Key) != 32 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name, addr and 32 bytes key are required"})
+			return
+		}
+
+		backend, err := connectBackend(r.Context(), cfg)
+		if err != nil {
+			writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+			return
+		}
+		if err = blc.AddBackend(backend); err != nil {
+			backend.Stop()
+			writeJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+			return
+		}
+		log.Info().Str("backend", cfg.Addr).Str("name", cfg.Name).Msg("backend added via admin api")
+		writeJSON(w, http.StatusCreated, map[string]string{"status": "added"})
+	case http.MethodDelete:
+		name := r.URL.Query().Get("name")
+		if name == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name is required"})
+			return
+		}
+
+		if err := blc.RemoveAndDrain(name, backendDrainTimeout); err != nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": "draining"})
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+// handleCachePurge drops cached objects, one of parameters can be passed:
+// class - to drop all objects of class, account - to drop states of account,
+// block - wc:shard:seqno to drop a single block; without parameters everything is dropped
+func (a *AdminAPI) handleCachePurge(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	q := r.URL.Query()
+	if q.Get("class") == "" && q.Get("account") == "" && q.Get("block") == "" {
+		a.proxy.PurgeResponses()
+		if a.cache == nil {
+			writeJSON(w, http.StatusOK, map[string]string{"status": "purged"})
+			return
+		}
+	}
+
+	if a.cache == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "cache is disabled"})
+		return
+	}
+
+	var err error
+	switch {
+	case q.Get("account") != "":
+		addr, perr := address.ParseAddr(q.Get("account"))
+		if perr != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid account: " + perr.Error()})
+			return
+		}
+		err = a.cache.PurgeAccount(r.Context(), addr)
+	case q.Get("block") != "":
+		var wc int32
+		var shard int64
+		var seqno uint32
+		if _, perr := fmt.Sscanf(q.Get("block"), "%d:%d:%d", &wc, &shard, &seqno); perr != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "block should be in format wc:shard:seqno"})
+			return
+		}
+
+		var found bool
+		found, err = a.cache.PurgeBlock(r.Context(), wc, shard, seqno)
+		if err == nil && !found {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "block is not cached"})
+			return
+		}
+	default:
+		err = a.cache.Purge(r.Context(), CacheClass(q.Get("class")))
+	}
+
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "purged"})
+}
+
+// handleClientConfig returns liteservers configs for clients by key name,
+// with name parameter only config of this key is returned
+func (a *AdminAPI) handleClientConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	configs, err := ClientConfigs(a.cfg, r.URL.Query().Get("addr"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if name := r.URL.Query().Get("name"); name != "" {
+		cfg, ok := configs[name]
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "no client key with this name"})
+			return
+		}
+		writeJSON(w, http.StatusOK, cfg)
+		return
+	}
+	writeJSON(w, http.StatusOK, configs)
+}
+
+type LogInfo struct {
+	Level        string   `json:"level"`
+	DebugModules []string `json:"debug_modules"`
+}
+
+// handleLog returns log level, and changes it with POST by level parameter, debug_modules parameter
+// is comma separated list of modules (source file or package names) to log debug events of, empty disables them
+func (a *AdminAPI) handleLog(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		q := r.URL.Query()
+		if q.Has("level") {
+			lvl, err := zerolog.ParseLevel(q.Get("level"))
+			if err != nil || lvl == zerolog.NoLevel {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid level"})
+				return
+			}
+			logging.SetLevel(lvl)
+		}
+		if q.Has("debug_modules") {
+			var list []string
+			for _, m := range strings.Split(q.Get("debug_modules"), ",") {
+				if m = strings.TrimSpace(m); m != "" {
+					list = append(list, m)
+				}
+			}
+			logging.SetDebugModules(list)
+		}
+		log.Warn().Str("level", logging.Level().String()).Strs("debug_modules", logging.DebugModules()).Msg("log settings changed via admin api")
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, LogInfo{
+		Level:        logging.Level().String(),
+		DebugModules: logging.DebugModules(),
+	})
+}
+
+// handleWatchlist returns watched accounts, POST adds account parameter to watchlist and DELETE removes it
+func (a *AdminAPI) handleWatchlist(w http.ResponseWriter, r *http.Request) {
+	if a.cache == nil || a.cache.Watchlist() == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "cache is disabled"})
+		return
+	}
+	wl := a.cache.Watchlist()
+
+	acc := r.URL.Query().Get("account")
+	if r.Method != http.MethodGet && acc == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "account is required"})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string][]string{"accounts": wl.List()})
+	case http.MethodPost:
+		added, err := wl.Add(acc)
+		if err != nil {
+			code := http.StatusBadRequest
+			if errors.Is(err, ErrWatchlistFull) {
+				code = http.StatusConflict
+			}
+			writeJSON(w, code, map[string]string{"error": err.Error()})
+			return
+		}
+		if !added {
+			writeJSON(w, http.StatusOK, map[string]string{"status": "already watched"})
+			return
+		}
+		log.Info().Str("addr", acc).Msg("account added to watchlist via admin api")
+		writeJSON(w, http.StatusOK, map[string]string{"status": "added"})
+	case http.MethodDelete:
+		if !wl.Remove(acc) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "account is not watched"})
+			return
+		}
+		log.Info().Str("addr", acc).Msg("account removed from watchlist via admin api")
+		writeJSON(w, http.StatusOK, map[string]string{"status": "removed"})
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+// Connections returns snapshot of currently connected clients
+func (s *ProxyBalancer) Connections() []ConnectionInfo {
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	list := make([]ConnectionInfo, 0, len(s.ips))
+	for ip, info := range s.ips {
+		for port, conn := range info.ActiveConnections {
+			ci := ConnectionInfo{
+				IP:            ip,
+				Port:          port,
+				ConnectedAt:   conn.ConnectedAt,
+				LastRequestAt: atomic.LoadInt64(&conn.LastRequest),
+				Requests:      atomic.LoadUint64(&conn.Requests),
+			}
+
+			if key := conn.key.Load(); key != nil {
+				ci.KeyName = key.name
+
+				var rl ConnectionRateLimit
+				if key.limiterPerIP != nil {
+					rl.IPRemaining = key.limiterPerIP.Remaining(s.limitKey(ip))
+					rl.IPCapacity = key.limiterPerIP.Capacity()
+				}
+				if key.limiterPerKey != nil {
+					rl.KeyRemaining = key.limiterPerKey.Remaining()
+					rl.KeyCapacity = key.limiterPerKey.Capacity()
+				}
+				rl.KeyInFlight = atomic.LoadInt64(&key.inFlight)
+				if key.bandwidth != nil {
+					rl.KeyBandwidthUsed, rl.KeyBandwidthQuota = key.bandwidth.usage()
+				}
+				ci.RateLimit = &rl
+			}
+			list = append(list, ci)
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ConnectedAt < list[j].ConnectedAt
+	})
+	return list
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Debug().Err(err).Msg("failed to write admin response")
+	}
+}
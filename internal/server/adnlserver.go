@@ -0,0 +1,333 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/adnl"
+	"github.com/xssnick/tonutils-go/tl"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxPacketSize is the biggest adnl tcp packet accepted from client
+const maxPacketSize = 8 << 20
+
+// adnlServer is adnl tcp server of liteserver protocol, the same as liteclient.Server,
+// but it serves listeners owned by caller, so sockets with SO_REUSEPORT and sockets
+// inherited from previous process are served directly
+type adnlServer struct {
+	keys map[string]ed25519.PrivateKey
+
+	messageHandler func(ctx context.Context, client *ServerClient, msg tl.Serializable) error
+	disconnectHook func(client *ServerClient)
+	connectHook    func(client *ServerClient) error
+
+	listeners map[net.Listener]struct{}
+	closed    bool
+	mx        sync.Mutex
+}
+
+// ServerClient is connection of adnl client
+type ServerClient struct {
+	conn      net.Conn
+	wCrypt    cipher.Stream
+	rCrypt    cipher.Stream
+	serverKey ed25519.PublicKey
+
+	port uint16
+	ip   string
+	mx   sync.Mutex
+}
+
+func newADNLServer(keys []ed25519.PrivateKey) *adnlServer {
+	list := map[string]ed25519.PrivateKey{}
+	for _, k := range keys {
+		kid, err := tl.Hash(adnl.PublicKeyED25519{Key: k.Public().(ed25519.PublicKey)})
+		if err != nil {
+			panic(err.Error())
+		}
+		list[string(kid)] = k
+	}
+
+	return &adnlServer{
+		keys:      list,
+		listeners: map[net.Listener]struct{}{},
+	}
+}
+
+// Serve accepts clients on lis until it or server is closed, nil is returned in both cases
+func (s *adnlServer) Serve(lis net.Listener) error {
+	s.mx.Lock()
+	if s.closed {
+		s.mx.Unlock()
+		return lis.Close()
+	}
+	s.listeners[lis] = struct{}{}
+	s.mx.Unlock()
+
+	defer func() {
+		s.mx.Lock()
+		delete(s.listeners, lis)
+		s.mx.Unlock()
+	}()
+
+	for {
+		conn, err := lis.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+
+			var ne net.Error
+			if errors.As(err, &ne) && ne.Timeout() {
+				continue
+			}
+
+			// too many open files and similar, wait a bit to not spin
+			log.Warn().Err(err).Msg("failed to accept client")
+			time.Sleep(50 * time.Millisecond)
+			continue
+		}
+
+		client := &ServerClient{
+			conn: conn,
+			ip:   conn.RemoteAddr().String(),
+		}
+		if host, port, err := net.SplitHostPort(client.ip); err == nil {
+			p, _ := strconv.ParseUint(port, 10, 16)
+			client.ip, client.port = host, uint16(p)
+		}
+
+		if !s.connect(client) {
+			continue
+		}
+		go s.serve(client)
+	}
+}
+
+// ServeConn serves client connection accepted by caller, like websocket tunnel, ip and port identify
+// client in limits the same way as for tcp clients, it blocks until connection is closed
+func (s *adnlServer) ServeConn(conn net.Conn, ip string, port uint16) {
+	client := &ServerClient{
+		conn: conn,
+		ip:   ip,
+		port: port,
+	}
+	if s.connect(client) {
+		s.serve(client)
+	}
+}
+
+// connect passes new client to connect hook, rejected client is closed
+func (s *adnlServer) connect(client *ServerClient) bool {
+	if s.connectHook != nil {
+		if err := s.connectHook(client); err != nil {
+			_ = client.conn.Close()
+			return false
+		}
+	}
+	return true
+}
+
+// Close stops accepting on all served listeners, connected clients are not affected
+func (s *adnlServer) Close() error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	s.closed = true
+
+	var err error
+	for lis := range s.listeners {
+		if e := lis.Close(); e != nil && !errors.Is(e, net.ErrClosed) && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func (s *adnlServer) serve(client *ServerClient) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		_ = client.conn.Close()
+		if s.disconnectHook != nil {
+			s.disconnectHook(client)
+		}
+	}()
+
+	// handshake is expected right after connect
+	_ = client.conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+
+	packet := make([]byte, 256)
+	if _, err := io.ReadFull(client.conn, packet); err != nil {
+		log.Debug().Err(err).Str("addr", client.ip).Msg("failed to read handshake")
+		return
+	}
+
+	var err error
+	client.serverKey, client.wCrypt, client.rCrypt, err = s.processHandshake(packet)
+	if err != nil {
+		log.Debug().Err(err).Str("addr", client.ip).Msg("invalid handshake")
+		return
+	}
+
+	// empty packet confirms handshake
+	if err = client.write(nil); err != nil {
+		log.Debug().Err(err).Str("addr", client.ip).Msg("failed to confirm handshake")
+		return
+	}
+	_ = client.conn.SetReadDeadline(time.Time{})
+
+	for {
+		data, err := client.read()
+		if err != nil {
+			return
+		}
+
+		msg, err := parseClientMessage(data)
+		if err != nil {
+			log.Debug().Err(err).Str("addr", client.ip).Msg("failed to parse client message")
+			return
+		}
+
+		if s.messageHandler == nil {
+			return
+		}
+
+		if err = s.messageHandler(ctx, client, msg); err != nil {
+			log.Debug().Err(err).Str("addr", client.ip).Msg("failed to handle client message")
+			return
+		}
+	}
+}
+
+func (s *adnlServer) processHandshake(packet []byte) (ed25519.PublicKey, cipher.Stream, cipher.Stream, error) {
+	serverKey := s.keys[string(packet[:32])]
+	if serverKey == nil {
+		return nil, nil, nil, fmt.Errorf("incorrect server key in packet")
+	}
+
+	key, err := adnl.SharedKey(serverKey, packet[32:64])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to calc shared key: %w", err)
+	}
+
+	checksum := packet[64:96]
+
+	k := make([]byte, 0, 32)
+	k = append(k, key[0:16]...)
+	k = append(k, checksum[16:32]...)
+
+	iv := make([]byte, 0, 16)
+	iv = append(iv, checksum[0:4]...)
+	iv = append(iv, key[20:32]...)
+
+	ctr, err := adnl.NewCipherCtr(k, iv)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to calc cipher for rnd: %w", err)
+	}
+
+	rnd := packet[96:]
+	ctr.XORKeyStream(rnd, rnd)
+
+	// server writes with key and iv which client uses for reading, and vice versa
+	w, err := adnl.NewCipherCtr(rnd[:32], rnd[64:80])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to calc cipher for w crypt: %w", err)
+	}
+	r, err := adnl.NewCipherCtr(rnd[32:64], rnd[80:96])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to calc cipher for r crypt: %w", err)
+	}
+
+	return serverKey.Public().(ed25519.PublicKey), w, r, nil
+}
+
+// read returns payload of the next packet, without nonce and checksum
+func (c *ServerClient) read() ([]byte, error) {
+	size := make([]byte, 4)
+	if _, err := io.ReadFull(c.conn, size); err != nil {
+		return nil, err
+	}
+	c.rCrypt.XORKeyStream(size, size)
+
+	sz := binary.LittleEndian.Uint32(size)
+	if sz < 64 || sz > maxPacketSize {
+		return nil, fmt.Errorf("invalid packet size %d", sz)
+	}
+
+	data := make([]byte, sz)
+	if _, err := io.ReadFull(c.conn, data); err != nil {
+		return nil, err
+	}
+	c.rCrypt.XORKeyStream(data, data)
+
+	checksum := sha256.Sum256(data[:sz-32])
+	if !bytes.Equal(checksum[:], data[sz-32:]) {
+		return nil, fmt.Errorf("invalid packet checksum")
+	}
+	return data[32 : sz-32], nil
+}
+
+// write sends packet with payload data
+func (c *ServerClient) write(data []byte) error {
+	buf := make([]byte, 4+32, 4+64+len(data))
+	binary.LittleEndian.PutUint32(buf, uint32(64+len(data)))
+
+	if _, err := io.ReadFull(rand.Reader, buf[4:]); err != nil {
+		return err
+	}
+	buf = append(buf, data...)
+
+	checksum := sha256.Sum256(buf[4:])
+	buf = append(buf, checksum[:]...)
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.wCrypt.XORKeyStream(buf, buf)
+
+	// stuck client should not block answers forever
+	_ = c.conn.SetWriteDeadline(time.Now().Add(7 * time.Second))
+	if _, err := c.conn.Write(buf); err != nil {
+		_ = c.conn.Close()
+		return err
+	}
+	return nil
+}
+
+// Send serializes msg and sends it to client
+func (c *ServerClient) Send(msg tl.Serializable) error {
+	data, err := tl.Serialize(msg, true)
+	if err != nil {
+		return err
+	}
+	return c.write(data)
+}
+
+func (c *ServerClient) Close() {
+	_ = c.conn.Close()
+}
+
+func (c *ServerClient) IP() string {
+	return c.ip
+}
+
+func (c *ServerClient) Port() uint16 {
+	return c.port
+}
+
+func (c *ServerClient) ServerKey() ed25519.PublicKey {
+	return c.serverKey
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"github.com/xssnick/tonutils-go/adnl"
+	"github.com/xssnick/tonutils-go/liteclient"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestADNLServerServe(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := newADNLServer([]ed25519.PrivateKey{key})
+	srv.messageHandler = func(ctx context.Context, client *ServerClient, msg tl.Serializable) error {
+		m, ok := msg.(adnl.MessageQuery)
+		if !ok {
+			return nil
+		}
+		return client.Send(adnl.MessageAnswer{ID: m.ID, Data: ton.CurrentTime{Now: 777}})
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	served := make(chan error, 1)
+	go func() {
+		served <- srv.Serve(lis)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pool := liteclient.NewConnectionPool()
+	defer pool.Stop()
+
+	serverKey := base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))
+	if err = pool.AddConnection(ctx, lis.Addr().String(), serverKey); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		var resp tl.Serializable
+		if err = pool.QueryLiteserver(ctx, ton.GetTime{}, &resp); err != nil {
+			t.Fatal(err)
+		}
+		if tm, ok := resp.(ton.CurrentTime); !ok || tm.Now != 777 {
+			t.Fatalf("unexpected response %v", resp)
+		}
+	}
+
+	if err = srv.Close(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err = <-served:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("serve is not stopped by close")
+	}
+}
@@ -0,0 +1,288 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// max distinct accounts counted during period, to not grow memory on scans of many accounts
+const maxAnalyticsAccounts = 100000
+
+const (
+	AnalyticsKindMethod  = "method"
+	AnalyticsKindAccount = "account"
+)
+
+// AnalyticsRow is one aggregated row of period, rows of method kind have counters per key, query type and hit type,
+// rows of account kind are top requested accounts. Table in clickhouse can be created as:
+//
+//	CREATE TABLE proxy_queries (time DateTime, kind LowCardinality(String), key_name LowCardinality(String),
+//	  method LowCardinality(String), hit_type LowCardinality(String), account String, queries UInt64, errors UInt64)
+//	ENGINE = MergeTree ORDER BY (time, kind, key_name)
+type AnalyticsRow struct {
+	Time    string `json:"time"`
+	Kind    string `json:"kind"`
+	KeyName string `json:"key_name"`
+	Method  string `json:"method"`
+	HitType string `json:"hit_type"`
+	Account string `json:"account"`
+	Queries uint64 `json:"queries"`
+	Errors  uint64 `json:"errors"`
+}
+
+type analyticsKey struct {
+	key     string
+	method  string
+	hitType string
+}
+
+type analyticsCounter struct {
+	queries uint64
+	errors  uint64
+}
+
+// QueryAnalytics aggregates statistics of client queries and exports them periodically
+// to clickhouse over http and to json lines files, which can be loaded to clickhouse or converted to parquet
+type QueryAnalytics struct {
+	interval    time.Duration
+	topAccounts int
+
+	clickhouseURL  string
+	clickhouseUser string
+	clickhousePass string
+	dir            string
+
+	client *http.Client
+
+	since    time.Time
+	methods  map[analyticsKey]*analyticsCounter
+	accounts map[string]uint64
+	mx       sync.Mutex
+
+	closed chan struct{}
+	done   chan struct{}
+}
+
+func NewQueryAnalytics(cfg config.AnalyticsConfig) (*QueryAnalytics, error) {
+	a := &QueryAnalytics{
+		interval:       time.Duration(cfg.IntervalSeconds) * time.Second,
+		topAccounts:    int(cfg.TopAccounts),
+		clickhouseUser: cfg.ClickHouseUser,
+		clickhousePass: cfg.ClickHousePassword,
+		dir:            cfg.Dir,
+		client:         &http.Client{Timeout: 30 * time.Second},
+		since:          time.Now(),
+		methods:        map[analyticsKey]*analyticsCounter{},
+		accounts:       map[string]uint64{},
+		closed:         make(chan struct{}),
+		done:           make(chan struct{}),
+	}
+	if a.interval == 0 {
+		a.interval = time.Minute
+	}
+
+	if cfg.ClickHouseURL != "" {
+		u, err := url.Parse(cfg.ClickHouseURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid clickhouse url: %w", err)
+		}
+
+		table := cfg.ClickHouseTable
+		if table == "" {
+			table = "proxy_queries"
+		}
+		q := u.Query()
+		q.Set("query", "INSERT INTO "+table+" FORMAT JSONEachRow")
+		u.RawQuery = q.Encode()
+		a.clickhouseURL = u.String()
+	}
+
+	if a.dir != "" {
+		if err := os.MkdirAll(a.dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create analytics dir: %w", err)
+		}
+	}
+
+	go a.worker()
+	return a, nil
+}
+
+// record counts finished query, it is called for every client query so only counters are touched
+func (a *QueryAnalytics) record(keyName string, query any, hitType string, resp tl.Serializable) {
+	k := analyticsKey{key: keyName, method: metrics.Global.TypeLabel(query), hitType: hitType}
+	_, failed := resp.(ton.LSError)
+	acc := analyticsAccount(query)
+
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	c := a.methods[k]
+	if c == nil {
+		c = &analyticsCounter{}
+		a.methods[k] = c
+	}
+	c.queries++
+	if failed {
+		c.errors++
+	}
+
+	if acc != "" {
+		if _, ok := a.accounts[acc]; ok || len(a.accounts) < maxAnalyticsAccounts {
+			a.accounts[acc]++
+		}
+	}
+}
+
+// analyticsAccount returns address of account which query is about, empty for other queries
+func analyticsAccount(query any) string {
+	var id *ton.AccountID
+	switch q := query.(type) {
+	case ton.GetAccountState:
+		id = &q.Account
+	case ton.RunSmcMethod:
+		id = &q.Account
+	case ton.GetTransactions:
+		id = q.AccID
+	case ton.GetOneTransaction:
+		id = q.AccID
+	}
+	if id == nil || len(id.ID) != 32 {
+		return ""
+	}
+	return address.NewAddress(0, byte(id.Workchain), id.ID).String()
+}
+
+func (a *QueryAnalytics) worker() {
+	defer close(a.done)
+
+	for {
+		select {
+		case <-a.closed:
+			a.export()
+			return
+		case <-time.After(a.interval):
+			a.export()
+		}
+	}
+}
+
+// rows takes counters of the current period and starts the next one
+func (a *QueryAnalytics) rows() []AnalyticsRow {
+	a.mx.Lock()
+	since, methods, accounts := a.since, a.methods, a.accounts
+	a.since = time.Now()
+	a.methods = map[analyticsKey]*analyticsCounter{}
+	a.accounts = map[string]uint64{}
+	a.mx.Unlock()
+
+	tm := since.UTC().Format("2006-01-02 15:04:05")
+	rows := make([]AnalyticsRow, 0, len(methods)+a.topAccounts)
+	for k, c := range methods {
+		rows = append(rows, AnalyticsRow{
+			Time:    tm,
+			Kind:    AnalyticsKindMethod,
+			KeyName: k.key,
+			Method:  k.method,
+			HitType: k.hitType,
+			Queries: c.queries,
+			Errors:  c.errors,
+		})
+	}
+
+	top := make([]string, 0, len(accounts))
+	for acc := range accounts {
+		top = append(top, acc)
+	}
+	sort.Slice(top, func(i, j int) bool {
+		return accounts[top[i]] > accounts[top[j]]
+	})
+	if len(top) > a.topAccounts {
+		top = top[:a.topAccounts]
+	}
+	for _, acc := range top {
+		rows = append(rows, AnalyticsRow{
+			Time:    tm,
+			Kind:    AnalyticsKindAccount,
+			Account: acc,
+			Queries: accounts[acc],
+		})
+	}
+	return rows
+}
+
+func (a *QueryAnalytics) export() {
+	rows := a.rows()
+	if len(rows) == 0 {
+		return
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, row := range rows {
+		if err := enc.Encode(row); err != nil {
+			log.Warn().Err(err).Msg("failed to encode analytics row")
+			return
+		}
+	}
+
+	if a.clickhouseURL != "" {
+		if err := a.sendClickHouse(buf.Bytes()); err != nil {
+			log.Warn().Err(err).Int("rows", len(rows)).Msg("failed to export analytics to clickhouse")
+			metrics.Global.AnalyticsExports.WithLabelValues("clickhouse", "failed").Add(1)
+		} else {
+			metrics.Global.AnalyticsExports.WithLabelValues("clickhouse", "ok").Add(1)
+		}
+	}
+
+	if a.dir != "" {
+		name := filepath.Join(a.dir, "queries-"+time.Now().UTC().Format("20060102T150405")+".jsonl")
+		if err := os.WriteFile(name, buf.Bytes(), 0644); err != nil {
+			log.Warn().Err(err).Str("file", name).Msg("failed to write analytics file")
+			metrics.Global.AnalyticsExports.WithLabelValues("file", "failed").Add(1)
+		} else {
+			metrics.Global.AnalyticsExports.WithLabelValues("file", "ok").Add(1)
+		}
+	}
+}
+
+func (a *QueryAnalytics) sendClickHouse(data []byte) error {
+	req, err := http.NewRequest(http.MethodPost, a.clickhouseURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if a.clickhouseUser != "" {
+		req.Header.Set("X-ClickHouse-User", a.clickhouseUser)
+		req.Header.Set("X-ClickHouse-Key", a.clickhousePass)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Close exports statistics of the last period
+func (a *QueryAnalytics) Close() {
+	close(a.closed)
+	<-a.done
+}
@@ -1,8 +1,10 @@
 package server
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"github.com/rs/zerolog/log"
 	"github.com/xssnick/tonutils-go/liteclient"
@@ -10,82 +12,899 @@ import (
 	"github.com/xssnick/tonutils-go/ton"
 	"github.com/xssnick/tonutils-liteserver-proxy/config"
 	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"golang.org/x/sync/singleflight"
 	"reflect"
+	"sync"
 	"sync/atomic"
 	"time"
 )
 
 type BalancerType string
 
+const backendDrainTimeout = 30 * time.Second
+
 const (
 	BalancerTypeRoundRobin = "round_robin"
 	BalancerTypeFailOver   = "fail_over"
-	// TODO: req hash balancer, ip/key balancer, weighted
+	BalancerTypeLatency    = "latency"
+	BalancerTypeWeighted   = "weighted"
+	// TODO: req hash balancer, ip/key balancer
 )
 
 type Backend struct {
 	Name   string
+	Addr   string
+	Key    []byte
 	Client *liteclient.ConnectionPool
 	Weight uint64
+	Tags   []string
+	Tier   uint32
+
+	maxInFlight int64
+	// another proxy instance, wait master of client is forwarded to it
+	chained bool
+
+	// Client is the first of clients, all of them are connected to the same node
+	clients  []*liteclient.ConnectionPool
+	clientRR uint32
+
+	// added from global config, not from static list
+	discovered bool
+
+	// config backend is connected with and address which its hostname was resolved to
+	cfg      config.BackendLiteserver
+	resolved string
+
+	inFlight int64
 
 	failsStreak uint64
 	lastRequest int64
 	lastSuccess int64
+
+	// set by active health checks
+	evicted       uint32
+	probeFails    uint32
+	lastSeenSeqno uint32
+
+	// unix time until which backend is out of rotation, set when its answers disagree with other backends in quorum mode
+	distrustedUntil int64
+
+	// exponentially weighted moving averages, stored as float64 bits
+	ewmaLatency   uint64
+	ewmaErrorRate uint64
+}
+
+// Balancer delivers queries to backend liteservers, BackendBalancer is the default implementation,
+// it can be replaced when proxy is embedded into another service
+type Balancer interface {
+	Query(ctx context.Context, payload tl.Serializable, result *tl.Serializable) error
+	// GetClient returns client of one of backends, used by cache to fetch data directly
+	GetClient() ton.LiteClient
+	// GetClientFor returns client of backend selected for account, the same one as for queries of clients
+	GetClientFor(acc *ton.AccountID) ton.LiteClient
+	// ObserveMasterSeqno is called by cache with each new master block
+	ObserveMasterSeqno(seqno uint32)
+	// ZeroState returns zero state of network, nil when it is not known yet
+	ZeroState() *ton.ZeroStateIDExt
+	HealthyBackends() int
+}
+
+// backendSet is immutable, it is replaced as a whole on membership changes
+type backendSet struct {
+	all   []*Backend
+	byTag map[string][]*Backend
 }
 
 type BackendBalancer struct {
-	backends []Backend
+	set      atomic.Pointer[backendSet]
+	setMx    sync.Mutex
+	selector BackendSelector
+	router   *RequestRouter
+
+	accountHashing bool
+
+	hedgeDelay time.Duration
 
-	balancerType BalancerType
-	counter      uint64
+	retryBudget    *RetryBudget
+	attemptTimeout time.Duration
+
+	quorum *quorumSettings
+	shadow *shadowPool
+
+	coalesce *singleflight.Group
+	fair     *fairQueue
+
+	zeroState *ton.ZeroStateIDExt
+
+	closed    chan struct{}
+	closeOnce sync.Once
 }
 
 func NewBackendBalancer(backends []config.BackendLiteserver, typ BalancerType) (*BackendBalancer, error) {
+	selector, err := NewBackendSelector(typ)
+	if err != nil {
+		return nil, err
+	}
+
 	var b BackendBalancer
-	b.balancerType = typ
+	b.selector = selector
+	b.closed = make(chan struct{})
+
+	var list []*Backend
 	for _, backend := range backends {
-		client := liteclient.NewConnectionPool()
-		if err := client.AddConnection(context.Background(), backend.Addr, base64.StdEncoding.EncodeToString(backend.Key)); err != nil {
+		be, err := connectBackend(context.Background(), backend)
+		if err != nil {
 			log.Error().Err(err).Str("backend", backend.Addr).Msg("failed to connect")
 			continue
 		}
 
-		b.backends = append(b.backends, Backend{
-			Name:   backend.Name,
-			Client: client,
-		})
+		list = append(list, be)
 		log.Info().Str("backend", backend.Addr).Msg("connected to backend")
 	}
 
-	if len(b.backends) == 0 {
+	if len(backends) > 0 && len(list) == 0 {
 		return nil, fmt.Errorf("no active backends")
 	}
+	b.set.Store(newBackendSet(list))
+
 	return &b, nil
 }
 
+func connectBackend(ctx context.Context, cfg config.BackendLiteserver) (*Backend, error) {
+	num := int(cfg.Connections)
+	if num == 0 {
+		num = 1
+	}
+
+	backend := &Backend{
+		Name:   cfg.NameOrAddr(),
+		Addr:   cfg.Addr,
+		Key:    cfg.Key,
+		Weight: cfg.Weight,
+		Tags:   cfg.Tags,
+		Tier:   cfg.Tier,
+
+		maxInFlight: int64(cfg.MaxInFlight),
+		chained:     cfg.Proxy,
+		cfg:         cfg,
+	}
+
+	addr, err := resolveBackendAddr(ctx, cfg.Addr)
+	if err != nil {
+		return nil, err
+	}
+	backend.resolved = addr
+
+	for i := 0; i < num; i++ {
+		client := liteclient.NewConnectionPool()
+		if err := client.AddConnection(ctx, addr, base64.StdEncoding.EncodeToString(cfg.Key)); err != nil {
+			backend.Stop()
+			return nil, err
+		}
+		backend.clients = append(backend.clients, client)
+	}
+	backend.Client = backend.clients[0]
+
+	return backend, nil
+}
+
+// Stop closes all connections of backend
+func (b *Backend) Stop() {
+	for _, client := range b.clients {
+		client.Stop()
+	}
+}
+
+func (b *Backend) nextClient() *liteclient.ConnectionPool {
+	if len(b.clients) <= 1 {
+		return b.Client
+	}
+	return b.clients[atomic.AddUint32(&b.clientRR, 1)%uint32(len(b.clients))]
+}
+
+func newBackendSet(list []*Backend) *backendSet {
+	byTag := map[string][]*Backend{}
+	for _, backend := range list {
+		tags := backend.Tags
+		if len(tags) == 0 {
+			tags = []string{BackendTagFast}
+		}
+		for _, tag := range tags {
+			byTag[tag] = append(byTag[tag], backend)
+		}
+	}
+	return &backendSet{all: list, byTag: byTag}
+}
+
+// Backends returns current list of backends
+func (b *BackendBalancer) Backends() []*Backend {
+	return b.set.Load().all
+}
+
+// AddBackend puts connected backend to rotation
+func (b *BackendBalancer) AddBackend(backend *Backend) error {
+	b.setMx.Lock()
+	defer b.setMx.Unlock()
+
+	cur := b.set.Load().all
+	for _, be := range cur {
+		if be.Name == backend.Name {
+			return fmt.Errorf("backend with name %s already exists", backend.Name)
+		}
+	}
+
+	list := make([]*Backend, 0, len(cur)+1)
+	list = append(list, cur...)
+	list = append(list, backend)
+	b.set.Store(newBackendSet(list))
+	return nil
+}
+
+// ReloadBackends synchronizes statically configured backends with the given list,
+// new are connected, missing are removed and drained, changed are replaced
+func (b *BackendBalancer) ReloadBackends(list []config.BackendLiteserver) error {
+	wanted := map[string]config.BackendLiteserver{}
+	for _, cfg := range list {
+		// unnamed backends are matched by address
+		name := cfg.NameOrAddr()
+		if _, ok := wanted[name]; ok {
+			return fmt.Errorf("duplicate backend name %s", name)
+		}
+		wanted[name] = cfg
+	}
+
+	var remove []string
+	for _, backend := range b.Backends() {
+		if backend.discovered {
+			continue
+		}
+
+		cfg, ok := wanted[backend.Name]
+		if ok && cfg.Addr == backend.Addr && bytes.Equal(cfg.Key, backend.Key) &&
+			cfg.Weight == backend.Weight && reflect.DeepEqual(cfg.Tags, backend.Tags) &&
+			cfg.Tier == backend.Tier && int64(cfg.MaxInFlight) == backend.maxInFlight && cfg.Proxy == backend.chained &&
+			(cfg.Connections == uint32(len(backend.clients)) || cfg.Connections == 0 && len(backend.clients) == 1) {
+			delete(wanted, backend.Name)
+			continue
+		}
+		remove = append(remove, backend.Name)
+	}
+
+	var add []*Backend
+	for _, cfg := range wanted {
+		backend, err := connectBackend(context.Background(), cfg)
+		if err != nil {
+			for _, a := range add {
+				a.Stop()
+			}
+			return fmt.Errorf("failed to connect backend %s: %w", cfg.NameOrAddr(), err)
+		}
+		add = append(add, backend)
+	}
+
+	removed, err := b.swapBackends(remove, add)
+	if err != nil {
+		for _, a := range add {
+			a.Stop()
+		}
+		return err
+	}
+
+	for _, backend := range add {
+		log.Info().Str("backend", backend.Addr).Str("name", backend.Name).Msg("backend added")
+	}
+	for _, backend := range removed {
+		log.Info().Str("backend", backend.Addr).Str("name", backend.Name).Msg("backend removed, draining")
+		go backend.drain(backendDrainTimeout)
+	}
+	return nil
+}
+
+// swapBackends atomically removes backends by names and adds new ones
+func (b *BackendBalancer) swapBackends(remove []string, add []*Backend) ([]*Backend, error) {
+	b.setMx.Lock()
+	defer b.setMx.Unlock()
+
+	toRemove := map[string]bool{}
+	for _, name := range remove {
+		toRemove[name] = true
+	}
+
+	var removed []*Backend
+	names := map[string]bool{}
+	list := make([]*Backend, 0, len(b.set.Load().all)+len(add))
+	for _, be := range b.set.Load().all {
+		if toRemove[be.Name] {
+			removed = append(removed, be)
+			continue
+		}
+		names[be.Name] = true
+		list = append(list, be)
+	}
+
+	for _, be := range add {
+		if names[be.Name] {
+			return nil, fmt.Errorf("backend with name %s already exists", be.Name)
+		}
+		names[be.Name] = true
+		list = append(list, be)
+	}
+
+	if len(list) == 0 {
+		return nil, fmt.Errorf("cannot remove the last backend")
+	}
+
+	b.set.Store(newBackendSet(list))
+	return removed, nil
+}
+
+// RemoveAndDrain takes backend out of rotation and closes its connections
+// after in-flight queries are completed or timeout is reached
+func (b *BackendBalancer) RemoveAndDrain(name string, timeout time.Duration) error {
+	backend, err := b.RemoveBackend(name)
+	if err != nil {
+		return err
+	}
+	log.Info().Str("backend", backend.Addr).Str("name", name).Msg("backend removed, draining")
+
+	go backend.drain(timeout)
+	return nil
+}
+
+func (b *Backend) drain(timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for atomic.LoadInt64(&b.inFlight) > 0 && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+	b.Stop()
+	log.Debug().Str("backend", b.Addr).Str("name", b.Name).Int64("in_flight", atomic.LoadInt64(&b.inFlight)).Msg("backend drained and stopped")
+}
+
+// RemoveBackend takes backend out of rotation, and returns it, last backend cannot be removed
+func (b *BackendBalancer) RemoveBackend(name string) (*Backend, error) {
+	b.setMx.Lock()
+	defer b.setMx.Unlock()
+
+	cur := b.set.Load().all
+
+	var removed *Backend
+	list := make([]*Backend, 0, len(cur))
+	for _, be := range cur {
+		if be.Name == name && removed == nil {
+			removed = be
+			continue
+		}
+		list = append(list, be)
+	}
+
+	if removed == nil {
+		return nil, fmt.Errorf("backend %s is not found", name)
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("cannot remove the last backend")
+	}
+
+	b.set.Store(newBackendSet(list))
+	return removed, nil
+}
+
+// SetRouter enables routing of requests to backends with tags required by request,
+// should be called before balancer usage
+func (b *BackendBalancer) SetRouter(router *RequestRouter) {
+	b.router = router
+}
+
+// candidates returns backends which are tagged to serve payload,
+// or all backends when there are no healthy tagged ones
+func (b *BackendBalancer) candidates(payload tl.Serializable) []*Backend {
+	tag := BackendTagFast
+	if b.router != nil && payload != nil {
+		tag = b.router.Tag(payload)
+	}
+
+	set := b.set.Load()
+	for _, backend := range set.byTag[tag] {
+		if backend.IsHealthy() {
+			return preferTier(set.byTag[tag])
+		}
+	}
+	return preferTier(set.all)
+}
+
+// preferTier leaves backends of the lowest tier which has healthy and not saturated backend,
+// so next tiers are used only when preferred ones are down or busy, all backends are returned when all are busy
+func preferTier(backends []*Backend) []*Backend {
+	var lowest, highest, best uint32
+	found := false
+	for i, backend := range backends {
+		if i == 0 || backend.Tier < lowest {
+			lowest = backend.Tier
+		}
+		if backend.Tier > highest {
+			highest = backend.Tier
+		}
+		if backend.IsHealthy() && !backend.saturated() && (!found || backend.Tier < best) {
+			best, found = backend.Tier, true
+		}
+	}
+	if !found || lowest == highest {
+		return backends
+	}
+	if best != lowest {
+		metrics.Global.BackendTierFallbacks.WithLabelValues(fmt.Sprint(best)).Add(1)
+	}
+
+	res := make([]*Backend, 0, len(backends))
+	for _, backend := range backends {
+		if backend.Tier == best {
+			res = append(res, backend)
+		}
+	}
+	return res
+}
+
+func (b *Backend) saturated() bool {
+	return b.maxInFlight > 0 && atomic.LoadInt64(&b.inFlight) >= b.maxInFlight
+}
+
+// EnableAccountHashing makes queries of accounts to prefer backend chosen by hash of address,
+// other queries are balanced by selector, should be called before balancer usage
+func (b *BackendBalancer) EnableAccountHashing() {
+	b.accountHashing = true
+}
+
+// selectFor returns backend which should serve payload first
+func (b *BackendBalancer) selectFor(backends []*Backend, payload tl.Serializable) *Backend {
+	if b.accountHashing {
+		if acc := requestAccount(payload); acc != nil {
+			if backend := selectByAccount(backends, acc); backend != nil {
+				return backend
+			}
+		}
+	}
+	return b.selector.Select(backends)
+}
+
+// SetSelector replaces backend selection strategy, should be called before balancer usage
+func (b *BackendBalancer) SetSelector(selector BackendSelector) {
+	b.selector = selector
+}
+
+// ObserveMasterSeqno passes the latest known master seqno to router, to detect requests for old blocks
+func (b *BackendBalancer) ObserveMasterSeqno(seqno uint32) {
+	if b.router != nil {
+		b.router.ObserveMasterSeqno(seqno)
+	}
+}
+
+// EnableHedging makes Query to send the same request to one more backend,
+// when the first one has not answered during delay, 0 disables it
+func (b *BackendBalancer) EnableHedging(delay time.Duration) {
+	b.hedgeDelay = delay
+}
+
+// EnableRetries makes Query to retry failed idempotent requests once on another backend,
+// first attempt is limited with attemptTimeout to leave time for retry
+func (b *BackendBalancer) EnableRetries(budget *RetryBudget, attemptTimeout time.Duration) {
+	b.retryBudget = budget
+	b.attemptTimeout = attemptTimeout
+}
+
 func (b *BackendBalancer) GetClient() ton.LiteClient {
-	switch b.balancerType {
-	case BalancerTypeFailOver:
-		for _, backend := range b.backends {
-			if atomic.LoadUint64(&backend.failsStreak) > 10 &&
-				atomic.LoadInt64(&backend.lastRequest)-atomic.LoadInt64(&backend.lastSuccess) > 5 {
-				// failed node
+	return b.wrapClient(b.selector.Select(b.candidates(nil)))
+}
+
+// GetClientFor returns client of backend which account is hashed to when account hashing is enabled,
+// so cache misses warm the same backend as queries of clients for this account
+func (b *BackendBalancer) GetClientFor(acc *ton.AccountID) ton.LiteClient {
+	backends := b.candidates(nil)
+
+	var backend *Backend
+	if b.accountHashing {
+		backend = selectByAccount(backends, acc)
+	}
+	if backend == nil {
+		backend = b.selector.Select(backends)
+	}
+	return b.wrapClient(backend)
+}
+
+// wrapClient applies quorum and fair queuing to direct queries of backend
+func (b *BackendBalancer) wrapClient(backend *Backend) ton.LiteClient {
+	if backend == nil {
+		return unavailableClient{}
+	}
+
+	var client ton.LiteClient = backend
+	if b.quorum != nil {
+		client = &quorumClient{balancer: b, primary: backend}
+	}
+	if b.fair != nil {
+		client = &fairClient{LiteClient: client, fair: b.fair}
+	}
+	return client
+}
+
+// EnableFairQueuing limits backend queries in flight to slots, when all of them are busy
+// keys get free slots in turn, so one key cannot take all backend connections
+func (b *BackendBalancer) EnableFairQueuing(slots int) {
+	b.fair = newFairQueue(slots)
+}
+
+// fairClient is returned to cache when fair queuing is enabled, so cache misses wait for slots too
+// unavailableClient is returned when there are no backends, its queries fail right away
+type unavailableClient struct{}
+
+func (unavailableClient) QueryLiteserver(context.Context, tl.Serializable, tl.Serializable) error {
+	return ErrBackendsUnavailable
+}
+
+func (unavailableClient) StickyContext(ctx context.Context) context.Context {
+	return ctx
+}
+
+func (unavailableClient) StickyContextNextNode(ctx context.Context) (context.Context, error) {
+	return ctx, ErrBackendsUnavailable
+}
+
+func (unavailableClient) StickyNodeID(context.Context) uint32 {
+	return 0
+}
+
+type fairClient struct {
+	ton.LiteClient
+	fair *fairQueue
+}
+
+func (c *fairClient) QueryLiteserver(ctx context.Context, payload tl.Serializable, result tl.Serializable) error {
+	if _, ok := payload.([]tl.Serializable); ok || isInternalQuery(ctx) {
+		// waiting for master block can take long, it should not hold the slot
+		return c.LiteClient.QueryLiteserver(ctx, payload, result)
+	}
+
+	if err := c.fair.acquire(ctx, keyNameFrom(ctx)); err != nil {
+		return err
+	}
+	defer c.fair.release()
+	return c.LiteClient.QueryLiteserver(ctx, payload, result)
+}
+
+type backendAnswer struct {
+	resp   tl.Serializable
+	err    error
+	hedged bool
+}
+
+// EnableCoalescing makes identical read queries, which are in flight at the same time, to be sent
+// to backend once with the answer shared by all callers
+func (b *BackendBalancer) EnableCoalescing() {
+	b.coalesce = &singleflight.Group{}
+}
+
+// Query sends request to backend selected by balancer, for idempotent requests
+// and enabled hedging it may also query a second backend and return the fastest answer,
+// when retries are enabled, transient failure is retried on another backend
+func (b *BackendBalancer) Query(ctx context.Context, payload tl.Serializable, result *tl.Serializable) error {
+	if _, ok := payload.([]tl.Serializable); !ok && b.fair != nil && !isInternalQuery(ctx) {
+		if err := b.fair.acquire(ctx, keyNameFrom(ctx)); err != nil {
+			return err
+		}
+		defer b.fair.release()
+	}
+
+	var err error
+	if b.coalesce != nil && isIdempotent(payload) {
+		err = b.queryCoalesced(ctx, payload, result)
+	} else {
+		err = b.query(ctx, payload, result)
+	}
+
+	if err == nil && b.shadow != nil {
+		b.shadow.mirror(payload, *result)
+	}
+	return err
+}
+
+func (b *BackendBalancer) queryCoalesced(ctx context.Context, payload tl.Serializable, result *tl.Serializable) error {
+	key, err := tl.Serialize(payload, true)
+	if err != nil {
+		return b.query(ctx, payload, result)
+	}
+
+	v, shared, err := coalesce(ctx, b.coalesce, string(key), func(ctx context.Context) (any, error) {
+		var resp tl.Serializable
+		err := b.query(ctx, payload, &resp)
+		return resp, err
+	})
+	if shared {
+		metrics.Global.CoalescedQueries.WithLabelValues(metrics.Global.TypeLabel(payload)).Add(1)
+	}
+	if err != nil {
+		return err
+	}
+	*result, _ = v.(tl.Serializable)
+	return nil
+}
+
+func (b *BackendBalancer) query(ctx context.Context, payload tl.Serializable, result *tl.Serializable) error {
+	if b.retryBudget != nil {
+		b.retryBudget.Deposit()
+	}
+
+	backends := b.candidates(payload)
+	first := b.selectFor(backends, payload)
+	if first == nil {
+		return ErrBackendsUnavailable
+	}
+	if b.quorum != nil && b.quorum.critical(payload) {
+		return b.queryQuorum(ctx, backends, first, payload, result)
+	}
+	if b.hedgeDelay > 0 && isIdempotent(payload) {
+		return b.queryHedged(ctx, backends, first, payload, result)
+	}
+
+	attemptCtx := ctx
+	if b.retryBudget != nil && b.attemptTimeout > 0 {
+		var cancel func()
+		attemptCtx, cancel = context.WithTimeout(ctx, b.attemptTimeout)
+		defer cancel()
+	}
+
+	err := first.QueryLiteserver(attemptCtx, payload, result)
+	if err == nil || !b.shouldRetry(ctx, payload, err) {
+		return err
+	}
+
+	second := b.pickOther(backends, first)
+	if second == nil {
+		return err
+	}
+	log.Ctx(ctx).Debug().Err(err).Str("backend", second.Name).Type("request", payload).Msg("retrying request on another backend")
+
+	return second.QueryLiteserver(ctx, payload, result)
+}
+
+func (b *BackendBalancer) shouldRetry(ctx context.Context, payload tl.Serializable, err error) bool {
+	if b.retryBudget == nil || ctx.Err() != nil || !isIdempotent(payload) {
+		return false
+	}
+
+	if _, ok := err.(ton.LSError); ok {
+		// answer from node, not a transient failure
+		return false
+	}
+
+	if !b.retryBudget.TryWithdraw() {
+		metrics.Global.BackendRetries.WithLabelValues("budget_exhausted").Add(1)
+		return false
+	}
+	metrics.Global.BackendRetries.WithLabelValues("retried").Add(1)
+	return true
+}
+
+func (b *BackendBalancer) queryHedged(ctx context.Context, backends []*Backend, first *Backend, payload tl.Serializable, result *tl.Serializable) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	answers := make(chan backendAnswer, 2)
+	run := func(backend *Backend, hedged bool) {
+		var resp tl.Serializable
+		err := backend.QueryLiteserver(ctx, payload, &resp)
+		answers <- backendAnswer{resp: resp, err: err, hedged: hedged}
+	}
+
+	go run(first, false)
+	inFlight := 1
+
+	timer := time.NewTimer(b.hedgeDelay)
+	defer timer.Stop()
+
+	hedgeSent := false
+	for {
+		select {
+		case <-timer.C:
+			hedgeSent = true
+			second := b.pickOther(backends, first)
+			if second == nil {
+				continue
+			}
+			log.Ctx(ctx).Debug().Str("backend", second.Name).Type("request", payload).Msg("sending hedged request")
+			go run(second, true)
+			inFlight++
+		case a := <-answers:
+			inFlight--
+			if a.err == nil {
+				if hedgeSent {
+					metrics.Global.HedgedRequests.WithLabelValues(fmt.Sprint(a.hedged)).Add(1)
+				}
+				*result = a.resp
+				return nil
+			}
+
+			if inFlight > 0 {
+				// another one may still answer
 				continue
 			}
-			return &backend
+			if hedgeSent || !b.shouldRetry(ctx, payload, a.err) {
+				return a.err
+			}
+
+			// first failed before hedge delay, try another backend right away
+			timer.Stop()
+			hedgeSent = true
+			second := b.pickOther(backends, first)
+			if second == nil {
+				return a.err
+			}
+			go run(second, true)
+			inFlight++
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (b *BackendBalancer) pickOther(backends []*Backend, exclude *Backend) *Backend {
+	for i := 0; i < len(backends); i++ {
+		if backend := b.selector.Select(backends); backend != exclude {
+			return backend
+		}
+	}
+
+	// selector prefers excluded backend, so take any other healthy
+	for _, backend := range backends {
+		if backend != exclude && backend.IsHealthy() {
+			return backend
+		}
+	}
+	return nil
+}
+
+func isIdempotent(payload tl.Serializable) bool {
+	switch v := payload.(type) {
+	case ton.SendMessage:
+		return false
+	case []tl.Serializable:
+		for _, p := range v {
+			if !isIdempotent(p) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// HealthyBackends returns the number of backends which are not considered failed
+func (b *BackendBalancer) HealthyBackends() int {
+	num := 0
+	for _, backend := range b.Backends() {
+		if backend.IsHealthy() {
+			num++
+		}
+	}
+	return num
+}
+
+func (b *Backend) IsHealthy() bool {
+	if atomic.LoadUint32(&b.evicted) == 1 || atomic.LoadInt64(&b.distrustedUntil) > time.Now().Unix() {
+		return false
+	}
+	return atomic.LoadUint64(&b.failsStreak) <= 10 ||
+		atomic.LoadInt64(&b.lastRequest)-atomic.LoadInt64(&b.lastSuccess) <= 5
+}
+
+// StartHealthChecks runs periodic probes of all backends, backends which are failing
+// or lagging behind others are removed from rotation until they recover
+func (b *BackendBalancer) StartHealthChecks(cfg config.BackendHealthCheckConfig) {
+	if cfg.IntervalSeconds == 0 {
+		return
+	}
+
+	timeout := time.Duration(cfg.TimeoutSeconds) * time.Second
+	if timeout == 0 {
+		timeout = 3 * time.Second
+	}
+
+	go func() {
+		for {
+			b.checkBackends(timeout, cfg.MaxSeqnoLag, cfg.FailuresToEvict)
+
+			select {
+			case <-b.closed:
+				return
+			case <-time.After(time.Duration(cfg.IntervalSeconds) * time.Second):
+			}
+		}
+	}()
+}
+
+// Close stops background checks and disconnects from all backends
+func (b *BackendBalancer) Close() {
+	b.closeOnce.Do(func() {
+		close(b.closed)
+
+		b.setMx.Lock()
+		defer b.setMx.Unlock()
+
+		for _, backend := range b.set.Load().all {
+			backend.Stop()
 		}
+		b.set.Store(newBackendSet(nil))
+
+		if b.shadow != nil {
+			b.shadow.pool.Close()
+		}
+	})
+}
 
-		// all nodes failed over switch to round-robin, and maybe it will become alive
-		fallthrough
-	case BalancerTypeRoundRobin:
-		x := atomic.AddUint64(&b.counter, 1)
-		return &b.backends[x%uint64(len(b.backends))]
-	default:
-		panic("unknown balancer type:" + b.balancerType)
+func (b *BackendBalancer) checkBackends(timeout time.Duration, maxLag, failuresToEvict uint32) {
+	backends := b.Backends()
+
+	var wg sync.WaitGroup
+	for _, backend := range backends {
+		wg.Add(1)
+		go func(backend *Backend) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			inf, err := getMasterchainInfo(ctx, backend, 0)
+			if err != nil {
+				log.Debug().Err(err).Str("backend", backend.Name).Msg("backend health probe failed")
+				atomic.AddUint32(&backend.probeFails, 1)
+				return
+			}
+			atomic.StoreUint32(&backend.probeFails, 0)
+			atomic.StoreUint32(&backend.lastSeenSeqno, inf.Last.SeqNo)
+		}(backend)
 	}
+	wg.Wait()
+
+	var topSeqno uint32
+	for _, backend := range backends {
+		if seqno := atomic.LoadUint32(&backend.lastSeenSeqno); seqno > topSeqno {
+			topSeqno = seqno
+		}
+	}
+	b.ObserveMasterSeqno(topSeqno)
+
+	for _, backend := range backends {
+		reason := ""
+		if fails := atomic.LoadUint32(&backend.probeFails); fails > 0 && fails >= failuresToEvict {
+			reason = "probe failed"
+		} else if fails == 0 && maxLag > 0 && atomic.LoadUint32(&backend.lastSeenSeqno)+maxLag < topSeqno {
+			reason = "lagging"
+		}
+
+		if reason != "" {
+			if atomic.CompareAndSwapUint32(&backend.evicted, 0, 1) {
+				log.Warn().Str("backend", backend.Name).Str("reason", reason).
+					Uint32("seqno", atomic.LoadUint32(&backend.lastSeenSeqno)).Uint32("top_seqno", topSeqno).
+					Msg("backend evicted from rotation")
+				metrics.Global.BackendEvictions.WithLabelValues(backend.Name, reason).Add(1)
+			}
+		} else if atomic.LoadUint32(&backend.probeFails) == 0 && atomic.CompareAndSwapUint32(&backend.evicted, 1, 0) {
+			log.Info().Str("backend", backend.Name).Msg("backend recovered and returned to rotation")
+		}
+
+		healthy := 1.0
+		if !backend.IsHealthy() {
+			healthy = 0
+		}
+		metrics.Global.BackendHealthy.WithLabelValues(backend.Name).Set(healthy)
+	}
+}
+
+func (b *Backend) QueryLiteserver(ctx context.Context, payload tl.Serializable, result tl.Serializable) error {
+	return b.queryClient(ctx, b.nextClient(), payload, result)
 }
 
-func (b *Backend) QueryLiteserver(ctx context.Context, payload tl.Serializable, result tl.Serializable) (err error) {
+// queryClient sends query over given connection of backend
+func (b *Backend) queryClient(ctx context.Context, client *liteclient.ConnectionPool, payload tl.Serializable, result tl.Serializable) (err error) {
+	atomic.AddInt64(&b.inFlight, 1)
+	defer atomic.AddInt64(&b.inFlight, -1)
+
 	tm := time.Now()
 	defer func() {
 		if _, ok := payload.([]tl.Serializable); ok {
@@ -93,11 +912,21 @@ func (b *Backend) QueryLiteserver(ctx context.Context, payload tl.Serializable,
 			return
 		}
 
+		if err != nil && errors.Is(err, context.Canceled) {
+			// canceled by us, for example when hedged request is already answered
+			metrics.Global.BackendQueries.WithLabelValues(b.Name, metrics.Global.TypeLabel(payload), "canceled").Observe(time.Since(tm).Seconds())
+			return
+		}
+
 		atomic.StoreInt64(&b.lastRequest, time.Now().Unix())
 		status := "ok"
+		took := time.Since(tm)
 		if err != nil {
-			atomic.AddUint64(&b.failsStreak, 1)
+			streak := atomic.AddUint64(&b.failsStreak, 1)
 			status = "failed"
+			if r := reporter.Load(); r != nil {
+				r.BackendFailed(b.Name, streak, err)
+			}
 		} else if _, ok := result.(ton.LSError); ok {
 			atomic.AddUint64(&b.failsStreak, 1)
 			status = "ls_error"
@@ -106,7 +935,11 @@ func (b *Backend) QueryLiteserver(ctx context.Context, payload tl.Serializable,
 			atomic.StoreInt64(&b.lastSuccess, atomic.LoadInt64(&b.lastRequest))
 		}
 
-		metrics.Global.BackendQueries.WithLabelValues(b.Name, reflect.TypeOf(payload).String(), status).Observe(time.Since(tm).Seconds())
+		b.observe(took, status != "ok")
+		if status != "ok" {
+			log.Ctx(ctx).Debug().Err(err).Str("backend", b.Name).Type("request", payload).Str("status", status).Msg("backend query failed")
+		}
+		metrics.Global.BackendQueries.WithLabelValues(b.Name, metrics.Global.TypeLabel(payload), status).Observe(took.Seconds())
 	}()
 
 	if dl, ok := ctx.Deadline(); !ok || dl.After(time.Now().Add(10*time.Second)) {
@@ -115,7 +948,13 @@ func (b *Backend) QueryLiteserver(ctx context.Context, payload tl.Serializable,
 		defer cancel()
 	}
 
-	if err = b.Client.QueryLiteserver(ctx, payload, result); err != nil {
+	wire := b.chainedPayload(ctx, payload)
+	if data := rawPayloadFrom(ctx); data != nil {
+		// raw passthrough, query goes as client sent it
+		wire = tl.Raw(data)
+	}
+
+	if err = client.QueryLiteserver(ctx, wire, result); err != nil {
 		return err
 	}
 	return nil
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"github.com/xssnick/tonutils-go/liteclient"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"testing"
+)
+
+func newTestBalancer(accountHashing bool, backends ...*Backend) *BackendBalancer {
+	b := &BackendBalancer{
+		selector:       &RoundRobinSelector{},
+		accountHashing: accountHashing,
+		closed:         make(chan struct{}),
+	}
+	b.set.Store(newBackendSet(backends))
+	return b
+}
+
+func TestGetClientForAccount(t *testing.T) {
+	backends := []*Backend{{Name: "a", Addr: "1.1.1.1:1"}, {Name: "b", Addr: "2.2.2.2:2"}, {Name: "c", Addr: "3.3.3.3:3"}}
+	b := newTestBalancer(true, backends...)
+
+	for i := 0; i < 16; i++ {
+		acc := &ton.AccountID{Workchain: 0, ID: make([]byte, 32)}
+		acc.ID[0] = byte(i)
+
+		want := selectByAccount(backends, acc)
+		// the same backend is expected on every call, round robin would move it
+		for j := 0; j < 3; j++ {
+			if got := b.GetClientFor(acc).(*Backend); got != want {
+				t.Fatalf("account %d: expected backend %s, got %s", i, want.Name, got.Name)
+			}
+		}
+	}
+}
+
+func TestReloadUnnamedBackends(t *testing.T) {
+	key := make([]byte, 32)
+	list := []config.BackendLiteserver{{Addr: "1.1.1.1:1", Key: key}, {Addr: "2.2.2.2:2", Key: key}}
+
+	var backends []*Backend
+	for _, cfg := range list {
+		backends = append(backends, &Backend{Name: cfg.NameOrAddr(), Addr: cfg.Addr, Key: key, clients: []*liteclient.ConnectionPool{nil}})
+	}
+	b := newTestBalancer(false, backends...)
+
+	// unchanged backends are matched by address, nothing is connected or removed
+	if err := b.ReloadBackends(list); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.Backends(); len(got) != 2 || got[0] != backends[0] || got[1] != backends[1] {
+		t.Fatalf("backends are replaced: %v", got)
+	}
+}
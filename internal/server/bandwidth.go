@@ -0,0 +1,83 @@
+package server
+
+import (
+	"sync"
+	"time"
+)
+
+// bandwidthQuota limits bytes of responses sent to client key during period, large answers like blocks and states
+// cost much more egress than their count suggests, so they are limited separately from requests
+type bandwidthQuota struct {
+	max    uint64
+	period time.Duration
+
+	used  uint64
+	start time.Time
+
+	mx sync.Mutex
+}
+
+func newBandwidthQuota(max uint64, period time.Duration) *bandwidthQuota {
+	return &bandwidthQuota{
+		max:    max,
+		period: period,
+		start:  time.Now(),
+	}
+}
+
+// available reports if quota is not used up yet, otherwise time until the next period is returned
+func (q *bandwidthQuota) available() (time.Duration, bool) {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	q.rotate()
+	if q.used < q.max {
+		return 0, true
+	}
+	return time.Until(q.start.Add(q.period)), false
+}
+
+// add counts sent bytes, response which is already prepared is sent anyway, so quota can be slightly exceeded
+func (q *bandwidthQuota) add(n int) {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	q.rotate()
+	q.used += uint64(n)
+}
+
+func (q *bandwidthQuota) usage() (used, max uint64) {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	q.rotate()
+	return q.used, q.max
+}
+
+func (q *bandwidthQuota) rotate() {
+	if since := time.Since(q.start); since >= q.period {
+		q.start = q.start.Add(since - since%q.period)
+		q.used = 0
+	}
+}
+
+// state returns used bytes and start of the current period
+func (q *bandwidthQuota) state() (uint64, time.Time) {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	q.rotate()
+	return q.used, q.start
+}
+
+// restore continues period saved by previous process, it is skipped when the period is already over
+func (q *bandwidthQuota) restore(used uint64, start time.Time) {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	if time.Since(start) >= q.period || start.After(time.Now()) {
+		return
+	}
+	q.start = start
+	q.used += used
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	BanKindIP  = "ip"
+	BanKindKey = "key"
+)
+
+type BanInfo struct {
+	Kind      string `json:"kind"`
+	Value     string `json:"value"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// Ban rejects connections from ip or queries of client key until ttl is passed,
+// already connected clients are disconnected, their number is returned
+func (s *ProxyBalancer) Ban(kind, value string, ttl time.Duration) int {
+	if kind == BanKindIP {
+		value = normalizeIP(value)
+	}
+
+	s.bansMx.Lock()
+	s.bans[kind+":"+value] = time.Now().Add(ttl)
+	s.bansMx.Unlock()
+
+	if kind == BanKindIP {
+		return s.Kick(value, 0)
+	}
+	return s.kickKey(value)
+}
+
+// Unban removes ban, false is returned when there is no such
+func (s *ProxyBalancer) Unban(kind, value string) bool {
+	if kind == BanKindIP {
+		value = normalizeIP(value)
+	}
+
+	s.bansMx.Lock()
+	defer s.bansMx.Unlock()
+
+	k := kind + ":" + value
+	if _, ok := s.bans[k]; !ok {
+		return false
+	}
+	delete(s.bans, k)
+	return true
+}
+
+// Bans returns active bans, expired ones are dropped
+func (s *ProxyBalancer) Bans() []BanInfo {
+	s.bansMx.Lock()
+	defer s.bansMx.Unlock()
+
+	now := time.Now()
+	list := make([]BanInfo, 0, len(s.bans))
+	for k, till := range s.bans {
+		if now.After(till) {
+			delete(s.bans, k)
+			continue
+		}
+
+		kind, value, _ := strings.Cut(k, ":")
+		list = append(list, BanInfo{Kind: kind, Value: value, ExpiresAt: till.Unix()})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ExpiresAt < list[j].ExpiresAt
+	})
+	return list
+}
+
+func (s *ProxyBalancer) isBanned(kind, value string) bool {
+	s.bansMx.Lock()
+	defer s.bansMx.Unlock()
+
+	if len(s.bans) == 0 {
+		return false
+	}
+
+	k := kind + ":" + value
+	till, ok := s.bans[k]
+	if ok && time.Now().After(till) {
+		delete(s.bans, k)
+		return false
+	}
+	return ok
+}
+
+// Kick disconnects client connection by ip and port, all connections of ip are disconnected when port is 0,
+// number of disconnected clients is returned
+func (s *ProxyBalancer) Kick(ip string, port uint16) int {
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	info := s.ips[normalizeIP(ip)]
+	if info == nil {
+		return 0
+	}
+
+	n := 0
+	for p, conn := range info.ActiveConnections {
+		if port == 0 || p == port {
+			conn.Client.Close()
+			n++
+		}
+	}
+	return n
+}
+
+func (s *ProxyBalancer) kickKey(name string) int {
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	n := 0
+	for _, info := range s.ips {
+		for _, conn := range info.ActiveConnections {
+			if key := conn.key.Load(); key != nil && key.name == name {
+				conn.Client.Close()
+				n++
+			}
+		}
+	}
+	return n
+}
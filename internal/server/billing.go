@@ -0,0 +1,254 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// max number of events kept for resend when sink is unavailable, older are dropped
+const maxPendingBillingEvents = 10000
+
+// BillingEvent is usage of client key during period, events of the same key never overlap
+type BillingEvent struct {
+	Key           string            `json:"key"`
+	From          int64             `json:"from"`
+	To            int64             `json:"to"`
+	Queries       uint64            `json:"queries"`
+	Rejected      uint64            `json:"rejected"`
+	Errors        uint64            `json:"errors"`
+	Cost          int64             `json:"cost"`
+	ResponseBytes uint64            `json:"response_bytes"`
+	Gas           int64             `json:"gas"`
+	Methods       map[string]uint64 `json:"methods"`
+}
+
+// BillingExporter aggregates usage of each client key and sends it to sink at the end of every period,
+// events which failed to send are resent with the next period, so usage is not lost while sink is down
+type BillingExporter struct {
+	sink     EventSink
+	interval time.Duration
+
+	from    time.Time
+	usage   map[string]*BillingEvent
+	pending []*BillingEvent
+	mx      sync.Mutex
+
+	closed chan struct{}
+	done   chan struct{}
+}
+
+// NewBillingSink creates sink of type selected in config, nil is returned when billing export is disabled
+func NewBillingSink(cfg config.BillingConfig) (EventSink, error) {
+	switch cfg.Type {
+	case "", "none":
+		return nil, nil
+	case "webhook":
+		return newWebhookSink(cfg.WebhookURL, cfg.WebhookToken), nil
+	case "file":
+		return newFileSink(cfg.Path)
+	case "kafka":
+		return NewKafkaSink(cfg.Kafka)
+	case "nats":
+		return NewNATSSink(cfg.NATS)
+	}
+	return nil, fmt.Errorf("unknown billing export type %s", cfg.Type)
+}
+
+func NewBillingExporter(sink EventSink, cfg config.BillingConfig) *BillingExporter {
+	b := &BillingExporter{
+		sink:     sink,
+		interval: time.Duration(cfg.IntervalSeconds) * time.Second,
+		from:     time.Now(),
+		usage:    map[string]*BillingEvent{},
+		closed:   make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if b.interval == 0 {
+		b.interval = time.Minute
+	}
+
+	go b.worker()
+	return b
+}
+
+// event returns usage of key in the current period, mx should be held
+func (b *BillingExporter) event(keyName string) *BillingEvent {
+	ev := b.usage[keyName]
+	if ev == nil {
+		ev = &BillingEvent{Key: keyName, Methods: map[string]uint64{}}
+		b.usage[keyName] = ev
+	}
+	return ev
+}
+
+// record counts answer sent to client, queries rejected by limits are counted separately and cost nothing
+func (b *BillingExporter) record(keyName string, query any, resp tl.Serializable, size int) {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	ev := b.event(keyName)
+	ev.Queries++
+	ev.ResponseBytes += uint64(size)
+	if ls, ok := resp.(ton.LSError); ok {
+		if ls.Code == 429 {
+			ev.Rejected++
+			return
+		}
+		ev.Errors++
+	}
+	ev.Cost += queryCost(query)
+	ev.Methods[metrics.Global.TypeLabel(query)]++
+}
+
+// addGas counts gas of get method emulated for key
+func (b *BillingExporter) addGas(keyName string, gas int64) {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	b.event(keyName).Gas += gas
+}
+
+func (b *BillingExporter) worker() {
+	defer close(b.done)
+
+	for {
+		select {
+		case <-b.closed:
+			b.flush()
+			return
+		case <-time.After(b.interval):
+			b.flush()
+		}
+	}
+}
+
+func (b *BillingExporter) flush() {
+	now := time.Now()
+
+	b.mx.Lock()
+	events := b.pending
+	for _, ev := range b.usage {
+		ev.From, ev.To = b.from.Unix(), now.Unix()
+		events = append(events, ev)
+	}
+	b.from = now
+	b.usage = map[string]*BillingEvent{}
+	b.pending = nil
+	b.mx.Unlock()
+
+	var failed []*BillingEvent
+	for i, ev := range events {
+		data, err := json.Marshal(ev)
+		if err != nil {
+			log.Warn().Err(err).Str("key", ev.Key).Msg("failed to serialize billing event")
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err = b.sink.Publish(ctx, ev.Key, data)
+		cancel()
+		if err != nil {
+			log.Warn().Err(err).Int("events", len(events)-i).Msg("failed to send billing events, they will be resent")
+			metrics.Global.BillingEvents.WithLabelValues("failed").Add(float64(len(events) - i))
+			// sink is down, there is no reason to try the rest now
+			failed = events[i:]
+			break
+		}
+		metrics.Global.BillingEvents.WithLabelValues("sent").Add(1)
+	}
+
+	if len(failed) > 0 {
+		b.mx.Lock()
+		b.pending = append(failed, b.pending...)
+		if n := len(b.pending) - maxPendingBillingEvents; n > 0 {
+			metrics.Global.BillingEvents.WithLabelValues("dropped").Add(float64(n))
+			b.pending = b.pending[n:]
+		}
+		b.mx.Unlock()
+	}
+}
+
+// Close sends usage of the last period and closes sink
+func (b *BillingExporter) Close() error {
+	close(b.closed)
+	<-b.done
+	return b.sink.Close()
+}
+
+// webhookSink posts each event as json to url
+type webhookSink struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func newWebhookSink(url, token string) *webhookSink {
+	return &webhookSink{
+		url:    url,
+		token:  token,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (w *webhookSink) Publish(ctx context.Context, _ string, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.token != "" {
+		req.Header.Set("Authorization", "Bearer "+w.token)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (w *webhookSink) Close() error {
+	return nil
+}
+
+// fileSink appends events to file as json lines
+type fileSink struct {
+	file *os.File
+	mx   sync.Mutex
+}
+
+func newFileSink(path string) (*fileSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &fileSink{file: f}, nil
+}
+
+func (f *fileSink) Publish(_ context.Context, _ string, data []byte) error {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	_, err := f.file.Write(append(data, '\n'))
+	return err
+}
+
+func (f *fileSink) Close() error {
+	return f.file.Close()
+}
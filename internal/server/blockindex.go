@@ -0,0 +1,376 @@
+package server
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// size of record header: payload length, workchain, shard, seqno, start and end lt
+const blockIndexHeaderSize = 4 + 4 + 8 + 4 + 8 + 8
+
+// BlockIndex keeps ids and header proofs of blocks ingested by cache in append only file,
+// so lookups by seqno and lt are answered locally after restart, without archive backends.
+// When maxBlocks is set, the oldest blocks by lt are dropped and file is rewritten
+// without them when they take more than half of it
+type BlockIndex struct {
+	path      string
+	file      *os.File
+	size      int64
+	shards    map[string][]blockIndexEntry
+	maxBlocks int
+	// number and bytes of records which are still referenced by shards
+	blocks int
+	live   int64
+	closed bool
+
+	mx sync.RWMutex
+}
+
+type blockIndexEntry struct {
+	seqno   uint32
+	startLt uint64
+	endLt   uint64
+	offset  int64
+	length  uint32
+}
+
+// OpenBlockIndex loads index from file, incomplete record at the end, left by crash, is cut,
+// maxBlocks limits number of indexed blocks of all shards, 0 keeps all of them
+func OpenBlockIndex(path string, maxBlocks int) (*BlockIndex, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open block index: %w", err)
+	}
+
+	idx := &BlockIndex{
+		path:      path,
+		file:      f,
+		shards:    map[string][]blockIndexEntry{},
+		maxBlocks: maxBlocks,
+	}
+
+	r := bufio.NewReaderSize(f, 1<<20)
+	hdr := make([]byte, blockIndexHeaderSize)
+	for {
+		if _, err = io.ReadFull(r, hdr); err != nil {
+			break
+		}
+
+		length := binary.BigEndian.Uint32(hdr)
+		if _, err = r.Discard(int(length)); err != nil {
+			break
+		}
+
+		wc := int32(binary.BigEndian.Uint32(hdr[4:]))
+		shard := int64(binary.BigEndian.Uint64(hdr[8:]))
+		if idx.insert(getShardKey(wc, shard), blockIndexEntry{
+			seqno:   binary.BigEndian.Uint32(hdr[16:]),
+			startLt: binary.BigEndian.Uint64(hdr[20:]),
+			endLt:   binary.BigEndian.Uint64(hdr[28:]),
+			offset:  idx.size + blockIndexHeaderSize,
+			length:  length,
+		}) {
+			idx.blocks++
+			idx.live += blockIndexHeaderSize + int64(length)
+		}
+		idx.size += blockIndexHeaderSize + int64(length)
+	}
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to read block index: %w", err)
+	}
+
+	if err = f.Truncate(idx.size); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to cut incomplete block index record: %w", err)
+	}
+
+	// limit could be lowered since the last run
+	idx.prune(idx.maxBlocks)
+	if err = idx.compactIfNeeded(); err != nil {
+		_ = idx.file.Close()
+		return nil, err
+	}
+
+	log.Info().Str("path", path).Int("blocks", idx.blocks).Int("shards", len(idx.shards)).Msg("block index loaded")
+	return idx, nil
+}
+
+// insert keeps entries sorted by seqno, blocks are mostly added in order
+func (idx *BlockIndex) insert(shardKey string, e blockIndexEntry) bool {
+	list := idx.shards[shardKey]
+	i := sort.Search(len(list), func(i int) bool {
+		return list[i].seqno >= e.seqno
+	})
+	if i < len(list) && list[i].seqno == e.seqno {
+		return false
+	}
+
+	list = append(list, blockIndexEntry{})
+	copy(list[i+1:], list[i:])
+	list[i] = e
+	idx.shards[shardKey] = list
+	return true
+}
+
+// Add appends block with its header proof, false is returned when block is already indexed
+func (idx *BlockIndex) Add(hdr *ton.BlockHeader, startLt, endLt uint64) (bool, error) {
+	payload, err := tl.Serialize(hdr, true)
+	if err != nil {
+		return false, err
+	}
+
+	rec := make([]byte, blockIndexHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(rec, uint32(len(payload)))
+	binary.BigEndian.PutUint32(rec[4:], uint32(hdr.ID.Workchain))
+	binary.BigEndian.PutUint64(rec[8:], uint64(hdr.ID.Shard))
+	binary.BigEndian.PutUint32(rec[16:], hdr.ID.SeqNo)
+	binary.BigEndian.PutUint64(rec[20:], startLt)
+	binary.BigEndian.PutUint64(rec[28:], endLt)
+	copy(rec[blockIndexHeaderSize:], payload)
+
+	idx.mx.Lock()
+	defer idx.mx.Unlock()
+
+	if idx.closed {
+		return false, fmt.Errorf("block index is closed")
+	}
+
+	e := blockIndexEntry{
+		seqno:   hdr.ID.SeqNo,
+		startLt: startLt,
+		endLt:   endLt,
+		offset:  idx.size + blockIndexHeaderSize,
+		length:  uint32(len(payload)),
+	}
+	if !idx.insert(getShardKey(hdr.ID.Workchain, hdr.ID.Shard), e) {
+		return false, nil
+	}
+
+	if _, err = idx.file.WriteAt(rec, idx.size); err != nil {
+		// entry points to data which is not written, file is cut to size on next open
+		idx.remove(getShardKey(hdr.ID.Workchain, hdr.ID.Shard), e.seqno)
+		return false, fmt.Errorf("failed to write block index: %w", err)
+	}
+	idx.size += int64(len(rec))
+	idx.blocks++
+	idx.live += int64(len(rec))
+
+	// blocks are dropped in batches, so sorting of all of them is not done on every add
+	if idx.maxBlocks > 0 && idx.blocks > idx.maxBlocks+idx.maxBlocks/10 {
+		idx.prune(idx.maxBlocks)
+		if err = idx.compactIfNeeded(); err != nil {
+			log.Warn().Err(err).Msg("failed to compact block index")
+		}
+	}
+	return true, nil
+}
+
+// prune drops the oldest blocks by lt until max is left, lists of shards which
+// have no blocks left, like ones before split or merge, are removed too
+func (idx *BlockIndex) prune(max int) {
+	if max <= 0 || idx.blocks <= max {
+		return
+	}
+
+	lts := make([]uint64, 0, idx.blocks)
+	for _, list := range idx.shards {
+		for _, e := range list {
+			lts = append(lts, e.endLt)
+		}
+	}
+	sort.Slice(lts, func(i, j int) bool {
+		return lts[i] < lts[j]
+	})
+	minLt := lts[len(lts)-max]
+
+	for shardKey, list := range idx.shards {
+		// entries are sorted by seqno, so lt grows too
+		i := sort.Search(len(list), func(i int) bool {
+			return list[i].endLt >= minLt
+		})
+		for _, e := range list[:i] {
+			idx.blocks--
+			idx.live -= blockIndexHeaderSize + int64(e.length)
+		}
+		if i == len(list) {
+			delete(idx.shards, shardKey)
+			continue
+		}
+		idx.shards[shardKey] = append([]blockIndexEntry{}, list[i:]...)
+	}
+}
+
+// compactIfNeeded rewrites file with only live records when dropped ones take more than half of it,
+// new file replaces old one by rename, so crash during compaction keeps the old file
+func (idx *BlockIndex) compactIfNeeded() error {
+	if idx.size <= 2*idx.live {
+		return nil
+	}
+
+	tmpPath := idx.path + ".tmp"
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_RDWR, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create compacted block index: %w", err)
+	}
+
+	shards := make(map[string][]blockIndexEntry, len(idx.shards))
+	w := bufio.NewWriterSize(f, 1<<20)
+	var size int64
+	for shardKey, list := range idx.shards {
+		moved := make([]blockIndexEntry, len(list))
+		for i, e := range list {
+			rec := make([]byte, blockIndexHeaderSize+int(e.length))
+			if _, err = idx.file.ReadAt(rec, e.offset-blockIndexHeaderSize); err == nil {
+				_, err = w.Write(rec)
+			}
+			if err != nil {
+				_ = f.Close()
+				_ = os.Remove(tmpPath)
+				return fmt.Errorf("failed to copy block index record: %w", err)
+			}
+
+			e.offset = size + blockIndexHeaderSize
+			moved[i] = e
+			size += int64(len(rec))
+		}
+		shards[shardKey] = moved
+	}
+
+	if err = w.Flush(); err == nil {
+		err = f.Sync()
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, idx.path)
+	}
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write compacted block index: %w", err)
+	}
+
+	log.Info().Str("path", idx.path).Int64("old_size", idx.size).Int64("size", size).Msg("block index compacted")
+
+	_ = idx.file.Close()
+	idx.file = f
+	idx.size = size
+	idx.live = size
+	idx.shards = shards
+	return nil
+}
+
+func (idx *BlockIndex) remove(shardKey string, seqno uint32) {
+	list := idx.shards[shardKey]
+	for i := range list {
+		if list[i].seqno == seqno {
+			idx.shards[shardKey] = append(list[:i], list[i+1:]...)
+			return
+		}
+	}
+}
+
+// Lookup returns header of block by seqno, nil when it is not indexed
+func (idx *BlockIndex) Lookup(wc int32, shard int64, seqno uint32) (*ton.BlockHeader, error) {
+	idx.mx.RLock()
+	list := idx.shards[getShardKey(wc, shard)]
+	i := sort.Search(len(list), func(i int) bool {
+		return list[i].seqno >= seqno
+	})
+	if i >= len(list) || list[i].seqno != seqno {
+		idx.mx.RUnlock()
+		return nil, nil
+	}
+	// lock is held while reading, file can be replaced by compaction
+	defer idx.mx.RUnlock()
+
+	return idx.read(list[i])
+}
+
+// LookupLt returns header of block which contains lt, nil when it is not indexed
+func (idx *BlockIndex) LookupLt(wc int32, shard int64, lt uint64) (*ton.BlockHeader, error) {
+	idx.mx.RLock()
+	list := idx.shards[getShardKey(wc, shard)]
+	// lt ranges grow with seqno
+	i := sort.Search(len(list), func(i int) bool {
+		return list[i].endLt > lt
+	})
+	if i >= len(list) || list[i].startLt > lt {
+		// block is not indexed, proxy was not running when it was created
+		idx.mx.RUnlock()
+		return nil, nil
+	}
+	defer idx.mx.RUnlock()
+
+	return idx.read(list[i])
+}
+
+// LastSeqnos returns seqno of the last indexed block of each shard, it is a cursor
+// of ingestion, kept in index file, so blocks created during restart are ingested too
+func (idx *BlockIndex) LastSeqnos() map[string]uint32 {
+	idx.mx.RLock()
+	defer idx.mx.RUnlock()
+
+	last := make(map[string]uint32, len(idx.shards))
+	for shardKey, list := range idx.shards {
+		last[shardKey] = list[len(list)-1].seqno
+	}
+	return last
+}
+
+func (idx *BlockIndex) read(e blockIndexEntry) (*ton.BlockHeader, error) {
+	data := make([]byte, e.length)
+	if _, err := idx.file.ReadAt(data, e.offset); err != nil {
+		return nil, fmt.Errorf("failed to read block index: %w", err)
+	}
+
+	var hdr ton.BlockHeader
+	if _, err := tl.Parse(&hdr, data, true); err != nil {
+		return nil, fmt.Errorf("failed to parse indexed block header: %w", err)
+	}
+	return &hdr, nil
+}
+
+func (idx *BlockIndex) Close() error {
+	idx.mx.Lock()
+	defer idx.mx.Unlock()
+
+	if idx.closed {
+		return nil
+	}
+	idx.closed = true
+	return idx.file.Close()
+}
+
+// EnableBlockIndex makes cache to index every new block and to answer lookups of old blocks from index,
+// index is closed together with cache
+func (c *BlockCache) EnableBlockIndex(idx *BlockIndex) {
+	c.index = idx
+	c.addBlockConsumer(c.indexBlock)
+}
+
+func (c *BlockCache) indexBlock(blk *Block, info *tlb.BlockHeader) {
+	proof, err := c.headerProof(blk, 0)
+	if err != nil {
+		return
+	}
+
+	added, err := c.index.Add(&ton.BlockHeader{ID: blk.ID, HeaderProof: proof}, info.StartLt, info.EndLt)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to add block to index")
+		return
+	}
+	if added {
+		metrics.Global.IndexedBlocks.WithLabelValues(fmt.Sprint(blk.ID.Workchain)).Add(1)
+	}
+}
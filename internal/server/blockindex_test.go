@@ -0,0 +1,94 @@
+package server
+
+import (
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func addIndexBlocks(t *testing.T, idx *BlockIndex, shard int64, from, to uint32) {
+	for seqno := from; seqno <= to; seqno++ {
+		hdr := &ton.BlockHeader{
+			ID:          &ton.BlockIDExt{Workchain: 0, Shard: shard, SeqNo: seqno, RootHash: make([]byte, 32), FileHash: make([]byte, 32)},
+			HeaderProof: cell.BeginCell().MustStoreUInt(uint64(seqno), 32).EndCell(),
+		}
+		// lt ranges of both shards grow together
+		if _, err := idx.Add(hdr, uint64(seqno)*10, uint64(seqno)*10+5); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestBlockIndexRetention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocks.idx")
+	idx, err := OpenBlockIndex(path, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// shard before split is not updated anymore, its blocks are the oldest
+	addIndexBlocks(t, idx, -0x8000000000000000, 1, 10)
+	addIndexBlocks(t, idx, 0x4000000000000000, 11, 100)
+
+	if idx.blocks > 22 {
+		t.Fatalf("expected at most 22 blocks, got %d", idx.blocks)
+	}
+	if _, ok := idx.shards[getShardKey(0, -0x8000000000000000)]; ok {
+		t.Fatal("list of shard without blocks is not removed")
+	}
+	if idx.size > 2*idx.live {
+		t.Fatalf("file is not compacted, size %d, live %d", idx.size, idx.live)
+	}
+
+	if hdr, err := idx.Lookup(0, 0x4000000000000000, 50); err != nil || hdr != nil {
+		t.Fatalf("expected dropped block, got %v, %v", hdr, err)
+	}
+	if err = idx.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idx, err = OpenBlockIndex(path, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	if st.Size() != idx.size {
+		t.Fatalf("expected file of %d bytes after reopen, got %d", st.Size(), idx.size)
+	}
+
+	tests := []struct {
+		name  string
+		seqno uint32
+		found bool
+	}{
+		{name: "latest block", seqno: 100, found: true},
+		{name: "oldest kept block", seqno: 100 - 19, found: true},
+		{name: "dropped block", seqno: 60},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hdr, err := idx.Lookup(0, 0x4000000000000000, tt.seqno)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (hdr != nil) != tt.found {
+				t.Fatalf("expected found %v, got %v", tt.found, hdr != nil)
+			}
+			if hdr != nil && hdr.ID.SeqNo != tt.seqno {
+				t.Fatalf("expected block %d, got %d", tt.seqno, hdr.ID.SeqNo)
+			}
+		})
+	}
+
+	if last := idx.LastSeqnos()[getShardKey(0, 0x4000000000000000)]; last != 100 {
+		t.Fatalf("expected cursor at 100, got %d", last)
+	}
+}
@@ -0,0 +1,341 @@
+package server
+
+import (
+	"bytes"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/rs/zerolog/log"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// MemoryConsumer is a cache which holds memory that budget can take back
+type MemoryConsumer interface {
+	// MemoryUsage returns bytes held by each class
+	MemoryUsage() map[CacheClass]int64
+	// Evict removes least recently used objects of class until at least n bytes are freed, freed bytes are returned
+	Evict(class CacheClass, n int64) int64
+}
+
+// MemoryBudget keeps memory of the whole process under the limit, when it is exceeded objects are evicted
+// from the biggest classes of all consumers first, instead of each cache evicting only by its own limits
+type MemoryBudget struct {
+	limit     int64
+	consumers []MemoryConsumer
+
+	stop chan struct{}
+}
+
+type budgetClass struct {
+	consumer MemoryConsumer
+	class    CacheClass
+	bytes    int64
+}
+
+func NewMemoryBudget(limit int64) *MemoryBudget {
+	return &MemoryBudget{
+		limit: limit,
+		stop:  make(chan struct{}),
+	}
+}
+
+// Add registers consumer, should be called before Start
+func (b *MemoryBudget) Add(c MemoryConsumer) {
+	b.consumers = append(b.consumers, c)
+}
+
+// Start checks memory periodically until Close
+func (b *MemoryBudget) Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-b.stop:
+				return
+			case <-ticker.C:
+				b.Check()
+			}
+		}
+	}()
+}
+
+// Check evicts objects when used memory is over the limit, returns freed bytes of objects
+func (b *MemoryBudget) Check() int64 {
+	used := processMemory()
+	if used <= b.limit {
+		return 0
+	}
+	excess := used - b.limit
+
+	var classes []budgetClass
+	for _, c := range b.consumers {
+		for class, n := range c.MemoryUsage() {
+			if n > 0 {
+				classes = append(classes, budgetClass{consumer: c, class: class, bytes: n})
+			}
+		}
+	}
+	sort.Slice(classes, func(i, j int) bool {
+		return classes[i].bytes > classes[j].bytes
+	})
+
+	var freed int64
+	for _, cl := range classes {
+		if freed >= excess {
+			break
+		}
+
+		n := excess - freed
+		if n > cl.bytes {
+			n = cl.bytes
+		}
+		freed += cl.consumer.Evict(cl.class, n)
+	}
+
+	if freed > 0 {
+		// return memory of evicted objects to os now, otherwise the next check sees the same usage
+		debug.FreeOSMemory()
+		log.Info().Int64("used", used).Int64("limit", b.limit).Int64("freed", freed).Msg("memory budget exceeded, cached objects evicted")
+	} else {
+		log.Debug().Int64("used", used).Int64("limit", b.limit).Msg("memory budget exceeded, nothing to evict")
+	}
+	return freed
+}
+
+func (b *MemoryBudget) Close() {
+	close(b.stop)
+}
+
+// processMemory returns resident memory of process, on systems without procfs memory obtained by go runtime is used
+func processMemory() int64 {
+	if data, err := os.ReadFile("/proc/self/statm"); err == nil {
+		// second field is resident pages
+		if fields := bytes.Fields(data); len(fields) > 1 {
+			if pages, err := strconv.ParseInt(string(fields[1]), 10, 64); err == nil {
+				return pages * int64(os.Getpagesize())
+			}
+		}
+	}
+
+	var st runtime.MemStats
+	runtime.ReadMemStats(&st)
+	return int64(st.Sys - st.HeapReleased)
+}
+
+// classes of in-process caches which are not kept in store
+const (
+	budgetClassResponses CacheClass = "responses"
+	budgetClassNegative  CacheClass = "negative"
+)
+
+// rough sizes of decoded objects of in-process caches, exact size of cell trees is too expensive to count,
+// they only decide which caches are evicted first, while the limit itself is checked by real process memory
+var budgetObjectSize = map[CacheClass]int64{
+	CacheClassMasterBlocks: 512 << 10,
+	CacheClassShardBlocks:  256 << 10,
+	CacheClassAccounts:     16 << 10,
+	CacheClassLibraries:    32 << 10,
+	budgetClassResponses:   8 << 10,
+	budgetClassNegative:    256,
+}
+
+// budgetObjects returns number of objects which should be evicted to free n bytes of class
+func budgetObjects(class CacheClass, n int64) int {
+	size := budgetObjectSize[class]
+	return int((n + size - 1) / size)
+}
+
+// MemoryUsage estimates memory of decoded blocks, accounts, libraries and negative answers kept by cache
+func (c *BlockCache) MemoryUsage() map[CacheClass]int64 {
+	var masters, shards, accounts int64
+
+	c.mx.RLock()
+	for _, mb := range c.masterBlocks {
+		masters++
+		if mb.accountsCache != nil {
+			accounts += int64(mb.accountsCache.Len())
+		}
+	}
+	for _, si := range c.shardBlocks {
+		for _, sb := range si.shardBlocks {
+			shards++
+			if sb.accountsCache != nil {
+				accounts += int64(sb.accountsCache.Len())
+			}
+		}
+		if si.accountStates != nil {
+			accounts += int64(si.accountStates.Len())
+		}
+	}
+	c.mx.RUnlock()
+
+	res := map[CacheClass]int64{
+		CacheClassMasterBlocks: masters * budgetObjectSize[CacheClassMasterBlocks],
+		CacheClassShardBlocks:  shards * budgetObjectSize[CacheClassShardBlocks],
+		CacheClassAccounts:     accounts * budgetObjectSize[CacheClassAccounts],
+	}
+	if c.libsCache != nil {
+		res[CacheClassLibraries] = int64(c.libsCache.Len()) * budgetObjectSize[CacheClassLibraries]
+	}
+	if c.negative != nil {
+		res[budgetClassNegative] = int64(c.negative.cache.Len()) * budgetObjectSize[budgetClassNegative]
+	}
+	return res
+}
+
+// Evict drops the oldest objects of class, the latest master and shard blocks are kept, they are needed to serve anything
+func (c *BlockCache) Evict(class CacheClass, n int64) int64 {
+	num := budgetObjects(class, n)
+	evicted := 0
+
+	switch class {
+	case CacheClassMasterBlocks:
+		c.mx.Lock()
+		seqnos := make([]uint32, 0, len(c.masterBlocks))
+		for seqno := range c.masterBlocks {
+			if c.lastBlock == nil || seqno != c.lastBlock.SeqNo {
+				seqnos = append(seqnos, seqno)
+			}
+		}
+		sort.Slice(seqnos, func(i, j int) bool { return seqnos[i] < seqnos[j] })
+		for _, seqno := range seqnos {
+			if evicted >= num {
+				break
+			}
+			delete(c.masterBlocks, seqno)
+			evicted++
+		}
+		c.mx.Unlock()
+	case CacheClassShardBlocks:
+		type shardBlockRef struct {
+			si    *ShardInfo
+			seqno uint32
+		}
+
+		c.mx.Lock()
+		var refs []shardBlockRef
+		for _, si := range c.shardBlocks {
+			for seqno := range si.shardBlocks {
+				if si.lastBlock == nil || seqno != si.lastBlock.SeqNo {
+					refs = append(refs, shardBlockRef{si: si, seqno: seqno})
+				}
+			}
+		}
+		sort.Slice(refs, func(i, j int) bool { return refs[i].seqno < refs[j].seqno })
+		for _, ref := range refs {
+			if evicted >= num {
+				break
+			}
+			delete(ref.si.shardBlocks, ref.seqno)
+			evicted++
+		}
+		c.mx.Unlock()
+	case CacheClassAccounts:
+		evicted = c.evictAccounts(num)
+	case CacheClassLibraries:
+		if c.libsCache != nil {
+			evicted = evictARC(c.libsCache, num)
+		}
+	case budgetClassNegative:
+		if c.negative != nil {
+			evicted = evictLRU(c.negative.cache, num)
+		}
+	default:
+		return 0
+	}
+	return int64(evicted) * budgetObjectSize[class]
+}
+
+// evictAccounts drops cached accounts of the oldest blocks first
+func (c *BlockCache) evictAccounts(num int) int {
+	type accountsRef struct {
+		seqno uint32
+		cache *lru.ARCCache
+	}
+
+	var refs []accountsRef
+	c.mx.RLock()
+	for _, mb := range c.masterBlocks {
+		if mb.accountsCache != nil {
+			refs = append(refs, accountsRef{seqno: mb.ID.SeqNo, cache: mb.accountsCache})
+		}
+	}
+	for _, si := range c.shardBlocks {
+		for _, sb := range si.shardBlocks {
+			if sb.accountsCache != nil {
+				refs = append(refs, accountsRef{seqno: sb.ID.SeqNo, cache: sb.accountsCache})
+			}
+		}
+		if si.accountStates != nil && si.lastBlock != nil {
+			refs = append(refs, accountsRef{seqno: si.lastBlock.SeqNo, cache: si.accountStates})
+		}
+	}
+	c.mx.RUnlock()
+
+	sort.Slice(refs, func(i, j int) bool { return refs[i].seqno < refs[j].seqno })
+
+	evicted := 0
+	for _, ref := range refs {
+		if evicted >= num {
+			break
+		}
+		evicted += evictARC(ref.cache, num-evicted)
+	}
+	return evicted
+}
+
+// MemoryUsage estimates memory of general and negative answers caches
+func (s *ProxyBalancer) MemoryUsage() map[CacheClass]int64 {
+	res := map[CacheClass]int64{}
+	if s.gpCache != nil {
+		res[budgetClassResponses] = int64(s.gpCache.Len()) * budgetObjectSize[budgetClassResponses]
+	}
+	if s.negCache != nil {
+		res[budgetClassNegative] = int64(s.negCache.cache.Len()) * budgetObjectSize[budgetClassNegative]
+	}
+	return res
+}
+
+func (s *ProxyBalancer) Evict(class CacheClass, n int64) int64 {
+	evicted := 0
+	switch class {
+	case budgetClassResponses:
+		if s.gpCache != nil {
+			evicted = evictARC(s.gpCache, budgetObjects(class, n))
+		}
+	case budgetClassNegative:
+		if s.negCache != nil {
+			evicted = evictLRU(s.negCache.cache, budgetObjects(class, n))
+		}
+	}
+	return int64(evicted) * budgetObjectSize[class]
+}
+
+// evictARC removes up to num keys, arc cache has no oldest entry, so keys are removed in order it lists them
+func evictARC(cache *lru.ARCCache, num int) int {
+	evicted := 0
+	for _, key := range cache.Keys() {
+		if evicted >= num {
+			break
+		}
+		cache.Remove(key)
+		evicted++
+	}
+	return evicted
+}
+
+func evictLRU(cache *lru.Cache, num int) int {
+	evicted := 0
+	for evicted < num {
+		if _, _, ok := cache.RemoveOldest(); !ok {
+			break
+		}
+		evicted++
+	}
+	return evicted
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"encoding/binary"
+	"github.com/xssnick/tonutils-go/adnl"
+	"github.com/xssnick/tonutils-go/tl"
+	"sync"
+)
+
+// larger buffers are not returned to pool, to not keep memory of rare huge answers
+const maxPooledBufferSize = 1 << 20
+
+// answerBufPool keeps buffers of serialized answers, Send copies answer to its packet,
+// so buffer can be reused right after it
+var answerBufPool = sync.Pool{
+	New: func() any {
+		buf := make([]byte, 0, 16<<10)
+		return &buf
+	},
+}
+
+// answerSchemaID is a boxed prefix of adnl.message.answer
+var answerSchemaID = func() []byte {
+	data, err := tl.Serialize(adnl.MessageAnswer{ID: make([]byte, 32), Data: tl.Raw{}}, true)
+	if err != nil {
+		panic("failed to serialize answer schema: " + err.Error())
+	}
+	return data[:4]
+}()
+
+// serializeAnswer writes adnl.message.answer with already serialized data into pooled buffer,
+// the same as tl.Serialize does, but without intermediate copies. Result is passed to Send as tl.Raw,
+// which is written as is, buffer should be released after it.
+func serializeAnswer(queryID, data []byte) *[]byte {
+	buf := answerBufPool.Get().(*[]byte)
+	b := append((*buf)[:0], answerSchemaID...)
+	b = append(b, queryID...)
+
+	if len(data) >= 0xFE {
+		b = binary.LittleEndian.AppendUint32(b, uint32(len(data)<<8)|0xFE)
+	} else {
+		b = append(b, byte(len(data)))
+	}
+	b = append(b, data...)
+
+	// bytes are padded to 4, prefix of schema and id is already aligned
+	for len(b)%4 != 0 {
+		b = append(b, 0)
+	}
+
+	*buf = b
+	return buf
+}
+
+func releaseBuffer(buf *[]byte) {
+	if cap(*buf) > maxPooledBufferSize {
+		return
+	}
+	answerBufPool.Put(buf)
+}
@@ -13,22 +13,37 @@ import (
 	"github.com/xssnick/tonutils-go/ton"
 	"github.com/xssnick/tonutils-go/tvm/cell"
 	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"golang.org/x/sync/singleflight"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
 	"unsafe"
 )
 
+const (
+	cacheLevelLocal = "local"
+	cacheLevelShard = "shard"
+	cacheLevelStore = "store"
+)
+
 var ErrTimeout = ton.LSError{
 	Code: 652,
 	Text: "timeout",
 }
 
+var ErrBackendsUnavailable = ton.LSError{
+	Code: 502,
+	Text: "backends are unavailable",
+}
+
 type MasterBlock struct {
 	Block
 	StateHash []byte
 	GenTime   uint32
 	Config    *cell.Dictionary
+	Shards    []*ton.BlockIDExt
 
 	mx sync.RWMutex
 }
@@ -52,30 +67,94 @@ type ShardInfo struct {
 	shardBlocks map[uint32]*ShardBlock
 	lastBlock   *ton.BlockIDExt
 	updatedAt   time.Time
+
+	// states of accounts at lastBlock, dropped only when shard advances
+	accountStates *lru.ARCCache
 }
 
 type BlockCache struct {
 	config config.CacheConfig
 
-	balancer  *BackendBalancer
+	balancer  Balancer
 	libsCache *lru.ARCCache
 
-	lastBlock *ton.BlockIDExt
-	zeroState *ton.ZeroStateIDExt
+	store        CacheStore
+	storeLimits  map[CacheClass]config.CacheStoreClassConfig
+	flight       singleflight.Group
+	negative     *NegativeCache
+	shardProofs  *lru.Cache
+	headerProofs *lru.Cache
+	pushed       *lru.Cache
+	verifier     *TrustVerifier
+	feed         blockFeed
+	index        *BlockIndex
+	txIndex      *TxIndex
+	watchlist    *Watchlist
+	pubsub       *PubSub
+	election     atomic.Pointer[PubSub]
+	upstream     atomic.Pointer[Upstream]
+	consumers    []blockConsumer
+	consumersMx  sync.Mutex
+
+	lastBlock        *ton.BlockIDExt
+	lastBlockGenTime uint32
+	zeroState        *ton.ZeroStateIDExt
 
 	masterBlocks map[uint32]*MasterBlock
 	shardBlocks  map[string]*ShardInfo
 
 	mcWaiter unsafe.Pointer
 	mx       sync.RWMutex
+
+	closed    chan struct{}
+	closeOnce sync.Once
 }
 
-func NewBlockCache(config config.CacheConfig, balancer *BackendBalancer) *BlockCache {
+// NewBlockCache creates cache, store is optional second level cache of immutable objects
+func NewBlockCache(config config.CacheConfig, balancer Balancer, store CacheStore) *BlockCache {
 	b := &BlockCache{
 		config:       config,
 		balancer:     balancer,
+		store:        store,
+		storeLimits:  StoreClassLimits(config),
 		masterBlocks: map[uint32]*MasterBlock{},
 		shardBlocks:  map[string]*ShardInfo{},
+		zeroState:    balancer.ZeroState(),
+		closed:       make(chan struct{}),
+	}
+
+	shardProofs, err := lru.New(1024)
+	if err != nil {
+		panic("failed to init shard proofs cache: " + err.Error())
+	}
+	b.shardProofs = shardProofs
+
+	headerProofs, err := lru.New(4096)
+	if err != nil {
+		panic("failed to init header proofs cache: " + err.Error())
+	}
+	b.headerProofs = headerProofs
+
+	pushed, err := lru.New(256)
+	if err != nil {
+		panic("failed to init pushed blocks cache: " + err.Error())
+	}
+	b.pushed = pushed
+
+	if !config.DisableSignatureVerification {
+		verifier, err := NewTrustVerifier(balancer, config.TrustedBlock)
+		if err != nil {
+			panic("failed to init trust verifier: " + err.Error())
+		}
+		b.verifier = verifier
+	}
+
+	if config.NegativeTTLMs > 0 {
+		negative, err := NewNegativeCache(config.NegativeMaxEntries, time.Duration(config.NegativeTTLMs)*time.Millisecond, config.NegativeErrorCodes)
+		if err != nil {
+			panic("failed to init negative cache: " + err.Error())
+		}
+		b.negative = negative
 	}
 
 	if config.MaxCachedLibraries > 0 {
@@ -94,7 +173,26 @@ func NewBlockCache(config config.CacheConfig, balancer *BackendBalancer) *BlockC
 	go func() {
 		var waitSeqno, streak uint32
 		for {
-			ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+			select {
+			case <-b.closed:
+				return
+			default:
+			}
+
+			if e := b.election.Load(); e != nil && waitSeqno > 0 {
+				if seqno, ok := e.awaitLeader(waitSeqno); ok {
+					waitSeqno = seqno + 1
+					continue
+				}
+			}
+			if u := b.upstream.Load(); u != nil && waitSeqno > 0 {
+				if seqno, ok := u.awaitPush(waitSeqno); ok {
+					waitSeqno = seqno + 1
+					continue
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(withInternalQuery(context.Background()), 8*time.Second)
 			inf, err := getMasterchainInfo(ctx, b.balancer.GetClient(), waitSeqno)
 			cancel()
 			if err != nil {
@@ -108,13 +206,17 @@ func NewBlockCache(config config.CacheConfig, balancer *BackendBalancer) *BlockC
 			streak = 0
 
 			b.mx.RLock()
-			hasZeroState := b.zeroState != nil
+			zeroState := b.zeroState
 			b.mx.RUnlock()
 
-			if !hasZeroState {
+			if zeroState == nil {
 				b.mx.Lock()
 				b.zeroState = inf.Init
 				b.mx.Unlock()
+			} else if !zeroStateEquals(zeroState, inf.Init) {
+				log.Warn().Msg("master info with zero state of another network received, we will retry in 1s")
+				time.Sleep(1 * time.Second)
+				continue
 			}
 
 			ctx, cancel = context.WithTimeout(context.Background(), 8*time.Second)
@@ -136,6 +238,7 @@ func NewBlockCache(config config.CacheConfig, balancer *BackendBalancer) *BlockC
 				log.Debug().Uint32("seqno", block.Block.ID.SeqNo).Dur("lag", lag/1000).Msg("new master info fetched")
 			}
 
+			b.balancer.ObserveMasterSeqno(block.Block.ID.SeqNo)
 			waitSeqno = block.Block.ID.SeqNo + 1
 		}
 	}()
@@ -145,6 +248,38 @@ func NewBlockCache(config config.CacheConfig, balancer *BackendBalancer) *BlockC
 	return b
 }
 
+// Close stops fetching of new master blocks, closes subscriptions and store
+func (c *BlockCache) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		c.feed.close()
+
+		if c.store != nil {
+			err = c.store.Close()
+		}
+		if c.index != nil {
+			if ierr := c.index.Close(); ierr != nil && err == nil {
+				err = ierr
+			}
+		}
+		if c.pubsub != nil {
+			if perr := c.pubsub.close(); perr != nil && err == nil {
+				err = perr
+			}
+		}
+		if u := c.upstream.Load(); u != nil {
+			u.close()
+		}
+	})
+	return err
+}
+
+// Store returns second level store of cache, nil when it is disabled
+func (c *BlockCache) Store() CacheStore {
+	return c.store
+}
+
 func (c *BlockCache) GetLibraries(ctx context.Context, hashes [][]byte) (*cell.Dictionary, bool, error) {
 	libs := cell.NewDict(256)
 	if len(hashes) == 0 {
@@ -155,6 +290,7 @@ func (c *BlockCache) GetLibraries(ctx context.Context, hashes [][]byte) (*cell.D
 	for _, hash := range hashes {
 		if c.libsCache != nil {
 			lib, ok := c.libsCache.Get(string(hash))
+			observeCache(CacheClassLibraries, cacheLevelLocal, ok)
 			if ok {
 				if err := libs.Set(cell.BeginCell().MustStoreSlice(hash, 256).EndCell(), lib.(*cell.Cell)); err != nil {
 					return nil, false, err
@@ -169,7 +305,7 @@ func (c *BlockCache) GetLibraries(ctx context.Context, hashes [][]byte) (*cell.D
 		return libs, true, nil
 	}
 
-	fetchedLibs, err := getLibraries(ctx, c.balancer.GetClient(), toFetch...)
+	fetchedLibs, err := c.fetchLibraries(ctx, toFetch)
 	if err != nil {
 		return nil, false, err
 	}
@@ -241,10 +377,12 @@ func (c *BlockCache) GetMasterBlock(ctx context.Context, id *ton.BlockIDExt) (*M
 				Text: "incorrect block id",
 			}
 		}
+		observeCache(CacheClassMasterBlocks, cacheLevelLocal, true)
 		return b, true, nil
 	}
+	observeCache(CacheClassMasterBlocks, cacheLevelLocal, false)
 
-	blockCell, err := getBlock(ctx, c.balancer.GetClient(), id)
+	blockCell, err := c.fetchBlock(ctx, id)
 	if err != nil {
 		return nil, false, fmt.Errorf("failed to get block data: %w", err)
 	}
@@ -283,6 +421,10 @@ func (c *BlockCache) GetMasterBlock(ctx context.Context, id *ton.BlockIDExt) (*M
 			prev.mx.RUnlock()
 		}
 
+		if v, ok := c.pushed.Get(pushedConfigKey(id)); cfg == nil && ok {
+			cfg = v.(*cell.Dictionary)
+		}
+
 		if cfg == nil {
 			// fetch config directly, because we don't know current
 			cfg, err = getBlockchainConfig(ctx, c.balancer.GetClient(), id)
@@ -302,6 +444,19 @@ func (c *BlockCache) GetMasterBlock(ctx context.Context, id *ton.BlockIDExt) (*M
 		return nil, false, err
 	}
 
+	if c.verifier != nil {
+		c.mx.RLock()
+		newer := c.lastBlock == nil || id.SeqNo > c.lastBlock.SeqNo
+		c.mx.RUnlock()
+
+		// only blocks which could become the latest are checked, data of older ones is checked against requested root hash
+		if newer {
+			if err = c.verifier.Verify(ctx, id); err != nil {
+				return nil, false, fmt.Errorf("failed to verify block signatures: %w", err)
+			}
+		}
+	}
+
 	var cache *lru.ARCCache
 	if c.config.MaxCachedAccountsPerBlock > 0 {
 		// arc cache will still hold frequently used accounts even if there are many new account requests
@@ -321,15 +476,23 @@ func (c *BlockCache) GetMasterBlock(ctx context.Context, id *ton.BlockIDExt) (*M
 	b.Config = cfg
 	b.GenTime = block.BlockInfo.GenUtime
 	b.StateHash = stateHash
+	b.Shards = shards
 
 	c.mx.RLock()
 	lastUpdated := c.lastBlock == nil || b.Block.ID.SeqNo > c.lastBlock.SeqNo
 	c.mx.RUnlock()
 
 	if lastUpdated {
+		var event *BlockEvent
+
 		c.mx.Lock()
 		if c.lastBlock == nil || b.Block.ID.SeqNo > c.lastBlock.SeqNo {
+			event = &BlockEvent{
+				Master:  b.Block.ID,
+				GenTime: b.GenTime,
+			}
 			c.lastBlock = b.Block.ID
+			c.lastBlockGenTime = b.GenTime
 
 			for _, shard := range shards {
 				shardKey := getShardKey(shard.Workchain, shard.Shard)
@@ -340,6 +503,14 @@ func (c *BlockCache) GetMasterBlock(ctx context.Context, id *ton.BlockIDExt) (*M
 					}
 					c.shardBlocks[shardKey] = si
 				}
+				if si.lastBlock == nil || !si.lastBlock.Equals(shard) {
+					// shard advanced, so account states could be changed
+					if c.config.TrackShardStates && shard.Workchain == 0 && si.lastBlock != nil && si.accountStates != nil {
+						go c.carryShardAccountStates(si.lastBlock, shard, si.accountStates)
+					}
+					si.accountStates = nil
+					event.Shards = append(event.Shards, shard)
+				}
 				si.lastBlock = shard
 				si.updatedAt = time.Now()
 
@@ -367,6 +538,10 @@ func (c *BlockCache) GetMasterBlock(ctx context.Context, id *ton.BlockIDExt) (*M
 		}
 		c.mx.Unlock()
 
+		if event != nil {
+			c.feed.publish(event)
+		}
+
 		// broadcast new master and init new waiter
 		old := (*chan struct{})(atomic.LoadPointer(&c.mcWaiter))
 		ch := make(chan struct{})
@@ -392,10 +567,68 @@ func (c *BlockCache) GetLastMasterBlock(ctx context.Context) (*MasterBlock, bool
 	if lb == nil {
 		return nil, false, fmt.Errorf("last master is not fetched yet")
 	}
+	if c.IsStale() && c.staleExpired() {
+		return nil, false, ErrBackendsUnavailable
+	}
 
 	return c.GetMasterBlock(ctx, lb)
 }
 
+// IsStale reports if all backends are down and answers are served from the last cached blocks,
+// it is false when StaleIfErrorSeconds is not set
+func (c *BlockCache) IsStale() bool {
+	return c.config.StaleIfErrorSeconds > 0 && c.balancer.HealthyBackends() == 0
+}
+
+// staleExpired reports if the last master block is older than stale window, so it should not be served anymore
+func (c *BlockCache) staleExpired() bool {
+	lag, ok := c.LastMasterBlockLag()
+	return ok && lag > time.Duration(c.config.StaleIfErrorSeconds)*time.Second
+}
+
+// GetStaleAccountState returns state of account kept in memory for the requested master block,
+// used only while all backends are down and the block is inside stale window,
+// state of any other block is never returned because its proofs would not match the request
+func (c *BlockCache) GetStaleAccountState(id *ton.BlockIDExt, addr *address.Address) (*ton.AccountState, bool) {
+	if !c.IsStale() || id == nil || id.Workchain != -1 {
+		return nil, false
+	}
+
+	c.mx.RLock()
+	b := c.masterBlocks[id.SeqNo]
+	c.mx.RUnlock()
+	if b == nil {
+		return nil, false
+	}
+
+	minGenTime := uint32(time.Now().Add(-time.Duration(c.config.StaleIfErrorSeconds) * time.Second).Unix())
+
+	b.mx.RLock()
+	defer b.mx.RUnlock()
+
+	if b.Block.ID == nil || !b.Block.ID.Equals(id) || b.accountsCache == nil || b.GenTime < minGenTime {
+		return nil, false
+	}
+
+	acc, ok := b.accountsCache.Peek(addr.String())
+	if !ok {
+		return nil, false
+	}
+	return acc.(*ton.AccountState), true
+}
+
+// LastMasterBlockLag returns time passed since generation of the last known master block,
+// false is returned when no master block is fetched yet
+func (c *BlockCache) LastMasterBlockLag() (time.Duration, bool) {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	if c.lastBlock == nil {
+		return 0, false
+	}
+	return time.Since(time.Unix(int64(c.lastBlockGenTime), 0)), true
+}
+
 func (c *BlockCache) WaitMasterBlock(ctx context.Context, seqno uint32, timeout time.Duration) error {
 	c.mx.RLock()
 	already := c.lastBlock != nil && seqno <= c.lastBlock.SeqNo
@@ -463,7 +696,7 @@ func (c *BlockCache) GetAccountState(ctx context.Context, id *ton.BlockIDExt, ad
 	}
 
 	if block == nil {
-		account, err := getAccount(ctx, c.balancer.GetClient(), id, addr)
+		account, err := c.fetchAccount(ctx, id, addr)
 		if err != nil {
 			return nil, false, err
 		}
@@ -488,11 +721,23 @@ func (c *BlockCache) GetAccountStateInBlock(ctx context.Context, block *Block, a
 	if block.accountsCache != nil {
 		acc, ok := block.accountsCache.Get(addrStr)
 		if ok {
+			observeCache(CacheClassAccounts, cacheLevelLocal, true)
 			return acc.(*ton.AccountState), true, nil
 		}
 	}
 
-	account, err := getAccount(ctx, c.balancer.GetClient(), block.ID, addr)
+	if block.ID.Workchain == -1 && addr.Workchain() != -1 {
+		if account := c.reuseShardAccountState(ctx, block.ID, addr); account != nil {
+			observeCache(CacheClassAccounts, cacheLevelShard, true)
+			if block.accountsCache != nil {
+				block.accountsCache.Add(addrStr, account)
+			}
+			return account, true, nil
+		}
+	}
+
+	observeCache(CacheClassAccounts, cacheLevelLocal, false)
+	account, err := c.fetchAccount(ctx, block.ID, addr)
 	if err != nil {
 		return nil, false, err
 	}
@@ -500,6 +745,7 @@ func (c *BlockCache) GetAccountStateInBlock(ctx context.Context, block *Block, a
 	if block.accountsCache != nil {
 		block.accountsCache.Add(addrStr, account)
 	}
+	c.rememberShardAccountState(addr, account)
 
 	return account, false, nil
 }
@@ -537,10 +783,7 @@ func (c *BlockCache) LookupBlockInCache(id *ton.BlockInfoShort) (*ton.BlockHeade
 	}
 
 	if blk != nil {
-		sk := cell.CreateProofSkeleton()
-		sk.ProofRef(0).SetRecursive()
-
-		hdrProof, err := blk.Data.CreateProof(sk)
+		hdrProof, err := c.headerProof(blk, 0)
 		if err != nil {
 			return nil, err
 		}
@@ -551,9 +794,48 @@ func (c *BlockCache) LookupBlockInCache(id *ton.BlockInfoShort) (*ton.BlockHeade
 			HeaderProof: hdrProof,
 		}, nil
 	}
+
+	if c.index != nil {
+		return c.index.Lookup(id.Workchain, id.Shard, uint32(id.Seqno))
+	}
 	return nil, nil
 }
 
+// GetTransactionsInCache returns transactions from transactions index, nil when they are not indexed
+func (c *BlockCache) GetTransactionsInCache(acc *ton.AccountID, lt int64, hash []byte, limit int32) *ton.TransactionList {
+	if c.txIndex == nil {
+		return nil
+	}
+	return c.txIndex.Get(acc, uint64(lt), hash, int(limit))
+}
+
+// LookupBlockByLtInCache returns header of block which contains lt, only block index is used
+func (c *BlockCache) LookupBlockByLtInCache(id *ton.BlockInfoShort, lt uint64) (*ton.BlockHeader, error) {
+	if c.index == nil {
+		return nil, nil
+	}
+	return c.index.LookupLt(id.Workchain, id.Shard, lt)
+}
+
+// headerProof returns proof of block header, it is created once per block and mode,
+// cells are immutable so the same proof is shared by all answers
+func (c *BlockCache) headerProof(blk *Block, mode uint32) (*cell.Cell, error) {
+	key := hex.EncodeToString(blk.ID.RootHash) + ":" + strconv.FormatUint(uint64(mode), 10)
+	if v, ok := c.headerProofs.Get(key); ok {
+		return v.(*cell.Cell), nil
+	}
+
+	sk := cell.CreateProofSkeleton()
+	sk.ProofRef(0).SetRecursive()
+
+	proof, err := blk.Data.CreateProof(sk)
+	if err != nil {
+		return nil, err
+	}
+	c.headerProofs.Add(key, proof)
+	return proof, nil
+}
+
 func (c *BlockCache) CacheBlockIfNeeded(ctx context.Context, id *ton.BlockIDExt) (*Block, bool, error) {
 	var fromCache bool
 	var data *Block
@@ -582,6 +864,7 @@ func (c *BlockCache) CacheBlockIfNeeded(ctx context.Context, id *ton.BlockIDExt)
 				}
 				data = &b.Block
 				fromCache = true
+				observeCache(CacheClassShardBlocks, cacheLevelLocal, true)
 			}
 		}
 
@@ -601,8 +884,9 @@ func (c *BlockCache) CacheBlockIfNeeded(ctx context.Context, id *ton.BlockIDExt)
 			b.mx.Lock()
 			defer b.mx.Unlock()
 
+			observeCache(CacheClassShardBlocks, cacheLevelLocal, b.Data != nil)
 			if b.Data == nil {
-				blk, err := getBlock(ctx, c.balancer.GetClient(), id)
+				blk, err := c.fetchBlock(ctx, id)
 				if err != nil {
 					return nil, false, err
 				}
@@ -655,6 +939,7 @@ func (c *BlockCache) CacheBlockIfNeeded(ctx context.Context, id *ton.BlockIDExt)
 			}
 			data = &b.Block
 			fromCache = true
+			observeCache(CacheClassMasterBlocks, cacheLevelLocal, true)
 		} else if needCache {
 			// fetch and cache master block
 			ms, cached, err := c.GetMasterBlock(ctx, id)
@@ -677,7 +962,7 @@ func (c *BlockCache) GetBlock(ctx context.Context, id *ton.BlockIDExt) (*ton.Blo
 
 	if block == nil {
 		// just fetch block from ls
-		blk, err := getBlock(ctx, c.balancer.GetClient(), id)
+		blk, err := c.fetchBlock(ctx, id)
 		if err != nil {
 			return nil, false, err
 		}
@@ -701,7 +986,7 @@ func (c *BlockCache) GetTransaction(ctx context.Context, id *ton.BlockIDExt, acc
 	}
 
 	if block == nil {
-		tx, err := getTransaction(ctx, c.balancer.GetClient(), id, account, lt)
+		tx, err := c.fetchTransaction(ctx, id, account, lt)
 		if err != nil {
 			return nil, false, err
 		}
@@ -721,7 +1006,7 @@ func (c *BlockCache) GetTransaction(ctx context.Context, id *ton.BlockIDExt, acc
 	}
 
 	if err = tlb.LoadFromCell(new(tlb.CurrencyCollection), acc); err != nil {
-		log.Warn().Err(err).Int64("lt", lt).Msg("failed to load currency collection from shard account")
+		log.Ctx(ctx).Warn().Err(err).Int64("lt", lt).Msg("failed to load currency collection from shard account")
 		return nil, false, ton.LSError{
 			Code: 500,
 			Text: "failed to load currency collection from shard account",
@@ -730,7 +1015,7 @@ func (c *BlockCache) GetTransaction(ctx context.Context, id *ton.BlockIDExt, acc
 
 	var accBlock tlb.AccountBlock
 	if err = tlb.LoadFromCell(&accBlock, acc); err != nil {
-		log.Warn().Err(err).Int64("lt", lt).Msg("failed to load account block from shard account")
+		log.Ctx(ctx).Warn().Err(err).Int64("lt", lt).Msg("failed to load account block from shard account")
 		return nil, false, ton.LSError{
 			Code: 500,
 			Text: "failed to load account block from shard account",
@@ -748,7 +1033,7 @@ func (c *BlockCache) GetTransaction(ctx context.Context, id *ton.BlockIDExt, acc
 
 	proof, err := block.Data.CreateProof(sk)
 	if err != nil {
-		log.Warn().Err(err).Int64("lt", lt).Msg("failed to create transaction proof")
+		log.Ctx(ctx).Warn().Err(err).Int64("lt", lt).Msg("failed to create transaction proof")
 		return nil, false, ton.LSError{
 			Code: 500,
 			Text: "failed to create proof",
@@ -757,7 +1042,7 @@ func (c *BlockCache) GetTransaction(ctx context.Context, id *ton.BlockIDExt, acc
 
 	tx, err := accTx.LoadRefCell()
 	if err != nil {
-		log.Warn().Err(err).Int64("lt", lt).Msg("failed to load transaction ref")
+		log.Ctx(ctx).Warn().Err(err).Int64("lt", lt).Msg("failed to load transaction ref")
 		return nil, false, ton.LSError{
 			Code: 500,
 			Text: "failed to load transaction ref",
@@ -771,6 +1056,218 @@ func (c *BlockCache) GetTransaction(ctx context.Context, id *ton.BlockIDExt, acc
 	}, cached, nil
 }
 
+func (c *BlockCache) fetchBlock(ctx context.Context, id *ton.BlockIDExt) (*cell.Cell, error) {
+	class := CacheClassShardBlocks
+	if id.Workchain == -1 {
+		class = CacheClassMasterBlocks
+	}
+
+	key := blockStoreKey(id)
+	if v, ok := c.pushed.Get(key); ok {
+		return v.(*cell.Cell), nil
+	}
+
+	v, err := c.coalesce(ctx, class, key, func(ctx context.Context) (any, error) {
+		if data := c.loadFromStore(ctx, class, key); data != nil {
+			cl, err := loadStoredCell(data, id.RootHash)
+			if err == nil {
+				return cl, nil
+			}
+			log.Ctx(ctx).Warn().Err(err).Str("key", key).Msg("failed to load block from store")
+		}
+
+		cl, err := getBlock(ctx, c.balancer.GetClient(), id)
+		if err != nil {
+			return nil, err
+		}
+		c.saveToStore(ctx, class, key, cl.ToBOCWithFlags(false))
+		return cl, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*cell.Cell), nil
+}
+
+func (c *BlockCache) fetchAccount(ctx context.Context, id *ton.BlockIDExt, addr *address.Address) (*ton.AccountState, error) {
+	key := accountStoreKey(id, addr.Workchain(), addr.Data())
+	v, err := c.coalesce(ctx, CacheClassAccounts, key, func(ctx context.Context) (any, error) {
+		if data := c.loadFromStore(ctx, CacheClassAccounts, key); data != nil {
+			acc, err := loadStoredTL[ton.AccountState](data)
+			if err == nil && !acc.ID.Equals(id) {
+				err = fmt.Errorf("stored account state is for another block")
+			}
+			if err == nil {
+				return acc, nil
+			}
+			log.Ctx(ctx).Warn().Err(err).Str("key", key).Msg("failed to load account state from store")
+		}
+
+		acc, err := getAccount(ctx, c.balancer.GetClientFor(&ton.AccountID{Workchain: addr.Workchain(), ID: addr.Data()}), id, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		if !c.config.DisableProofVerification {
+			if err = verifyAccountState(id, addr, acc); err != nil {
+				log.Ctx(ctx).Warn().Err(err).Str("addr", addr.String()).Uint32("seqno", id.SeqNo).Msg("backend returned account state with invalid proof")
+				return nil, fmt.Errorf("backend returned invalid proof: %w", err)
+			}
+		}
+		c.saveTLToStore(ctx, CacheClassAccounts, key, *acc)
+		return acc, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*ton.AccountState), nil
+}
+
+func (c *BlockCache) fetchTransaction(ctx context.Context, id *ton.BlockIDExt, acc *ton.AccountID, lt int64) (*ton.TransactionInfo, error) {
+	key := transactionStoreKey(id, acc, lt)
+	v, err := c.coalesce(ctx, CacheClassTransactions, key, func(ctx context.Context) (any, error) {
+		if data := c.loadFromStore(ctx, CacheClassTransactions, key); data != nil {
+			tx, err := loadStoredTL[ton.TransactionInfo](data)
+			if err == nil && !tx.ID.Equals(id) {
+				err = fmt.Errorf("stored transaction is for another block")
+			}
+			if err == nil {
+				return tx, nil
+			}
+			log.Ctx(ctx).Warn().Err(err).Str("key", key).Msg("failed to load transaction from store")
+		}
+
+		tx, err := getTransaction(ctx, c.balancer.GetClientFor(acc), id, acc, lt)
+		if err != nil {
+			return nil, err
+		}
+
+		if !c.config.DisableProofVerification {
+			if err = verifyTransaction(id, acc, lt, tx); err != nil {
+				log.Ctx(ctx).Warn().Err(err).Int64("lt", lt).Uint32("seqno", id.SeqNo).Msg("backend returned transaction with invalid proof")
+				return nil, fmt.Errorf("backend returned invalid proof: %w", err)
+			}
+		}
+		c.saveTLToStore(ctx, CacheClassTransactions, key, *tx)
+		return tx, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*ton.TransactionInfo), nil
+}
+
+func (c *BlockCache) fetchLibraries(ctx context.Context, hashes [][]byte) ([]*cell.Cell, error) {
+	libs := make([]*cell.Cell, len(hashes))
+
+	var toFetch [][]byte
+	for i, hash := range hashes {
+		key := hex.EncodeToString(hash)
+		if data := c.loadFromStore(ctx, CacheClassLibraries, key); data != nil {
+			cl, err := loadStoredCell(data, hash)
+			if err == nil {
+				libs[i] = cl
+				continue
+			}
+			log.Ctx(ctx).Warn().Err(err).Str("key", key).Msg("failed to load library from store")
+		}
+		toFetch = append(toFetch, hash)
+	}
+
+	if len(toFetch) == 0 {
+		return libs, nil
+	}
+
+	fetched, err := getLibraries(ctx, c.balancer.GetClient(), toFetch...)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, 0; i < len(libs); i++ {
+		if libs[i] != nil {
+			continue
+		}
+		libs[i] = fetched[j]
+		if fetched[j] != nil {
+			c.saveToStore(ctx, CacheClassLibraries, hex.EncodeToString(toFetch[j]), fetched[j].ToBOCWithFlags(false))
+		}
+		j++
+	}
+	return libs, nil
+}
+
+// coalesce executes only one fetch for all concurrent callers with the same key of class,
+// errors of fetch are remembered by negative cache
+func (c *BlockCache) coalesce(ctx context.Context, class CacheClass, key string, fetch func(ctx context.Context) (any, error)) (any, error) {
+	flightKey := string(class) + ":" + key
+	if c.negative != nil {
+		if ls, ok := c.negative.Get(flightKey); ok {
+			return nil, ls
+		}
+	}
+
+	v, shared, err := coalesce(ctx, &c.flight, flightKey, func(ctx context.Context) (any, error) {
+		v, err := fetch(ctx)
+		if err != nil && c.negative != nil {
+			c.negative.Observe(flightKey, err)
+		}
+		return v, err
+	})
+	if shared {
+		log.Ctx(ctx).Debug().Str("class", string(class)).Msg("fetch coalesced with concurrent request")
+	}
+	return v, err
+}
+
+func (c *BlockCache) loadFromStore(ctx context.Context, class CacheClass, key string) []byte {
+	if c.store == nil {
+		return nil
+	}
+
+	data, ok, err := c.store.Get(ctx, class, key)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Str("class", string(class)).Msg("failed to get from cache store")
+		return nil
+	}
+	observeCache(class, cacheLevelStore, ok)
+	if !ok {
+		return nil
+	}
+	return data
+}
+
+func observeCache(class CacheClass, level string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	metrics.Global.CacheRequests.WithLabelValues(string(class), level, result).Add(1)
+}
+
+func (c *BlockCache) saveToStore(ctx context.Context, class CacheClass, key string, data []byte) {
+	if c.store == nil {
+		return
+	}
+
+	ttl := time.Duration(c.storeLimits[class].TTLSeconds) * time.Second
+	if err := c.store.Set(ctx, class, key, data, ttl); err != nil {
+		log.Ctx(ctx).Debug().Err(err).Str("class", string(class)).Msg("failed to save to cache store")
+	}
+}
+
+func (c *BlockCache) saveTLToStore(ctx context.Context, class CacheClass, key string, v tl.Serializable) {
+	if c.store == nil {
+		return
+	}
+
+	data, err := tl.Serialize(v, true)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Str("class", string(class)).Msg("failed to serialize object for cache store")
+		return
+	}
+	c.saveToStore(ctx, class, key, data)
+}
+
 func getAccount(ctx context.Context, client ton.LiteClient, block *ton.BlockIDExt, addr *address.Address) (*ton.AccountState, error) {
 	var resp tl.Serializable
 	err := client.QueryLiteserver(ctx, ton.GetAccountState{
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"math/rand"
+	"time"
+)
+
+// canaryChecker queries backend in background for sample of locally answered account states and get methods,
+// and compares answers, so divergence of emulation from real liteservers is visible in metrics
+type canaryChecker struct {
+	balancer Balancer
+	percent  float64
+	slots    chan struct{}
+}
+
+func newCanaryChecker(balancer Balancer, cfg config.CanaryConfig) *canaryChecker {
+	maxInFlight := int(cfg.MaxInFlight)
+	if maxInFlight == 0 {
+		maxInFlight = 16
+	}
+
+	return &canaryChecker{
+		balancer: balancer,
+		percent:  cfg.Percent,
+		slots:    make(chan struct{}, maxInFlight),
+	}
+}
+
+func (c *canaryChecker) check(query any, resp tl.Serializable) {
+	switch query.(type) {
+	case ton.RunSmcMethod, ton.GetAccountState:
+	default:
+		return
+	}
+	if resp == nil || rand.Float64()*100 >= c.percent {
+		return
+	}
+	if _, ok := resp.(ton.LSError); ok {
+		return
+	}
+
+	typ := metrics.Global.TypeLabel(query)
+	select {
+	case c.slots <- struct{}{}:
+	default:
+		metrics.Global.CanaryChecks.WithLabelValues(typ, "dropped").Add(1)
+		return
+	}
+
+	go func() {
+		defer func() { <-c.slots }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var remote tl.Serializable
+		if err := c.balancer.Query(ctx, query, &remote); err != nil {
+			metrics.Global.CanaryChecks.WithLabelValues(typ, "failed").Add(1)
+			return
+		}
+
+		result := compareCanary(resp, remote)
+		metrics.Global.CanaryChecks.WithLabelValues(typ, result).Add(1)
+		if result == "mismatch" {
+			log.Warn().Type("request", query).Type("answer", resp).Type("backend_answer", remote).Msg("local answer differs from backend answer")
+		}
+	}()
+}
+
+// compareCanary returns match, mismatch or failed when backend answered with error,
+// get method results are compared by exit code and stack, proofs and c7 of emulated answers can differ
+func compareCanary(local, remote tl.Serializable) string {
+	if _, ok := remote.(ton.LSError); ok {
+		return "failed"
+	}
+
+	switch l := local.(type) {
+	case ton.RunMethodResult:
+		r, ok := remote.(ton.RunMethodResult)
+		if !ok || l.ExitCode != r.ExitCode || (l.Result == nil) != (r.Result == nil) {
+			return "mismatch"
+		}
+		if l.Result != nil && !bytes.Equal(l.Result.Hash(), r.Result.Hash()) {
+			return "mismatch"
+		}
+		return "match"
+	case *ton.RunMethodResult:
+		return compareCanary(*l, remote)
+	case *ton.AccountState:
+		return compareCanary(*l, remote)
+	}
+
+	a, b := digestAnswer(local), digestAnswer(remote)
+	if a == nil || b == nil || a.key != b.key || !bytes.Equal(a.hash, b.hash) {
+		return "mismatch"
+	}
+	return "match"
+}
@@ -0,0 +1,159 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/liteclient"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+)
+
+// CapturedQuery is one line of capture file, query is serialized liteserver query as received from client,
+// so it can be parsed back to liteclient.LiteServerQuery and replayed as is
+type CapturedQuery struct {
+	Time     int64  `json:"time"`
+	Key      string `json:"key"`
+	Query    []byte `json:"query"`
+	Response []byte `json:"response"`
+}
+
+// QueryCapture writes sampled client queries with their responses to file, for replay against new versions of proxy
+type QueryCapture struct {
+	file    *os.File
+	w       *bufio.Writer
+	percent float64
+	maxSize int64
+	written int64
+
+	queue  chan *pendingCapture
+	done   chan struct{}
+	closed bool
+	mx     sync.Mutex
+}
+
+func NewQueryCapture(cfg config.CaptureConfig) (*QueryCapture, error) {
+	f, err := os.OpenFile(cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open capture file: %w", err)
+	}
+
+	st, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	c := &QueryCapture{
+		file:    f,
+		w:       bufio.NewWriter(f),
+		percent: cfg.SamplePercent,
+		maxSize: int64(cfg.MaxSizeMB) << 20,
+		written: st.Size(),
+		queue:   make(chan *pendingCapture, 1024),
+		done:    make(chan struct{}),
+	}
+	go c.writer()
+	return c, nil
+}
+
+// pendingCapture is sampled query waiting for writer, it is serialized there, so query path only queues it
+type pendingCapture struct {
+	time    int64
+	keyName string
+	query   any
+	resp    tl.Serializable
+}
+
+// record captures query with its response when it is sampled, serialization and writes are done in background
+func (c *QueryCapture) record(keyName string, query any, resp tl.Serializable) {
+	if resp == nil || rand.Float64()*100 >= c.percent {
+		return
+	}
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	if c.closed {
+		return
+	}
+
+	select {
+	case c.queue <- &pendingCapture{time: time.Now().Unix(), keyName: keyName, query: query, resp: resp}:
+	default:
+		// disk is slower than traffic, capture is only a sample anyway
+	}
+}
+
+func (p *pendingCapture) encode() ([]byte, error) {
+	q, err := tl.Serialize(liteclient.LiteServerQuery{Data: p.query}, true)
+	if err != nil {
+		return nil, err
+	}
+	r, err := tl.Serialize(p.resp, true)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(&CapturedQuery{Time: p.time, Key: p.keyName, Query: q, Response: r})
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+func (c *QueryCapture) writer() {
+	defer close(c.done)
+
+	flush := time.NewTicker(time.Second)
+	defer flush.Stop()
+
+	for {
+		select {
+		case rec, ok := <-c.queue:
+			if !ok {
+				_ = c.w.Flush()
+				return
+			}
+			if c.maxSize > 0 && c.written >= c.maxSize {
+				continue
+			}
+
+			data, err := rec.encode()
+			if err != nil {
+				continue
+			}
+
+			n, err := c.w.Write(data)
+			c.written += int64(n)
+			if err != nil {
+				log.Warn().Err(err).Msg("failed to write captured query")
+				continue
+			}
+			if c.maxSize > 0 && c.written >= c.maxSize {
+				log.Warn().Int64("size", c.written).Msg("capture file reached max size, capture is stopped")
+			}
+		case <-flush.C:
+			_ = c.w.Flush()
+		}
+	}
+}
+
+// Close writes queued queries and closes file
+func (c *QueryCapture) Close() error {
+	c.mx.Lock()
+	if c.closed {
+		c.mx.Unlock()
+		return nil
+	}
+	c.closed = true
+	close(c.queue)
+	c.mx.Unlock()
+
+	<-c.done
+	return c.file.Close()
+}
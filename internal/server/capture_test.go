@@ -0,0 +1,54 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestQueryCapture(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture.jsonl")
+	c, err := NewQueryCapture(config.CaptureConfig{Path: path, SamplePercent: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.record("test", ton.GetTime{}, ton.CurrentTime{Now: 7})
+	if err = c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var rec CapturedQuery
+	if err = json.Unmarshal(data, &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Key != "test" {
+		t.Fatalf("expected key test, got %s", rec.Key)
+	}
+
+	// parsed the same way as by replay
+	q, err := ParseLiteQuery(rec.Query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := q.(ton.GetTime); !ok {
+		t.Fatalf("unexpected captured query %T", q)
+	}
+
+	var resp tl.Serializable
+	if _, err = tl.Parse(&resp, rec.Response, true); err != nil {
+		t.Fatal(err)
+	}
+	if tm, ok := resp.(ton.CurrentTime); !ok || tm.Now != 7 {
+		t.Fatalf("unexpected captured response %v", resp)
+	}
+}
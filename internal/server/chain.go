@@ -0,0 +1,32 @@
+package server
+
+import (
+	"context"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+)
+
+type masterWaitCtx struct{}
+
+// withMasterWait marks context of query which client wrapped with wait for master block,
+// the wait is already done by us, but chained proxy backend should do it too
+func withMasterWait(ctx context.Context, wt ton.WaitMasterchainSeqno) context.Context {
+	return context.WithValue(ctx, masterWaitCtx{}, wt)
+}
+
+// chainedPayload wraps query for chained proxy with wait of client, so it is not answered
+// from its cache which may not have the block yet, other backends get query as is
+func (b *Backend) chainedPayload(ctx context.Context, payload tl.Serializable) tl.Serializable {
+	if !b.chained {
+		return payload
+	}
+	if _, ok := payload.([]tl.Serializable); ok {
+		return payload
+	}
+
+	wt, ok := ctx.Value(masterWaitCtx{}).(ton.WaitMasterchainSeqno)
+	if !ok {
+		return payload
+	}
+	return []tl.Serializable{wt, payload}
+}
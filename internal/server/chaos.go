@@ -0,0 +1,128 @@
+package server
+
+import (
+	"context"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"math/rand"
+	"reflect"
+	"time"
+)
+
+const (
+	ChaosFaultTooManyRequests = "429"
+	ChaosFaultBadGateway      = "502"
+	ChaosFaultTruncate        = "truncate"
+)
+
+// chaosRule injects latency and faults into answers of matching keys and methods,
+// so clients can test their retry logic against proxy
+type chaosRule struct {
+	keys    map[string]bool
+	methods map[string]bool
+	percent float64
+	latency time.Duration
+	jitter  time.Duration
+	fault   string
+}
+
+func newChaosRules(list []config.ChaosRule) []*chaosRule {
+	rules := make([]*chaosRule, 0, len(list))
+	for _, r := range list {
+		rule := &chaosRule{
+			percent: r.Percent,
+			latency: time.Duration(r.LatencyMs) * time.Millisecond,
+			jitter:  time.Duration(r.LatencyJitterMs) * time.Millisecond,
+			fault:   r.Fault,
+		}
+		if len(r.Keys) > 0 {
+			rule.keys = map[string]bool{}
+			for _, k := range r.Keys {
+				rule.keys[k] = true
+			}
+		}
+		if len(r.Methods) > 0 {
+			rule.methods = map[string]bool{}
+			for _, m := range r.Methods {
+				rule.methods[m] = true
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// chaosFor returns the first rule which matches query of key and fires by its percent, nil when none
+func (s *ProxyBalancer) chaosFor(keyName string, query any) *chaosRule {
+	if list, ok := query.([]tl.Serializable); ok && len(list) > 0 {
+		// wait master with wrapped query
+		query = list[len(list)-1]
+	}
+
+	for _, r := range s.chaos {
+		if r.keys != nil && !r.keys[keyName] {
+			continue
+		}
+		if r.methods != nil && !r.methods[reflect.TypeOf(query).String()] {
+			continue
+		}
+		if rand.Float64()*100 >= r.percent {
+			continue
+		}
+
+		metrics.Global.ChaosInjections.WithLabelValues(keyName, metrics.Global.TypeLabel(query), r.name()).Add(1)
+		return r
+	}
+	return nil
+}
+
+func (r *chaosRule) name() string {
+	if r.fault == "" {
+		return "latency"
+	}
+	return r.fault
+}
+
+// delay sleeps for latency of rule, false is returned when context is done before
+func (r *chaosRule) delay(ctx context.Context) bool {
+	d := r.latency
+	if r.jitter > 0 {
+		d += time.Duration(rand.Int63n(int64(r.jitter)))
+	}
+	if d == 0 {
+		return true
+	}
+
+	select {
+	case <-time.After(d):
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// failure returns error answer of rule instead of processing query, nil when query should be processed
+func (r *chaosRule) failure() tl.Serializable {
+	switch r.fault {
+	case ChaosFaultTooManyRequests:
+		return ton.LSError{Code: 429, Text: "too many requests, injected by chaos mode"}
+	case ChaosFaultBadGateway:
+		return ton.LSError{Code: 502, Text: "backend is unavailable, injected by chaos mode"}
+	}
+	return nil
+}
+
+// truncate cuts serialized answer in half when rule requires, client gets answer which cannot be parsed
+func (r *chaosRule) truncate(resp tl.Serializable) tl.Serializable {
+	if r.fault != ChaosFaultTruncate || resp == nil {
+		return resp
+	}
+
+	data, err := tl.Serialize(resp, true)
+	if err != nil {
+		return resp
+	}
+	return tl.Raw(data[:len(data)/2])
+}
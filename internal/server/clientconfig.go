@@ -0,0 +1,76 @@
+package server
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"github.com/xssnick/tonutils-go/liteclient"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"net"
+	"strconv"
+)
+
+// ClientLiteserversConfig is a part of ton global config with liteservers, it can be used by clients as is
+type ClientLiteserversConfig struct {
+	Liteservers []liteclient.LiteserverConfig `json:"liteservers"`
+}
+
+// ClientConfigs builds liteservers config for each client key by its name, addr is ip:port
+// which clients connect to, PublicAddr or ListenAddr of config is used when it is empty
+func ClientConfigs(cfg *config.Config, addr string) (map[string]*ClientLiteserversConfig, error) {
+	ip, port, err := PublicEndpoint(cfg, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	res := map[string]*ClientLiteserversConfig{}
+	for _, client := range cfg.Clients {
+		if len(client.PrivateKey) != ed25519.SeedSize {
+			return nil, fmt.Errorf("invalid private key of client %s", client.Name)
+		}
+		pub := ed25519.NewKeyFromSeed(client.PrivateKey).Public().(ed25519.PublicKey)
+
+		res[client.Name] = &ClientLiteserversConfig{
+			Liteservers: []liteclient.LiteserverConfig{
+				{
+					// global config stores ip as signed int
+					IP:   int64(int32(binary.BigEndian.Uint32(ip))),
+					Port: port,
+					ID: liteclient.ServerID{
+						Type: "pub.ed25519",
+						Key:  base64.StdEncoding.EncodeToString(pub),
+					},
+				},
+			},
+		}
+	}
+	return res, nil
+}
+
+// PublicEndpoint returns ipv4 and port which clients connect to, addr is used when not empty,
+// then PublicAddr and ListenAddr of config
+func PublicEndpoint(cfg *config.Config, addr string) (net.IP, int, error) {
+	if addr == "" {
+		addr = cfg.PublicAddr
+	}
+	if addr == "" {
+		addr = cfg.ListenAddr
+	}
+
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid address %s: %w", addr, err)
+	}
+
+	ip := net.ParseIP(host).To4()
+	if ip == nil || ip.IsUnspecified() {
+		return nil, 0, fmt.Errorf("public ipv4 address is required, %s is not, set PublicAddr", host)
+	}
+
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid port %s", portStr)
+	}
+	return ip, int(port), nil
+}
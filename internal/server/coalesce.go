@@ -0,0 +1,35 @@
+package server
+
+import (
+	"context"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/singleflight"
+	"time"
+)
+
+// coalescedFetchTimeout limits shared fetch when the first caller has no deadline
+const coalescedFetchTimeout = 10 * time.Second
+
+// coalesce executes fetch only once for all concurrent callers with the same key, fetch is detached from
+// cancellation of the first caller, so it does not fail others, but it keeps deadline of that caller.
+// Each caller waits with its own context, shared reports if result was given to several callers.
+func coalesce(ctx context.Context, group *singleflight.Group, key string, fetch func(ctx context.Context) (any, error)) (v any, shared bool, err error) {
+	ch := group.DoChan(key, func() (any, error) {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			deadline = time.Now().Add(coalescedFetchTimeout)
+		}
+
+		fetchCtx, cancel := context.WithDeadline(log.Ctx(ctx).WithContext(context.Background()), deadline)
+		defer cancel()
+
+		return fetch(fetchCtx)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	case res := <-ch:
+		return res.Val, res.Shared, res.Err
+	}
+}
@@ -0,0 +1,30 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"golang.org/x/sync/singleflight"
+	"testing"
+	"time"
+)
+
+func TestCoalesceKeepsDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+
+	var group singleflight.Group
+	v, _, err := coalesce(ctx, &group, "key", func(fetchCtx context.Context) (any, error) {
+		got, ok := fetchCtx.Deadline()
+		if !ok || !got.Equal(deadline) {
+			return nil, errors.New("deadline of caller is not kept")
+		}
+		return 1, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != 1 {
+		t.Fatalf("unexpected value %v", v)
+	}
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"sync/atomic"
+)
+
+// how long lagging backend is asked to wait for master block already seen by client, in milliseconds as liteserver expects
+const monotonicWaitTimeoutMs = 3000
+
+// masterSeqnoOf returns seqno of the last master block from master info answer, 0 for other answers
+func masterSeqnoOf(resp tl.Serializable) uint32 {
+	switch v := resp.(type) {
+	case ton.MasterchainInfo:
+		if v.Last != nil {
+			return v.Last.SeqNo
+		}
+	case ton.MasterchainInfoExt:
+		if v.Last != nil {
+			return v.Last.SeqNo
+		}
+	}
+	return 0
+}
+
+// keepMonotonic makes sure client connection never gets master info older than it has already got,
+// answers of lagging backends or caches are requeried from backend waiting for the known block,
+// so client does not see account balance going backwards after it has seen the newer block
+func (s *ProxyBalancer) keepMonotonic(ctx context.Context, conn *ClientConnInfo, query any, resp tl.Serializable) tl.Serializable {
+	seqno := masterSeqnoOf(resp)
+	if seqno == 0 {
+		return resp
+	}
+
+	seen := atomic.LoadUint32(&conn.masterSeqno)
+	if seqno < seen {
+		metrics.Global.LaggingAnswers.WithLabelValues(metrics.Global.TypeLabel(query)).Add(1)
+
+		if v, ok := query.([]tl.Serializable); ok {
+			query = v[len(v)-1]
+		}
+		resp, _ = s.queryBackend(ctx, []tl.Serializable{ton.WaitMasterchainSeqno{Seqno: int32(seen), Timeout: monotonicWaitTimeoutMs}, query})
+
+		if seqno = masterSeqnoOf(resp); seqno == 0 {
+			return resp
+		}
+		if seqno < seen {
+			return ErrTimeout
+		}
+	}
+
+	for {
+		cur := atomic.LoadUint32(&conn.masterSeqno)
+		if seqno <= cur || atomic.CompareAndSwapUint32(&conn.masterSeqno, cur, seqno) {
+			return resp
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+	"testing"
+)
+
+// requeryBalancer answers every query with master info of fixed seqno and remembers the last query
+type requeryBalancer struct {
+	Balancer
+	seqno uint32
+	query tl.Serializable
+}
+
+func (b *requeryBalancer) Query(_ context.Context, payload tl.Serializable, result *tl.Serializable) error {
+	b.query = payload
+	*result = masterInfo(b.seqno)
+	return nil
+}
+
+func masterInfo(seqno uint32) ton.MasterchainInfo {
+	return ton.MasterchainInfo{Last: &ton.BlockIDExt{Workchain: -1, SeqNo: seqno}}
+}
+
+func TestKeepMonotonic(t *testing.T) {
+	tests := []struct {
+		name      string
+		seen      uint32
+		resp      tl.Serializable
+		backend   uint32
+		wantSeqno uint32
+		wantErr   bool
+		requeried bool
+		wantSeen  uint32
+	}{
+		{name: "newer answer moves connection forward", seen: 10, resp: masterInfo(12), wantSeqno: 12, wantSeen: 12},
+		{name: "same block is kept", seen: 10, resp: masterInfo(10), wantSeqno: 10, wantSeen: 10},
+		{name: "other answers are not checked", seen: 10, resp: ton.CurrentTime{Now: 1}, wantSeen: 10},
+		{name: "lagging answer is requeried", seen: 10, resp: masterInfo(8), backend: 11, wantSeqno: 11, requeried: true, wantSeen: 11},
+		{name: "still lagging backend times out", seen: 10, resp: masterInfo(8), backend: 9, wantErr: true, requeried: true, wantSeen: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestProxy(&testCache{})
+			b := &requeryBalancer{seqno: tt.backend}
+			s.backendBalancer = b
+
+			conn := &ClientConnInfo{masterSeqno: tt.seen}
+			resp := s.keepMonotonic(context.Background(), conn, ton.GetMasterchainInf{}, tt.resp)
+
+			if _, isErr := resp.(ton.LSError); isErr != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, resp)
+			}
+			if !tt.wantErr && masterSeqnoOf(resp) != tt.wantSeqno {
+				t.Fatalf("expected seqno %d, got %d", tt.wantSeqno, masterSeqnoOf(resp))
+			}
+			if (b.query != nil) != tt.requeried {
+				t.Fatalf("expected requery %v, got %v", tt.requeried, b.query)
+			}
+			if b.query != nil {
+				wait, ok := b.query.([]tl.Serializable)[0].(ton.WaitMasterchainSeqno)
+				if !ok || uint32(wait.Seqno) != tt.seen {
+					t.Fatalf("expected wait for seqno %d, got %v", tt.seen, b.query)
+				}
+			}
+			if conn.masterSeqno != tt.wantSeen {
+				t.Fatalf("expected connection at seqno %d, got %d", tt.wantSeen, conn.masterSeqno)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package server
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/adnl"
+	"github.com/xssnick/tonutils-go/adnl/address"
+	"github.com/xssnick/tonutils-go/adnl/dht"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"time"
+)
+
+// DHTPublisher announces address of proxy in ton dht under adnl id of each client key,
+// the same way nodes announce themselves, so clients which know only key can find proxy
+type DHTPublisher struct {
+	client *dht.Client
+	keys   []dhtKey
+	addr   *address.UDP
+
+	ttl      time.Duration
+	interval time.Duration
+
+	closed chan struct{}
+}
+
+type dhtKey struct {
+	name string
+	key  ed25519.PrivateKey
+}
+
+func NewDHTPublisher(cfg *config.Config) (*DHTPublisher, error) {
+	ip, port, err := PublicEndpoint(cfg, "")
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := time.Duration(cfg.DHT.TTLSeconds) * time.Second
+	if ttl == 0 {
+		ttl = time.Hour
+	}
+	interval := time.Duration(cfg.DHT.IntervalSeconds) * time.Second
+	if interval == 0 || interval >= ttl {
+		// address should be re-announced before it is expired
+		interval = ttl / 2
+	}
+
+	p := &DHTPublisher{
+		addr:     &address.UDP{IP: ip, Port: int32(port)},
+		ttl:      ttl,
+		interval: interval,
+		closed:   make(chan struct{}),
+	}
+	for _, client := range cfg.Clients {
+		p.keys = append(p.keys, dhtKey{name: client.Name, key: ed25519.NewKeyFromSeed(client.PrivateKey)})
+	}
+
+	_, gateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	gate := adnl.NewGateway(gateKey)
+	if err = gate.StartClient(); err != nil {
+		return nil, fmt.Errorf("failed to start adnl gateway: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	p.client, err = dht.NewClientFromConfigUrl(ctx, gate, cfg.DHT.GlobalConfigURL)
+	if err != nil {
+		_ = gate.Close()
+		return nil, fmt.Errorf("failed to init dht client: %w", err)
+	}
+	return p, nil
+}
+
+// Start announces address immediately and then periodically, until Close
+func (p *DHTPublisher) Start() {
+	go func() {
+		for {
+			for _, k := range p.keys {
+				p.announce(k)
+			}
+
+			select {
+			case <-p.closed:
+				return
+			case <-time.After(p.interval):
+			}
+		}
+	}()
+}
+
+func (p *DHTPublisher) announce(k dhtKey) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	now := int32(time.Now().Unix())
+	list := address.List{
+		Addresses:  []*address.UDP{p.addr},
+		Version:    now,
+		ReinitDate: now,
+		ExpireAt:   now + int32(p.ttl/time.Second),
+	}
+
+	replicas, _, err := p.client.StoreAddress(ctx, list, p.ttl, k.key, 0)
+	if err != nil {
+		log.Warn().Err(err).Str("key", k.name).Msg("failed to announce address in dht")
+		metrics.Global.DHTAnnounces.WithLabelValues(k.name, "failed").Add(1)
+		return
+	}
+
+	log.Debug().Str("key", k.name).Int("replicas", replicas).Msg("address announced in dht")
+	metrics.Global.DHTAnnounces.WithLabelValues(k.name, "ok").Add(1)
+}
+
+// Close stops announces, dht client closes its gateway too
+func (p *DHTPublisher) Close() {
+	close(p.closed)
+	p.client.Close()
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/liteclient"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"net"
+	"sync"
+	"time"
+)
+
+// StartDiscovery loads liteservers from global config url and adds reachable ones as backends,
+// then refreshes the list periodically, discovered backends which disappeared from config are removed
+func (b *BackendBalancer) StartDiscovery(url string, refresh time.Duration, tags []string) error {
+	if err := b.discover(url, tags); err != nil {
+		return err
+	}
+
+	if refresh > 0 {
+		go func() {
+			for {
+				select {
+				case <-b.closed:
+					return
+				case <-time.After(refresh):
+				}
+
+				if err := b.discover(url, tags); err != nil {
+					log.Warn().Err(err).Str("url", url).Msg("failed to refresh backends from global config")
+				}
+			}
+		}()
+	}
+	return nil
+}
+
+func (b *BackendBalancer) discover(url string, tags []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	gc, err := liteclient.GetConfigFromUrl(ctx, url)
+	if err != nil {
+		return fmt.Errorf("failed to download global config: %w", err)
+	}
+
+	if len(gc.Liteservers) == 0 {
+		return fmt.Errorf("no liteservers in global config")
+	}
+
+	existing := map[string]*Backend{}
+	for _, backend := range b.Backends() {
+		existing[backend.Addr+"/"+base64.StdEncoding.EncodeToString(backend.Key)] = backend
+	}
+
+	wanted := map[string]bool{}
+	var wg sync.WaitGroup
+	for _, ls := range gc.Liteservers {
+		key, err := base64.StdEncoding.DecodeString(ls.ID.Key)
+		if err != nil || len(key) != 32 {
+			log.Warn().Str("key", ls.ID.Key).Msg("invalid liteserver key in global config, skipping")
+			continue
+		}
+
+		addr := net.JoinHostPort(intToIP4(ls.IP), fmt.Sprint(ls.Port))
+		id := addr + "/" + ls.ID.Key
+		wanted[id] = true
+
+		if existing[id] != nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(cfg config.BackendLiteserver) {
+			defer wg.Done()
+
+			if err := b.addDiscovered(ctx, cfg); err != nil {
+				log.Debug().Err(err).Str("backend", cfg.Addr).Msg("discovered backend is not reachable")
+				return
+			}
+			log.Info().Str("backend", cfg.Addr).Msg("discovered backend connected")
+		}(config.BackendLiteserver{
+			Name: "global-" + addr,
+			Addr: addr,
+			Key:  key,
+			Tags: tags,
+		})
+	}
+	wg.Wait()
+
+	for id, backend := range existing {
+		if !backend.discovered || wanted[id] {
+			continue
+		}
+
+		if err := b.RemoveAndDrain(backend.Name, backendDrainTimeout); err != nil {
+			log.Warn().Err(err).Str("backend", backend.Addr).Msg("failed to remove backend which is not in global config anymore")
+			continue
+		}
+		log.Info().Str("backend", backend.Addr).Msg("backend removed, it is not in global config anymore")
+	}
+
+	if len(b.Backends()) == 0 {
+		return fmt.Errorf("no reachable liteservers in global config")
+	}
+	return nil
+}
+
+func (b *BackendBalancer) addDiscovered(ctx context.Context, cfg config.BackendLiteserver) error {
+	backend, err := connectBackend(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	backend.discovered = true
+
+	// verify that it really serves requests
+	if _, err = getMasterchainInfo(ctx, backend.Client, 0); err != nil {
+		backend.Stop()
+		return fmt.Errorf("probe failed: %w", err)
+	}
+
+	if err = b.AddBackend(backend); err != nil {
+		backend.Stop()
+		return err
+	}
+	return nil
+}
+
+func intToIP4(ip int64) string {
+	return net.IPv4(byte(ip>>24), byte(ip>>16), byte(ip>>8), byte(ip)).String()
+}
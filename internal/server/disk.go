@@ -0,0 +1,314 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DiskStore is a CacheStore which keeps each object in a separate file, it survives restarts.
+// Expired objects are removed by background compaction, which also keeps total size under the cap
+// by removing least recently used files, reads are refreshing modification time of files
+type DiskStore struct {
+	path    string
+	ttl     time.Duration
+	maxSize int64
+
+	jitter uint32
+	stale  time.Duration
+
+	stop chan struct{}
+}
+
+type diskFile struct {
+	class   string
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+func NewDiskStore(cfg config.DiskCacheConfig) (*DiskStore, error) {
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("disk store path should be set")
+	}
+
+	if err := os.MkdirAll(cfg.Path, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create disk store dir: %w", err)
+	}
+
+	d := &DiskStore{
+		path:    cfg.Path,
+		ttl:     time.Duration(cfg.TTLSeconds) * time.Second,
+		maxSize: int64(cfg.MaxSizeMB) << 20,
+		stop:    make(chan struct{}),
+	}
+
+	interval := time.Duration(cfg.CompactionIntervalSeconds) * time.Second
+	if interval == 0 {
+		interval = 5 * time.Minute
+	}
+	go d.compactionLoop(interval)
+
+	return d, nil
+}
+
+func (d *DiskStore) compactionLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.Compact(); err != nil {
+			log.Warn().Err(err).Str("path", d.path).Msg("disk cache compaction failed")
+		}
+
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Compact removes expired and unfinished objects, and the least recently used ones if size cap is exceeded
+func (d *DiskStore) Compact() error {
+	tm := time.Now()
+	now := tm.Unix()
+
+	var files []diskFile
+	var total, removed int64
+	sizes, evictions := map[string]int64{}, map[string]int64{}
+	err := filepath.WalkDir(d.path, func(path string, e fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if e.IsDir() {
+			return nil
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			return nil
+		}
+
+		if strings.HasPrefix(e.Name(), ".tmp-") {
+			// leftovers of interrupted writes
+			if tm.Sub(info.ModTime()) > time.Minute {
+				_ = os.Remove(path)
+			}
+			return nil
+		}
+
+		class := ""
+		if rel, err := filepath.Rel(d.path, path); err == nil {
+			class = strings.SplitN(rel, string(filepath.Separator), 2)[0]
+		}
+
+		if exp := readExpiration(path); exp < 0 || (exp > 0 && now > exp+int64(d.stale/time.Second)) {
+			if os.Remove(path) == nil {
+				removed++
+				evictions[class]++
+			}
+			return nil
+		}
+
+		files = append(files, diskFile{class: class, path: path, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+		sizes[class] += info.Size()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if d.maxSize > 0 && total > d.maxSize {
+		sort.Slice(files, func(i, j int) bool {
+			return files[i].modTime.Before(files[j].modTime)
+		})
+
+		for _, f := range files {
+			if total <= d.maxSize {
+				break
+			}
+			if os.Remove(f.path) == nil {
+				total -= f.size
+				sizes[f.class] -= f.size
+				removed++
+				evictions[f.class]++
+			}
+		}
+	}
+
+	for class, size := range sizes {
+		metrics.Global.CacheBytes.WithLabelValues(class, "disk").Set(float64(size))
+	}
+	for class, num := range evictions {
+		metrics.Global.CacheEvictions.WithLabelValues(class, "disk").Add(float64(num))
+	}
+
+	log.Debug().Int64("removed", removed).Int64("size", total).Dur("took", time.Since(tm)).Msg("disk cache compaction finished")
+	return nil
+}
+
+// readExpiration returns -1 if file is corrupted
+func readExpiration(path string) int64 {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+
+	var buf [8]byte
+	if _, err = io.ReadFull(f, buf[:]); err != nil {
+		return -1
+	}
+	return int64(binary.LittleEndian.Uint64(buf[:]))
+}
+
+func (d *DiskStore) file(class CacheClass, key string) string {
+	h := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(h[:])
+	// files are split to subdirectories by hash prefix to not have too many files in one
+	return filepath.Join(d.path, string(class), name[:2], name)
+}
+
+// SetExpiration sets max random deviation of ttl in percents, and how long expired objects
+// are still served, each read during this window renews object
+func (d *DiskStore) SetExpiration(jitterPercent uint32, stale time.Duration) {
+	d.jitter = jitterPercent
+	d.stale = stale
+}
+
+func (d *DiskStore) Get(_ context.Context, class CacheClass, key string) ([]byte, bool, error) {
+	file := d.file(class, key)
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	// first 8 bytes are expiration unix time, 0 means no expiration
+	if len(data) < 8 {
+		_ = os.Remove(file)
+		return nil, false, nil
+	}
+
+	if exp := int64(binary.LittleEndian.Uint64(data)); exp > 0 && time.Now().Unix() > exp {
+		if time.Now().Unix() > exp+int64(d.stale/time.Second) {
+			_ = os.Remove(file)
+			return nil, false, nil
+		}
+
+		// still requested after expiration, original ttl is not stored so default one is used
+		ttl := d.ttl
+		if ttl == 0 {
+			ttl = d.stale
+		}
+		d.renew(file, time.Now().Add(jitterTTL(ttl, d.jitter)).Unix())
+		metrics.Global.CacheStaleRenewals.WithLabelValues(string(class), "disk").Add(1)
+	}
+
+	// mark as recently used for compaction
+	now := time.Now()
+	_ = os.Chtimes(file, now, now)
+
+	return data[8:], true, nil
+}
+
+// renew overwrites expiration in header of file
+func (d *DiskStore) renew(file string, exp int64) {
+	f, err := os.OpenFile(file, os.O_WRONLY, 0)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(exp))
+	_, _ = f.WriteAt(buf[:], 0)
+}
+
+func (d *DiskStore) Set(_ context.Context, class CacheClass, key string, data []byte, ttl time.Duration) error {
+	if ttl == 0 {
+		ttl = d.ttl
+	}
+
+	var exp int64
+	if ttl > 0 {
+		exp = time.Now().Add(jitterTTL(ttl, d.jitter)).Unix()
+	}
+
+	file := d.file(class, key)
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 8+len(data))
+	binary.LittleEndian.PutUint64(buf, uint64(exp))
+	copy(buf[8:], data)
+
+	// write to temp file and rename to not leave partially written objects
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	if _, err = tmp.Write(buf); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+func (d *DiskStore) Delete(_ context.Context, class CacheClass, key string) error {
+	if err := os.Remove(d.file(class, key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (d *DiskStore) Purge(_ context.Context, class CacheClass) error {
+	if class != "" {
+		return os.RemoveAll(filepath.Join(d.path, string(class)))
+	}
+
+	entries, err := os.ReadDir(d.path)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err = os.RemoveAll(filepath.Join(d.path, e.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *DiskStore) Close() error {
+	close(d.stop)
+	return nil
+}
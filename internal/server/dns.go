@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/ton/dns"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const dnsCacheSize = 4096
+
+type DNSInfo struct {
+	Domain      string `json:"domain"`
+	Wallet      string `json:"wallet,omitempty"`
+	SiteADNL    string `json:"site_adnl,omitempty"`
+	SiteBag     string `json:"site_bag,omitempty"`
+	Item        string `json:"item,omitempty"`
+	Owner       string `json:"owner,omitempty"`
+	MasterSeqNo uint32 `json:"master_seqno"`
+}
+
+// handleDNS serves /dns/{domain}, domain is resolved by running dnsresolve of root and next resolvers
+// at the latest master block, results are cached for each master block
+func (a *HelperAPI) handleDNS(w http.ResponseWriter, r *http.Request) {
+	domain := strings.ToLower(strings.Trim(strings.TrimPrefix(r.URL.Path, "/dns/"), "/."))
+	if domain == "" || strings.Contains(domain, "/") || strings.Contains(domain, "..") ||
+		(!strings.HasSuffix(domain, ".ton") && !strings.HasSuffix(domain, ".t.me")) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid domain, only .ton and .t.me are supported"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	block, err := a.api.CurrentMasterchainInfo(ctx)
+	if err != nil {
+		writeHelperError(w, err)
+		return
+	}
+
+	key := fmt.Sprintf("%s:%d", domain, block.SeqNo)
+	if v, ok := a.dnsCache.Get(key); ok {
+		writeJSON(w, http.StatusOK, v)
+		return
+	}
+
+	root, err := a.dnsRoot(ctx, block)
+	if err != nil {
+		writeHelperError(w, err)
+		return
+	}
+
+	res, err := dns.NewDNSClient(a.api, root).ResolveAtBlock(ctx, domain, block)
+	if err != nil {
+		if errors.Is(err, dns.ErrNoSuchRecord) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "domain not found"})
+			return
+		}
+		writeHelperError(w, err)
+		return
+	}
+
+	info := &DNSInfo{
+		Domain:      domain,
+		Item:        res.GetNFTAddress().String(),
+		MasterSeqNo: block.SeqNo,
+	}
+	if wallet := res.GetWalletRecord(); wallet != nil {
+		info.Wallet = wallet.String()
+	}
+	if site, inStorage := res.GetSiteRecord(); site != nil {
+		if inStorage {
+			info.SiteBag = hex.EncodeToString(site)
+		} else {
+			info.SiteADNL = hex.EncodeToString(site)
+		}
+	}
+
+	// domain which is not taken from auction yet has no owner
+	if data, err := res.GetNFTDataAtBlock(ctx, block); err == nil && data.OwnerAddress != nil && !data.OwnerAddress.IsAddrNone() {
+		info.Owner = data.OwnerAddress.String()
+	}
+
+	a.dnsCache.Add(key, info)
+	writeJSON(w, http.StatusOK, info)
+}
+
+// dnsRoot returns address of root dns contract from config param 4, it is not changed in practice,
+// so it is loaded once
+func (a *HelperAPI) dnsRoot(ctx context.Context, block *ton.BlockIDExt) (*address.Address, error) {
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	if a.root != nil {
+		return a.root, nil
+	}
+
+	cfg, err := a.api.GetBlockchainConfig(ctx, block, 4)
+	if err != nil {
+		return nil, err
+	}
+
+	param := cfg.Get(4)
+	if param == nil {
+		return nil, fmt.Errorf("root dns address is not in config")
+	}
+
+	hash, err := param.BeginParse().LoadSlice(256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load root dns address: %w", err)
+	}
+
+	a.root = address.NewAddress(0, 255, hash)
+	return a.root, nil
+}
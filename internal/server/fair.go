@@ -0,0 +1,135 @@
+package server
+
+import (
+	"context"
+	"sync"
+)
+
+type keyNameCtx struct{}
+
+// withKeyName marks context with name of client key, so backend slots can be shared fairly between keys
+func withKeyName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, keyNameCtx{}, name)
+}
+
+// keyNameFrom returns name of client key, internal queries of proxy have empty name and share one turn
+func keyNameFrom(ctx context.Context) string {
+	name, _ := ctx.Value(keyNameCtx{}).(string)
+	return name
+}
+
+type internalQueryCtx struct{}
+
+// withInternalQuery marks context of internal query of proxy, like master polling, which waits for new block
+// and should not hold backend slot of clients
+func withInternalQuery(ctx context.Context) context.Context {
+	return context.WithValue(ctx, internalQueryCtx{}, true)
+}
+
+func isInternalQuery(ctx context.Context) bool {
+	internal, _ := ctx.Value(internalQueryCtx{}).(bool)
+	return internal
+}
+
+// fairQueue limits backend queries in flight, when all slots are busy, waiting queries of different keys
+// get free slots in turn, so key which floods requests waits in its own queue and cannot starve others
+type fairQueue struct {
+	max     int
+	running int
+
+	waiting map[string][]chan struct{}
+	// keys with waiting queries, in order of their turn
+	order []string
+
+	mx sync.Mutex
+}
+
+func newFairQueue(max int) *fairQueue {
+	return &fairQueue{
+		max:     max,
+		waiting: map[string][]chan struct{}{},
+	}
+}
+
+// acquire waits for free slot, release should be called after query when nil is returned
+func (q *fairQueue) acquire(ctx context.Context, key string) error {
+	q.mx.Lock()
+	if q.running < q.max {
+		q.running++
+		q.mx.Unlock()
+		return nil
+	}
+
+	ch := make(chan struct{}, 1)
+	if len(q.waiting[key]) == 0 {
+		q.order = append(q.order, key)
+	}
+	q.waiting[key] = append(q.waiting[key], ch)
+	q.mx.Unlock()
+
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+	}
+
+	q.mx.Lock()
+	removed := q.remove(key, ch)
+	q.mx.Unlock()
+
+	if !removed {
+		// slot was given at the same time
+		<-ch
+		q.release()
+	}
+	return ctx.Err()
+}
+
+func (q *fairQueue) release() {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	if len(q.order) == 0 {
+		q.running--
+		return
+	}
+
+	key := q.order[0]
+	q.order = q.order[1:]
+
+	list := q.waiting[key]
+	ch := list[0]
+	if len(list) > 1 {
+		q.waiting[key] = list[1:]
+		// key waits for the next turn after all other keys
+		q.order = append(q.order, key)
+	} else {
+		delete(q.waiting, key)
+	}
+
+	// slot is passed to waiter as is, so running is not changed
+	ch <- struct{}{}
+}
+
+func (q *fairQueue) remove(key string, ch chan struct{}) bool {
+	list := q.waiting[key]
+	for i, w := range list {
+		if w != ch {
+			continue
+		}
+
+		if len(list) == 1 {
+			delete(q.waiting, key)
+			for j, k := range q.order {
+				if k == key {
+					q.order = append(q.order[:j], q.order[j+1:]...)
+					break
+				}
+			}
+		} else {
+			q.waiting[key] = append(list[:i], list[i+1:]...)
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+	"testing"
+	"time"
+)
+
+// answerClient answers every query with current time
+type answerClient struct {
+	ton.LiteClient
+}
+
+func (c *answerClient) QueryLiteserver(_ context.Context, _ tl.Serializable, result tl.Serializable) error {
+	*result.(*tl.Serializable) = ton.CurrentTime{}
+	return nil
+}
+
+func TestFairClientBusy(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     func() context.Context
+		payload tl.Serializable
+		wantErr error
+	}{
+		{name: "client query waits for slot", ctx: context.Background, payload: ton.GetTime{}, wantErr: context.DeadlineExceeded},
+		{name: "internal master polling skips slots", ctx: func() context.Context {
+			return withInternalQuery(context.Background())
+		}, payload: tl.Raw{}},
+		{name: "wrapped query skips slots", ctx: context.Background, payload: []tl.Serializable{ton.GetTime{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fair := newFairQueue(1)
+			// the only slot is held by another query
+			if err := fair.acquire(context.Background(), "other"); err != nil {
+				t.Fatal(err)
+			}
+			defer fair.release()
+
+			ctx, cancel := context.WithTimeout(tt.ctx(), 20*time.Millisecond)
+			defer cancel()
+
+			var resp tl.Serializable
+			c := &fairClient{LiteClient: &answerClient{}, fair: fair}
+			if err := c.QueryLiteserver(ctx, tt.payload, &resp); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestFairQueueOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		waiting []string
+		want    []string
+	}{
+		{name: "one key", waiting: []string{"a", "a", "a"}, want: []string{"a", "a", "a"}},
+		{name: "flooding key does not starve others", waiting: []string{"a", "a", "a", "b", "c"}, want: []string{"a", "b", "c", "a", "a"}},
+		{name: "keys take turns", waiting: []string{"a", "b", "a", "b"}, want: []string{"a", "b", "a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newFairQueue(1)
+			if err := q.acquire(context.Background(), "holder"); err != nil {
+				t.Fatal(err)
+			}
+
+			got := make(chan string, len(tt.waiting))
+			for i, key := range tt.waiting {
+				go func(key string) {
+					if err := q.acquire(context.Background(), key); err != nil {
+						t.Error(err)
+						return
+					}
+					got <- key
+				}(key)
+
+				// waiters are queued in order of the list
+				for waiting(q) != i+1 {
+					time.Sleep(time.Millisecond)
+				}
+			}
+
+			for i, want := range tt.want {
+				q.release()
+				if key := <-got; key != want {
+					t.Fatalf("turn %d: expected key %s, got %s", i, want, key)
+				}
+			}
+			q.release()
+
+			if q.running != 0 {
+				t.Fatalf("expected no running queries, got %d", q.running)
+			}
+		})
+	}
+}
+
+func TestFairQueueCanceled(t *testing.T) {
+	q := newFairQueue(1)
+	if err := q.acquire(context.Background(), "holder"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := q.acquire(ctx, "a"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error, got %v", err)
+	}
+	if waiting(q) != 0 || len(q.order) != 0 {
+		t.Fatal("canceled waiter is left in queue")
+	}
+
+	q.release()
+	if q.running != 0 {
+		t.Fatalf("expected no running queries, got %d", q.running)
+	}
+}
+
+func waiting(q *fairQueue) int {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	n := 0
+	for _, list := range q.waiting {
+		n += len(list)
+	}
+	return n
+}
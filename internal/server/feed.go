@@ -0,0 +1,73 @@
+package server
+
+import (
+	"github.com/xssnick/tonutils-go/ton"
+	"sync"
+)
+
+// BlockEvent is published when new master block becomes the latest in cache,
+// Shards contains shard blocks which were first seen in it
+type BlockEvent struct {
+	Master  *ton.BlockIDExt
+	GenTime uint32
+	Shards  []*ton.BlockIDExt
+}
+
+type blockFeed struct {
+	subs map[chan *BlockEvent]struct{}
+	mx   sync.Mutex
+}
+
+func (f *blockFeed) subscribe(buffer int) (<-chan *BlockEvent, func()) {
+	ch := make(chan *BlockEvent, buffer)
+
+	f.mx.Lock()
+	if f.subs == nil {
+		f.subs = map[chan *BlockEvent]struct{}{}
+	}
+	f.subs[ch] = struct{}{}
+	f.mx.Unlock()
+
+	return ch, func() {
+		f.mx.Lock()
+		defer f.mx.Unlock()
+
+		if _, ok := f.subs[ch]; ok {
+			delete(f.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+func (f *blockFeed) publish(ev *BlockEvent) {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	for ch := range f.subs {
+		select {
+		case ch <- ev:
+		default:
+			// subscriber is too slow, we close its channel instead of blocking cache updates,
+			// so it can reconnect and continue from the latest block
+			delete(f.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// close closes channels of all subscribers
+func (f *blockFeed) close() {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	for ch := range f.subs {
+		delete(f.subs, ch)
+		close(ch)
+	}
+}
+
+// SubscribeBlocks returns channel with new block events, it is closed on unsubscribe, cache close
+// or when subscriber cannot keep up with the chain
+func (c *BlockCache) SubscribeBlocks(buffer int) (<-chan *BlockEvent, func()) {
+	return c.feed.subscribe(buffer)
+}
@@ -0,0 +1,31 @@
+//go:build linux || darwin
+
+package server
+
+import (
+	"golang.org/x/sys/unix"
+	"os"
+)
+
+// lockFile takes advisory lock of file at path, shared or exclusive, the file is created when missing,
+// returned function releases the lock
+func lockFile(path string, exclusive bool) (func(), error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	how := unix.LOCK_SH
+	if exclusive {
+		how = unix.LOCK_EX
+	}
+	if err = unix.Flock(int(f.Fd()), how); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	return func() {
+		_ = unix.Flock(int(f.Fd()), unix.LOCK_UN)
+		_ = f.Close()
+	}, nil
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/kevinms/leakybucket-go"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"sync"
+	"time"
+)
+
+// gasBudget limits gas of get methods emulated for client key, it is separate from requests limits,
+// so keys calling cheap getters are not throttled the same way as ones running heavy methods
+type gasBudget struct {
+	bucket *leakybucket.LeakyBucket
+	mx     sync.Mutex
+}
+
+func newGasBudget(perSec float64, capacity int64) *gasBudget {
+	return &gasBudget{bucket: leakybucket.NewLeakyBucket(perSec, capacity)}
+}
+
+// available reports if there is gas left, otherwise time until it is refilled is returned
+func (g *gasBudget) available() (time.Duration, bool) {
+	g.mx.Lock()
+	defer g.mx.Unlock()
+
+	if g.bucket.Remaining() > 0 {
+		return 0, true
+	}
+	return retryAfter(g.bucket.TillEmpty(), g.bucket.Capacity(), g.bucket.Rate(), 1), false
+}
+
+// charge spends used gas, actual usage is known only after emulation, so budget is drained to zero at most
+func (g *gasBudget) charge(used int64) {
+	g.mx.Lock()
+	defer g.mx.Unlock()
+
+	g.bucket.Add(used)
+}
+
+// takeGasBudget returns gas budget of key to charge after emulation, nil when key has no budget or is exempt,
+// error for client is returned when budget is exceeded
+func (s *ProxyBalancer) takeGasBudget(ctx context.Context) (*gasBudget, *ton.LSError) {
+	keyName := keyNameFrom(ctx)
+	gas := s.gasBudgets[keyName]
+	if gas == nil || isExempt(ctx) {
+		// exempt clients are not limited by gas the same as by requests
+		return nil, nil
+	}
+
+	if wait, ok := gas.available(); !ok {
+		metrics.Global.RetryAfter.WithLabelValues(keyName).Observe(wait.Seconds())
+		return nil, &ton.LSError{
+			Code: 429,
+			Text: fmt.Sprintf("gas budget is exceeded, retry after %dms", wait.Milliseconds()),
+		}
+	}
+	return gas, nil
+}
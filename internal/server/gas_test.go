@@ -0,0 +1,57 @@
+package server
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGasBudget(t *testing.T) {
+	tests := []struct {
+		name    string
+		used    int64
+		exempt  bool
+		limited bool
+		charged bool
+	}{
+		{name: "budget left", used: 50, charged: true},
+		{name: "budget is drained", used: 100, limited: true},
+		{name: "exempt client is not limited", used: 100, exempt: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestProxy(&testCache{})
+			budget := newGasBudget(0.001, 100)
+			budget.charge(tt.used)
+			s.gasBudgets["test"] = budget
+
+			ctx := withKeyName(context.Background(), "test")
+			if tt.exempt {
+				ctx = withExempt(ctx)
+			}
+
+			gas, ls := s.takeGasBudget(ctx)
+			if (ls != nil) != tt.limited {
+				t.Fatalf("expected limited %v, got %v", tt.limited, ls)
+			}
+			if ls != nil && ls.Code != 429 {
+				t.Fatalf("expected code 429, got %d", ls.Code)
+			}
+			if (gas != nil) != tt.charged {
+				t.Fatalf("expected budget to charge %v, got %v", tt.charged, gas != nil)
+			}
+		})
+	}
+}
+
+func TestGasBudgetOtherKey(t *testing.T) {
+	s := newTestProxy(&testCache{})
+	budget := newGasBudget(0.001, 100)
+	budget.charge(100)
+	s.gasBudgets["test"] = budget
+
+	// budget of one key does not limit others
+	if gas, ls := s.takeGasBudget(withKeyName(context.Background(), "other")); gas != nil || ls != nil {
+		t.Fatalf("expected no budget, got %v, %v", gas, ls)
+	}
+}
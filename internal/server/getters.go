@@ -0,0 +1,108 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+	"github.com/xssnick/tonutils-liteserver-proxy/internal/emulate"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"sync"
+)
+
+// precomputedGetters keeps results of get methods without arguments for watched accounts,
+// they are computed once per state of account, so the same method is not emulated for every client
+type precomputedGetters struct {
+	methods map[uint64]string
+
+	accounts map[string]*precomputedAccount
+	mx       sync.RWMutex
+}
+
+type precomputedAccount struct {
+	stateHash []byte
+	results   map[uint64]*emulate.RunResult
+}
+
+// EnablePrecomputedGetters runs methods by names for every new state of accounts in watchlist,
+// runSmcMethod of these methods without arguments is answered from results instead of emulation
+func (s *ProxyBalancer) EnablePrecomputedGetters(wl *Watchlist, methods []string) {
+	g := &precomputedGetters{
+		methods:  map[uint64]string{},
+		accounts: map[string]*precomputedAccount{},
+	}
+	for _, name := range methods {
+		g.methods[tlb.MethodNameHash(name)] = name
+	}
+	s.getters = g
+
+	wl.setRefreshHook(s.precompute)
+}
+
+// precomputedResult returns result of method for state when it was computed, nil otherwise,
+// c7 of emulation is not kept, so queries asking for it are emulated as usual
+func (s *ProxyBalancer) precomputedResult(addr *address.Address, state *cell.Cell, v *ton.RunSmcMethod) *emulate.RunResult {
+	g := s.getters
+	if g == nil || v.Mode&8 != 0 || !isEmptyStack(v) {
+		return nil
+	}
+
+	g.mx.RLock()
+	defer g.mx.RUnlock()
+
+	acc := g.accounts[addr.String()]
+	if acc == nil || !bytes.Equal(acc.stateHash, state.Hash()) {
+		return nil
+	}
+	return acc.results[v.MethodID]
+}
+
+func (s *ProxyBalancer) precompute(ctx context.Context, block *Block, addr *address.Address, state *ton.AccountState) {
+	g := s.getters
+	if state.State == nil || block.ID.Workchain != -1 {
+		return
+	}
+
+	hash := state.State.Hash()
+	g.mx.RLock()
+	acc := g.accounts[addr.String()]
+	g.mx.RUnlock()
+	if acc != nil && bytes.Equal(acc.stateHash, hash) {
+		// state is not changed by new block
+		return
+	}
+
+	var st tlb.AccountState
+	if err := st.LoadFromCell(state.State.BeginParse()); err != nil {
+		return
+	}
+
+	masterBlock, _, err := s.cache.GetMasterBlock(ctx, block.ID)
+	if err != nil {
+		return
+	}
+
+	params, err := tlb.NewStack().ToCell()
+	if err != nil {
+		return
+	}
+
+	acc = &precomputedAccount{stateHash: hash, results: map[uint64]*emulate.RunResult{}}
+	for id, name := range g.methods {
+		res, _, _, errResp, _ := s.runGetMethod(ctx, block, masterBlock, addr, &st, params, id)
+		if errResp != nil {
+			log.Debug().Str("addr", addr.String()).Str("method", name).Interface("error", errResp).Msg("failed to precompute get method")
+			metrics.Global.PrecomputedGetters.WithLabelValues("failed").Add(1)
+			continue
+		}
+		acc.results[id] = res
+		metrics.Global.PrecomputedGetters.WithLabelValues("computed").Add(1)
+	}
+
+	g.mx.Lock()
+	g.accounts[addr.String()] = acc
+	g.mx.Unlock()
+}
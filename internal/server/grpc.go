@@ -0,0 +1,741 @@
+package server
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/subtle"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+	"github.com/xssnick/tonutils-liteserver-proxy/api/liteserver"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"net"
+	"reflect"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// GRPCServer serves liteserver methods over gRPC, queries are processed
+// by the same pipeline as ADNL ones, so cache and emulation are shared
+type GRPCServer struct {
+	liteserver.UnimplementedLiteServerServer
+
+	proxy *ProxyBalancer
+	token string
+	// limits of this client apply to grpc queries
+	lim *KeyConfig
+}
+
+// NewGRPCServer creates grpc server with registered liteserver service, when token is not empty
+// it is required to be passed as bearer authorization metadata. Queries are accounted as queries of client,
+// so its rate limits, bandwidth quota and in-flight limit apply to them.
+func NewGRPCServer(proxy *ProxyBalancer, token, client string) (*grpc.Server, error) {
+	key, ok := proxy.keysByName[client]
+	if !ok {
+		return nil, fmt.Errorf("unknown client %s", client)
+	}
+
+	g := &GRPCServer{
+		proxy: proxy,
+		token: token,
+		lim:   proxy.configs[string(key.Public().(ed25519.PublicKey))],
+	}
+
+	srv := grpc.NewServer(grpc.UnaryInterceptor(g.authorize))
+	liteserver.RegisterLiteServerServer(srv, g)
+	return srv, nil
+}
+
+func (g *GRPCServer) authorize(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if g.token != "" {
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		var auth string
+		if v := md.Get("authorization"); len(v) > 0 {
+			auth = v[0]
+		}
+		if subtle.ConstantTimeCompare([]byte(auth), []byte("Bearer "+g.token)) != 1 {
+			return nil, status.Error(codes.Unauthenticated, "unauthorized")
+		}
+	}
+	return handler(ctx, req)
+}
+
+// request is grpc request message, each of them can have wait master
+type request interface {
+	proto.Message
+	GetWait() *liteserver.WaitMasterchainSeqno
+}
+
+func (g *GRPCServer) query(ctx context.Context, req request, query tl.Serializable) (resp tl.Serializable, err error) {
+	if wait := req.GetWait(); wait != nil {
+		// the same wrapping as liteclient does, so wait is processed by the same path as for adnl queries
+		query = []tl.Serializable{ton.WaitMasterchainSeqno{Seqno: int32(wait.Seqno), Timeout: int32(wait.TimeoutMs)}, query}
+	}
+
+	reqID := newRequestID()
+	ctx = log.With().Str("request_id", reqID).Logger().WithContext(ctx)
+	// size limit of queries applies to grpc message as received
+	ctx = withQuerySize(ctx, proto.Size(req))
+	lim := g.lim
+
+	defer func() {
+		if r := recover(); r != nil {
+			metrics.Global.Panics.WithLabelValues(metrics.Global.TypeLabel(query)).Add(1)
+			stack := string(debug.Stack())
+			log.Ctx(ctx).Error().Interface("panic", r).Str("stack", stack).Type("request", query).Msg("grpc query processing panicked")
+			reportError(ReportKindPanic, fmt.Sprint(r), map[string]string{
+				"request_id":   reqID,
+				"key_name":     lim.name,
+				"request_type": reflect.TypeOf(query).String(),
+			}, stack)
+			err = status.Error(codes.Internal, "internal error")
+		}
+	}()
+
+	var ip string
+	if p, ok := peer.FromContext(ctx); ok {
+		ip = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+		ip = normalizeIP(ip)
+	}
+	exempt := lim.exempt || g.proxy.isExemptIP(ip)
+	if exempt {
+		ctx = withExempt(ctx)
+	}
+
+	ls := g.proxy.admit(lim, ip, queryCost(query), exempt)
+	metrics.Global.Requests.WithLabelValues(lim.name, metrics.Global.TypeLabel(query), fmt.Sprint(ls != nil)).Add(1)
+	if ls != nil {
+		resp = *ls
+	} else {
+		defer atomic.AddInt64(&lim.inFlight, -1)
+
+		resp = g.proxy.processQuery(ctx, lim.name, query)
+		if resp == nil {
+			return nil, status.Error(codes.Unavailable, "no answer")
+		}
+	}
+
+	// the same size limit and accounting as for adnl answers
+	if _, resp, err = g.proxy.encodeAnswer(lim, reqID, query, resp); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Type("request", query).Msg("failed to encode grpc answer")
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	if t, ok := resp.(ton.LSError); ok {
+		return nil, status.Error(lsErrorCode(t.Code), fmt.Sprintf("%d: %s", t.Code, t.Text))
+	}
+	return resp, nil
+}
+
+func lsErrorCode(code int32) codes.Code {
+	switch code {
+	case 400:
+		return codes.InvalidArgument
+	case 404, 651:
+		return codes.NotFound
+	case 429:
+		return codes.ResourceExhausted
+	case 413:
+		return codes.OutOfRange
+	case 502:
+		return codes.Unavailable
+	case 652:
+		return codes.DeadlineExceeded
+	case ton.ErrCodeContractNotInitialized:
+		return codes.FailedPrecondition
+	}
+	return codes.Unknown
+}
+
+func unexpectedAnswer(resp tl.Serializable) error {
+	return status.Error(codes.Internal, fmt.Sprintf("unexpected answer type %T", resp))
+}
+
+func (g *GRPCServer) GetMasterchainInfo(ctx context.Context, req *liteserver.GetMasterchainInfoRequest) (*liteserver.MasterchainInfo, error) {
+	resp, err := g.query(ctx, req, ton.GetMasterchainInf{})
+	if err != nil {
+		return nil, err
+	}
+
+	t, ok := resp.(ton.MasterchainInfo)
+	if !ok {
+		return nil, unexpectedAnswer(resp)
+	}
+
+	return &liteserver.MasterchainInfo{
+		Last:          fromBlockID(t.Last),
+		StateRootHash: t.StateRootHash,
+		Init:          fromZeroState(t.Init),
+	}, nil
+}
+
+func (g *GRPCServer) GetMasterchainInfoExt(ctx context.Context, req *liteserver.GetMasterchainInfoExtRequest) (*liteserver.MasterchainInfoExt, error) {
+	resp, err := g.query(ctx, req, ton.GetMasterchainInfoExt{Mode: req.Mode})
+	if err != nil {
+		return nil, err
+	}
+
+	t, ok := resp.(ton.MasterchainInfoExt)
+	if !ok {
+		return nil, unexpectedAnswer(resp)
+	}
+
+	return &liteserver.MasterchainInfoExt{
+		Mode:          t.Mode,
+		Version:       t.Version,
+		Capabilities:  t.Capabilities,
+		Last:          fromBlockID(t.Last),
+		LastUtime:     t.LastUTime,
+		Now:           t.Now,
+		StateRootHash: t.StateRootHash,
+		Init:          fromZeroState(t.Init),
+	}, nil
+}
+
+func (g *GRPCServer) GetTime(ctx context.Context, req *liteserver.GetTimeRequest) (*liteserver.CurrentTime, error) {
+	resp, err := g.query(ctx, req, ton.GetTime{})
+	if err != nil {
+		return nil, err
+	}
+
+	t, ok := resp.(ton.CurrentTime)
+	if !ok {
+		return nil, unexpectedAnswer(resp)
+	}
+	return &liteserver.CurrentTime{Now: t.Now}, nil
+}
+
+func (g *GRPCServer) GetVersion(ctx context.Context, req *liteserver.GetVersionRequest) (*liteserver.Version, error) {
+	resp, err := g.query(ctx, req, ton.GetVersion{})
+	if err != nil {
+		return nil, err
+	}
+
+	t, ok := resp.(ton.Version)
+	if !ok {
+		return nil, unexpectedAnswer(resp)
+	}
+
+	return &liteserver.Version{
+		Mode:         t.Mode,
+		Version:      t.Version,
+		Capabilities: t.Capabilities,
+		Now:          t.Now,
+	}, nil
+}
+
+func (g *GRPCServer) GetBlock(ctx context.Context, req *liteserver.GetBlockRequest) (*liteserver.BlockData, error) {
+	resp, err := g.query(ctx, req, ton.GetBlockData{ID: toBlockID(req.Id)})
+	if err != nil {
+		return nil, err
+	}
+
+	t, ok := resp.(ton.BlockData)
+	if !ok {
+		return nil, unexpectedAnswer(resp)
+	}
+
+	return &liteserver.BlockData{
+		Id:   fromBlockID(t.ID),
+		Data: cellToBOC(t.Payload),
+	}, nil
+}
+
+func (g *GRPCServer) LookupBlock(ctx context.Context, req *liteserver.LookupBlockRequest) (*liteserver.BlockHeader, error) {
+	resp, err := g.query(ctx, req, ton.LookupBlock{
+		Mode: req.Mode,
+		ID: &ton.BlockInfoShort{
+			Workchain: req.Workchain,
+			Shard:     req.Shard,
+			Seqno:     int32(req.Seqno),
+		},
+		LT:    req.Lt,
+		UTime: req.Utime,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	t, ok := resp.(ton.BlockHeader)
+	if !ok {
+		return nil, unexpectedAnswer(resp)
+	}
+
+	return &liteserver.BlockHeader{
+		Id:          fromBlockID(t.ID),
+		Mode:        t.Mode,
+		HeaderProof: cellToBOC(t.HeaderProof),
+	}, nil
+}
+
+func (g *GRPCServer) GetAccountState(ctx context.Context, req *liteserver.GetAccountStateRequest) (*liteserver.AccountState, error) {
+	acc, err := toAccountID(req.Account)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := g.query(ctx, req, ton.GetAccountState{ID: toBlockID(req.Id), Account: *acc})
+	if err != nil {
+		return nil, err
+	}
+
+	t, ok := resp.(ton.AccountState)
+	if !ok {
+		return nil, unexpectedAnswer(resp)
+	}
+
+	return &liteserver.AccountState{
+		Id:         fromBlockID(t.ID),
+		Shard:      fromBlockID(t.Shard),
+		ShardProof: cellsToBOC(t.ShardProof),
+		Proof:      cellsToBOC(t.Proof),
+		State:      cellToBOC(t.State),
+	}, nil
+}
+
+func (g *GRPCServer) RunSmcMethod(ctx context.Context, req *liteserver.RunSmcMethodRequest) (*liteserver.RunMethodResult, error) {
+	acc, err := toAccountID(req.Account)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := cell.FromBOC(req.Params)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid params boc")
+	}
+
+	resp, err := g.query(ctx, req, ton.RunSmcMethod{
+		Mode:     req.Mode,
+		ID:       toBlockID(req.Id),
+		Account:  *acc,
+		MethodID: req.MethodId,
+		Params:   params,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	t, ok := resp.(ton.RunMethodResult)
+	if !ok {
+		return nil, unexpectedAnswer(resp)
+	}
+
+	return &liteserver.RunMethodResult{
+		Mode:       t.Mode,
+		Id:         fromBlockID(t.ID),
+		ShardBlock: fromBlockID(t.ShardBlock),
+		ShardProof: cellsToBOC(t.ShardProof),
+		Proof:      cellsToBOC(t.Proof),
+		StateProof: cellToBOC(t.StateProof),
+		InitC7:     cellToBOC(t.InitC7),
+		LibExtras:  cellToBOC(t.LibExtras),
+		ExitCode:   t.ExitCode,
+		Result:     cellToBOC(t.Result),
+	}, nil
+}
+
+func (g *GRPCServer) GetOneTransaction(ctx context.Context, req *liteserver.GetOneTransactionRequest) (*liteserver.TransactionInfo, error) {
+	acc, err := toAccountID(req.Account)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := g.query(ctx, req, ton.GetOneTransaction{ID: toBlockID(req.Id), AccID: acc, LT: req.Lt})
+	if err != nil {
+		return nil, err
+	}
+
+	t, ok := resp.(ton.TransactionInfo)
+	if !ok {
+		return nil, unexpectedAnswer(resp)
+	}
+
+	return &liteserver.TransactionInfo{
+		Id:          fromBlockID(t.ID),
+		Proof:       t.Proof,
+		Transaction: t.Transaction,
+	}, nil
+}
+
+func (g *GRPCServer) GetLibraries(ctx context.Context, req *liteserver.GetLibrariesRequest) (*liteserver.LibraryResult, error) {
+	resp, err := g.query(ctx, req, ton.GetLibraries{LibraryList: req.Hashes})
+	if err != nil {
+		return nil, err
+	}
+
+	t, ok := resp.(ton.LibraryResult)
+	if !ok {
+		return nil, unexpectedAnswer(resp)
+	}
+
+	res := &liteserver.LibraryResult{}
+	for _, e := range t.Result {
+		if e == nil {
+			continue
+		}
+		res.Result = append(res.Result, &liteserver.LibraryEntry{
+			Hash: e.Hash,
+			Data: cellToBOC(e.Data),
+		})
+	}
+	return res, nil
+}
+
+func (g *GRPCServer) SendMessage(ctx context.Context, req *liteserver.SendMessageRequest) (*liteserver.SendMessageStatus, error) {
+	resp, err := g.query(ctx, req, ton.SendMessage{Body: req.Body})
+	if err != nil {
+		return nil, err
+	}
+
+	t, ok := resp.(ton.SendMessageStatus)
+	if !ok {
+		return nil, unexpectedAnswer(resp)
+	}
+	return &liteserver.SendMessageStatus{Status: t.Status}, nil
+}
+
+func (g *GRPCServer) GetTransactions(ctx context.Context, req *liteserver.GetTransactionsRequest) (*liteserver.TransactionList, error) {
+	acc, err := toAccountID(req.Account)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := g.query(ctx, req, ton.GetTransactions{
+		Limit:  int32(req.Limit),
+		AccID:  acc,
+		LT:     req.Lt,
+		TxHash: req.Hash,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	t, ok := resp.(ton.TransactionList)
+	if !ok {
+		return nil, unexpectedAnswer(resp)
+	}
+
+	res := &liteserver.TransactionList{Transactions: t.Transactions}
+	for _, id := range t.IDs {
+		res.Ids = append(res.Ids, fromBlockID(id))
+	}
+	return res, nil
+}
+
+func (g *GRPCServer) GetBlockHeader(ctx context.Context, req *liteserver.GetBlockHeaderRequest) (*liteserver.BlockHeader, error) {
+	resp, err := g.query(ctx, req, ton.GetBlockHeader{
+		Mode: req.Mode,
+		ID: &ton.BlockInfoShort{
+			Workchain: req.Workchain,
+			Shard:     req.Shard,
+			Seqno:     int32(req.Seqno),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	t, ok := resp.(ton.BlockHeader)
+	if !ok {
+		return nil, unexpectedAnswer(resp)
+	}
+
+	return &liteserver.BlockHeader{
+		Id:          fromBlockID(t.ID),
+		Mode:        t.Mode,
+		HeaderProof: cellToBOC(t.HeaderProof),
+	}, nil
+}
+
+func (g *GRPCServer) GetConfigAll(ctx context.Context, req *liteserver.GetConfigAllRequest) (*liteserver.ConfigInfo, error) {
+	resp, err := g.query(ctx, req, ton.GetConfigAll{Mode: int32(req.Mode), BlockID: toBlockID(req.Id)})
+	if err != nil {
+		return nil, err
+	}
+	return toConfigInfo(resp)
+}
+
+func (g *GRPCServer) GetConfigParams(ctx context.Context, req *liteserver.GetConfigParamsRequest) (*liteserver.ConfigInfo, error) {
+	resp, err := g.query(ctx, req, ton.GetConfigParams{
+		Mode:    int32(req.Mode),
+		BlockID: toBlockID(req.Id),
+		Params:  req.Params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toConfigInfo(resp)
+}
+
+func toConfigInfo(resp tl.Serializable) (*liteserver.ConfigInfo, error) {
+	t, ok := resp.(ton.ConfigAll)
+	if !ok {
+		return nil, unexpectedAnswer(resp)
+	}
+
+	return &liteserver.ConfigInfo{
+		Mode:        uint32(t.Mode),
+		Id:          fromBlockID(t.ID),
+		StateProof:  cellToBOC(t.StateProof),
+		ConfigProof: cellToBOC(t.ConfigProof),
+	}, nil
+}
+
+func (g *GRPCServer) GetAllShardsInfo(ctx context.Context, req *liteserver.GetAllShardsInfoRequest) (*liteserver.AllShardsInfo, error) {
+	resp, err := g.query(ctx, req, ton.GetAllShardsInfo{ID: toBlockID(req.Id)})
+	if err != nil {
+		return nil, err
+	}
+
+	t, ok := resp.(ton.AllShardsInfo)
+	if !ok {
+		return nil, unexpectedAnswer(resp)
+	}
+
+	return &liteserver.AllShardsInfo{
+		Id:    fromBlockID(t.ID),
+		Proof: cellsToBOC(t.Proof),
+		Data:  cellToBOC(t.Data),
+	}, nil
+}
+
+func (g *GRPCServer) GetShardInfo(ctx context.Context, req *liteserver.GetShardInfoRequest) (*liteserver.ShardInfo, error) {
+	resp, err := g.query(ctx, req, ton.GetShardInfo{
+		ID:        toBlockID(req.Id),
+		Workchain: req.Workchain,
+		Shard:     req.Shard,
+		Exact:     req.Exact,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	t, ok := resp.(ton.ShardInfo)
+	if !ok {
+		return nil, unexpectedAnswer(resp)
+	}
+
+	return &liteserver.ShardInfo{
+		Id:         fromBlockID(t.ID),
+		ShardBlock: fromBlockID(t.ShardBlock),
+		ShardProof: cellsToBOC(t.ShardProof),
+		ShardDescr: cellToBOC(t.ShardDescription),
+	}, nil
+}
+
+func (g *GRPCServer) ListBlockTransactions(ctx context.Context, req *liteserver.ListBlockTransactionsRequest) (*liteserver.BlockTransactions, error) {
+	resp, err := g.query(ctx, req, toListBlockTransactions(req))
+	if err != nil {
+		return nil, err
+	}
+
+	t, ok := resp.(ton.BlockTransactions)
+	if !ok {
+		return nil, unexpectedAnswer(resp)
+	}
+
+	res := &liteserver.BlockTransactions{
+		Id:         fromBlockID(t.ID),
+		ReqCount:   t.ReqCount,
+		Incomplete: t.Incomplete,
+		Proof:      cellToBOC(t.Proof),
+	}
+	for _, id := range t.TransactionIds {
+		res.Ids = append(res.Ids, &liteserver.TransactionID{
+			Mode:    id.Flags,
+			Account: id.Account,
+			Lt:      id.LT,
+			Hash:    id.Hash,
+		})
+	}
+	return res, nil
+}
+
+func (g *GRPCServer) ListBlockTransactionsExt(ctx context.Context, req *liteserver.ListBlockTransactionsRequest) (*liteserver.BlockTransactionsExt, error) {
+	resp, err := g.query(ctx, req, ton.ListBlockTransactionsExt(toListBlockTransactions(req)))
+	if err != nil {
+		return nil, err
+	}
+
+	t, ok := resp.(ton.BlockTransactionsExt)
+	if !ok {
+		return nil, unexpectedAnswer(resp)
+	}
+
+	return &liteserver.BlockTransactionsExt{
+		Id:           fromBlockID(t.ID),
+		ReqCount:     t.ReqCount,
+		Incomplete:   t.Incomplete,
+		Transactions: cellToBOC(t.Transactions),
+		Proof:        t.Proof,
+	}, nil
+}
+
+// toListBlockTransactions sets mode flags of optional fields, they are derived from request fields
+func toListBlockTransactions(req *liteserver.ListBlockTransactionsRequest) ton.ListBlockTransactions {
+	q := ton.ListBlockTransactions{
+		ID:    toBlockID(req.Id),
+		Mode:  req.Mode &^ (1<<7 | 1<<6 | 1<<5),
+		Count: req.Count,
+	}
+	if req.After != nil {
+		q.Mode |= 1 << 7
+		q.After = &ton.TransactionID3{Account: req.After.Account, LT: req.After.Lt}
+	}
+	if req.ReverseOrder {
+		q.Mode |= 1 << 6
+		q.ReverseOrder = &ton.True{}
+	}
+	if req.WantProof {
+		q.Mode |= 1 << 5
+		q.WantProof = &ton.True{}
+	}
+	return q
+}
+
+func (g *GRPCServer) GetBlockProof(ctx context.Context, req *liteserver.GetBlockProofRequest) (*liteserver.PartialBlockProof, error) {
+	q := ton.GetBlockProof{
+		Mode:       req.Mode &^ 1,
+		KnownBlock: toBlockID(req.KnownBlock),
+	}
+	if req.TargetBlock != nil {
+		q.Mode |= 1
+		q.TargetBlock = toBlockID(req.TargetBlock)
+	}
+
+	resp, err := g.query(ctx, req, q)
+	if err != nil {
+		return nil, err
+	}
+
+	t, ok := resp.(ton.PartialBlockProof)
+	if !ok {
+		return nil, unexpectedAnswer(resp)
+	}
+
+	res := &liteserver.PartialBlockProof{
+		Complete: t.Complete,
+		From:     fromBlockID(t.From),
+		To:       fromBlockID(t.To),
+	}
+	for _, step := range t.Steps {
+		switch s := step.(type) {
+		case ton.BlockLinkBackward:
+			res.Steps = append(res.Steps, &liteserver.BlockLink{Link: &liteserver.BlockLink_Back{Back: &liteserver.BlockLinkBackward{
+				ToKeyBlock: s.ToKeyBlock,
+				From:       fromBlockID(s.From),
+				To:         fromBlockID(s.To),
+				DestProof:  s.DestProof,
+				Proof:      s.Proof,
+				StateProof: s.StateProof,
+			}}})
+		case ton.BlockLinkForward:
+			res.Steps = append(res.Steps, &liteserver.BlockLink{Link: &liteserver.BlockLink_Forward{Forward: &liteserver.BlockLinkForward{
+				ToKeyBlock:  s.ToKeyBlock,
+				From:        fromBlockID(s.From),
+				To:          fromBlockID(s.To),
+				DestProof:   s.DestProof,
+				ConfigProof: s.ConfigProof,
+				Signatures:  fromSignatureSet(s.SignatureSet),
+			}}})
+		default:
+			return nil, unexpectedAnswer(step)
+		}
+	}
+	return res, nil
+}
+
+func fromSignatureSet(set *ton.SignatureSet) *liteserver.SignatureSet {
+	if set == nil {
+		return nil
+	}
+
+	res := &liteserver.SignatureSet{
+		ValidatorSetHash: set.ValidatorSetHash,
+		CatchainSeqno:    set.CatchainSeqno,
+	}
+	for _, sig := range set.Signatures {
+		res.Signatures = append(res.Signatures, &liteserver.Signature{
+			NodeIdShort: sig.NodeIDShort,
+			Signature:   sig.Signature,
+		})
+	}
+	return res
+}
+
+func toBlockID(id *liteserver.BlockIDExt) *ton.BlockIDExt {
+	if id == nil {
+		return nil
+	}
+
+	return &ton.BlockIDExt{
+		Workchain: id.Workchain,
+		Shard:     id.Shard,
+		SeqNo:     id.Seqno,
+		RootHash:  id.RootHash,
+		FileHash:  id.FileHash,
+	}
+}
+
+func fromBlockID(id *ton.BlockIDExt) *liteserver.BlockIDExt {
+	if id == nil {
+		return nil
+	}
+
+	return &liteserver.BlockIDExt{
+		Workchain: id.Workchain,
+		Shard:     id.Shard,
+		Seqno:     id.SeqNo,
+		RootHash:  id.RootHash,
+		FileHash:  id.FileHash,
+	}
+}
+
+func fromZeroState(id *ton.ZeroStateIDExt) *liteserver.ZeroStateIDExt {
+	if id == nil {
+		return nil
+	}
+
+	return &liteserver.ZeroStateIDExt{
+		Workchain: id.Workchain,
+		RootHash:  id.RootHash,
+		FileHash:  id.FileHash,
+	}
+}
+
+func toAccountID(acc *liteserver.AccountID) (*ton.AccountID, error) {
+	if acc == nil || len(acc.Id) != 32 {
+		return nil, status.Error(codes.InvalidArgument, "account id should be 32 bytes")
+	}
+
+	return &ton.AccountID{
+		Workchain: acc.Workchain,
+		ID:        acc.Id,
+	}, nil
+}
+
+func cellToBOC(c *cell.Cell) []byte {
+	if c == nil {
+		return nil
+	}
+	return c.ToBOCWithFlags(false)
+}
+
+func cellsToBOC(list []*cell.Cell) []byte {
+	if len(list) == 0 {
+		return nil
+	}
+	return cell.ToBOCWithFlags(list, false)
+}
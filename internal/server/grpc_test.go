@@ -0,0 +1,288 @@
+package server
+
+import (
+	"context"
+	"github.com/kevinms/leakybucket-go"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+	"github.com/xssnick/tonutils-liteserver-proxy/api/liteserver"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGRPCQueryLimits(t *testing.T) {
+	tests := []struct {
+		name            string
+		lim             func() *KeyConfig
+		maxResponseSize int
+		code            codes.Code
+	}{
+		{
+			name: "answered",
+			lim:  func() *KeyConfig { return &KeyConfig{name: "test"} },
+			code: codes.OK,
+		},
+		{
+			name: "rate limited",
+			lim: func() *KeyConfig {
+				bucket := leakybucket.NewLeakyBucket(0.001, 1)
+				bucket.Add(1)
+				return &KeyConfig{name: "test", limiterPerKey: bucket}
+			},
+			code: codes.ResourceExhausted,
+		},
+		{
+			name: "too many in flight",
+			lim:  func() *KeyConfig { return &KeyConfig{name: "test", inFlight: 1, maxInFlight: 1} },
+			code: codes.ResourceExhausted,
+		},
+		{
+			name: "exempt key is not limited",
+			lim:  func() *KeyConfig { return &KeyConfig{name: "test", inFlight: 1, maxInFlight: 1, exempt: true} },
+			code: codes.OK,
+		},
+		{
+			name:            "response is too big",
+			lim:             func() *KeyConfig { return &KeyConfig{name: "test"} },
+			maxResponseSize: 4,
+			code:            codes.OutOfRange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestProxy(&testCache{})
+			s.maxResponseSize = tt.maxResponseSize
+
+			g := &GRPCServer{proxy: s, lim: tt.lim()}
+			inFlight := g.lim.inFlight
+
+			_, err := g.GetTime(context.Background(), nil)
+			if code := status.Code(err); code != tt.code {
+				t.Fatalf("expected code %s, got %s: %v", tt.code, code, err)
+			}
+			if g.lim.inFlight != inFlight {
+				t.Fatalf("in flight counter is not released, %d before, %d after", inFlight, g.lim.inFlight)
+			}
+		})
+	}
+}
+
+func TestGRPCQueryBandwidth(t *testing.T) {
+	s := newTestProxy(&testCache{})
+	g := &GRPCServer{proxy: s, lim: &KeyConfig{name: "test", bandwidth: newBandwidthQuota(1, time.Hour)}}
+
+	// the first answer is sent and uses up the quota
+	if _, err := g.GetTime(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.GetTime(context.Background(), nil); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected quota to be exceeded, got %v", err)
+	}
+}
+
+// answerBalancer answers every query with fixed answer and remembers the last query
+type answerBalancer struct {
+	Balancer
+	answer tl.Serializable
+	query  tl.Serializable
+}
+
+func (b *answerBalancer) Query(_ context.Context, payload tl.Serializable, result *tl.Serializable) error {
+	b.query = payload
+	*result = b.answer
+	return nil
+}
+
+// txCache has transactions of every account indexed
+type txCache struct {
+	testCache
+	list ton.TransactionList
+}
+
+func (c *txCache) GetTransactionsInCache(_ *ton.AccountID, _ int64, _ []byte, _ int32) *ton.TransactionList {
+	return &c.list
+}
+
+func TestGRPCMethods(t *testing.T) {
+	blk := &ton.BlockIDExt{Workchain: -1, Shard: -0x8000000000000000, SeqNo: 5, RootHash: make([]byte, 32), FileHash: make([]byte, 32)}
+	c := cell.BeginCell().MustStoreUInt(7, 8).EndCell()
+	boc := c.ToBOCWithFlags(false)
+	acc := make([]byte, 32)
+	acc[0] = 1
+
+	// shard info is not here, tonutils can't serialize its description cell stored as bytes
+	tests := []struct {
+		name      string
+		call      func(g *GRPCServer) (proto.Message, error)
+		wantQuery tl.Serializable
+		answer    tl.Serializable
+		wantResp  proto.Message
+	}{
+		{
+			name: "block header",
+			call: func(g *GRPCServer) (proto.Message, error) {
+				return g.GetBlockHeader(context.Background(), &liteserver.GetBlockHeaderRequest{Mode: 1, Workchain: -1, Shard: blk.Shard, Seqno: 5})
+			},
+			wantQuery: ton.GetBlockHeader{Mode: 1, ID: &ton.BlockInfoShort{Workchain: -1, Shard: blk.Shard, Seqno: 5}},
+			answer:    ton.BlockHeader{ID: blk, Mode: 1, HeaderProof: c},
+			wantResp:  &liteserver.BlockHeader{Id: fromBlockID(blk), Mode: 1, HeaderProof: boc},
+		},
+		{
+			name: "config all",
+			call: func(g *GRPCServer) (proto.Message, error) {
+				return g.GetConfigAll(context.Background(), &liteserver.GetConfigAllRequest{Mode: 2, Id: fromBlockID(blk)})
+			},
+			wantQuery: ton.GetConfigAll{Mode: 2, BlockID: blk},
+			answer:    ton.ConfigAll{Mode: 2, ID: blk, StateProof: c, ConfigProof: c},
+			wantResp:  &liteserver.ConfigInfo{Mode: 2, Id: fromBlockID(blk), StateProof: boc, ConfigProof: boc},
+		},
+		{
+			name: "config params",
+			call: func(g *GRPCServer) (proto.Message, error) {
+				return g.GetConfigParams(context.Background(), &liteserver.GetConfigParamsRequest{Id: fromBlockID(blk), Params: []int32{34}})
+			},
+			wantQuery: ton.GetConfigParams{BlockID: blk, Params: []int32{34}},
+			answer:    ton.ConfigAll{ID: blk, StateProof: c, ConfigProof: c},
+			wantResp:  &liteserver.ConfigInfo{Id: fromBlockID(blk), StateProof: boc, ConfigProof: boc},
+		},
+		{
+			name: "all shards info",
+			call: func(g *GRPCServer) (proto.Message, error) {
+				return g.GetAllShardsInfo(context.Background(), &liteserver.GetAllShardsInfoRequest{Id: fromBlockID(blk)})
+			},
+			wantQuery: ton.GetAllShardsInfo{ID: blk},
+			answer:    ton.AllShardsInfo{ID: blk, Proof: []*cell.Cell{c, c}, Data: c},
+			wantResp:  &liteserver.AllShardsInfo{Id: fromBlockID(blk), Proof: cellsToBOC([]*cell.Cell{c, c}), Data: boc},
+		},
+		{
+			name: "list block transactions",
+			call: func(g *GRPCServer) (proto.Message, error) {
+				return g.ListBlockTransactions(context.Background(), &liteserver.ListBlockTransactionsRequest{
+					Id: fromBlockID(blk), Mode: 7, Count: 10, After: &liteserver.TransactionID3{Account: acc, Lt: 5}, WantProof: true,
+				})
+			},
+			wantQuery: ton.ListBlockTransactions{
+				ID: blk, Mode: 7 | 1<<7 | 1<<5, Count: 10, After: &ton.TransactionID3{Account: acc, LT: 5}, WantProof: &ton.True{},
+			},
+			answer: ton.BlockTransactions{ID: blk, ReqCount: 10, Incomplete: true, TransactionIds: []ton.TransactionID{
+				{Flags: 7, Account: acc, LT: 6, Hash: acc},
+			}},
+			wantResp: &liteserver.BlockTransactions{Id: fromBlockID(blk), ReqCount: 10, Incomplete: true, Ids: []*liteserver.TransactionID{
+				{Mode: 7, Account: acc, Lt: 6, Hash: acc},
+			}},
+		},
+		{
+			name: "list block transactions ext",
+			call: func(g *GRPCServer) (proto.Message, error) {
+				return g.ListBlockTransactionsExt(context.Background(), &liteserver.ListBlockTransactionsRequest{Id: fromBlockID(blk), Count: 1, ReverseOrder: true})
+			},
+			wantQuery: ton.ListBlockTransactionsExt{ID: blk, Mode: 1 << 6, Count: 1, ReverseOrder: &ton.True{}},
+			answer:    ton.BlockTransactionsExt{ID: blk, ReqCount: 1, Transactions: c, Proof: []byte{}},
+			wantResp:  &liteserver.BlockTransactionsExt{Id: fromBlockID(blk), ReqCount: 1, Transactions: boc, Proof: []byte{}},
+		},
+		{
+			name: "block proof",
+			call: func(g *GRPCServer) (proto.Message, error) {
+				return g.GetBlockProof(context.Background(), &liteserver.GetBlockProofRequest{KnownBlock: fromBlockID(blk), TargetBlock: fromBlockID(blk)})
+			},
+			wantQuery: ton.GetBlockProof{Mode: 1, KnownBlock: blk, TargetBlock: blk},
+			answer: ton.PartialBlockProof{Complete: true, From: blk, To: blk, Steps: []any{
+				ton.BlockLinkBackward{From: blk, To: blk, DestProof: []byte{1}, Proof: []byte{2}, StateProof: []byte{3}},
+				ton.BlockLinkForward{ToKeyBlock: true, From: blk, To: blk, DestProof: []byte{1}, ConfigProof: []byte{2},
+					SignatureSet: &ton.SignatureSet{CatchainSeqno: 3, Signatures: []ton.Signature{{NodeIDShort: acc, Signature: []byte{4}}}}},
+			}},
+			wantResp: &liteserver.PartialBlockProof{Complete: true, From: fromBlockID(blk), To: fromBlockID(blk), Steps: []*liteserver.BlockLink{
+				{Link: &liteserver.BlockLink_Back{Back: &liteserver.BlockLinkBackward{
+					From: fromBlockID(blk), To: fromBlockID(blk), DestProof: []byte{1}, Proof: []byte{2}, StateProof: []byte{3},
+				}}},
+				{Link: &liteserver.BlockLink_Forward{Forward: &liteserver.BlockLinkForward{
+					ToKeyBlock: true, From: fromBlockID(blk), To: fromBlockID(blk), DestProof: []byte{1}, ConfigProof: []byte{2},
+					Signatures: &liteserver.SignatureSet{CatchainSeqno: 3, Signatures: []*liteserver.Signature{{NodeIdShort: acc, Signature: []byte{4}}}},
+				}}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestProxy(&testCache{})
+			b := &answerBalancer{answer: tt.answer}
+			s.backendBalancer = b
+
+			g := &GRPCServer{proxy: s, lim: &KeyConfig{name: "test"}}
+			resp, err := tt.call(g)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(b.query, tt.wantQuery) {
+				t.Fatalf("expected backend query %+v, got %+v", tt.wantQuery, b.query)
+			}
+			if !proto.Equal(resp, tt.wantResp) {
+				t.Fatalf("expected answer %v, got %v", tt.wantResp, resp)
+			}
+		})
+	}
+}
+
+func TestGRPCGetTransactionsFromCache(t *testing.T) {
+	blk := &ton.BlockIDExt{Workchain: 0, SeqNo: 5, RootHash: make([]byte, 32), FileHash: make([]byte, 32)}
+	s := newTestProxy(&txCache{list: ton.TransactionList{IDs: []*ton.BlockIDExt{blk}, Transactions: []byte{1, 2, 3}}})
+	b := &answerBalancer{}
+	s.backendBalancer = b
+
+	g := &GRPCServer{proxy: s, lim: &KeyConfig{name: "test"}}
+	resp, err := g.GetTransactions(context.Background(), &liteserver.GetTransactionsRequest{
+		Limit:   1,
+		Account: &liteserver.AccountID{Id: make([]byte, 32)},
+		Lt:      10,
+		Hash:    make([]byte, 32),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.query != nil {
+		t.Fatalf("expected answer from cache, backend got %v", b.query)
+	}
+
+	want := &liteserver.TransactionList{Ids: []*liteserver.BlockIDExt{fromBlockID(blk)}, Transactions: []byte{1, 2, 3}}
+	if !proto.Equal(resp, want) {
+		t.Fatalf("expected answer %v, got %v", want, resp)
+	}
+}
+
+func TestGRPCWaitMasterchainSeqno(t *testing.T) {
+	tests := []struct {
+		name string
+		wait *liteserver.WaitMasterchainSeqno
+		code codes.Code
+	}{
+		{name: "no wait", code: codes.OK},
+		{name: "block is known", wait: &liteserver.WaitMasterchainSeqno{Seqno: 10, TimeoutMs: 100}, code: codes.OK},
+		{name: "block is not reached", wait: &liteserver.WaitMasterchainSeqno{Seqno: 11, TimeoutMs: 100}, code: codes.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestProxy(&testCache{lastSeqno: 10})
+			b := &answerBalancer{answer: ton.BlockHeader{ID: &ton.BlockIDExt{}, HeaderProof: cell.BeginCell().EndCell()}}
+			s.backendBalancer = b
+
+			g := &GRPCServer{proxy: s, lim: &KeyConfig{name: "test"}}
+			_, err := g.GetBlockHeader(context.Background(), &liteserver.GetBlockHeaderRequest{Mode: 1, Seqno: 5, Wait: tt.wait})
+			if code := status.Code(err); code != tt.code {
+				t.Fatalf("expected code %s, got %s: %v", tt.code, code, err)
+			}
+
+			// wait is done by proxy, backend gets query itself
+			if _, ok := b.query.(ton.GetBlockHeader); ok != (tt.code == codes.OK) {
+				t.Fatalf("unexpected backend query %v", b.query)
+			}
+		})
+	}
+}
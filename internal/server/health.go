@@ -0,0 +1,58 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type HealthChecker struct {
+	balancer  Balancer
+	cache     *BlockCache
+	maxMCLag  time.Duration
+	startedAt time.Time
+}
+
+func NewHealthChecker(balancer Balancer, cache *BlockCache, maxMasterLag time.Duration) *HealthChecker {
+	return &HealthChecker{
+		balancer:  balancer,
+		cache:     cache,
+		maxMCLag:  maxMasterLag,
+		startedAt: time.Now(),
+	}
+}
+
+// Ready checks that proxy is able to serve requests, error describes the reason if not
+func (h *HealthChecker) Ready() error {
+	if h.balancer.HealthyBackends() == 0 {
+		return fmt.Errorf("no healthy backends")
+	}
+
+	if h.cache != nil {
+		lag, ok := h.cache.LastMasterBlockLag()
+		if !ok {
+			return fmt.Errorf("master block is not fetched yet")
+		}
+
+		if h.maxMCLag > 0 && lag > h.maxMCLag {
+			return fmt.Errorf("last master block is too old: %s", lag.Round(time.Second).String())
+		}
+	}
+	return nil
+}
+
+func (h *HealthChecker) HandleHealthz(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprintf(w, "ok, uptime %s\n", time.Since(h.startedAt).Round(time.Second).String())
+}
+
+func (h *HealthChecker) HandleReadyz(w http.ResponseWriter, _ *http.Request) {
+	if err := h.Ready(); err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = fmt.Fprintf(w, "not ready: %s\n", err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprintln(w, "ready")
+}
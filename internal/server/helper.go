@@ -0,0 +1,330 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/ton/jetton"
+	"github.com/xssnick/tonutils-go/ton/nft"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const helperKeyName = "helper"
+
+// HelperAPI exposes structured data of common contracts over http, get methods are executed
+// by proxy itself, so they are served from the same caches and emulation as liteserver clients
+type HelperAPI struct {
+	api   *ton.APIClient
+	token string
+	mux   *http.ServeMux
+
+	// resolved domains by domain and master seqno
+	dnsCache *lru.Cache
+	root     *address.Address
+	mx       sync.Mutex
+}
+
+type ContentInfo struct {
+	URI         string `json:"uri,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Image       string `json:"image,omitempty"`
+	Symbol      string `json:"symbol,omitempty"`
+	Decimals    string `json:"decimals,omitempty"`
+}
+
+type JettonInfo struct {
+	TotalSupply string       `json:"total_supply"`
+	Mintable    bool         `json:"mintable"`
+	Admin       string       `json:"admin,omitempty"`
+	Content     *ContentInfo `json:"content,omitempty"`
+	MasterSeqNo uint32       `json:"master_seqno"`
+}
+
+type JettonBalance struct {
+	Wallet      string `json:"wallet"`
+	Balance     string `json:"balance"`
+	MasterSeqNo uint32 `json:"master_seqno"`
+}
+
+type NFTInfo struct {
+	Initialized bool         `json:"initialized"`
+	Index       string       `json:"index"`
+	Collection  string       `json:"collection,omitempty"`
+	Owner       string       `json:"owner,omitempty"`
+	Content     *ContentInfo `json:"content,omitempty"`
+	MasterSeqNo uint32       `json:"master_seqno"`
+}
+
+// NewHelperAPI creates http handler of helper endpoints, when token is not empty
+// it is required to be passed as bearer authorization
+func NewHelperAPI(proxy *ProxyBalancer, token string) *HelperAPI {
+	// size is constant and positive, so it cannot fail
+	dnsCache, _ := lru.New(dnsCacheSize)
+
+	a := &HelperAPI{
+		api:      ton.NewAPIClient(proxy.LocalClient(helperKeyName), ton.ProofCheckPolicyUnsafe),
+		token:    token,
+		mux:      http.NewServeMux(),
+		dnsCache: dnsCache,
+	}
+	a.mux.HandleFunc("/jetton/", a.handleJetton)
+	a.mux.HandleFunc("/nft/", a.handleNFT)
+	a.mux.HandleFunc("/dns/", a.handleDNS)
+
+	return a
+}
+
+func (a *HelperAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if a.token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+a.token)) != 1 {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	a.mux.ServeHTTP(w, r)
+}
+
+// handleJetton serves /jetton/{master} and /jetton/{master}/balanceOf/{owner}
+func (a *HelperAPI) handleJetton(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/jetton/"), "/"), "/")
+	if len(parts) != 1 && (len(parts) != 3 || parts[1] != "balanceOf") {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		return
+	}
+
+	master, err := address.ParseAddr(parts[0])
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid jetton address"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	block, err := a.api.CurrentMasterchainInfo(ctx)
+	if err != nil {
+		writeHelperError(w, err)
+		return
+	}
+
+	client := jetton.NewJettonMasterClient(a.api, master)
+	if len(parts) == 1 {
+		data, err := client.GetJettonDataAtBlock(ctx, block)
+		if err != nil {
+			writeHelperError(w, err)
+			return
+		}
+
+		res := &JettonInfo{
+			TotalSupply: data.TotalSupply.String(),
+			Mintable:    data.Mintable,
+			Content:     contentInfo(data.Content),
+			MasterSeqNo: block.SeqNo,
+		}
+		if data.AdminAddr != nil && !data.AdminAddr.IsAddrNone() {
+			res.Admin = data.AdminAddr.String()
+		}
+		writeJSON(w, http.StatusOK, res)
+		return
+	}
+
+	owner, err := address.ParseAddr(parts[2])
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid owner address"})
+		return
+	}
+
+	wallet, err := client.GetJettonWalletAtBlock(ctx, owner, block)
+	if err != nil {
+		writeHelperError(w, err)
+		return
+	}
+
+	balance, err := wallet.GetBalanceAtBlock(ctx, block)
+	if err != nil {
+		var ls ton.LSError
+		if !errors.As(err, &ls) || ls.Code != ton.ErrCodeContractNotInitialized {
+			writeHelperError(w, err)
+			return
+		}
+		// wallet is not deployed until first transfer, so owner has no tokens
+		balance = big.NewInt(0)
+	}
+
+	writeJSON(w, http.StatusOK, &JettonBalance{
+		Wallet:      wallet.Address().String(),
+		Balance:     balance.String(),
+		MasterSeqNo: block.SeqNo,
+	})
+}
+
+// handleNFT serves /nft/{item}/metadata, content of collection items is completed by collection
+func (a *HelperAPI) handleNFT(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/nft/"), "/"), "/")
+	if len(parts) != 2 || parts[1] != "metadata" {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		return
+	}
+
+	addr, err := address.ParseAddr(parts[0])
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid nft address"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	block, err := a.api.CurrentMasterchainInfo(ctx)
+	if err != nil {
+		writeHelperError(w, err)
+		return
+	}
+
+	data, err := nft.NewItemClient(a.api, addr).GetNFTDataAtBlock(ctx, block)
+	if err != nil {
+		writeHelperError(w, err)
+		return
+	}
+
+	res := &NFTInfo{
+		Initialized: data.Initialized,
+		Index:       data.Index.String(),
+		MasterSeqNo: block.SeqNo,
+	}
+	if data.OwnerAddress != nil && !data.OwnerAddress.IsAddrNone() {
+		res.Owner = data.OwnerAddress.String()
+	}
+
+	content := data.Content
+	if data.CollectionAddress != nil && !data.CollectionAddress.IsAddrNone() {
+		res.Collection = data.CollectionAddress.String()
+
+		if data.Initialized {
+			content, err = nft.NewCollectionClient(a.api, data.CollectionAddress).GetNFTContentAtBlock(ctx, data.Index, data.Content, block)
+			if err != nil {
+				writeHelperError(w, err)
+				return
+			}
+		}
+	}
+	res.Content = contentInfo(content)
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+func contentInfo(content nft.ContentAny) *ContentInfo {
+	onchainInfo := func(c *nft.ContentOnchain) *ContentInfo {
+		return &ContentInfo{
+			Name:        c.Name,
+			Description: c.Description,
+			Image:       c.Image,
+			Symbol:      c.GetAttribute("symbol"),
+			Decimals:    c.GetAttribute("decimals"),
+		}
+	}
+
+	switch c := content.(type) {
+	case *nft.ContentOffchain:
+		return &ContentInfo{URI: c.URI}
+	case *nft.ContentOnchain:
+		return onchainInfo(c)
+	case *nft.ContentSemichain:
+		info := onchainInfo(&c.ContentOnchain)
+		info.URI = c.URI
+		return info
+	}
+	return nil
+}
+
+func writeHelperError(w http.ResponseWriter, err error) {
+	var ls ton.LSError
+	var exec ton.ContractExecError
+	switch {
+	case errors.As(err, &exec):
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+	case errors.As(err, &ls):
+		code := http.StatusBadGateway
+		switch ls.Code {
+		case 400:
+			code = http.StatusBadRequest
+		case 404, 651, ton.ErrCodeContractNotInitialized:
+			code = http.StatusNotFound
+		case 429:
+			code = http.StatusTooManyRequests
+		}
+		writeJSON(w, code, map[string]string{"error": ls.Text})
+	case errors.Is(err, context.DeadlineExceeded):
+		writeJSON(w, http.StatusGatewayTimeout, map[string]string{"error": "timeout"})
+	default:
+		log.Debug().Err(err).Msg("helper request failed")
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
+
+// localClient serves tonutils api client queries by proxy itself, queries are passed through
+// the same tl encoding as over adnl, so they are decoded exactly like queries of clients
+type localClient struct {
+	proxy   *ProxyBalancer
+	keyName string
+}
+
+// LocalClient returns client which queries proxy in process, queries are processed
+// like queries of clients with key of keyName, but without rate limits
+func (s *ProxyBalancer) LocalClient(keyName string) ton.LiteClient {
+	return &localClient{proxy: s, keyName: keyName}
+}
+
+func (c *localClient) QueryLiteserver(ctx context.Context, payload tl.Serializable, result tl.Serializable) error {
+	// the same data as in liteServer.query of client
+	data, err := tl.Serialize(payload, true)
+	if err != nil {
+		return fmt.Errorf("failed to serialize query: %w", err)
+	}
+
+	query, err := decodeLiteQuery(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse query: %w", err)
+	}
+	ctx = withClientQuery(ctx, data)
+
+	metrics.Global.Requests.WithLabelValues(c.keyName, metrics.Global.TypeLabel(query), "false").Add(1)
+
+	resp := c.proxy.processQuery(ctx, c.keyName, query)
+	if resp == nil {
+		return fmt.Errorf("no answer")
+	}
+
+	res, ok := result.(*tl.Serializable)
+	if !ok {
+		return fmt.Errorf("unsupported result type")
+	}
+	*res = resp
+	return nil
+}
+
+func (c *localClient) StickyContext(ctx context.Context) context.Context {
+	return ctx
+}
+
+func (c *localClient) StickyContextNextNode(ctx context.Context) (context.Context, error) {
+	return ctx, fmt.Errorf("no more nodes")
+}
+
+func (c *localClient) StickyNodeID(ctx context.Context) uint32 {
+	return 0
+}
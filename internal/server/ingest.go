@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/ton"
+	"time"
+)
+
+// blockConsumer receives every block ingested by cache with its parsed header,
+// including shard blocks which are not referenced by master directly
+type blockConsumer func(blk *Block, info *tlb.BlockHeader)
+
+// addBlockConsumer registers consumer of new blocks, ingestion is started with the first one
+func (c *BlockCache) addBlockConsumer(fn blockConsumer) {
+	c.consumersMx.Lock()
+	c.consumers = append(c.consumers, fn)
+	first := len(c.consumers) == 1
+	c.consumersMx.Unlock()
+
+	if first {
+		go c.ingest()
+	}
+}
+
+func (c *BlockCache) ingest() {
+	// last ingested seqno of each shard, to find shard blocks not referenced by master directly
+	lastShards := map[string]uint32{}
+	if c.index != nil {
+		// continue from blocks indexed before restart
+		lastShards = c.index.LastSeqnos()
+	}
+
+	for {
+		events, _ := c.SubscribeBlocks(256)
+		for ev := range events {
+			c.ingestMaster(ev.Master, lastShards)
+		}
+
+		select {
+		case <-c.closed:
+			return
+		default:
+		}
+
+		// channel is closed when we were too slow, shard gaps are recovered by parents
+		log.Warn().Msg("block ingestion is behind the chain, resubscribing")
+	}
+}
+
+func (c *BlockCache) ingestMaster(id *ton.BlockIDExt, lastShards map[string]uint32) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	master, _, err := c.GetMasterBlock(ctx, id)
+	if err != nil {
+		log.Debug().Err(err).Uint32("seqno", id.SeqNo).Msg("failed to get master block for ingestion")
+		return
+	}
+
+	for _, shard := range master.Shards {
+		c.ingestShard(ctx, shard, lastShards)
+	}
+	c.ingestBlock(&master.Block)
+}
+
+// ingestShard passes shard block and its parents after the last ingested one, new shard is ingested from top block
+func (c *BlockCache) ingestShard(ctx context.Context, top *ton.BlockIDExt, lastShards map[string]uint32) {
+	shardKey := getShardKey(top.Workchain, top.Shard)
+	last, known := lastShards[shardKey]
+
+	id := top
+	for i := 0; i < maxStreamBacktrack && (!known || id.SeqNo > last); i++ {
+		data, err := c.fetchBlock(ctx, id)
+		if err != nil {
+			log.Debug().Err(err).Int32("workchain", id.Workchain).Uint32("seqno", id.SeqNo).Msg("failed to get shard block for ingestion")
+			return
+		}
+
+		info, ok := c.ingestBlock(&Block{ID: id, Data: data})
+		if !ok || !known {
+			break
+		}
+
+		parents, err := info.GetParentBlocks()
+		if err != nil || len(parents) != 1 {
+			// after merge both parents were already ingested as tops of their shards
+			break
+		}
+		id = &ton.BlockIDExt{
+			Workchain: parents[0].Workchain,
+			Shard:     parents[0].Shard,
+			SeqNo:     parents[0].SeqNo,
+			RootHash:  parents[0].RootHash,
+			FileHash:  parents[0].FileHash,
+		}
+	}
+	lastShards[shardKey] = top.SeqNo
+}
+
+func (c *BlockCache) ingestBlock(blk *Block) (*tlb.BlockHeader, bool) {
+	ref, err := blk.Data.PeekRef(0)
+	if err != nil {
+		return nil, false
+	}
+
+	var info tlb.BlockHeader
+	if err = tlb.LoadFromCell(&info, ref.BeginParse()); err != nil {
+		log.Debug().Err(err).Uint32("seqno", blk.ID.SeqNo).Msg("failed to parse header of ingested block")
+		return nil, false
+	}
+
+	c.consumersMx.Lock()
+	consumers := c.consumers
+	c.consumersMx.Unlock()
+
+	for _, fn := range consumers {
+		fn(blk, &info)
+	}
+	return &info, true
+}
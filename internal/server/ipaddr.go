@@ -0,0 +1,80 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// normalizeIP returns canonical form of client ip, liteclient keeps brackets of ipv6 address,
+// and ipv4 clients of dual-stack listener are seen as ipv4-mapped ipv6, so the same client
+// could be counted under different keys
+func normalizeIP(ip string) string {
+	host := strings.Trim(ip, "[]")
+	if i := strings.IndexByte(host, '%'); i >= 0 {
+		// zone of link-local address is not a part of client identity
+		host = host[:i]
+	}
+
+	parsed := net.ParseIP(host)
+	if parsed == nil {
+		return host
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return v4.String()
+	}
+	return parsed.String()
+}
+
+// clientIP returns ip of client, for websocket clients it is ip of websocket connection or forwarded one
+func (s *ProxyBalancer) clientIP(client *ServerClient) string {
+	return normalizeIP(client.IP())
+}
+
+// limitKey returns key of client ip for limits, ipv6 clients usually own the whole /64,
+// so their addresses are aggregated by prefix, otherwise limits are bypassed by changing address
+func (s *ProxyBalancer) limitKey(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil || parsed.To4() != nil || s.ipv6Prefix >= 128 {
+		return ip
+	}
+	return (&net.IPNet{
+		IP:   parsed.Mask(net.CIDRMask(s.ipv6Prefix, 128)),
+		Mask: net.CIDRMask(s.ipv6Prefix, 128),
+	}).String()
+}
+
+// parseNetworks parses list of ips and cidrs, single ip is a network of one address
+func parseNetworks(list []string) ([]*net.IPNet, error) {
+	var res []*net.IPNet
+	for _, n := range list {
+		if _, ipNet, err := net.ParseCIDR(n); err == nil {
+			res = append(res, ipNet)
+			continue
+		}
+
+		ip := net.ParseIP(n)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid network %q", n)
+		}
+		if v4 := ip.To4(); v4 != nil {
+			ip = v4
+		}
+		res = append(res, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+	}
+	return res, nil
+}
+
+// isExemptIP reports if client ip is not limited
+func (s *ProxyBalancer) isExemptIP(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, n := range s.exemptNets {
+		if n.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/segmentio/kafka-go"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"time"
+)
+
+// KafkaSink publishes events to kafka topic
+type KafkaSink struct {
+	writer *kafka.Writer
+}
+
+func NewKafkaSink(cfg config.KafkaStreamConfig) (*KafkaSink, error) {
+	if len(cfg.Brokers) == 0 || cfg.Topic == "" {
+		return nil, fmt.Errorf("kafka brokers and topic should be set")
+	}
+
+	return &KafkaSink{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(cfg.Brokers...),
+			Topic:        cfg.Topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			// writes are synchronous, with default batch timeout of 1s each event waits for a second
+			BatchTimeout: 5 * time.Millisecond,
+		},
+	}, nil
+}
+
+func (k *KafkaSink) Publish(ctx context.Context, key string, data []byte) error {
+	return k.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(key),
+		Value: data,
+	})
+}
+
+// Close flushes pending messages, it is called by owner of sink on shutdown
+func (k *KafkaSink) Close() error {
+	return k.writer.Close()
+}
@@ -0,0 +1,207 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// limiterState is usage of limits of all keys at the moment of save
+type limiterState struct {
+	Time int64                      `json:"time"`
+	Keys map[string]keyLimiterState `json:"keys"`
+}
+
+type keyLimiterState struct {
+	Requests       int64  `json:"requests,omitempty"`
+	Gas            int64  `json:"gas,omitempty"`
+	BandwidthUsed  uint64 `json:"bandwidth_used,omitempty"`
+	BandwidthStart int64  `json:"bandwidth_start,omitempty"`
+}
+
+// EnableLimiterState restores usage of key limits and quotas saved by previous process and saves it periodically
+// and on Close, so clients cannot reset their limits by waiting for restart. Per ip limits are not kept.
+func (s *ProxyBalancer) EnableLimiterState(cfg config.LimiterStateConfig) error {
+	if err := s.loadLimiterState(cfg.Path); err != nil {
+		return err
+	}
+
+	s.limiterStatePath = cfg.Path
+
+	interval := time.Duration(cfg.SaveIntervalSeconds) * time.Second
+	if interval == 0 {
+		interval = 10 * time.Second
+	}
+
+	go func() {
+		for {
+			select {
+			case <-s.closed:
+				return
+			case <-time.After(interval):
+			}
+
+			if err := s.saveLimiterState(cfg.Path); err != nil {
+				log.Warn().Err(err).Msg("failed to save limiter state")
+			}
+		}
+	}()
+	return nil
+}
+
+func (s *ProxyBalancer) loadLimiterState(path string) error {
+	unlock, err := lockFile(path+".lock", false)
+	if err != nil {
+		return fmt.Errorf("failed to lock limiter state: %w", err)
+	}
+	defer unlock()
+
+	st, err := readLimiterState(path)
+	if err != nil || st == nil {
+		return err
+	}
+	elapsed := time.Since(time.Unix(st.Time, 0)).Seconds()
+
+	restored := 0
+	for _, lim := range s.configs {
+		ks, ok := st.Keys[lim.name]
+		if !ok {
+			continue
+		}
+		restored++
+
+		if lim.limiterPerKey != nil {
+			// bucket leaked while proxy was down
+			if n := ks.Requests - int64(elapsed*lim.limiterPerKey.Rate()); n > 0 {
+				lim.limiterPerKey.Add(n)
+			}
+		}
+		if gas := s.gasBudgets[lim.name]; gas != nil {
+			if n := ks.Gas - int64(elapsed*gas.bucket.Rate()); n > 0 {
+				gas.charge(n)
+			}
+		}
+		if lim.bandwidth != nil && ks.BandwidthStart > 0 {
+			lim.bandwidth.restore(ks.BandwidthUsed, time.Unix(ks.BandwidthStart, 0))
+		}
+	}
+	log.Info().Int("keys", restored).Dur("age", time.Duration(elapsed*float64(time.Second))).Msg("limiter state restored")
+	return nil
+}
+
+// readLimiterState reads saved state, nil is returned when there is no state or it is corrupted
+func readLimiterState(path string) (*limiterState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read limiter state: %w", err)
+	}
+
+	var st limiterState
+	if err = json.Unmarshal(data, &st); err != nil {
+		// state is not critical, limits just start from zero
+		log.Warn().Err(err).Msg("limiter state is corrupted, ignored")
+		return nil, nil
+	}
+	return &st, nil
+}
+
+// saveLimiterState writes usage of limits under exclusive lock, the file can be shared with another process,
+// like the next one during handoff, so usage saved by it is merged and never lowered
+func (s *ProxyBalancer) saveLimiterState(path string) error {
+	unlock, err := lockFile(path+".lock", true)
+	if err != nil {
+		return fmt.Errorf("failed to lock limiter state: %w", err)
+	}
+	defer unlock()
+
+	prev, err := readLimiterState(path)
+	if err != nil {
+		return err
+	}
+	var elapsed float64
+	if prev != nil {
+		elapsed = time.Since(time.Unix(prev.Time, 0)).Seconds()
+	}
+
+	st := limiterState{
+		Time: time.Now().Unix(),
+		Keys: map[string]keyLimiterState{},
+	}
+
+	for _, lim := range s.configs {
+		var ks keyLimiterState
+		if lim.limiterPerKey != nil {
+			ks.Requests = lim.limiterPerKey.Count()
+		}
+		if gas := s.gasBudgets[lim.name]; gas != nil {
+			gas.mx.Lock()
+			ks.Gas = gas.bucket.Count()
+			gas.mx.Unlock()
+		}
+		if lim.bandwidth != nil {
+			var start time.Time
+			ks.BandwidthUsed, start = lim.bandwidth.state()
+			ks.BandwidthStart = start.Unix()
+		}
+		if prev != nil {
+			if pk, ok := prev.Keys[lim.name]; ok {
+				ks = mergeKeyLimiterState(ks, pk, lim, s.gasBudgets[lim.name], elapsed)
+			}
+		}
+		if ks != (keyLimiterState{}) {
+			st.Keys[lim.name] = ks
+		}
+	}
+
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+
+	// write to temp file and rename to not leave partially written state
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".limits-*")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// mergeKeyLimiterState takes the highest usage of own and saved state, saved buckets are leaked for elapsed seconds
+func mergeKeyLimiterState(ks, saved keyLimiterState, lim *KeyConfig, gas *gasBudget, elapsed float64) keyLimiterState {
+	if lim.limiterPerKey != nil {
+		if n := saved.Requests - int64(elapsed*lim.limiterPerKey.Rate()); n > ks.Requests {
+			ks.Requests = n
+		}
+	}
+	if gas != nil {
+		if n := saved.Gas - int64(elapsed*gas.bucket.Rate()); n > ks.Gas {
+			ks.Gas = n
+		}
+	}
+	if lim.bandwidth != nil {
+		switch {
+		case saved.BandwidthStart > ks.BandwidthStart:
+			ks.BandwidthUsed, ks.BandwidthStart = saved.BandwidthUsed, saved.BandwidthStart
+		case saved.BandwidthStart == ks.BandwidthStart && saved.BandwidthUsed > ks.BandwidthUsed:
+			ks.BandwidthUsed = saved.BandwidthUsed
+		}
+	}
+	return ks
+}
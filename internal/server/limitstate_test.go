@@ -0,0 +1,58 @@
+package server
+
+import (
+	"github.com/kevinms/leakybucket-go"
+	"path/filepath"
+	"testing"
+)
+
+func newLimitStateProxy(used int64) *ProxyBalancer {
+	bucket := leakybucket.NewLeakyBucket(0.0001, 100)
+	bucket.Add(used)
+
+	s := newTestProxy(&testCache{})
+	s.configs["key"] = &KeyConfig{name: "test", limiterPerKey: bucket}
+	return s
+}
+
+func TestLimiterStateShared(t *testing.T) {
+	tests := []struct {
+		name   string
+		first  int64
+		second int64
+		want   int64
+	}{
+		{name: "lower usage does not overwrite", first: 50, second: 20, want: 50},
+		{name: "higher usage overwrites", first: 20, second: 50, want: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "limits.json")
+
+			// both processes save the same file, like old and new one during handoff
+			if err := newLimitStateProxy(tt.first).saveLimiterState(path); err != nil {
+				t.Fatal(err)
+			}
+			if err := newLimitStateProxy(tt.second).saveLimiterState(path); err != nil {
+				t.Fatal(err)
+			}
+
+			st, err := readLimiterState(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := st.Keys["test"].Requests; got != tt.want {
+				t.Fatalf("expected %d requests in state, got %d", tt.want, got)
+			}
+
+			restored := newLimitStateProxy(0)
+			if err = restored.loadLimiterState(path); err != nil {
+				t.Fatal(err)
+			}
+			if got := restored.configs["key"].limiterPerKey.Count(); got != tt.want {
+				t.Fatalf("expected %d requests restored, got %d", tt.want, got)
+			}
+		})
+	}
+}
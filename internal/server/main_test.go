@@ -0,0 +1,40 @@
+package server
+
+import (
+	"context"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	metrics.InitMetrics("test", "server")
+	os.Exit(m.Run())
+}
+
+// testCache knows only the last master seqno, other methods are not expected to be called
+type testCache struct {
+	Cache
+	lastSeqno uint32
+}
+
+func (c *testCache) WaitMasterBlock(_ context.Context, seqno uint32, _ time.Duration) error {
+	if seqno > c.lastSeqno {
+		return ton.LSError{Code: 652, Text: "timeout"}
+	}
+	return nil
+}
+
+func newTestProxy(cache Cache) *ProxyBalancer {
+	return &ProxyBalancer{
+		cache:            cache,
+		configs:          map[string]*KeyConfig{},
+		keyModes:         map[string]string{},
+		gasBudgets:       map[string]*gasBudget{},
+		keyMaxWaitMaster: map[string]time.Duration{},
+		validator:        NewQueryValidator(0),
+		closed:           make(chan struct{}),
+	}
+}
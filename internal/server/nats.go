@@ -0,0 +1,43 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/nats-io/nats.go"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"time"
+)
+
+// NATSSink publishes events to nats subject
+type NATSSink struct {
+	conn    *nats.Conn
+	subject string
+}
+
+func NewNATSSink(cfg config.NATSStreamConfig) (*NATSSink, error) {
+	if cfg.Subject == "" {
+		return nil, fmt.Errorf("nats subject should be set")
+	}
+
+	conn, err := nats.Connect(cfg.URL, nats.Timeout(5*time.Second), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, err
+	}
+
+	return &NATSSink{
+		conn:    conn,
+		subject: cfg.Subject,
+	}, nil
+}
+
+// Publish sends event, nats has no partitions, so key is passed only as header
+func (n *NATSSink) Publish(ctx context.Context, key string, data []byte) error {
+	msg := nats.NewMsg(n.subject)
+	msg.Header.Set("Key", key)
+	msg.Data = data
+	return n.conn.PublishMsg(msg)
+}
+
+func (n *NATSSink) Close() error {
+	return n.conn.Drain()
+}
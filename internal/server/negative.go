@@ -0,0 +1,73 @@
+package server
+
+import (
+	"errors"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/xssnick/tonutils-go/ton"
+	"time"
+)
+
+type negativeEntry struct {
+	err      ton.LSError
+	expireAt time.Time
+}
+
+// NegativeCache remembers deterministic errors of backends for a short time,
+// so clients polling for not yet existing objects are not hitting backends on each poll
+type NegativeCache struct {
+	cache *lru.Cache
+	ttl   time.Duration
+	codes map[int32]bool
+}
+
+func NewNegativeCache(size uint32, ttl time.Duration, codes []int32) (*NegativeCache, error) {
+	if size == 0 {
+		size = 10000
+	}
+
+	cache, err := lru.New(int(size))
+	if err != nil {
+		return nil, err
+	}
+
+	n := &NegativeCache{
+		cache: cache,
+		ttl:   ttl,
+		codes: map[int32]bool{},
+	}
+	for _, code := range codes {
+		n.codes[code] = true
+	}
+	return n, nil
+}
+
+func (n *NegativeCache) Get(key string) (ton.LSError, bool) {
+	v, ok := n.cache.Get(key)
+	if !ok {
+		return ton.LSError{}, false
+	}
+
+	e := v.(*negativeEntry)
+	if time.Now().After(e.expireAt) {
+		n.cache.Remove(key)
+		return ton.LSError{}, false
+	}
+	return e.err, true
+}
+
+// Observe remembers error if it is LSError with one of the configured codes
+func (n *NegativeCache) Observe(key string, err error) {
+	var ls ton.LSError
+	if !errors.As(err, &ls) || !n.codes[ls.Code] {
+		return
+	}
+
+	n.cache.Add(key, &negativeEntry{
+		err:      ls,
+		expireAt: time.Now().Add(n.ttl),
+	})
+}
+
+func (n *NegativeCache) Purge() {
+	n.cache.Purge()
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	NetworkMainnet = "mainnet"
+	NetworkTestnet = "testnet"
+	NetworkCustom  = "custom"
+)
+
+var knownZeroStates = map[string][2]string{
+	NetworkMainnet: {"F6OpKZKqvqeFp6CQmFomXNMfMj2EnaUSOXN+Mh+wVWk=", "XplPz01CXAps5qeSWUtxcyBfdAo5zVb1N979KLSKD24="},
+	NetworkTestnet: {"gj+B8wb/AmlPk1z1AhVI484rhrUpgSr2oSFIh56VoSg=", "Z+IKwYS54DmmJmesw/nAD5DzWadnOCMzee+kdgSYDOg="},
+}
+
+// NetworkZeroState returns zero state of configured network,
+// nil is returned when network is not set, then zero state of backends is used
+func NetworkZeroState(network string, custom config.ZeroStateConfig) (*ton.ZeroStateIDExt, error) {
+	switch network {
+	case "":
+		return nil, nil
+	case NetworkCustom:
+		if len(custom.RootHash) != 32 || len(custom.FileHash) != 32 {
+			return nil, fmt.Errorf("zero state hashes should be 32 bytes")
+		}
+		return &ton.ZeroStateIDExt{
+			Workchain: -1,
+			RootHash:  custom.RootHash,
+			FileHash:  custom.FileHash,
+		}, nil
+	}
+
+	hashes, ok := knownZeroStates[network]
+	if !ok {
+		return nil, fmt.Errorf("unknown network %s", network)
+	}
+
+	rootHash, err := base64.StdEncoding.DecodeString(hashes[0])
+	if err != nil {
+		return nil, err
+	}
+	fileHash, err := base64.StdEncoding.DecodeString(hashes[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return &ton.ZeroStateIDExt{
+		Workchain: -1,
+		RootHash:  rootHash,
+		FileHash:  fileHash,
+	}, nil
+}
+
+// CheckZeroState asks all backends for their zero state and fails if any of them is from another network,
+// when expected is nil, backends should agree with each other. Agreed zero state is remembered by balancer
+func (b *BackendBalancer) CheckZeroState(ctx context.Context, expected *ton.ZeroStateIDExt, timeout time.Duration) error {
+	backends := b.Backends()
+
+	states := make([]*ton.ZeroStateIDExt, len(backends))
+	errs := make([]error, len(backends))
+
+	var wg sync.WaitGroup
+	for i, backend := range backends {
+		wg.Add(1)
+		go func(i int, backend *Backend) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			inf, err := getMasterchainInfo(ctx, backend, 0)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			states[i] = inf.Init
+		}(i, backend)
+	}
+	wg.Wait()
+
+	var mismatched []string
+	for i, backend := range backends {
+		if errs[i] != nil {
+			// unavailable backend is not a reason to not start, it will be checked by health checks
+			log.Warn().Err(errs[i]).Str("backend", backend.Name).Msg("failed to check zero state of backend")
+			continue
+		}
+
+		if expected == nil {
+			expected = states[i]
+			continue
+		}
+
+		if !zeroStateEquals(expected, states[i]) {
+			log.Error().Str("backend", backend.Name).
+				Str("zero_state", base64.StdEncoding.EncodeToString(states[i].RootHash)).
+				Str("expected", base64.StdEncoding.EncodeToString(expected.RootHash)).
+				Msg("backend is from another network")
+			mismatched = append(mismatched, backend.Name)
+		}
+	}
+
+	if len(mismatched) > 0 {
+		return fmt.Errorf("backends with different zero state: %s", strings.Join(mismatched, ", "))
+	}
+
+	b.zeroState = expected
+	return nil
+}
+
+// ZeroState returns zero state verified by CheckZeroState, nil if it was not checked
+func (b *BackendBalancer) ZeroState() *ton.ZeroStateIDExt {
+	return b.zeroState
+}
+
+func zeroStateEquals(a, b *ton.ZeroStateIDExt) bool {
+	return a != nil && b != nil && a.Workchain == b.Workchain &&
+		bytes.Equal(a.RootHash, b.RootHash) && bytes.Equal(a.FileHash, b.FileHash)
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"github.com/xssnick/tonutils-go/adnl"
+	"github.com/xssnick/tonutils-go/liteclient"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestPassthroughValidates(t *testing.T) {
+	s := newTestProxy(&testCache{})
+	s.rawPassthrough = true
+	s.validator = NewQueryValidator(16)
+
+	query := ton.GetConfigAll{BlockID: &ton.BlockIDExt{Workchain: -1, RootHash: make([]byte, 32), FileHash: make([]byte, 32)}}
+	if !s.isRawProxied(query, false) {
+		t.Fatal("query is expected to be raw proxied")
+	}
+
+	data, err := tl.Serialize(query, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// backend is not set, so query which passed validation would panic here
+	resp := s.processQuery(withClientQuery(context.Background(), data), "test", query)
+	ls, ok := resp.(ton.LSError)
+	if !ok || ls.Code != 400 {
+		t.Fatalf("expected rejection of too big query, got %v", resp)
+	}
+}
+
+func TestPassthroughForwardsClientBytes(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	received := make(chan []byte, 2)
+	srv := newADNLServer([]ed25519.PrivateKey{key})
+	srv.messageHandler = func(ctx context.Context, client *ServerClient, msg tl.Serializable) error {
+		m, ok := msg.(adnl.MessageQuery)
+		if !ok {
+			return nil
+		}
+		if raw, ok := m.Data.(rawLiteServerQuery); ok {
+			received <- raw.Data
+		}
+		return client.Send(adnl.MessageAnswer{ID: m.ID, Data: ton.SendMessageStatus{Status: 1}})
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve(lis)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pool := liteclient.NewConnectionPool()
+	defer pool.Stop()
+	if err = pool.AddConnection(ctx, lis.Addr().String(), base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))); err != nil {
+		t.Fatal(err)
+	}
+
+	s := newTestProxy(&testCache{lastSeqno: 10})
+	s.rawPassthrough = true
+	s.backendBalancer = newTestBalancer(false, &Backend{Name: "a", Client: pool})
+
+	// padding of body is not zero, so query serialized again would differ from what client sent
+	msg, err := tl.Serialize(ton.SendMessage{Body: []byte{1}}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg[len(msg)-1], msg[len(msg)-2] = 0xff, 0xff
+
+	wait, err := tl.Serialize(ton.WaitMasterchainSeqno{Seqno: 10}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clean, err := tl.Serialize(ton.SendMessage{Body: []byte{1}}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want [][]byte
+	}{
+		{name: "client data is forwarded", data: msg, want: [][]byte{msg}},
+		// wait is done by proxy, wrapped queries go to backend one by one
+		{name: "wrapped queries are sent by themselves", data: bytes.Join([][]byte{wait, msg, msg}, nil), want: [][]byte{clean, clean}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := decodeLiteQuery(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp := s.processQuery(withClientQuery(ctx, tt.data), "test", query)
+			if ls, ok := resp.(ton.LSError); ok || resp == nil {
+				t.Fatalf("unexpected answer %v", ls)
+			}
+
+			for _, want := range tt.want {
+				select {
+				case got := <-received:
+					if !bytes.Equal(got, want) {
+						t.Fatalf("backend got %x, expected %x", got, want)
+					}
+				case <-time.After(time.Second):
+					t.Fatal("query is not received by backend")
+				}
+			}
+		})
+	}
+}
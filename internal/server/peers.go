@@ -0,0 +1,217 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/liteclient"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"sync"
+	"time"
+)
+
+func init() {
+	tl.Register(PeerCacheGet{}, "liteProxy.peerCacheGet class:string key:string = liteProxy.PeerCacheData")
+	tl.Register(PeerCacheData{}, "liteProxy.peerCacheData data:bytes = liteProxy.PeerCacheData")
+}
+
+// PeerCacheGet asks sibling proxy for object of its cache store, it is sent over liteserver protocol,
+// so peers are reached the same way as clients reach proxy. Miss is answered with 404 error.
+type PeerCacheGet struct {
+	Class string `tl:"string"`
+	Key   string `tl:"string"`
+}
+
+type PeerCacheData struct {
+	Data []byte `tl:"bytes"`
+}
+
+type noPeersCtx struct{}
+
+// withoutPeers marks context of lookup made for peer, so it is not passed to other peers and cannot loop
+func withoutPeers(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noPeersCtx{}, true)
+}
+
+// PeerStore is a read only CacheStore over sibling proxies, it is placed below local store,
+// so objects missing locally are asked from peers before backends. Peers which are not reachable
+// at start are reconnected in background.
+type PeerStore struct {
+	peers   []*cachePeer
+	classes map[CacheClass]bool
+	timeout time.Duration
+
+	closed chan struct{}
+}
+
+type cachePeer struct {
+	addr string
+	key  string
+
+	client *liteclient.ConnectionPool
+	mx     sync.RWMutex
+}
+
+// default classes asked from peers, libraries are few and fetched once anyway
+var peerDefaultClasses = []CacheClass{CacheClassMasterBlocks, CacheClassShardBlocks, CacheClassAccounts, CacheClassTransactions}
+
+func NewPeerStore(cfg config.PeerCacheConfig) *PeerStore {
+	p := &PeerStore{
+		classes: map[CacheClass]bool{},
+		timeout: time.Duration(cfg.TimeoutMs) * time.Millisecond,
+		closed:  make(chan struct{}),
+	}
+	if p.timeout == 0 {
+		p.timeout = 300 * time.Millisecond
+	}
+
+	classes := peerDefaultClasses
+	if len(cfg.Classes) > 0 {
+		classes = nil
+		for _, c := range cfg.Classes {
+			classes = append(classes, CacheClass(c))
+		}
+	}
+	for _, c := range classes {
+		p.classes[c] = true
+	}
+
+	for _, pc := range cfg.Peers {
+		peer := &cachePeer{
+			addr: pc.Addr,
+			key:  base64.StdEncoding.EncodeToString(pc.Key),
+		}
+		p.peers = append(p.peers, peer)
+		go p.connect(peer)
+	}
+	return p
+}
+
+func (p *PeerStore) connect(peer *cachePeer) {
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		client := liteclient.NewConnectionPool()
+		err := client.AddConnection(ctx, peer.addr, peer.key)
+		cancel()
+		if err == nil {
+			peer.mx.Lock()
+			peer.client = client
+			peer.mx.Unlock()
+
+			log.Info().Str("addr", peer.addr).Msg("connected to cache peer")
+			return
+		}
+		log.Debug().Err(err).Str("addr", peer.addr).Msg("failed to connect to cache peer, we will retry in 10s")
+
+		select {
+		case <-p.closed:
+			return
+		case <-time.After(10 * time.Second):
+		}
+	}
+}
+
+// Get asks all connected peers at once and returns the first found object
+func (p *PeerStore) Get(ctx context.Context, class CacheClass, key string) ([]byte, bool, error) {
+	if !p.classes[class] || ctx.Value(noPeersCtx{}) != nil {
+		return nil, false, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	found := make(chan []byte, len(p.peers))
+	var wg sync.WaitGroup
+	for _, peer := range p.peers {
+		peer.mx.RLock()
+		client := peer.client
+		peer.mx.RUnlock()
+		if client == nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(addr string, client *liteclient.ConnectionPool) {
+			defer wg.Done()
+
+			var resp tl.Serializable
+			err := client.QueryLiteserver(ctx, PeerCacheGet{Class: string(class), Key: key}, &resp)
+			if err != nil {
+				log.Ctx(ctx).Debug().Err(err).Str("addr", addr).Msg("failed to query cache peer")
+				metrics.Global.PeerCache.WithLabelValues("get", string(class), "failed").Add(1)
+				return
+			}
+
+			if d, ok := resp.(PeerCacheData); ok {
+				found <- d.Data
+			}
+		}(peer.addr, client)
+	}
+
+	go func() {
+		wg.Wait()
+		close(found)
+	}()
+
+	if data, ok := <-found; ok {
+		metrics.Global.PeerCache.WithLabelValues("get", string(class), "hit").Add(1)
+		return data, true, nil
+	}
+	metrics.Global.PeerCache.WithLabelValues("get", string(class), "miss").Add(1)
+	return nil, false, nil
+}
+
+// Set does nothing, peers fill their own stores
+func (p *PeerStore) Set(context.Context, CacheClass, string, []byte, time.Duration) error {
+	return nil
+}
+
+func (p *PeerStore) Delete(context.Context, CacheClass, string) error {
+	return nil
+}
+
+func (p *PeerStore) Purge(context.Context, CacheClass) error {
+	return nil
+}
+
+func (p *PeerStore) Close() error {
+	close(p.closed)
+	for _, peer := range p.peers {
+		peer.mx.Lock()
+		if peer.client != nil {
+			peer.client.Stop()
+		}
+		peer.mx.Unlock()
+	}
+	return nil
+}
+
+// SetPeerCacheSource enables answers to sibling proxies from store, it should be set before start
+func (s *ProxyBalancer) SetPeerCacheSource(store CacheStore) {
+	s.peerSource = store
+}
+
+// handlePeerCacheGet answers sibling proxy from store only, objects are never fetched from backends for peers
+func (s *ProxyBalancer) handlePeerCacheGet(ctx context.Context, q *PeerCacheGet) tl.Serializable {
+	class := CacheClass(q.Class)
+	if s.peerSource == nil || !isCacheClass(class) {
+		return ton.LSError{
+			Code: 404,
+			Text: "not cached",
+		}
+	}
+
+	data, ok, err := s.peerSource.Get(withoutPeers(ctx), class, q.Key)
+	if err != nil || !ok {
+		metrics.Global.PeerCache.WithLabelValues("serve", q.Class, "miss").Add(1)
+		return ton.LSError{
+			Code: 404,
+			Text: "not cached",
+		}
+	}
+	metrics.Global.PeerCache.WithLabelValues("serve", q.Class, "hit").Add(1)
+	return PeerCacheData{Data: data}
+}
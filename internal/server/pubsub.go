@@ -0,0 +1,334 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	pubSubTypeBlock        = "block"
+	pubSubTypePurge        = "purge"
+	pubSubTypePurgeAccount = "purge_account"
+	pubSubTypePurgeBlock   = "purge_block"
+)
+
+type pubSubMessage struct {
+	Type     string `json:"type"`
+	Instance string `json:"instance"`
+
+	Workchain int32  `json:"workchain,omitempty"`
+	Shard     int64  `json:"shard,omitempty"`
+	SeqNo     uint32 `json:"seqno,omitempty"`
+	RootHash  []byte `json:"root_hash,omitempty"`
+	FileHash  []byte `json:"file_hash,omitempty"`
+
+	Class   string `json:"class,omitempty"`
+	Account string `json:"account,omitempty"`
+}
+
+// PubSub connects proxy instances through redis channel. New master blocks are announced by the instance
+// which got them first, others take them from shared store instead of waiting for their backends.
+// With leader election only instance holding redis lock polls backends for new master blocks.
+// Purges made with admin api are applied by all instances. Channel should be trusted,
+// announced block ids are verified only when signature verification is enabled.
+type PubSub struct {
+	cache    *BlockCache
+	client   *redis.Client
+	sub      *redis.PubSub
+	channel  string
+	instance string
+
+	// the highest master seqno announced by other instances, it is not announced again
+	remoteSeqno uint32
+
+	lockKey   string
+	lockTTL   time.Duration
+	leader    atomic.Bool
+	closed    chan struct{}
+	campaigns chan struct{}
+}
+
+// renews lock only when it is still held by this instance
+var leaderRenewScript = redis.NewScript(`if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("pexpire", KEYS[1], ARGV[2]) end return 0`)
+
+// releases lock only when it is still held by this instance
+var leaderReleaseScript = redis.NewScript(`if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("del", KEYS[1]) end return 0`)
+
+// EnablePubSub subscribes to channel from config and starts to announce new master blocks
+func (c *BlockCache) EnablePubSub(cfg config.PubSubConfig) error {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sub := client.Subscribe(ctx, cfg.Channel)
+	if _, err := sub.Receive(ctx); err != nil {
+		_ = sub.Close()
+		_ = client.Close()
+		return fmt.Errorf("failed to subscribe to channel: %w", err)
+	}
+
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+
+	p := &PubSub{
+		cache:    c,
+		client:   client,
+		sub:      sub,
+		channel:  cfg.Channel,
+		instance: hex.EncodeToString(id),
+		lockKey:  cfg.LeaderLockKey,
+		lockTTL:  time.Duration(cfg.LeaderTTLSeconds) * time.Second,
+		closed:   make(chan struct{}),
+	}
+	if p.lockTTL == 0 {
+		p.lockTTL = 10 * time.Second
+	}
+	c.pubsub = p
+
+	go p.listen()
+	go p.follow()
+
+	if cfg.LeaderElection {
+		p.campaigns = make(chan struct{})
+		go p.campaign()
+		c.election.Store(p)
+	}
+
+	log.Info().Str("channel", cfg.Channel).Str("instance", p.instance).Msg("cache pub/sub enabled")
+	return nil
+}
+
+func (p *PubSub) close() error {
+	close(p.closed)
+	if p.campaigns != nil {
+		// lock is released after campaign is stopped, so it is not taken again
+		<-p.campaigns
+		if p.leader.Load() {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			_ = leaderReleaseScript.Run(ctx, p.client, []string{p.lockKey}, p.instance).Err()
+			cancel()
+			metrics.Global.ClusterLeader.Set(0)
+		}
+	}
+
+	err := p.sub.Close()
+	if cerr := p.client.Close(); cerr != nil && err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (p *PubSub) publish(ctx context.Context, msg *pubSubMessage) {
+	msg.Instance = p.instance
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+
+	if err = p.client.Publish(ctx, p.channel, data).Err(); err != nil {
+		log.Warn().Err(err).Str("type", msg.Type).Msg("failed to publish to cache channel")
+		metrics.Global.PubSubMessages.WithLabelValues(msg.Type, "failed").Add(1)
+		return
+	}
+	metrics.Global.PubSubMessages.WithLabelValues(msg.Type, "out").Add(1)
+}
+
+// follow announces master blocks which became the latest, blocks got from other instances are skipped
+func (p *PubSub) follow() {
+	for {
+		events, _ := p.cache.SubscribeBlocks(16)
+		for ev := range events {
+			if ev.Master.SeqNo <= atomic.LoadUint32(&p.remoteSeqno) {
+				continue
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			p.publish(ctx, &pubSubMessage{
+				Type:      pubSubTypeBlock,
+				Workchain: ev.Master.Workchain,
+				Shard:     ev.Master.Shard,
+				SeqNo:     ev.Master.SeqNo,
+				RootHash:  ev.Master.RootHash,
+				FileHash:  ev.Master.FileHash,
+			})
+			cancel()
+		}
+
+		select {
+		case <-p.cache.closed:
+			return
+		default:
+		}
+		// we were too slow, the next block is announced
+	}
+}
+
+// listen applies messages of other instances until subscription is closed, redis client reconnects by itself
+func (p *PubSub) listen() {
+	for m := range p.sub.Channel() {
+		var msg pubSubMessage
+		if err := json.Unmarshal([]byte(m.Payload), &msg); err != nil {
+			log.Debug().Err(err).Msg("invalid message in cache channel")
+			continue
+		}
+		if msg.Instance == p.instance {
+			continue
+		}
+		metrics.Global.PubSubMessages.WithLabelValues(msg.Type, "in").Add(1)
+
+		switch msg.Type {
+		case pubSubTypeBlock:
+			p.applyBlock(&msg)
+		default:
+			go p.applyPurge(&msg)
+		}
+	}
+}
+
+func (p *PubSub) applyBlock(msg *pubSubMessage) {
+	if msg.Workchain != -1 || len(msg.RootHash) != 32 || len(msg.FileHash) != 32 {
+		return
+	}
+
+	for {
+		seqno := atomic.LoadUint32(&p.remoteSeqno)
+		if msg.SeqNo <= seqno {
+			return
+		}
+		if atomic.CompareAndSwapUint32(&p.remoteSeqno, seqno, msg.SeqNo) {
+			break
+		}
+	}
+
+	p.cache.mx.RLock()
+	known := p.cache.lastBlock != nil && p.cache.lastBlock.SeqNo >= msg.SeqNo
+	p.cache.mx.RUnlock()
+	if known {
+		return
+	}
+
+	id := &ton.BlockIDExt{
+		Workchain: msg.Workchain,
+		Shard:     msg.Shard,
+		SeqNo:     msg.SeqNo,
+		RootHash:  msg.RootHash,
+		FileHash:  msg.FileHash,
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+		defer cancel()
+
+		// block becomes the latest when fetched, so master waiters are released right away
+		if _, _, err := p.cache.GetMasterBlock(ctx, id); err != nil {
+			log.Debug().Err(err).Uint32("seqno", id.SeqNo).Msg("failed to get master block announced by another instance")
+			return
+		}
+		p.cache.balancer.ObserveMasterSeqno(id.SeqNo)
+	}()
+}
+
+func (p *PubSub) applyPurge(msg *pubSubMessage) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var err error
+	switch msg.Type {
+	case pubSubTypePurge:
+		err = p.cache.purge(ctx, CacheClass(msg.Class))
+	case pubSubTypePurgeAccount:
+		var addr *address.Address
+		if addr, err = address.ParseAddr(msg.Account); err == nil {
+			err = p.cache.purgeAccount(ctx, addr)
+		}
+	case pubSubTypePurgeBlock:
+		_, err = p.cache.purgeBlock(ctx, msg.Workchain, msg.Shard, msg.SeqNo)
+	default:
+		return
+	}
+
+	if err != nil {
+		log.Warn().Err(err).Str("type", msg.Type).Str("instance", msg.Instance).Msg("failed to apply purge of another instance")
+	}
+}
+
+// campaign tries to take leader lock and keeps it renewed while instance is alive
+func (p *PubSub) campaign() {
+	defer close(p.campaigns)
+
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), p.lockTTL/3)
+		leader, err := p.lead(ctx)
+		cancel()
+		if err != nil {
+			// lock can expire meanwhile, so we stop acting as leader until redis answers
+			log.Warn().Err(err).Msg("failed to take leader lock")
+			leader = false
+		}
+
+		if p.leader.Swap(leader) != leader {
+			if leader {
+				log.Info().Str("instance", p.instance).Msg("instance became cluster leader, new master blocks are fetched from backends")
+				metrics.Global.ClusterLeader.Set(1)
+			} else {
+				log.Info().Str("instance", p.instance).Msg("instance is not cluster leader anymore")
+				metrics.Global.ClusterLeader.Set(0)
+			}
+		}
+
+		select {
+		case <-p.closed:
+			return
+		case <-time.After(p.lockTTL / 3):
+		}
+	}
+}
+
+func (p *PubSub) lead(ctx context.Context) (bool, error) {
+	if p.leader.Load() {
+		n, err := leaderRenewScript.Run(ctx, p.client, []string{p.lockKey}, p.instance, p.lockTTL.Milliseconds()).Int()
+		if err != nil {
+			return false, err
+		}
+		if n == 1 {
+			return true, nil
+		}
+	}
+	return p.client.SetNX(ctx, p.lockKey, p.instance, p.lockTTL).Result()
+}
+
+// awaitLeader waits until master block seqno is announced by leader, false is returned when instance
+// is leader itself or leader was silent for lock ttl, then block should be fetched from backends
+func (p *PubSub) awaitLeader(seqno uint32) (uint32, bool) {
+	if p.leader.Load() {
+		return 0, false
+	}
+
+	if err := p.cache.WaitMasterBlock(context.Background(), seqno, p.lockTTL); err != nil {
+		log.Debug().Uint32("seqno", seqno).Msg("no master block from cluster leader, fetching it from backends")
+		return 0, false
+	}
+
+	p.cache.mx.RLock()
+	last := p.cache.lastBlock.SeqNo
+	p.cache.mx.RUnlock()
+	return last, true
+}
@@ -0,0 +1,222 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/ton"
+)
+
+// Purge drops objects of class from memory and from store, all objects are dropped when class is empty,
+// other instances drop them too when pub/sub is enabled
+func (c *BlockCache) Purge(ctx context.Context, class CacheClass) error {
+	if err := c.purge(ctx, class); err != nil {
+		return err
+	}
+	if c.pubsub != nil {
+		c.pubsub.publish(ctx, &pubSubMessage{Type: pubSubTypePurge, Class: string(class)})
+	}
+	return nil
+}
+
+func (c *BlockCache) purge(ctx context.Context, class CacheClass) error {
+	if class != "" && !isCacheClass(class) {
+		return fmt.Errorf("unknown cache class %s", class)
+	}
+	all := class == ""
+
+	c.mx.Lock()
+	masters := make([]*MasterBlock, 0, len(c.masterBlocks))
+	for _, mb := range c.masterBlocks {
+		masters = append(masters, mb)
+	}
+	if all || class == CacheClassMasterBlocks {
+		c.masterBlocks = map[uint32]*MasterBlock{}
+	}
+
+	var shards []*ShardBlock
+	for _, si := range c.shardBlocks {
+		for _, sb := range si.shardBlocks {
+			shards = append(shards, sb)
+		}
+		if all || class == CacheClassShardBlocks {
+			si.shardBlocks = map[uint32]*ShardBlock{}
+		}
+		if all || class == CacheClassAccounts {
+			si.accountStates = nil
+		}
+	}
+	c.mx.Unlock()
+
+	if all || class == CacheClassMasterBlocks || class == CacheClassShardBlocks {
+		c.headerProofs.Purge()
+	}
+
+	if all || class == CacheClassAccounts {
+		for _, mb := range masters {
+			mb.mx.RLock()
+			if mb.accountsCache != nil {
+				mb.accountsCache.Purge()
+			}
+			mb.mx.RUnlock()
+		}
+		for _, sb := range shards {
+			sb.mx.RLock()
+			if sb.accountsCache != nil {
+				sb.accountsCache.Purge()
+			}
+			sb.mx.RUnlock()
+		}
+		c.shardProofs.Purge()
+	}
+
+	if (all || class == CacheClassLibraries) && c.libsCache != nil {
+		c.libsCache.Purge()
+	}
+
+	if c.negative != nil {
+		c.negative.Purge()
+	}
+
+	if c.store != nil {
+		if err := c.store.Purge(ctx, class); err != nil {
+			return fmt.Errorf("failed to purge store: %w", err)
+		}
+	}
+
+	log.Info().Str("class", string(class)).Msg("cache purged")
+	return nil
+}
+
+// PurgeAccount drops cached states of account in all cached blocks
+func (c *BlockCache) PurgeAccount(ctx context.Context, addr *address.Address) error {
+	if err := c.purgeAccount(ctx, addr); err != nil {
+		return err
+	}
+	if c.pubsub != nil {
+		c.pubsub.publish(ctx, &pubSubMessage{Type: pubSubTypePurgeAccount, Account: addr.String()})
+	}
+	return nil
+}
+
+func (c *BlockCache) purgeAccount(ctx context.Context, addr *address.Address) error {
+	// cache keys are built from addresses without flags
+	addr = address.NewAddress(0, byte(addr.Workchain()), addr.Data())
+	addrStr := addr.String()
+
+	var masters []*MasterBlock
+	var shards []*ShardBlock
+	c.mx.Lock()
+	for _, mb := range c.masterBlocks {
+		masters = append(masters, mb)
+	}
+	for _, si := range c.shardBlocks {
+		for _, sb := range si.shardBlocks {
+			shards = append(shards, sb)
+		}
+		if si.accountStates != nil {
+			si.accountStates.Remove(addrStr)
+		}
+	}
+	c.mx.Unlock()
+
+	var ids []*ton.BlockIDExt
+	for _, mb := range masters {
+		mb.mx.RLock()
+		if mb.accountsCache != nil {
+			mb.accountsCache.Remove(addrStr)
+		}
+		if mb.Block.ID != nil {
+			ids = append(ids, mb.Block.ID)
+		}
+		mb.mx.RUnlock()
+	}
+	for _, sb := range shards {
+		sb.mx.RLock()
+		if sb.accountsCache != nil {
+			sb.accountsCache.Remove(addrStr)
+		}
+		ids = append(ids, sb.ID)
+		sb.mx.RUnlock()
+	}
+
+	if c.store != nil {
+		for _, id := range ids {
+			if err := c.store.Delete(ctx, CacheClassAccounts, accountStoreKey(id, addr.Workchain(), addr.Data())); err != nil {
+				return fmt.Errorf("failed to delete from store: %w", err)
+			}
+		}
+	}
+
+	log.Info().Str("addr", addrStr).Msg("account purged from cache")
+	return nil
+}
+
+// PurgeBlock drops block from memory and from store, false is returned when block is not cached in memory,
+// other instances are asked to drop it anyway because they could have it
+func (c *BlockCache) PurgeBlock(ctx context.Context, workchain int32, shard int64, seqno uint32) (bool, error) {
+	found, err := c.purgeBlock(ctx, workchain, shard, seqno)
+	if err != nil {
+		return found, err
+	}
+	if c.pubsub != nil {
+		c.pubsub.publish(ctx, &pubSubMessage{Type: pubSubTypePurgeBlock, Workchain: workchain, Shard: shard, SeqNo: seqno})
+	}
+	return found, nil
+}
+
+func (c *BlockCache) purgeBlock(ctx context.Context, workchain int32, shard int64, seqno uint32) (bool, error) {
+	var id *ton.BlockIDExt
+
+	c.mx.Lock()
+	if workchain == -1 {
+		if mb := c.masterBlocks[seqno]; mb != nil {
+			id = mb.Block.ID
+			delete(c.masterBlocks, seqno)
+		}
+	} else if si := c.shardBlocks[getShardKey(workchain, shard)]; si != nil {
+		if sb := si.shardBlocks[seqno]; sb != nil {
+			id = sb.ID
+			delete(si.shardBlocks, seqno)
+		}
+	}
+	c.mx.Unlock()
+
+	if id == nil {
+		return false, nil
+	}
+
+	if c.store != nil {
+		class := CacheClassShardBlocks
+		if workchain == -1 {
+			class = CacheClassMasterBlocks
+		}
+
+		if err := c.store.Delete(ctx, class, blockStoreKey(id)); err != nil {
+			return true, fmt.Errorf("failed to delete from store: %w", err)
+		}
+	}
+
+	log.Info().Int32("wc", workchain).Int64("shard", shard).Uint32("seqno", seqno).Msg("block purged from cache")
+	return true, nil
+}
+
+// PurgeResponses drops general purpose and negative caches of responses
+func (s *ProxyBalancer) PurgeResponses() {
+	if s.gpCache != nil {
+		s.gpCache.Purge()
+	}
+	if s.negCache != nil {
+		s.negCache.Purge()
+	}
+}
+
+func isCacheClass(class CacheClass) bool {
+	for _, cl := range cacheClasses {
+		if cl == class {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type Priority int
+
+const (
+	PriorityFree Priority = iota
+	PriorityStandard
+	PriorityPlatinum
+)
+
+var priorityNames = [...]string{"free", "standard", "platinum"}
+
+// ParsePriority returns priority by its name from config, empty and unknown names are standard
+func ParsePriority(name string) Priority {
+	for i, n := range priorityNames {
+		if n == name {
+			return Priority(i)
+		}
+	}
+	return PriorityStandard
+}
+
+func (p Priority) String() string {
+	return priorityNames[p]
+}
+
+type qosWaiter struct {
+	// true when slot is given, false when waiter is shed
+	ch chan bool
+}
+
+// qosScheduler limits number of queries processed at once, when all slots are busy queries wait
+// and free slot is given to the highest priority first. When queue is full, the newest waiters of
+// the lowest priority are shed to give place to higher ones, so free tier is rejected first under load.
+type qosScheduler struct {
+	running  int
+	max      int
+	maxQueue int
+	maxWait  time.Duration
+
+	queued int
+	queues [len(priorityNames)][]*qosWaiter
+
+	mx sync.Mutex
+}
+
+func newQoSScheduler(max, maxQueue int, maxWait time.Duration) *qosScheduler {
+	return &qosScheduler{
+		max:      max,
+		maxQueue: maxQueue,
+		maxWait:  maxWait,
+	}
+}
+
+// acquire waits for slot, false is returned when query is shed or waited too long,
+// release should be called after processing when true is returned
+func (q *qosScheduler) acquire(ctx context.Context, p Priority) bool {
+	q.mx.Lock()
+	if q.running < q.max {
+		q.running++
+		q.mx.Unlock()
+		return true
+	}
+
+	if q.queued >= q.maxQueue && !q.shedLower(p) {
+		q.mx.Unlock()
+		return false
+	}
+
+	w := &qosWaiter{ch: make(chan bool, 1)}
+	q.queues[p] = append(q.queues[p], w)
+	q.queued++
+	q.mx.Unlock()
+
+	if q.maxWait > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, q.maxWait)
+		defer cancel()
+	}
+
+	select {
+	case ok := <-w.ch:
+		return ok
+	case <-ctx.Done():
+	}
+
+	q.mx.Lock()
+	removed := q.remove(p, w)
+	q.mx.Unlock()
+
+	if !removed {
+		// slot was given or waiter was shed at the same time
+		if <-w.ch {
+			q.release()
+		}
+	}
+	return false
+}
+
+func (q *qosScheduler) release() {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	for p := len(q.queues) - 1; p >= 0; p-- {
+		if len(q.queues[p]) == 0 {
+			continue
+		}
+
+		// slot is passed to waiter as is, so running is not changed
+		w := q.queues[p][0]
+		q.queues[p] = q.queues[p][1:]
+		q.queued--
+		w.ch <- true
+		return
+	}
+	q.running--
+}
+
+// shedLower rejects the newest waiter with priority lower than p, false is returned when there is no such
+func (q *qosScheduler) shedLower(p Priority) bool {
+	for lp := Priority(0); lp < p; lp++ {
+		if n := len(q.queues[lp]); n > 0 {
+			w := q.queues[lp][n-1]
+			q.queues[lp] = q.queues[lp][:n-1]
+			q.queued--
+			w.ch <- false
+			return true
+		}
+	}
+	return false
+}
+
+func (q *qosScheduler) remove(p Priority, w *qosWaiter) bool {
+	for i, qw := range q.queues[p] {
+		if qw == w {
+			q.queues[p] = append(q.queues[p][:i], q.queues[p][i+1:]...)
+			q.queued--
+			return true
+		}
+	}
+	return false
+}
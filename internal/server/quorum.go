@@ -0,0 +1,242 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"reflect"
+	"sync/atomic"
+	"time"
+)
+
+var ErrQuorumNotReached = ton.LSError{
+	Code: 502,
+	Text: "backends returned different answers",
+}
+
+type quorumSettings struct {
+	size     int
+	methods  map[string]bool
+	evict    bool
+	distrust time.Duration
+}
+
+// answerDigest identifies what answer is about (key) and its content (hash),
+// answers with the same key and different hashes are conflicting
+type answerDigest struct {
+	key  string
+	hash []byte
+}
+
+// EnableQuorum makes critical queries to be sent to several backends and their answers to be cross-checked,
+// backends disagreeing with majority are flagged and, when configured, removed from rotation for distrust period
+func (b *BackendBalancer) EnableQuorum(cfg config.QuorumConfig) {
+	if cfg.Size <= 1 {
+		return
+	}
+
+	q := &quorumSettings{
+		size:     int(cfg.Size),
+		methods:  map[string]bool{},
+		evict:    cfg.EvictOnMismatch,
+		distrust: time.Duration(cfg.DistrustSeconds) * time.Second,
+	}
+	if q.distrust == 0 {
+		q.distrust = 5 * time.Minute
+	}
+
+	methods := cfg.Methods
+	if len(methods) == 0 {
+		methods = []string{
+			reflect.TypeOf(ton.GetMasterchainInf{}).String(),
+			reflect.TypeOf(ton.LookupBlock{}).String(),
+			reflect.TypeOf(ton.GetAccountState{}).String(),
+		}
+	}
+	for _, m := range methods {
+		q.methods[m] = true
+	}
+	b.quorum = q
+}
+
+func (q *quorumSettings) critical(payload tl.Serializable) bool {
+	if list, ok := payload.([]tl.Serializable); ok {
+		// wait master with wrapped queries
+		for _, p := range list {
+			if q.critical(p) {
+				return true
+			}
+		}
+		return false
+	}
+	return q.methods[reflect.TypeOf(payload).String()]
+}
+
+func (b *BackendBalancer) queryQuorum(ctx context.Context, backends []*Backend, first *Backend, payload tl.Serializable, result *tl.Serializable) error {
+	selected := []*Backend{first}
+	for _, backend := range backends {
+		if len(selected) >= b.quorum.size {
+			break
+		}
+		if backend != first && backend.IsHealthy() {
+			selected = append(selected, backend)
+		}
+	}
+
+	answers := make([]backendAnswer, len(selected))
+	done := make(chan int, len(selected))
+	for i, backend := range selected {
+		go func(i int, backend *Backend) {
+			var resp tl.Serializable
+			err := backend.QueryLiteserver(ctx, payload, &resp)
+			answers[i] = backendAnswer{resp: resp, err: err}
+			done <- i
+		}(i, backend)
+	}
+	for range selected {
+		<-done
+	}
+
+	var votes []int
+	digests := make([]*answerDigest, len(selected))
+	for i, a := range answers {
+		if a.err != nil {
+			continue
+		}
+		if digests[i] = digestAnswer(a.resp); digests[i] == nil {
+			continue
+		}
+		votes = append(votes, i)
+	}
+
+	if len(votes) == 0 {
+		// nothing comparable, errors and ls errors are returned as is
+		for _, a := range answers {
+			if a.err == nil {
+				*result = a.resp
+				return nil
+			}
+		}
+		return answers[0].err
+	}
+
+	// the answer with the most agreeing backends wins, when another answer with the same key
+	// has as many votes there is no majority, and quorum is not reached
+	best, bestVotes := -1, 0
+	for _, i := range votes {
+		num := 0
+		for _, j := range votes {
+			if digests[j].key == digests[i].key && bytes.Equal(digests[j].hash, digests[i].hash) {
+				num++
+			}
+		}
+		if num > bestVotes {
+			best, bestVotes = i, num
+		}
+	}
+
+	tie := false
+	for _, i := range votes {
+		if digests[i].key != digests[best].key || bytes.Equal(digests[i].hash, digests[best].hash) {
+			// different keys are not conflicting, backends can be at different heights
+			continue
+		}
+
+		num := 0
+		for _, j := range votes {
+			if digests[j].key == digests[i].key && bytes.Equal(digests[j].hash, digests[i].hash) {
+				num++
+			}
+		}
+		if num == bestVotes {
+			tie = true
+		}
+
+		if num < bestVotes {
+			b.flagMismatch(ctx, selected[i], payload)
+		}
+	}
+
+	if tie {
+		log.Ctx(ctx).Warn().Type("request", payload).Str("key", digests[best].key).Msg("backends returned different answers, no majority")
+		return ErrQuorumNotReached
+	}
+
+	*result = answers[best].resp
+	return nil
+}
+
+func (b *BackendBalancer) flagMismatch(ctx context.Context, backend *Backend, payload tl.Serializable) {
+	metrics.Global.QuorumMismatches.WithLabelValues(backend.Name, metrics.Global.TypeLabel(payload)).Add(1)
+	log.Ctx(ctx).Warn().Str("backend", backend.Name).Type("request", payload).Msg("backend answer disagrees with other backends")
+
+	if !b.quorum.evict {
+		return
+	}
+
+	// backend returns to rotation after distrust period, if it still disagrees it is evicted again
+	now := time.Now()
+	if prev := atomic.SwapInt64(&backend.distrustedUntil, now.Add(b.quorum.distrust).Unix()); prev <= now.Unix() {
+		log.Warn().Str("backend", backend.Name).Dur("for", b.quorum.distrust).Msg("backend evicted from rotation because of answer mismatch")
+		metrics.Global.BackendEvictions.WithLabelValues(backend.Name, "answer mismatch").Add(1)
+	}
+}
+
+// digestAnswer returns nil for answers which could not be compared
+func digestAnswer(resp tl.Serializable) *answerDigest {
+	switch t := resp.(type) {
+	case ton.MasterchainInfo:
+		if t.Last == nil {
+			return nil
+		}
+		return &answerDigest{key: fmt.Sprint("master:", t.Last.SeqNo), hash: t.Last.RootHash}
+	case ton.BlockHeader:
+		if t.ID == nil {
+			return nil
+		}
+		return &answerDigest{key: fmt.Sprint("block:", t.ID.Workchain, ":", t.ID.Shard, ":", t.ID.SeqNo), hash: t.ID.RootHash}
+	case ton.AccountState:
+		if t.ID == nil {
+			return nil
+		}
+
+		var hash []byte
+		if t.State != nil {
+			hash = t.State.Hash()
+		}
+		return &answerDigest{key: fmt.Sprint("account:", t.ID.SeqNo, ":", string(t.ID.RootHash)), hash: hash}
+	}
+	return nil
+}
+
+// quorumClient is returned to cache instead of a single backend when quorum is enabled,
+// so the cache pipeline also cross-checks critical queries
+type quorumClient struct {
+	balancer *BackendBalancer
+	primary  *Backend
+}
+
+func (c *quorumClient) QueryLiteserver(ctx context.Context, payload tl.Serializable, result tl.Serializable) error {
+	res, ok := result.(*tl.Serializable)
+	if !ok || !c.balancer.quorum.critical(payload) {
+		return c.primary.QueryLiteserver(ctx, payload, result)
+	}
+	return c.balancer.queryQuorum(ctx, c.balancer.candidates(payload), c.primary, payload, res)
+}
+
+func (c *quorumClient) StickyContext(ctx context.Context) context.Context {
+	return c.primary.StickyContext(ctx)
+}
+
+func (c *quorumClient) StickyContextNextNode(ctx context.Context) (context.Context, error) {
+	return c.primary.StickyContextNextNode(ctx)
+}
+
+func (c *quorumClient) StickyNodeID(ctx context.Context) uint32 {
+	return c.primary.StickyNodeID(ctx)
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"github.com/xssnick/tonutils-go/adnl"
+	"github.com/xssnick/tonutils-go/liteclient"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+	"net"
+	"testing"
+	"time"
+)
+
+// newFixedBackend returns backend connected to local adnl server which answers every query with resp
+func newFixedBackend(t *testing.T, name string, resp tl.Serializable) *Backend {
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := newADNLServer([]ed25519.PrivateKey{key})
+	srv.messageHandler = func(ctx context.Context, client *ServerClient, msg tl.Serializable) error {
+		if m, ok := msg.(adnl.MessageQuery); ok {
+			return client.Send(adnl.MessageAnswer{ID: m.ID, Data: resp})
+		}
+		return nil
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve(lis)
+	t.Cleanup(func() { _ = srv.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pool := liteclient.NewConnectionPool()
+	t.Cleanup(pool.Stop)
+	if err = pool.AddConnection(ctx, lis.Addr().String(), base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))); err != nil {
+		t.Fatal(err)
+	}
+	return &Backend{Name: name, Client: pool}
+}
+
+func hashedMasterInfo(seqno uint32, hash byte) ton.MasterchainInfo {
+	root := make([]byte, 32)
+	root[0] = hash
+	return ton.MasterchainInfo{
+		Last: &ton.BlockIDExt{Workchain: -1, SeqNo: seqno, RootHash: root, FileHash: make([]byte, 32)},
+		Init: &ton.ZeroStateIDExt{},
+	}
+}
+
+func TestQueryQuorum(t *testing.T) {
+	tests := []struct {
+		name       string
+		answers    []ton.MasterchainInfo
+		wantErr    error
+		wantHash   byte
+		distrusted []bool
+	}{
+		{
+			name:       "all agree",
+			answers:    []ton.MasterchainInfo{hashedMasterInfo(10, 1), hashedMasterInfo(10, 1), hashedMasterInfo(10, 1)},
+			wantHash:   1,
+			distrusted: []bool{false, false, false},
+		},
+		{
+			name:       "majority wins, minority is distrusted",
+			answers:    []ton.MasterchainInfo{hashedMasterInfo(10, 2), hashedMasterInfo(10, 1), hashedMasterInfo(10, 1)},
+			wantHash:   1,
+			distrusted: []bool{true, false, false},
+		},
+		{
+			name:       "tie is not a majority",
+			answers:    []ton.MasterchainInfo{hashedMasterInfo(10, 1), hashedMasterInfo(10, 2)},
+			wantErr:    ErrQuorumNotReached,
+			distrusted: []bool{false, false},
+		},
+		{
+			name:       "all disagree",
+			answers:    []ton.MasterchainInfo{hashedMasterInfo(10, 1), hashedMasterInfo(10, 2), hashedMasterInfo(10, 3)},
+			wantErr:    ErrQuorumNotReached,
+			distrusted: []bool{false, false, false},
+		},
+		{
+			name:       "different heights are not conflicting",
+			answers:    []ton.MasterchainInfo{hashedMasterInfo(10, 1), hashedMasterInfo(11, 2)},
+			wantHash:   1,
+			distrusted: []bool{false, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var backends []*Backend
+			for i, answer := range tt.answers {
+				backends = append(backends, newFixedBackend(t, string(rune('a'+i)), answer))
+			}
+
+			b := newTestBalancer(false, backends...)
+			b.quorum = &quorumSettings{size: len(backends), evict: true, distrust: time.Minute}
+
+			var resp tl.Serializable
+			err := b.queryQuorum(context.Background(), backends, backends[0], ton.GetMasterchainInf{}, &resp)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil {
+				if inf, ok := resp.(ton.MasterchainInfo); !ok || inf.Last.RootHash[0] != tt.wantHash {
+					t.Fatalf("expected answer with hash %d, got %v", tt.wantHash, resp)
+				}
+			}
+
+			for i, backend := range backends {
+				if backend.IsHealthy() == tt.distrusted[i] {
+					t.Fatalf("backend %s: expected distrusted %v", backend.Name, tt.distrusted[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDistrustExpires(t *testing.T) {
+	backend := &Backend{Name: "a"}
+	b := newTestBalancer(false, backend)
+	b.quorum = &quorumSettings{evict: true, distrust: time.Minute}
+
+	b.flagMismatch(context.Background(), backend, ton.GetMasterchainInf{})
+	if backend.IsHealthy() {
+		t.Fatal("backend is expected to be out of rotation after mismatch")
+	}
+
+	// distrust period is over
+	backend.distrustedUntil = time.Now().Add(-time.Second).Unix()
+	if !backend.IsHealthy() {
+		t.Fatal("backend is expected to return to rotation")
+	}
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+type exemptCtx struct{}
+
+// withExempt marks context of query of exempt key or ip, so limits applied during processing, like gas budget, are skipped
+func withExempt(ctx context.Context) context.Context {
+	return context.WithValue(ctx, exemptCtx{}, true)
+}
+
+func isExempt(ctx context.Context) bool {
+	exempt, _ := ctx.Value(exemptCtx{}).(bool)
+	return exempt
+}
+
+// takeLimits charges cost to rate limiters of key, when it is rejected
+// time until enough capacity is freed is returned, so client can back off
+func (s *ProxyBalancer) takeLimits(lim *KeyConfig, ip string, cost int64) (time.Duration, bool) {
+	if s.sharedLimits != nil {
+		if wait, ok, err := s.sharedLimits.takeLimits(lim, s.limitKey(ip), cost); err == nil {
+			return wait, ok
+		}
+	}
+
+	if lim.limiterPerIP != nil {
+		key := s.limitKey(ip)
+		if lim.limiterPerIP.Add(key, cost) != cost {
+			return retryAfter(lim.limiterPerIP.TillEmpty(key), lim.limiterPerIP.Capacity(), lim.limiterPerIP.Rate(), cost), false
+		}
+	}
+	if lim.limiterPerKey != nil && lim.limiterPerKey.Add(cost) != cost {
+		return retryAfter(lim.limiterPerKey.TillEmpty(), lim.limiterPerKey.Capacity(), lim.limiterPerKey.Rate(), cost), false
+	}
+	return 0, true
+}
+
+// retryAfter returns time until bucket leaks enough to accept cost, rounded up to milliseconds
+func retryAfter(tillEmpty time.Duration, capacity int64, rate float64, cost int64) time.Duration {
+	if cost > capacity {
+		cost = capacity
+	}
+	wait := tillEmpty - time.Duration(float64(capacity-cost)/rate*float64(time.Second))
+	if wait < time.Millisecond {
+		return time.Millisecond
+	}
+	return time.Duration(math.Ceil(float64(wait)/float64(time.Millisecond))) * time.Millisecond
+}
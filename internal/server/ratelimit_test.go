@@ -0,0 +1,116 @@
+package server
+
+import (
+	"github.com/kevinms/leakybucket-go"
+	"github.com/redis/go-redis/v9"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestTakeLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		lim     func() *KeyConfig
+		exempt  bool
+		allowed []bool
+	}{
+		{
+			name:    "no limits",
+			lim:     func() *KeyConfig { return &KeyConfig{name: "test"} },
+			allowed: []bool{true, true, true},
+		},
+		{
+			name: "key limit",
+			lim: func() *KeyConfig {
+				return &KeyConfig{name: "test", limiterPerKey: leakybucket.NewLeakyBucket(0.001, 2)}
+			},
+			allowed: []bool{true, true, false},
+		},
+		{
+			name: "ip limit",
+			lim: func() *KeyConfig {
+				return &KeyConfig{name: "test", limiterPerIP: leakybucket.NewCollector(0.001, 1, true)}
+			},
+			allowed: []bool{true, false, false},
+		},
+		{
+			name: "exempt client",
+			lim: func() *KeyConfig {
+				return &KeyConfig{name: "test", limiterPerKey: leakybucket.NewLeakyBucket(0.001, 1)}
+			},
+			exempt:  true,
+			allowed: []bool{true, true, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestProxy(&testCache{})
+			lim := tt.lim()
+			for i, allowed := range tt.allowed {
+				ls := s.limitRate(lim, "1.2.3.4", 1, tt.exempt)
+				if (ls == nil) != allowed {
+					t.Fatalf("query %d: expected allowed %v, got %v", i, allowed, ls)
+				}
+				if ls != nil && ls.Code != 429 {
+					t.Fatalf("query %d: expected code 429, got %d", i, ls.Code)
+				}
+			}
+		})
+	}
+}
+
+func TestWrappedQueriesCost(t *testing.T) {
+	wait := ton.WaitMasterchainSeqno{Seqno: 1, Timeout: 1000}
+
+	tests := []struct {
+		name    string
+		queries []any
+		allowed []bool
+	}{
+		{name: "single queries", queries: []any{ton.GetTime{}, ton.GetTime{}, ton.GetTime{}}, allowed: []bool{true, true, true}},
+		{name: "wait with one query", queries: []any{[]tl.Serializable{wait, ton.GetTime{}}, ton.GetTime{}}, allowed: []bool{true, true}},
+		{name: "wrapped queries take a unit each", queries: []any{[]tl.Serializable{wait, ton.GetTime{}, ton.GetVersion{}, ton.GetTime{}}, ton.GetTime{}}, allowed: []bool{true, false}},
+		{name: "more wrapped queries than capacity", queries: []any{[]tl.Serializable{wait, ton.GetTime{}, ton.GetTime{}, ton.GetTime{}, ton.GetTime{}}}, allowed: []bool{false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestProxy(&testCache{})
+			lim := &KeyConfig{name: "test", limiterPerKey: leakybucket.NewLeakyBucket(0.001, 3)}
+			for i, q := range tt.queries {
+				if ls := s.limitRate(lim, "1.2.3.4", queryCost(q), false); (ls == nil) != tt.allowed[i] {
+					t.Fatalf("query %d: expected allowed %v, got %v", i, tt.allowed[i], ls)
+				}
+			}
+		})
+	}
+}
+
+func TestSharedLimitsFallback(t *testing.T) {
+	// nothing listens on the address, so redis fails right away
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := lis.Addr().String()
+	_ = lis.Close()
+
+	s := newTestProxy(&testCache{})
+	s.sharedLimits = &sharedLimiter{
+		client:  redis.NewClient(&redis.Options{Addr: addr, MaxRetries: -1}),
+		timeout: time.Second,
+	}
+	defer s.sharedLimits.client.Close()
+
+	lim := &KeyConfig{name: "test", limiterPerKey: leakybucket.NewLeakyBucket(0.001, 1)}
+	if _, ok := s.takeLimits(lim, "1.2.3.4", 1); !ok {
+		t.Fatal("expected query to be allowed by local limit")
+	}
+	if _, ok := s.takeLimits(lim, "1.2.3.4", 1); ok {
+		t.Fatal("expected query to be rejected by local limit")
+	}
+}
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"time"
+)
+
+// RedisStore is a CacheStore shared by all proxy instances connected to the same redis
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+	ttl    time.Duration
+	jitter uint32
+}
+
+func NewRedisStore(cfg config.RedisCacheConfig) (*RedisStore, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+
+	return &RedisStore{
+		client: client,
+		prefix: cfg.KeyPrefix,
+		ttl:    time.Duration(cfg.TTLSeconds) * time.Second,
+	}, nil
+}
+
+func (r *RedisStore) key(class CacheClass, key string) string {
+	return r.prefix + string(class) + ":" + key
+}
+
+// SetTTLJitter sets max random deviation of ttl in percents
+func (r *RedisStore) SetTTLJitter(percent uint32) {
+	r.jitter = percent
+}
+
+func (r *RedisStore) Get(ctx context.Context, class CacheClass, key string) ([]byte, bool, error) {
+	data, err := r.client.Get(ctx, r.key(class, key)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+func (r *RedisStore) Set(ctx context.Context, class CacheClass, key string, data []byte, ttl time.Duration) error {
+	if ttl == 0 {
+		ttl = r.ttl
+	}
+	return r.client.Set(ctx, r.key(class, key), data, jitterTTL(ttl, r.jitter)).Err()
+}
+
+func (r *RedisStore) Delete(ctx context.Context, class CacheClass, key string) error {
+	return r.client.Del(ctx, r.key(class, key)).Err()
+}
+
+func (r *RedisStore) Purge(ctx context.Context, class CacheClass) error {
+	if r.prefix == "" {
+		// keys of other applications in the same database would be matched too
+		return errors.New("purge of redis store without key prefix is refused")
+	}
+
+	match := r.prefix + "*"
+	if class != "" {
+		match = r.key(class, "*")
+	}
+
+	iter := r.client.Scan(ctx, 0, match, 1000).Iterator()
+	var keys []string
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) == 1000 {
+			if err := r.client.Del(ctx, keys...).Err(); err != nil {
+				return err
+			}
+			keys = keys[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	if len(keys) > 0 {
+		return r.client.Del(ctx, keys...).Err()
+	}
+	return nil
+}
+
+func (r *RedisStore) Close() error {
+	return r.client.Close()
+}
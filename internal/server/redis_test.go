@@ -0,0 +1,16 @@
+package server
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRedisPurgeRequiresPrefix(t *testing.T) {
+	// client is not set, purge should be refused before any command is sent
+	r := &RedisStore{}
+	for _, class := range []CacheClass{"", "blocks"} {
+		if err := r.Purge(context.Background(), class); err == nil {
+			t.Fatalf("purge of class %q without prefix is expected to be refused", class)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package server
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	ReportKindPanic           = "panic"
+	ReportKindEmulationFailed = "emulation_failed"
+	ReportKindBackendFailing  = "backend_failing"
+)
+
+// ErrorReport is sent to webhook as json
+type ErrorReport struct {
+	Kind        string            `json:"kind"`
+	Message     string            `json:"message"`
+	Time        int64             `json:"time"`
+	Environment string            `json:"environment,omitempty"`
+	Fields      map[string]string `json:"fields,omitempty"`
+	Stack       string            `json:"stack,omitempty"`
+}
+
+// ErrorReporter sends panics, emulation failures and repeated backend errors to sentry and webhook,
+// reports are sent in background and the same error is reported once per interval, so spikes do not flood
+type ErrorReporter struct {
+	sentryURL  string
+	sentryAuth string
+	webhookURL string
+	env        string
+
+	minInterval     time.Duration
+	backendFailures uint64
+
+	client  *http.Client
+	queue   chan *ErrorReport
+	lastMx  sync.Mutex
+	last    map[string]time.Time
+	closed  chan struct{}
+	closeMx sync.Once
+}
+
+var reporter atomic.Pointer[ErrorReporter]
+
+// SetErrorReporter sets reporter used by proxy components, nil disables reporting
+func SetErrorReporter(r *ErrorReporter) {
+	reporter.Store(r)
+}
+
+// reportError sends report with global reporter when it is set
+func reportError(kind, message string, fields map[string]string, stack string) {
+	if r := reporter.Load(); r != nil {
+		r.Report(kind, message, fields, stack)
+	}
+}
+
+func NewErrorReporter(cfg config.ErrorReportingConfig) (*ErrorReporter, error) {
+	r := &ErrorReporter{
+		webhookURL:      cfg.WebhookURL,
+		env:             cfg.Environment,
+		minInterval:     time.Duration(cfg.MinIntervalSeconds) * time.Second,
+		backendFailures: uint64(cfg.BackendFailuresToReport),
+		client:          &http.Client{Timeout: 10 * time.Second},
+		queue:           make(chan *ErrorReport, 256),
+		last:            map[string]time.Time{},
+		closed:          make(chan struct{}),
+	}
+	if r.minInterval == 0 {
+		r.minInterval = time.Minute
+	}
+	if r.backendFailures == 0 {
+		r.backendFailures = 10
+	}
+
+	if cfg.SentryDSN != "" {
+		var err error
+		if r.sentryURL, r.sentryAuth, err = parseSentryDSN(cfg.SentryDSN); err != nil {
+			return nil, err
+		}
+	}
+
+	go r.worker()
+	return r, nil
+}
+
+// parseSentryDSN returns store endpoint and auth header of project from dsn like https://key@host/project
+func parseSentryDSN(dsn string) (string, string, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid sentry dsn: %w", err)
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return "", "", fmt.Errorf("sentry dsn has no public key")
+	}
+
+	path := strings.Trim(u.Path, "/")
+	i := strings.LastIndexByte(path, '/')
+	prefix, project := "", path
+	if i >= 0 {
+		prefix, project = "/"+path[:i], path[i+1:]
+	}
+	if project == "" {
+		return "", "", fmt.Errorf("sentry dsn has no project id")
+	}
+
+	endpoint := fmt.Sprintf("%s://%s%s/api/%s/store/", u.Scheme, u.Host, prefix, project)
+	auth := "Sentry sentry_version=7, sentry_client=tonutils-liteserver-proxy/1.0, sentry_key=" + u.User.Username()
+	return endpoint, auth, nil
+}
+
+// Report queues error to send, it is dropped when the same kind and message was reported recently or queue is full
+func (r *ErrorReporter) Report(kind, message string, fields map[string]string, stack string) {
+	fingerprint := kind + ":" + message
+	now := time.Now()
+
+	r.lastMx.Lock()
+	if at, ok := r.last[fingerprint]; ok && now.Sub(at) < r.minInterval {
+		r.lastMx.Unlock()
+		return
+	}
+	r.last[fingerprint] = now
+	if len(r.last) > 10000 {
+		// messages with unique details should not grow map forever
+		r.last = map[string]time.Time{fingerprint: now}
+	}
+	r.lastMx.Unlock()
+
+	select {
+	case r.queue <- &ErrorReport{
+		Kind:        kind,
+		Message:     message,
+		Time:        now.Unix(),
+		Environment: r.env,
+		Fields:      fields,
+		Stack:       stack,
+	}:
+	default:
+		log.Debug().Str("kind", kind).Msg("error report dropped, queue is full")
+	}
+}
+
+// BackendFailed is called with each failed backend query, streak of failures is reported once when it reaches threshold
+func (r *ErrorReporter) BackendFailed(name string, streak uint64, err error) {
+	if streak != r.backendFailures {
+		return
+	}
+
+	msg := "unknown error"
+	if err != nil {
+		msg = err.Error()
+	}
+	r.Report(ReportKindBackendFailing, fmt.Sprintf("backend %s failed %d queries in a row", name, streak),
+		map[string]string{"backend": name, "last_error": msg}, "")
+}
+
+func (r *ErrorReporter) worker() {
+	for {
+		select {
+		case <-r.closed:
+			return
+		case rep := <-r.queue:
+			if r.sentryURL != "" {
+				if err := r.send(r.sentryURL, r.sentryEvent(rep), r.sentryAuth); err != nil {
+					log.Warn().Err(err).Msg("failed to send error report to sentry")
+				}
+			}
+			if r.webhookURL != "" {
+				if err := r.send(r.webhookURL, rep, ""); err != nil {
+					log.Warn().Err(err).Msg("failed to send error report to webhook")
+				}
+			}
+		}
+	}
+}
+
+func (r *ErrorReporter) sentryEvent(rep *ErrorReport) map[string]any {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+
+	level := "error"
+	if rep.Kind == ReportKindPanic {
+		level = "fatal"
+	}
+
+	tags := map[string]string{"kind": rep.Kind}
+	for k, v := range rep.Fields {
+		tags[k] = v
+	}
+
+	ev := map[string]any{
+		"event_id":    hex.EncodeToString(id),
+		"timestamp":   time.Unix(rep.Time, 0).UTC().Format(time.RFC3339),
+		"level":       level,
+		"logger":      "liteserver-proxy",
+		"platform":    "go",
+		"message":     rep.Message,
+		"tags":        tags,
+		"fingerprint": []string{rep.Kind, rep.Message},
+	}
+	if rep.Environment != "" {
+		ev["environment"] = rep.Environment
+	}
+	if rep.Stack != "" {
+		ev["extra"] = map[string]string{"stack": rep.Stack}
+	}
+	return ev
+}
+
+func (r *ErrorReporter) send(endpoint string, v any, auth string) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if auth != "" {
+		req.Header.Set("X-Sentry-Auth", auth)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Close stops sending, queued reports are dropped
+func (r *ErrorReporter) Close() {
+	r.closeMx.Do(func() {
+		close(r.closed)
+	})
+}
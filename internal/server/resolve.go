@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"net"
+	"time"
+)
+
+// how often unhealthy backends with hostnames are resolved again, their address could be changed
+const unhealthyResolveInterval = 5 * time.Second
+
+// resolveBackendAddr returns ip:port of backend, hostname is resolved to its first address
+func resolveBackendAddr(ctx context.Context, addr string) (string, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", err
+	}
+	if net.ParseIP(host) != nil {
+		return addr, nil
+	}
+
+	ips, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", host, err)
+	}
+	if len(ips) == 0 {
+		return "", fmt.Errorf("no addresses of %s", host)
+	}
+	return net.JoinHostPort(ips[0], port), nil
+}
+
+// StartDNSRefresh resolves hostnames of backends periodically and reconnects backends which addresses are changed,
+// unhealthy backends are checked more often, because connection failure is usually the first sign of moved node
+func (b *BackendBalancer) StartDNSRefresh(interval time.Duration) {
+	go func() {
+		last := time.Now()
+		for {
+			select {
+			case <-b.closed:
+				return
+			case <-time.After(unhealthyResolveInterval):
+			}
+
+			all := time.Since(last) >= interval
+			if all {
+				last = time.Now()
+			}
+
+			for _, backend := range b.Backends() {
+				if all || !backend.IsHealthy() {
+					b.refreshBackendAddr(backend)
+				}
+			}
+		}
+	}()
+}
+
+func (b *BackendBalancer) refreshBackendAddr(backend *Backend) {
+	host, port, err := net.SplitHostPort(backend.Addr)
+	if backend.discovered || err != nil || net.ParseIP(host) != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ips, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil || len(ips) == 0 {
+		log.Warn().Err(err).Str("backend", backend.Addr).Msg("failed to resolve backend hostname")
+		metrics.Global.BackendResolves.WithLabelValues(backend.Name, "failed").Add(1)
+		return
+	}
+
+	for _, ip := range ips {
+		if net.JoinHostPort(ip, port) == backend.resolved {
+			metrics.Global.BackendResolves.WithLabelValues(backend.Name, "same").Add(1)
+			return
+		}
+	}
+	metrics.Global.BackendResolves.WithLabelValues(backend.Name, "changed").Add(1)
+
+	fresh, err := connectBackend(ctx, backend.cfg)
+	if err != nil {
+		log.Warn().Err(err).Str("backend", backend.Addr).Msg("failed to connect backend at new address")
+		return
+	}
+
+	removed, err := b.swapBackends([]string{backend.Name}, []*Backend{fresh})
+	if err != nil {
+		fresh.Stop()
+		log.Warn().Err(err).Str("backend", backend.Addr).Msg("failed to replace backend with new address")
+		return
+	}
+
+	log.Info().Str("backend", backend.Addr).Str("from", backend.resolved).Str("to", fresh.resolved).Msg("backend address changed, reconnected")
+	for _, old := range removed {
+		go old.drain(backendDrainTimeout)
+	}
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"sync"
+	"time"
+)
+
+// RetryBudget limits amount of retries relatively to amount of requests,
+// so retries can't multiply load on backends during an outage
+type RetryBudget struct {
+	ratio     float64
+	minPerSec float64
+	max       float64
+
+	tokens  float64
+	updated time.Time
+	mx      sync.Mutex
+}
+
+// NewRetryBudget creates budget which allows ratio retries per request,
+// plus minPerSec retries per second to let low traffic to be retried too
+func NewRetryBudget(ratio, minPerSec float64) *RetryBudget {
+	max := minPerSec * 10
+	if max < 10 {
+		max = 10
+	}
+
+	return &RetryBudget{
+		ratio:     ratio,
+		minPerSec: minPerSec,
+		max:       max,
+		tokens:    max,
+		updated:   time.Now(),
+	}
+}
+
+func (r *RetryBudget) Deposit() {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.add(r.ratio)
+}
+
+func (r *RetryBudget) TryWithdraw() bool {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	now := time.Now()
+	r.add(now.Sub(r.updated).Seconds() * r.minPerSec)
+	r.updated = now
+
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	return true
+}
+
+func (r *RetryBudget) add(tokens float64) {
+	r.tokens += tokens
+	if r.tokens > r.max {
+		r.tokens = r.max
+	}
+}
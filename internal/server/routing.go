@@ -0,0 +1,170 @@
+package server
+
+import (
+	"encoding/binary"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"hash/fnv"
+	"math"
+	"reflect"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	BackendTagFast      = "fast"
+	BackendTagArchive   = "archive"
+	BackendTagFullState = "full-state"
+)
+
+// RequestRouter decides which kind of backend should serve a request
+type RequestRouter struct {
+	methodTags       map[string]string
+	archiveSeqnoDiff uint32
+	archiveAge       time.Duration
+
+	masterSeqno uint32
+}
+
+func NewRequestRouter(cfg config.RoutingConfig) *RequestRouter {
+	r := &RequestRouter{
+		methodTags:       map[string]string{},
+		archiveSeqnoDiff: cfg.ArchiveMasterSeqnoDiff,
+		archiveAge:       time.Duration(cfg.ArchiveAfterSeconds) * time.Second,
+	}
+
+	r.methodTags[reflect.TypeOf(ton.GetTransactions{}).String()] = BackendTagArchive
+	r.methodTags[reflect.TypeOf(ton.GetState{}).String()] = BackendTagFullState
+	for method, tag := range cfg.MethodTags {
+		r.methodTags[method] = tag
+	}
+	return r
+}
+
+// ObserveMasterSeqno updates known top master seqno, which is used to detect old blocks
+func (r *RequestRouter) ObserveMasterSeqno(seqno uint32) {
+	for {
+		old := atomic.LoadUint32(&r.masterSeqno)
+		if seqno <= old || atomic.CompareAndSwapUint32(&r.masterSeqno, old, seqno) {
+			return
+		}
+	}
+}
+
+// Tag returns backend tag required to serve payload
+func (r *RequestRouter) Tag(payload tl.Serializable) string {
+	if list, ok := payload.([]tl.Serializable); ok && len(list) > 0 {
+		// wait master with wrapped query
+		return r.Tag(list[len(list)-1])
+	}
+
+	if tag, ok := r.methodTags[reflect.TypeOf(payload).String()]; ok {
+		return tag
+	}
+
+	switch v := payload.(type) {
+	case ton.LookupBlock:
+		if v.Mode&4 != 0 && r.archiveAge > 0 && time.Unix(int64(v.UTime), 0).Before(time.Now().Add(-r.archiveAge)) {
+			return BackendTagArchive
+		}
+		if v.ID != nil && r.isOldMaster(v.ID.Workchain, uint32(v.ID.Seqno)) {
+			return BackendTagArchive
+		}
+	case ton.GetBlockHeader:
+		if v.ID != nil && r.isOldMaster(v.ID.Workchain, uint32(v.ID.Seqno)) {
+			return BackendTagArchive
+		}
+	default:
+		if id := requestBlockID(payload); id != nil && r.isOldMaster(id.Workchain, id.SeqNo) {
+			return BackendTagArchive
+		}
+	}
+	return BackendTagFast
+}
+
+// isOldMaster checks only masterchain blocks, because for shards we don't know the latest seqno
+func (r *RequestRouter) isOldMaster(wc int32, seqno uint32) bool {
+	top := atomic.LoadUint32(&r.masterSeqno)
+	return wc == -1 && r.archiveSeqnoDiff > 0 && top > 0 && seqno+r.archiveSeqnoDiff < top
+}
+
+// requestBlockID returns block which request refers to, or nil
+func requestBlockID(payload tl.Serializable) *ton.BlockIDExt {
+	switch v := payload.(type) {
+	case ton.GetBlockData:
+		return v.ID
+	case ton.GetOneTransaction:
+		return v.ID
+	case ton.GetAccountState:
+		return v.ID
+	case ton.GetAccountStatePruned:
+		return v.ID
+	case ton.RunSmcMethod:
+		return v.ID
+	case ton.ListBlockTransactions:
+		return v.ID
+	case ton.ListBlockTransactionsExt:
+		return v.ID
+	case ton.GetAllShardsInfo:
+		return v.ID
+	case ton.GetShardInfo:
+		return v.ID
+	case ton.GetConfigAll:
+		return v.BlockID
+	case ton.GetConfigParams:
+		return v.BlockID
+	case ton.GetState:
+		return v.ID
+	case ton.GetShardBlockProof:
+		return v.ID
+	}
+	return nil
+}
+
+// requestAccount returns account which request refers to, or nil
+func requestAccount(payload tl.Serializable) *ton.AccountID {
+	switch v := payload.(type) {
+	case ton.GetAccountState:
+		return &v.Account
+	case ton.GetAccountStatePruned:
+		return &v.Account
+	case ton.RunSmcMethod:
+		return &v.Account
+	case ton.GetOneTransaction:
+		return v.AccID
+	case ton.GetTransactions:
+		return v.AccID
+	}
+	return nil
+}
+
+// selectByAccount picks healthy backend by weighted rendezvous hash of account, so each backend gets
+// stable subset of accounts, and when backend is added or removed only its share of accounts is moved.
+// nil is returned when there are no healthy backends.
+func selectByAccount(backends []*Backend, acc *ton.AccountID) *Backend {
+	var best *Backend
+	bestScore := math.Inf(-1)
+	for _, backend := range backends {
+		if !backend.IsHealthy() {
+			continue
+		}
+
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(backend.Addr))
+		_ = binary.Write(h, binary.BigEndian, acc.Workchain)
+		_, _ = h.Write(acc.ID)
+
+		// uniform value in (0, 1), score of backend grows with its weight
+		u := (float64(h.Sum64()>>11) + 0.5) / (1 << 53)
+		weight := float64(backend.Weight)
+		if weight == 0 {
+			weight = 1
+		}
+
+		if score := -weight / math.Log(u); score > bestScore {
+			best, bestScore = backend, score
+		}
+	}
+	return best
+}
@@ -0,0 +1,395 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// S3Store is a CacheStore in s3 compatible object storage, it is the cold tier for history, objects are immutable
+// and never expire there. Keys of existing objects are indexed in memory, so misses do not go to storage.
+type S3Store struct {
+	endpoint  string
+	region    string
+	bucket    string
+	prefix    string
+	accessKey string
+	secretKey string
+	classes   map[CacheClass]bool
+
+	client *http.Client
+
+	index   map[string]struct{}
+	indexMx sync.RWMutex
+
+	uploads chan s3Upload
+	done    chan struct{}
+}
+
+type s3Upload struct {
+	key  string
+	data []byte
+}
+
+type s3ListResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// default classes of s3 tier, libraries are small and hot, so they stay in faster layers
+var s3DefaultClasses = []CacheClass{CacheClassMasterBlocks, CacheClassShardBlocks, CacheClassAccounts, CacheClassTransactions}
+
+func NewS3Store(cfg config.S3CacheConfig) (*S3Store, error) {
+	s := &S3Store{
+		endpoint:  strings.TrimSuffix(cfg.Endpoint, "/"),
+		region:    cfg.Region,
+		bucket:    cfg.Bucket,
+		prefix:    cfg.KeyPrefix,
+		accessKey: cfg.AccessKey,
+		secretKey: cfg.SecretKey,
+		classes:   map[CacheClass]bool{},
+		client:    &http.Client{Timeout: 30 * time.Second},
+		index:     map[string]struct{}{},
+		uploads:   make(chan s3Upload, 1024),
+		done:      make(chan struct{}),
+	}
+	if s.region == "" {
+		s.region = "us-east-1"
+	}
+
+	classes := s3DefaultClasses
+	if len(cfg.Classes) > 0 {
+		classes = nil
+		for _, c := range cfg.Classes {
+			classes = append(classes, CacheClass(c))
+		}
+	}
+	for _, c := range classes {
+		s.classes[c] = true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if err := s.loadIndex(ctx); err != nil {
+		return nil, fmt.Errorf("failed to list s3 bucket: %w", err)
+	}
+	log.Info().Str("bucket", s.bucket).Int("objects", len(s.index)).Msg("s3 store index loaded")
+
+	workers := int(cfg.UploadWorkers)
+	if workers == 0 {
+		workers = 4
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.uploader()
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(s.done)
+	}()
+
+	return s, nil
+}
+
+func (s *S3Store) key(class CacheClass, key string) string {
+	return s.prefix + string(class) + "/" + key
+}
+
+// loadIndex lists all objects under prefix
+func (s *S3Store) loadIndex(ctx context.Context) error {
+	token := ""
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", s.prefix)
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		resp, err := s.do(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return err
+		}
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("list status %d: %s", resp.StatusCode, string(body))
+		}
+
+		var res s3ListResult
+		if err = xml.Unmarshal(body, &res); err != nil {
+			return fmt.Errorf("failed to parse list result: %w", err)
+		}
+
+		for _, obj := range res.Contents {
+			s.index[obj.Key] = struct{}{}
+		}
+		if !res.IsTruncated || res.NextContinuationToken == "" {
+			return nil
+		}
+		token = res.NextContinuationToken
+	}
+}
+
+func (s *S3Store) has(key string) bool {
+	s.indexMx.RLock()
+	defer s.indexMx.RUnlock()
+
+	_, ok := s.index[key]
+	return ok
+}
+
+func (s *S3Store) Get(ctx context.Context, class CacheClass, key string) ([]byte, bool, error) {
+	k := s.key(class, key)
+	if !s.classes[class] || !s.has(k) {
+		return nil, false, nil
+	}
+
+	tm := time.Now()
+	resp, err := s.do(ctx, http.MethodGet, k, nil, nil)
+	if err != nil {
+		metrics.Global.S3Requests.WithLabelValues("get", "failed").Observe(time.Since(tm).Seconds())
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		// removed from bucket by someone else
+		s.indexMx.Lock()
+		delete(s.index, k)
+		s.indexMx.Unlock()
+		metrics.Global.S3Requests.WithLabelValues("get", "not_found").Observe(time.Since(tm).Seconds())
+		return nil, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		metrics.Global.S3Requests.WithLabelValues("get", "failed").Observe(time.Since(tm).Seconds())
+		return nil, false, fmt.Errorf("s3 get status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, err
+	}
+	metrics.Global.S3Requests.WithLabelValues("get", "ok").Observe(time.Since(tm).Seconds())
+	return data, true, nil
+}
+
+// Set uploads object in background, ttl is ignored because objects of history are immutable
+func (s *S3Store) Set(_ context.Context, class CacheClass, key string, data []byte, _ time.Duration) error {
+	k := s.key(class, key)
+	if !s.classes[class] || s.has(k) {
+		return nil
+	}
+
+	select {
+	case s.uploads <- s3Upload{key: k, data: data}:
+	default:
+		// storage is slower than ingestion, object will be uploaded when it is requested again
+		metrics.Global.S3Requests.WithLabelValues("put", "dropped").Observe(0)
+	}
+	return nil
+}
+
+func (s *S3Store) uploader() {
+	for up := range s.uploads {
+		if s.has(up.key) {
+			continue
+		}
+
+		tm := time.Now()
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		resp, err := s.do(ctx, http.MethodPut, up.key, nil, up.data)
+		cancel()
+		if err != nil {
+			log.Debug().Err(err).Str("key", up.key).Msg("failed to upload object to s3")
+			metrics.Global.S3Requests.WithLabelValues("put", "failed").Observe(time.Since(tm).Seconds())
+			continue
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			log.Debug().Int("status", resp.StatusCode).Str("key", up.key).Msg("failed to upload object to s3")
+			metrics.Global.S3Requests.WithLabelValues("put", "failed").Observe(time.Since(tm).Seconds())
+			continue
+		}
+
+		s.indexMx.Lock()
+		s.index[up.key] = struct{}{}
+		s.indexMx.Unlock()
+		metrics.Global.S3Requests.WithLabelValues("put", "ok").Observe(time.Since(tm).Seconds())
+	}
+}
+
+func (s *S3Store) Delete(ctx context.Context, class CacheClass, key string) error {
+	return s.delete(ctx, s.key(class, key))
+}
+
+func (s *S3Store) delete(ctx context.Context, key string) error {
+	s.indexMx.Lock()
+	delete(s.index, key)
+	s.indexMx.Unlock()
+
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, nil)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("s3 delete status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Purge removes indexed objects of class one by one
+func (s *S3Store) Purge(ctx context.Context, class CacheClass) error {
+	prefix := s.prefix
+	if class != "" {
+		prefix = s.key(class, "")
+	}
+
+	var keys []string
+	s.indexMx.RLock()
+	for k := range s.index {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	s.indexMx.RUnlock()
+
+	for _, k := range keys {
+		if err := s.delete(ctx, k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close waits for queued uploads
+func (s *S3Store) Close() error {
+	close(s.uploads)
+	<-s.done
+	return nil
+}
+
+// do sends request to bucket signed with aws signature v4, path style urls are used
+// because they are supported by all s3 compatible storages
+func (s *S3Store) do(ctx context.Context, method, key string, query url.Values, body []byte) (*http.Response, error) {
+	path := "/" + s.bucket
+	if key != "" {
+		path += "/" + key
+	}
+
+	u, err := url.Parse(s.endpoint)
+	if err != nil {
+		return nil, err
+	}
+	u.RawPath = s3EscapePath(path)
+	u.Path = path
+	u.RawQuery = s3CanonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+
+	payloadHash := sha256.Sum256(body)
+	s.sign(req, u.RawPath, hex.EncodeToString(payloadHash[:]), time.Now().UTC())
+	return s.client.Do(req)
+}
+
+func (s *S3Store) sign(req *http.Request, canonicalPath, payloadHash string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+
+	canonicalRequest := strings.Join([]string{
+		req.Method, canonicalPath, req.URL.RawQuery, canonicalHeaders, signedHeaders, payloadHash,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+s.accessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// s3EscapePath encodes everything except unreserved characters and slashes, as required by signature
+func s3EscapePath(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if c == '/' || c == '-' || c == '_' || c == '.' || c == '~' ||
+			('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+// s3CanonicalQuery sorts parameters and encodes them the same way as path
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		for _, v := range query[k] {
+			parts = append(parts, strings.ReplaceAll(s3EscapePath(k), "/", "%2F")+"="+strings.ReplaceAll(s3EscapePath(v), "/", "%2F"))
+		}
+	}
+	return strings.Join(parts, "&")
+}
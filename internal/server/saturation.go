@@ -0,0 +1,89 @@
+package server
+
+import (
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"sync/atomic"
+	"time"
+)
+
+// saturation gauges are sampled, autoscalers scrape them much less often anyway
+const saturationSampleInterval = time.Second
+
+// sampleSaturation exports queued queries and busy workers of client connections and qos scheduler
+// until balancer is closed, they show real pressure on proxy, unlike cpu which is mostly idle waiting for backends
+func (s *ProxyBalancer) sampleSaturation() {
+	for {
+		var queued, running, max int
+		s.mx.RLock()
+		for _, ip := range s.ips {
+			for _, conn := range ip.ActiveConnections {
+				if conn.workers == nil {
+					continue
+				}
+				queued += len(conn.workers.queue)
+				running += int(atomic.LoadInt32(&conn.workers.running))
+				max += int(conn.workers.max)
+			}
+		}
+		s.mx.RUnlock()
+
+		metrics.Global.QueueDepth.WithLabelValues("connections").Set(float64(queued))
+		metrics.Global.PoolUtilization.WithLabelValues("connection_workers").Set(utilization(running, max))
+
+		if s.qos != nil {
+			s.qos.mx.Lock()
+			queued, running, max = s.qos.queued, s.qos.running, s.qos.max
+			s.qos.mx.Unlock()
+
+			metrics.Global.QueueDepth.WithLabelValues("qos").Set(float64(queued))
+			metrics.Global.PoolUtilization.WithLabelValues("qos").Set(utilization(running, max))
+		}
+
+		select {
+		case <-s.closed:
+			return
+		case <-time.After(saturationSampleInterval):
+		}
+	}
+}
+
+// StartSaturationGauges exports queries in flight of backends and waiting for fair queue slots
+func (b *BackendBalancer) StartSaturationGauges() {
+	go func() {
+		for {
+			for _, backend := range b.Backends() {
+				inFlight := atomic.LoadInt64(&backend.inFlight)
+				metrics.Global.BackendInFlight.WithLabelValues(backend.Name).Set(float64(inFlight))
+				if backend.maxInFlight > 0 {
+					metrics.Global.BackendSaturation.WithLabelValues(backend.Name).Set(float64(inFlight) / float64(backend.maxInFlight))
+				}
+			}
+
+			if b.fair != nil {
+				b.fair.mx.Lock()
+				queued := 0
+				for _, list := range b.fair.waiting {
+					queued += len(list)
+				}
+				running, max := b.fair.running, b.fair.max
+				b.fair.mx.Unlock()
+
+				metrics.Global.QueueDepth.WithLabelValues("backend_fair").Set(float64(queued))
+				metrics.Global.PoolUtilization.WithLabelValues("backend_fair").Set(utilization(running, max))
+			}
+
+			select {
+			case <-b.closed:
+				return
+			case <-time.After(saturationSampleInterval):
+			}
+		}
+	}()
+}
+
+func utilization(running, max int) float64 {
+	if max <= 0 {
+		return 0
+	}
+	return float64(running) / float64(max)
+}
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BackendSelector decides which backend should serve the next query, nil is returned when list is empty
+type BackendSelector interface {
+	Select(backends []*Backend) *Backend
+}
+
+const ewmaAlpha = 0.2
+
+// how much error rate increases backend's latency score, 100% errors makes it 10x slower
+const ewmaErrorPenalty = 9
+
+// each n-th latency selection is done with round-robin, to refresh stats of slower backends
+const latencyExploreEvery = 20
+
+func NewBackendSelector(typ BalancerType) (BackendSelector, error) {
+	switch typ {
+	case BalancerTypeRoundRobin:
+		return &RoundRobinSelector{}, nil
+	case BalancerTypeFailOver:
+		return &FailOverSelector{}, nil
+	case BalancerTypeLatency:
+		return &LatencySelector{}, nil
+	case BalancerTypeWeighted:
+		return &WeightedSelector{}, nil
+	}
+	return nil, fmt.Errorf("unknown balancer type: %s", typ)
+}
+
+type RoundRobinSelector struct {
+	counter uint64
+}
+
+func (r *RoundRobinSelector) Select(backends []*Backend) *Backend {
+	if len(backends) == 0 {
+		return nil
+	}
+
+	x := atomic.AddUint64(&r.counter, 1)
+	for i := uint64(0); i < uint64(len(backends)); i++ {
+		if backend := backends[(x+i)%uint64(len(backends))]; backend.IsHealthy() {
+			return backend
+		}
+	}
+	// nothing healthy, try anything
+	return backends[x%uint64(len(backends))]
+}
+
+type FailOverSelector struct {
+	fallback RoundRobinSelector
+}
+
+func (f *FailOverSelector) Select(backends []*Backend) *Backend {
+	for _, backend := range backends {
+		if !backend.IsHealthy() {
+			// failed node
+			continue
+		}
+		return backend
+	}
+
+	// all nodes failed over switch to round-robin, and maybe it will become alive
+	return f.fallback.Select(backends)
+}
+
+// WeightedSelector distributes queries proportionally to backend weights,
+// using smooth weighted round-robin, so heavy backend's queries are interleaved with others
+type WeightedSelector struct {
+	current  map[*Backend]int64
+	fallback RoundRobinSelector
+	mx       sync.Mutex
+}
+
+func (w *WeightedSelector) Select(backends []*Backend) *Backend {
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	if len(w.current) > len(backends) {
+		// backends were removed by reload or discovery, state is rebuilt from the current set
+		current := make(map[*Backend]int64, len(backends))
+		for _, backend := range backends {
+			if v, ok := w.current[backend]; ok {
+				current[backend] = v
+			}
+		}
+		w.current = current
+	}
+	if w.current == nil {
+		w.current = map[*Backend]int64{}
+	}
+
+	var best *Backend
+	var total int64
+	for _, backend := range backends {
+		if !backend.IsHealthy() {
+			continue
+		}
+
+		weight := int64(backend.Weight)
+		if weight == 0 {
+			weight = 1
+		}
+		total += weight
+
+		w.current[backend] += weight
+		if best == nil || w.current[backend] > w.current[best] {
+			best = backend
+		}
+	}
+
+	if best == nil {
+		return w.fallback.Select(backends)
+	}
+	w.current[best] -= total
+
+	return best
+}
+
+// LatencySelector prefers healthy backend with the lowest
+// moving average of latency, adjusted by its error rate
+type LatencySelector struct {
+	explore RoundRobinSelector
+	counter uint64
+}
+
+func (l *LatencySelector) Select(backends []*Backend) *Backend {
+	if atomic.AddUint64(&l.counter, 1)%latencyExploreEvery == 0 {
+		return l.explore.Select(backends)
+	}
+
+	var best *Backend
+	bestScore := math.MaxFloat64
+	for _, backend := range backends {
+		if !backend.IsHealthy() {
+			continue
+		}
+
+		if score := backend.LatencyScore(); score < bestScore {
+			best, bestScore = backend, score
+		}
+	}
+
+	if best == nil {
+		return l.explore.Select(backends)
+	}
+	return best
+}
+
+// LatencyScore returns smoothed latency in seconds multiplied by error penalty,
+// backend without stats has zero score, so it will be tried first
+func (b *Backend) LatencyScore() float64 {
+	latency := math.Float64frombits(atomic.LoadUint64(&b.ewmaLatency))
+	errRate := math.Float64frombits(atomic.LoadUint64(&b.ewmaErrorRate))
+	return latency * (1 + errRate*ewmaErrorPenalty)
+}
+
+func (b *Backend) observe(took time.Duration, failed bool) {
+	errVal := 0.0
+	if failed {
+		errVal = 1
+	}
+	updateEWMA(&b.ewmaLatency, took.Seconds(), true)
+	updateEWMA(&b.ewmaErrorRate, errVal, false)
+}
+
+// updateEWMA atomically applies new value to average, when seed is true
+// the first value is taken as is instead of being averaged with zero
+func updateEWMA(addr *uint64, val float64, seed bool) {
+	for {
+		oldBits := atomic.LoadUint64(addr)
+		old := math.Float64frombits(oldBits)
+
+		upd := val
+		if oldBits != 0 || !seed {
+			upd = old*(1-ewmaAlpha) + val*ewmaAlpha
+		}
+
+		if atomic.CompareAndSwapUint64(addr, oldBits, math.Float64bits(upd)) {
+			return
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"testing"
+)
+
+func TestSelectorsEmpty(t *testing.T) {
+	for _, typ := range []BalancerType{BalancerTypeRoundRobin, BalancerTypeFailOver, BalancerTypeLatency, BalancerTypeWeighted} {
+		t.Run(string(typ), func(t *testing.T) {
+			selector, err := NewBackendSelector(typ)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if backend := selector.Select(nil); backend != nil {
+				t.Fatalf("expected nil for empty list, got %s", backend.Name)
+			}
+		})
+	}
+}
+
+func TestSelectorsSkipUnhealthy(t *testing.T) {
+	for _, typ := range []BalancerType{BalancerTypeRoundRobin, BalancerTypeFailOver, BalancerTypeLatency, BalancerTypeWeighted} {
+		t.Run(string(typ), func(t *testing.T) {
+			selector, err := NewBackendSelector(typ)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			down := &Backend{Name: "down", evicted: 1}
+			up := &Backend{Name: "up"}
+			for i := 0; i < 10; i++ {
+				if backend := selector.Select([]*Backend{down, up}); backend != up {
+					t.Fatalf("expected healthy backend, got %s", backend.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestWeightedSelector(t *testing.T) {
+	a := &Backend{Name: "a", Weight: 3}
+	b := &Backend{Name: "b", Weight: 1}
+	c := &Backend{Name: "c", Weight: 1}
+
+	tests := []struct {
+		name     string
+		backends []*Backend
+		want     map[string]int
+	}{
+		{name: "proportional to weights", backends: []*Backend{a, b}, want: map[string]int{"a": 6, "b": 2}},
+		{name: "removed backend is not selected", backends: []*Backend{a, c}, want: map[string]int{"a": 6, "c": 2}},
+		{name: "zero weight is one", backends: []*Backend{{Name: "d"}, {Name: "e"}}, want: map[string]int{"d": 4, "e": 4}},
+	}
+
+	w := &WeightedSelector{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[string]int{}
+			for i := 0; i < 8; i++ {
+				got[w.Select(tt.backends).Name]++
+			}
+			for name, n := range tt.want {
+				if got[name] != n {
+					t.Fatalf("expected %v, got %v", tt.want, got)
+				}
+			}
+
+			if len(w.current) > len(tt.backends) {
+				t.Fatalf("state of removed backends is kept, %d entries for %d backends", len(w.current), len(tt.backends))
+			}
+		})
+	}
+}
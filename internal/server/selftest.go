@@ -0,0 +1,144 @@
+package server
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/liteclient"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"net"
+	"strconv"
+	"time"
+)
+
+// elector, exists in every network
+const selfTestAccount = "Ef8zMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzMzM0vF"
+
+// SelfTestProbe periodically connects to public endpoint of proxy as a client and runs a few queries,
+// so breakage of external path like firewall or wrong key is visible even when proxy itself is healthy
+type SelfTestProbe struct {
+	addr     string
+	key      string
+	interval time.Duration
+	timeout  time.Duration
+	account  *address.Address
+
+	closed chan struct{}
+}
+
+func NewSelfTestProbe(cfg *config.Config) (*SelfTestProbe, error) {
+	ip, port, err := PublicEndpoint(cfg, cfg.SelfTest.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	var seed []byte
+	for _, client := range cfg.Clients {
+		if cfg.SelfTest.Key == "" || client.Name == cfg.SelfTest.Key {
+			seed = client.PrivateKey
+			break
+		}
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("client key for self test is not found")
+	}
+
+	timeout := time.Duration(cfg.SelfTest.TimeoutSeconds) * time.Second
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &SelfTestProbe{
+		addr:     net.JoinHostPort(ip.String(), strconv.Itoa(port)),
+		key:      base64.StdEncoding.EncodeToString(ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)),
+		interval: time.Duration(cfg.SelfTest.IntervalSeconds) * time.Second,
+		timeout:  timeout,
+		account:  address.MustParseAddr(selfTestAccount),
+		closed:   make(chan struct{}),
+	}, nil
+}
+
+// Start runs probes every interval until Close
+func (p *SelfTestProbe) Start() {
+	go func() {
+		for {
+			select {
+			case <-p.closed:
+				return
+			case <-time.After(p.interval):
+			}
+
+			ok := p.probe()
+			if ok {
+				metrics.Global.SelfTestUp.Set(1)
+			} else {
+				metrics.Global.SelfTestUp.Set(0)
+			}
+		}
+	}()
+}
+
+// probe opens new connection each time, to check handshake too
+func (p *SelfTestProbe) probe() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	client := liteclient.NewConnectionPool()
+	defer client.Stop()
+
+	tm := time.Now()
+	if err := client.AddConnection(ctx, p.addr, p.key); err != nil {
+		p.fail("connect", tm, err)
+		return false
+	}
+	p.observe("connect", tm, "ok")
+
+	tm = time.Now()
+	var resp tl.Serializable
+	if err := client.QueryLiteserver(ctx, ton.GetMasterchainInf{}, &resp); err != nil {
+		p.fail("master", tm, err)
+		return false
+	}
+	info, ok := resp.(ton.MasterchainInfo)
+	if !ok {
+		p.fail("master", tm, fmt.Errorf("unexpected answer %T", resp))
+		return false
+	}
+	p.observe("master", tm, "ok")
+
+	tm = time.Now()
+	resp = nil
+	err := client.QueryLiteserver(ctx, ton.GetAccountState{
+		ID:      info.Last,
+		Account: ton.AccountID{Workchain: p.account.Workchain(), ID: p.account.Data()},
+	}, &resp)
+	if err != nil {
+		p.fail("account", tm, err)
+		return false
+	}
+	if _, ok = resp.(ton.AccountState); !ok {
+		p.fail("account", tm, fmt.Errorf("unexpected answer %T", resp))
+		return false
+	}
+	p.observe("account", tm, "ok")
+	return true
+}
+
+func (p *SelfTestProbe) fail(step string, tm time.Time, err error) {
+	log.Warn().Err(err).Str("addr", p.addr).Str("step", step).Msg("self test through public endpoint failed")
+	p.observe(step, tm, "failed")
+}
+
+func (p *SelfTestProbe) observe(step string, tm time.Time, status string) {
+	metrics.Global.SelfTestProbes.WithLabelValues(step, status).Observe(time.Since(tm).Seconds())
+}
+
+func (p *SelfTestProbe) Close() {
+	close(p.closed)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/ed25519"
 	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/kevinms/leakybucket-go"
@@ -19,7 +20,10 @@ import (
 	"github.com/xssnick/tonutils-liteserver-proxy/internal/emulate"
 	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
 	"hash/crc64"
+	"net"
 	"reflect"
+	"runtime/debug"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -30,11 +34,39 @@ const HitTypeEmulated = "emulated"
 const HitTypeBackend = "backend"
 const HitTypeCache = "cache"
 const HitTypeGPCache = "gp_cache"
+const HitTypeNegativeCache = "negative_cache"
 const HitTypeFailedValidate = "failed_validate"
 const HitTypeFailedInternal = "failed_internal"
+const HitTypeRawProxy = "raw_proxy"
+const HitTypeStale = "stale"
+const HitTypePrecomputed = "precomputed"
+const HitTypeGasLimited = "gas_limited"
+
+// modes of client keys, overriding global mode of proxy
+const KeyModeProxy = "proxy"
+const KeyModeRaw = "raw"
+
+var ErrResponseTooBig = ton.LSError{
+	Code: 413,
+	Text: "response is too big",
+}
+
+// DefaultMetricsTypeLabels - types of messages and queries known by proxy,
+// used as metrics labels allowlist when it is not configured
+var DefaultMetricsTypeLabels = typeNames(
+	adnl.MessageQuery{}, liteclient.TCPPing{}, []tl.Serializable{}, tl.Raw{},
+	ton.GetVersion{}, ton.GetTime{}, ton.GetMasterchainInf{}, ton.GetMasterchainInfoExt{},
+	ton.GetLibraries{}, ton.GetOneTransaction{}, ton.GetTransactions{}, ton.GetBlockData{},
+	ton.GetBlockHeader{}, ton.GetBlockProof{}, ton.GetShardBlockProof{}, ton.GetAccountState{},
+	ton.GetAccountStatePruned{}, ton.RunSmcMethod{}, ton.LookupBlock{}, ton.GetConfigAll{},
+	ton.GetConfigParams{}, ton.GetAllShardsInfo{}, ton.GetShardInfo{}, ton.ListBlockTransactions{},
+	ton.ListBlockTransactionsExt{}, ton.SendMessage{}, ton.GetState{}, PeerCacheGet{},
+)
 
 type Cache interface {
 	LookupBlockInCache(id *ton.BlockInfoShort) (*ton.BlockHeader, error)
+	LookupBlockByLtInCache(id *ton.BlockInfoShort, lt uint64) (*ton.BlockHeader, error)
+	GetTransactionsInCache(acc *ton.AccountID, lt int64, hash []byte, limit int32) *ton.TransactionList
 	GetTransaction(ctx context.Context, id *ton.BlockIDExt, account *ton.AccountID, lt int64) (*ton.TransactionInfo, bool, error)
 	GetLibraries(ctx context.Context, hashes [][]byte) (*cell.Dictionary, bool, error)
 	WaitMasterBlock(ctx context.Context, seqno uint32, timeout time.Duration) error
@@ -45,6 +77,8 @@ type Cache interface {
 	GetAccountState(ctx context.Context, id *ton.BlockIDExt, addr *address.Address) (*ton.AccountState, bool, error)
 	GetAccountStateInBlock(ctx context.Context, block *Block, addr *address.Address) (*ton.AccountState, bool, error)
 	CacheBlockIfNeeded(ctx context.Context, id *ton.BlockIDExt) (*Block, bool, error)
+	IsStale() bool
+	GetStaleAccountState(id *ton.BlockIDExt, addr *address.Address) (*ton.AccountState, bool)
 }
 
 type Client struct {
@@ -52,8 +86,19 @@ type Client struct {
 }
 
 type ClientConnInfo struct {
-	Client      *liteclient.ServerClient
+	Client      *ServerClient
+	ConnectedAt int64
 	LastRequest int64
+	Requests    uint64
+
+	// key is known only after handshake, so it is set on first request
+	key atomic.Pointer[KeyConfig]
+
+	workers *connWorkers
+	// ip of client is in exemption list
+	exempt bool
+	// the highest master block seqno answered to client, for sticky consistency
+	masterSeqno uint32
 }
 
 type ClientIPInfo struct {
@@ -61,69 +106,268 @@ type ClientIPInfo struct {
 }
 
 type ProxyBalancer struct {
-	srv             *liteclient.Server
-	backendBalancer *BackendBalancer
+	srv             *adnlServer
+	backendBalancer Balancer
+
+	// servers of additional listeners, each accepts its own subset of client keys
+	extraServers []*adnlServer
+	keysByName   map[string]ed25519.PrivateKey
 
 	ips map[string]*ClientIPInfo
+	// active connections by limit key, ipv6 clients are counted by prefix
+	conns      map[string]int
+	ipv6Prefix int
 
 	cache               Cache
 	configs             map[string]*KeyConfig
+	keyModes            map[string]string
+	gasBudgets          map[string]*gasBudget
+	maxWaitMaster       time.Duration
+	keyMaxWaitMaster    map[string]time.Duration
 	onlyProxy           bool
+	rawPassthrough      bool
+	methodOverrides     atomic.Pointer[map[string]string]
 	maxConnectionsPerIP int
 	maxKeepAlive        time.Duration
-
-	gpCache *lru.ARCCache
+	exposeRequestID     bool
+	validator           *QueryValidator
+	maxResponseSize     int
+	connWorkers         int
+	connQueueSize       int
+	qos                 *qosScheduler
+	exemptNets          []*net.IPNet
+	stickyConsistency   bool
+	capture             *QueryCapture
+	canary              *canaryChecker
+	chaos               []*chaosRule
+	analytics           *QueryAnalytics
+	billing             *BillingExporter
+	limiterStatePath    string
+	sharedLimits        *sharedLimiter
+	getters             *precomputedGetters
+	systemGetters       *systemGetters
+	peerSource          CacheStore
+
+	// banned ips and keys by kind:value, with time until ban is active
+	bans   map[string]time.Time
+	bansMx sync.Mutex
+
+	// limits new connections per ip before handshake, to not spend crypto cpu on churning clients
+	handshakeLimiter *leakybucket.Collector
+
+	gpCache  *lru.ARCCache
+	negCache *NegativeCache
+
+	closed    chan struct{}
+	closeOnce sync.Once
 
 	mx sync.RWMutex
 }
 
 type KeyConfig struct {
 	name          string
+	priority      Priority
 	limiterPerIP  *leakybucket.Collector
 	limiterPerKey *leakybucket.LeakyBucket
+
+	// queries of key being processed, bounded by maxInFlight when it is set
+	inFlight    int64
+	maxInFlight int64
+
+	// key is not limited by rate and concurrency limits
+	exempt bool
+
+	// bytes of responses sent to key during period, nil when not limited
+	bandwidth *bandwidthQuota
 }
 
-func NewProxyBalancer(configs []config.ClientConfig, backendBalancer *BackendBalancer, cache Cache, onlyProxy bool, maxConnectionsPerIP int, maxKeepAlive time.Duration, gpCacheSize int) *ProxyBalancer {
+func NewProxyBalancer(cfg *config.Config, backendBalancer Balancer, cache Cache) *ProxyBalancer {
 	s := &ProxyBalancer{
 		backendBalancer:     backendBalancer,
 		configs:             map[string]*KeyConfig{},
+		keyModes:            map[string]string{},
+		gasBudgets:          map[string]*gasBudget{},
+		maxWaitMaster:       time.Duration(cfg.MaxWaitMasterMs) * time.Millisecond,
+		keyMaxWaitMaster:    map[string]time.Duration{},
 		cache:               cache,
-		onlyProxy:           onlyProxy,
-		maxConnectionsPerIP: maxConnectionsPerIP,
-		maxKeepAlive:        maxKeepAlive,
+		onlyProxy:           cfg.DisableEmulationAndCache,
+		rawPassthrough:      cfg.RawPassthrough,
+		maxConnectionsPerIP: int(cfg.MaxConnectionsPerIP),
+		maxKeepAlive:        time.Duration(cfg.MaxKeepAliveSeconds) * time.Second,
+		exposeRequestID:     cfg.ExposeRequestIDInErrors,
+		stickyConsistency:   cfg.StickyConsistency,
+		validator:           NewQueryValidator(cfg.MaxQuerySizeBytes),
+		maxResponseSize:     int(cfg.MaxResponseSizeBytes),
+		connWorkers:         int(cfg.ConnectionWorkers),
+		connQueueSize:       int(cfg.ConnectionQueueSize),
 		ips:                 map[string]*ClientIPInfo{},
+		conns:               map[string]int{},
+		ipv6Prefix:          int(cfg.IPv6LimitPrefix),
+		keysByName:          map[string]ed25519.PrivateKey{},
+		bans:                map[string]time.Time{},
+		closed:              make(chan struct{}),
+	}
+
+	s.SetMethodOverrides(cfg.MethodOverrides)
+
+	s.chaos = newChaosRules(cfg.Chaos)
+
+	if cfg.Canary.Percent > 0 {
+		s.canary = newCanaryChecker(backendBalancer, cfg.Canary)
+	}
+
+	if s.ipv6Prefix == 0 {
+		s.ipv6Prefix = 64
+	}
+	if s.connWorkers == 0 {
+		s.connWorkers = 64
+	}
+	if s.connQueueSize == 0 {
+		s.connQueueSize = 1024
+	}
+
+	if cfg.QoS.MaxConcurrentQueries > 0 {
+		maxQueued := int(cfg.QoS.MaxQueued)
+		if maxQueued == 0 {
+			maxQueued = 10000
+		}
+		s.qos = newQoSScheduler(int(cfg.QoS.MaxConcurrentQueries), maxQueued, time.Duration(cfg.QoS.MaxWaitMs)*time.Millisecond)
 	}
 
-	if gpCacheSize > 0 {
+	if cfg.ResponseGeneralCacheSize > 0 {
 		var err error
-		s.gpCache, err = lru.NewARC(gpCacheSize)
+		s.gpCache, err = lru.NewARC(int(cfg.ResponseGeneralCacheSize))
 		if err != nil {
 			panic("failed to init general purpose cache: " + err.Error())
 		}
 	}
 
+	if cfg.SystemGettersCacheSize > 0 {
+		var err error
+		s.systemGetters, err = newSystemGetters(int(cfg.SystemGettersCacheSize))
+		if err != nil {
+			panic("failed to init system getters cache: " + err.Error())
+		}
+	}
+
+	if cfg.HandshakeLimit.PerIPPerSec > 0 {
+		burst := cfg.HandshakeLimit.Burst
+		if burst <= 0 {
+			burst = 1
+		}
+		s.handshakeLimiter = leakybucket.NewCollector(cfg.HandshakeLimit.PerIPPerSec, burst, true)
+	}
+
+	if cfg.CacheConfig.NegativeTTLMs > 0 {
+		var err error
+		s.negCache, err = NewNegativeCache(cfg.CacheConfig.NegativeMaxEntries,
+			time.Duration(cfg.CacheConfig.NegativeTTLMs)*time.Millisecond, cfg.CacheConfig.NegativeErrorCodes)
+		if err != nil {
+			panic("failed to init negative cache: " + err.Error())
+		}
+	}
+
+	var err error
+	if s.exemptNets, err = parseNetworks(cfg.LimitExemptions.Networks); err != nil {
+		panic("failed to parse limit exemptions: " + err.Error())
+	}
+	exemptKeys := map[string]bool{}
+	for _, name := range cfg.LimitExemptions.Keys {
+		exemptKeys[name] = true
+	}
+
 	var keys []ed25519.PrivateKey
 
-	for _, cfg := range configs {
-		key := ed25519.NewKeyFromSeed(cfg.PrivateKey)
+	for _, clientCfg := range cfg.Clients {
+		key := ed25519.NewKeyFromSeed(clientCfg.PrivateKey)
 		keys = append(keys, key)
+		s.keysByName[clientCfg.Name] = key
 
 		var keyCfg KeyConfig
-		keyCfg.name = cfg.Name
-		if cfg.CapacityPerKey > 0 {
-			keyCfg.limiterPerKey = leakybucket.NewLeakyBucket(cfg.CoolingPerSec, cfg.CapacityPerKey)
+		keyCfg.name = clientCfg.Name
+		keyCfg.priority = ParsePriority(clientCfg.Priority)
+		keyCfg.maxInFlight = clientCfg.MaxInFlight
+		keyCfg.exempt = exemptKeys[clientCfg.Name]
+		if clientCfg.BandwidthQuotaMB > 0 {
+			period := time.Duration(clientCfg.BandwidthPeriodSeconds) * time.Second
+			if period == 0 {
+				period = 24 * time.Hour
+			}
+			keyCfg.bandwidth = newBandwidthQuota(clientCfg.BandwidthQuotaMB<<20, period)
 		}
-		if cfg.CapacityPerIP > 0 {
-			keyCfg.limiterPerIP = leakybucket.NewCollector(cfg.CoolingPerSec, cfg.CapacityPerIP, true)
+		if clientCfg.CapacityPerKey > 0 {
+			keyCfg.limiterPerKey = leakybucket.NewLeakyBucket(clientCfg.CoolingPerSec, clientCfg.CapacityPerKey)
+		}
+		if clientCfg.CapacityPerIP > 0 {
+			keyCfg.limiterPerIP = leakybucket.NewCollector(clientCfg.CoolingPerSec, clientCfg.CapacityPerIP, true)
 		}
 
 		s.configs[string(key.Public().(ed25519.PublicKey))] = &keyCfg
+		if clientCfg.Mode != "" {
+			s.keyModes[clientCfg.Name] = clientCfg.Mode
+		}
+		if clientCfg.GasCapacity > 0 {
+			s.gasBudgets[clientCfg.Name] = newGasBudget(clientCfg.GasPerSec, clientCfg.GasCapacity)
+		}
+		if clientCfg.MaxWaitMasterMs > 0 {
+			s.keyMaxWaitMaster[clientCfg.Name] = time.Duration(clientCfg.MaxWaitMasterMs) * time.Millisecond
+		}
+	}
+	s.srv = s.newServer(keys)
+
+	if s.maxKeepAlive > 0 {
+		go func() {
+			for {
+				start := time.Now()
+				last := start.Add(-s.maxKeepAlive).Unix()
+				s.mx.Lock()
+				for _, ip := range s.ips {
+					for _, client := range ip.ActiveConnections {
+						if client.LastRequest < last {
+							client.Client.Close()
+						}
+					}
+				}
+				s.mx.Unlock()
+				log.Debug().Str("took", time.Since(start).String()).Msg("connections cleanup completed")
+
+				select {
+				case <-s.closed:
+					return
+				case <-time.After(5 * time.Second):
+				}
+			}
+		}()
 	}
-	s.srv = liteclient.NewServer(keys)
 
-	s.srv.SetMessageHandler(s.handleRequest)
-	s.srv.SetConnectionHook(func(client *liteclient.ServerClient) error {
-		ip := client.IP()
+	go s.sampleSaturation()
+	return s
+}
+
+// newServer creates adnl server for keys, all servers share handler and connection accounting
+func (s *ProxyBalancer) newServer(keys []ed25519.PrivateKey) *adnlServer {
+	srv := newADNLServer(keys)
+
+	srv.messageHandler = s.handleRequest
+	srv.connectHook = func(client *ServerClient) error {
+		ip := s.clientIP(client)
+		if s.isBanned(BanKindIP, ip) {
+			log.Debug().Str("addr", ip).Msg("client connection refused, ip is banned")
+			metrics.Global.RejectedHandshakes.WithLabelValues("banned").Add(1)
+
+			return fmt.Errorf("ip is banned")
+		}
+
+		key := s.limitKey(ip)
+		exempt := s.isExemptIP(ip)
+
+		// hook is called before handshake, so rejected connection costs no crypto
+		if !exempt && s.handshakeLimiter != nil && s.handshakeLimiter.Add(key, 1) != 1 {
+			log.Debug().Str("addr", ip).Msg("client connection refused, too many handshakes")
+			metrics.Global.RejectedHandshakes.WithLabelValues("rate_limited").Add(1)
+
+			return fmt.Errorf("too many handshakes")
+		}
 
 		s.mx.Lock()
 		defer s.mx.Unlock()
@@ -136,23 +380,30 @@ func NewProxyBalancer(configs []config.ClientConfig, backendBalancer *BackendBal
 			s.ips[ip] = info
 		}
 
-		if s.maxConnectionsPerIP > 0 && len(info.ActiveConnections) >= s.maxConnectionsPerIP {
+		if !exempt && s.maxConnectionsPerIP > 0 && s.conns[key] >= s.maxConnectionsPerIP {
 			log.Debug().Str("addr", ip).Msg("client connection refused, too many connections")
+			metrics.Global.RejectedHandshakes.WithLabelValues("too_many_connections").Add(1)
 
 			return fmt.Errorf("too many connections")
 		}
+		now := time.Now().Unix()
 		info.ActiveConnections[client.Port()] = &ClientConnInfo{
 			Client:      client,
-			LastRequest: time.Now().Unix(),
+			ConnectedAt: now,
+			LastRequest: now,
+			workers:     newConnWorkers(s.connWorkers, s.connQueueSize),
+			exempt:      exempt,
 		}
+		s.conns[key]++
 
 		log.Debug().Str("addr", ip).Uint16("port", client.Port()).Int("connections", len(info.ActiveConnections)).Msg("new client connected")
 		metrics.Global.ActiveADNLConnections.Add(1)
 
 		return nil
-	})
-	s.srv.SetDisconnectHook(func(client *liteclient.ServerClient) {
-		ip := client.IP()
+	}
+	srv.disconnectHook = func(client *ServerClient) {
+		ip := s.clientIP(client)
+
 		s.mx.Lock()
 		if info := s.ips[ip]; info != nil {
 			delete(info.ActiveConnections, client.Port())
@@ -160,286 +411,766 @@ func NewProxyBalancer(configs []config.ClientConfig, backendBalancer *BackendBal
 				delete(s.ips, ip)
 			}
 		}
+		if key := s.limitKey(ip); s.conns[key] <= 1 {
+			delete(s.conns, key)
+		} else {
+			s.conns[key]--
+		}
 		s.mx.Unlock()
 
 		log.Debug().Str("addr", ip).Msg("client disconnected")
 		metrics.Global.ActiveADNLConnections.Sub(1)
-	})
+	}
+	return srv
+}
 
-	if s.maxKeepAlive > 0 {
-		go func() {
-			for {
-				start := time.Now()
-				last := start.Add(-s.maxKeepAlive).Unix()
-				s.mx.Lock()
-				for _, ip := range s.ips {
-					for _, client := range ip.ActiveConnections {
-						if client.LastRequest < last {
-							client.Client.Close()
-						}
-					}
-				}
-				s.mx.Unlock()
-				log.Debug().Str("took", time.Since(start).String()).Msg("connections cleanup completed")
+func (s *ProxyBalancer) Listen(addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(lis)
+}
 
-				time.Sleep(5 * time.Second)
-			}
-		}()
+// Serve accepts clients of all keys on listener owned by caller, like socket inherited from previous process,
+// it blocks like Listen and returns nil after Close
+func (s *ProxyBalancer) Serve(lis net.Listener) error {
+	return s.srv.Serve(lis)
+}
+
+// ListenClients listens additional addr which accepts only client keys with given names,
+// or all keys when names are empty, it blocks like Listen and returns nil after Close
+func (s *ProxyBalancer) ListenClients(addr string, names []string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
 	}
-	return s
+	return s.ServeClients(lis, names)
 }
 
-func (s *ProxyBalancer) Listen(addr string) error {
-	return s.srv.Listen(addr)
+// ServeClients is ListenClients on listener owned by caller
+func (s *ProxyBalancer) ServeClients(lis net.Listener, names []string) error {
+	var keys []ed25519.PrivateKey
+	if len(names) == 0 {
+		for _, key := range s.keysByName {
+			keys = append(keys, key)
+		}
+	}
+	for _, name := range names {
+		key, ok := s.keysByName[name]
+		if !ok {
+			_ = lis.Close()
+			return fmt.Errorf("unknown client %s", name)
+		}
+		keys = append(keys, key)
+	}
+
+	srv := s.newServer(keys)
+
+	s.mx.Lock()
+	select {
+	case <-s.closed:
+		s.mx.Unlock()
+		return lis.Close()
+	default:
+	}
+	s.extraServers = append(s.extraServers, srv)
+	s.mx.Unlock()
+
+	return srv.Serve(lis)
+}
+
+// Close stops listener and disconnects all clients, Listen returns nil after it
+func (s *ProxyBalancer) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.closed)
+		err = s.srv.Close()
+
+		s.mx.RLock()
+		defer s.mx.RUnlock()
+
+		for _, srv := range s.extraServers {
+			if e := srv.Close(); e != nil && err == nil {
+				err = e
+			}
+		}
+
+		for _, info := range s.ips {
+			for _, conn := range info.ActiveConnections {
+				conn.Client.Close()
+			}
+		}
+
+		if s.limiterStatePath != "" {
+			if e := s.saveLimiterState(s.limiterStatePath); e != nil {
+				log.Warn().Err(e).Msg("failed to save limiter state")
+			}
+		}
+
+		if s.sharedLimits != nil {
+			_ = s.sharedLimits.client.Close()
+		}
+	})
+	return err
 }
 
 var crcTable = crc64.MakeTable(crc64.ECMA)
 
-func (s *ProxyBalancer) handleRequest(ctx context.Context, sc *liteclient.ServerClient, msg tl.Serializable) error {
+func (s *ProxyBalancer) handleRequest(ctx context.Context, sc *ServerClient, msg tl.Serializable) error {
 	lim := s.configs[string(sc.ServerKey())]
 	if lim == nil {
 		return fmt.Errorf("unknown server key")
 	}
+	if s.isBanned(BanKindKey, lim.name) {
+		// error closes connection
+		return fmt.Errorf("key %s is banned", lim.name)
+	}
 
+	var workers *connWorkers
+	var client *ClientConnInfo
+	exempt := lim.exempt
 	s.mx.RLock()
-	if ip := s.ips[sc.IP()]; ip != nil {
+	if ip := s.ips[s.clientIP(sc)]; ip != nil {
 		if conn := ip.ActiveConnections[sc.Port()]; conn != nil {
 			atomic.StoreInt64(&conn.LastRequest, time.Now().Unix())
+			atomic.AddUint64(&conn.Requests, 1)
+			conn.key.Store(lim)
+			workers = conn.workers
+			exempt = exempt || conn.exempt
+			client = conn
 		}
 	}
 	s.mx.RUnlock()
 
-	limited := false
+	countRequest := func(limited bool) {
+		metrics.Global.Requests.WithLabelValues(lim.name, metrics.Global.TypeLabel(msg), fmt.Sprint(limited)).Add(1)
+		if exempt {
+			metrics.Global.ExemptRequests.WithLabelValues(lim.name, metrics.Global.TypeLabel(msg)).Add(1)
+		}
+	}
+
+	// query passed to worker is counted there, after rate limits
+	limited, queued := false, false
 	defer func() {
-		metrics.Global.Requests.WithLabelValues(lim.name, reflect.TypeOf(msg).String(), fmt.Sprint(limited)).Add(1)
+		if !queued {
+			countRequest(limited)
+		}
 	}()
 
 	switch m := msg.(type) {
 	case adnl.MessageQuery:
+		var rawData []byte
+		if raw, ok := m.Data.(rawLiteServerQuery); ok {
+			data, err := decodeLiteQuery(raw.Data)
+			if err != nil {
+				// error closes connection, the same as for queries tl cannot parse
+				return fmt.Errorf("failed to parse query: %w", err)
+			}
+			m.Data = liteclient.LiteServerQuery{Data: data}
+			rawData = raw.Data
+		}
+
 		switch q := m.Data.(type) {
 		case liteclient.LiteServerQuery:
-			cost := int64(1) // TODO: dynamic cost (depending on query)
+			reqID := newRequestID()
+			ctx := log.With().Str("request_id", reqID).Logger().WithContext(ctx)
+			if rawData != nil {
+				ctx = withClientQuery(ctx, rawData)
+			}
+			if exempt {
+				ctx = withExempt(ctx)
+			}
 
-			if (lim.limiterPerIP != nil && lim.limiterPerIP.Add(sc.IP(), cost) != cost) || (lim.limiterPerKey != nil && lim.limiterPerKey.Add(cost) != cost) {
+			cost := queryCost(q.Data)
+			ip := s.clientIP(sc)
+
+			// rate limits are taken in worker, shared ones wait for redis and would stall reading of connection
+			if ls := s.reserve(lim, exempt); ls != nil {
 				limited = true
-				return sc.Send(adnl.MessageAnswer{ID: m.ID, Data: ton.LSError{
-					Code: 429,
-					Text: "too many requests",
-				}})
+				return s.sendAnswer(sc, lim, m.ID, reqID, q.Data, *ls)
 			}
 
-			go func() {
-				var resp tl.Serializable
-
-				tm := time.Now()
-				hitType := HitTypeBackend
-				if !s.onlyProxy {
-					switch v := q.Data.(type) {
-					case []tl.Serializable: // wait master probably
-						if len(v) != 2 {
-							_ = sc.Send(adnl.MessageAnswer{ID: m.ID, Data: ton.LSError{
-								Code: 400,
-								Text: "unexpected len of queries",
-							}})
-							return
-						}
+			task := func() {
+				defer atomic.AddInt64(&lim.inFlight, -1)
 
-						wt, ok := v[0].(ton.WaitMasterchainSeqno)
-						if !ok {
-							_ = sc.Send(adnl.MessageAnswer{ID: m.ID, Data: ton.LSError{
-								Code: 400,
-								Text: "unexpected first query type",
-							}})
-							return
-						}
+				if ls := s.limitRate(lim, ip, cost, exempt); ls != nil {
+					countRequest(true)
+					_ = s.sendAnswer(sc, lim, m.ID, reqID, q.Data, *ls)
+					return
+				}
+				countRequest(false)
 
-						tmWait := time.Now()
-						if err := s.cache.WaitMasterBlock(ctx, uint32(wt.Seqno), time.Duration(wt.Timeout)*time.Second); err != nil {
-							if ls, ok := err.(ton.LSError); ok {
-								_ = sc.Send(adnl.MessageAnswer{ID: m.ID, Data: ls})
-								return
-							}
-							return
-						}
-						log.Debug().Dur("took", time.Since(tmWait)).Msg("master block wait finished")
-						q.Data = v[1]
+				defer func() {
+					// malformed data can panic deep in parsing, it should fail only this query
+					if r := recover(); r != nil {
+						metrics.Global.Panics.WithLabelValues(metrics.Global.TypeLabel(q.Data)).Add(1)
+						stack := string(debug.Stack())
+						log.Ctx(ctx).Error().Interface("panic", r).Str("stack", stack).Type("request", q.Data).Msg("query processing panicked")
+						reportError(ReportKindPanic, fmt.Sprint(r), map[string]string{
+							"request_id":   reqID,
+							"key_name":     lim.name,
+							"request_type": reflect.TypeOf(q.Data).String(),
+						}, stack)
+
+						_ = s.sendAnswer(sc, lim, m.ID, reqID, q.Data, ton.LSError{
+							Code: 500,
+							Text: "internal error",
+						})
+					}
+				}()
 
-						// reset time to not track waiting time
-						tm = time.Now()
+				if s.qos != nil && !exempt {
+					if !s.qos.acquire(ctx, lim.priority) {
+						metrics.Global.ShedQueries.WithLabelValues(lim.name, lim.priority.String()).Add(1)
+						_ = s.sendAnswer(sc, lim, m.ID, reqID, q.Data, ton.LSError{
+							Code: 429,
+							Text: "server is overloaded",
+						})
+						return
 					}
+					defer s.qos.release()
+				}
 
-					switch v := q.Data.(type) {
-					case ton.GetVersion:
-						hitType = HitTypeEmulated
-						resp = ton.Version{
-							Mode:         0,
-							Version:      0x101,
-							Capabilities: 7,
-							Now:          uint32(time.Now().Unix()),
+				var chaos *chaosRule
+				if len(s.chaos) > 0 {
+					if chaos = s.chaosFor(lim.name, q.Data); chaos != nil {
+						if !chaos.delay(ctx) {
+							return
 						}
-					case ton.GetTime:
-						hitType = HitTypeEmulated
-						resp = ton.CurrentTime{
-							Now: uint32(time.Now().Unix()),
+						if resp := chaos.failure(); resp != nil {
+							_ = s.sendAnswer(sc, lim, m.ID, reqID, q.Data, resp)
+							return
 						}
-					case ton.GetMasterchainInfoExt:
-						resp, hitType = s.handleGetMasterchainInfoExt(ctx, &v)
-					case ton.GetMasterchainInf:
-						resp, hitType = s.handleGetMasterchainInfo(ctx)
-					case ton.GetLibraries:
-						resp, hitType = s.handleGetLibraries(ctx, &v)
-					case ton.GetOneTransaction:
-						resp, hitType = s.handleGetTransaction(ctx, &v)
-					case ton.GetBlockData:
-						resp, hitType = s.handleGetBlock(ctx, &v)
-					case ton.GetAccountState:
-						resp, hitType = s.handleGetAccount(ctx, &v)
-					case ton.RunSmcMethod:
-						resp, hitType = s.handleRunSmcMethod(ctx, &v)
-					case ton.LookupBlock:
-						resp, hitType = s.handleLookupBlock(ctx, &v)
-					case ton.GetBlockHeader:
-					case ton.GetConfigAll:
-					case ton.GetBlockProof:
-					case ton.GetConfigParams:
-					case ton.GetAllShardsInfo:
-					case ton.ListBlockTransactions:
-					case ton.ListBlockTransactionsExt:
-						// TODO: cache all of this
 					}
 				}
 
-				defer func() {
-					if ls, ok := resp.(ton.LSError); ok {
-						metrics.Global.LSErrors.WithLabelValues(lim.name, reflect.TypeOf(q.Data).String(), fmt.Sprint(ls.Code)).Add(1)
-					}
+				resp := s.processQuery(ctx, lim.name, q.Data)
+				if s.stickyConsistency && client != nil {
+					resp = s.keepMonotonic(ctx, client, q.Data, resp)
+				}
+				if s.capture != nil {
+					s.capture.record(lim.name, q.Data, resp)
+				}
+				if chaos != nil {
+					resp = chaos.truncate(resp)
+				}
+				if resp != nil {
+					_ = s.sendAnswer(sc, lim, m.ID, reqID, q.Data, resp)
+				}
+			}
 
-					snc := time.Since(tm)
-					metrics.Global.Queries.WithLabelValues(lim.name, reflect.TypeOf(q.Data).String(), hitType).Observe(snc.Seconds())
-					log.Debug().Type("request", q.Data).Dur("took", snc).Msg("query finished")
-				}()
+			queued = true
+			if workers == nil {
+				go task()
+			} else if !workers.submit(task) {
+				if exempt {
+					// queue of connection is a limit too, so exempt client is not rejected
+					go task()
+					return nil
+				}
 
-				var gpKey uint64
-				if resp == nil && s.gpCache != nil {
-					rqData, err := tl.Serialize(q.Data, true)
-					if err != nil {
-						log.Warn().Type("request", q.Data).Msg("serialization for hash failed")
+				// client pipelines more queries than it is allowed to have in flight
+				atomic.AddInt64(&lim.inFlight, -1)
+				queued, limited = false, true
+				log.Ctx(ctx).Debug().Str("addr", ip).Msg("query rejected, queue of connection is full")
+				return s.sendAnswer(sc, lim, m.ID, reqID, q.Data, ton.LSError{
+					Code: 429,
+					Text: "too many queries in flight",
+				})
+			}
+			return nil
+		}
+	case liteclient.TCPPing:
+		return sc.Send(liteclient.TCPPong{RandomID: m.RandomID})
+	}
 
-						resp = ton.LSError{
-							Code: 400,
-							Text: "request serialization failed",
-						}
-					}
-					gpKey = crc64.Checksum(rqData, crcTable)
+	return fmt.Errorf("something unknown: %s", reflect.TypeOf(msg).String())
+}
 
-					resp, _ = s.gpCache.Get(gpKey)
-					if resp != nil {
-						log.Debug().Type("request", q.Data).Type("response", resp).Msg("fetched from gp cache")
-						hitType = HitTypeGPCache
-					}
+// processQuery serves client query from emulation, caches or backends, nil is returned when there is nothing to answer
+func (s *ProxyBalancer) processQuery(ctx context.Context, keyName string, query any) tl.Serializable {
+	ctx = withKeyName(ctx, keyName)
+
+	if q, ok := query.(PeerCacheGet); ok {
+		// internal query of sibling proxy, it is never passed to backends
+		return s.handlePeerCacheGet(ctx, &q)
+	}
+
+	// key can be configured to never get emulated or cached answers, regardless of global mode
+	mode := s.keyModes[keyName]
+	onlyProxy := s.onlyProxy || mode == KeyModeProxy
+	if mode == KeyModeRaw || s.isRawProxied(query, onlyProxy) {
+		return s.passthrough(ctx, keyName, query)
+	}
+
+	var resp tl.Serializable
+
+	tm := time.Now()
+	hitType := HitTypeBackend
+	if err := s.validator.Validate(query, clientQuerySize(ctx)); err != nil {
+		log.Ctx(ctx).Debug().Err(err).Type("request", query).Msg("invalid query")
+		resp = ton.LSError{
+			Code: 400,
+			Text: "invalid query: " + err.Error(),
+		}
+		hitType = HitTypeFailedValidate
+	} else if !onlyProxy {
+		switch v := query.(type) {
+		case []tl.Serializable: // wait master probably
+			if len(v) < 2 {
+				return ton.LSError{
+					Code: 400,
+					Text: "unexpected len of queries",
 				}
+			}
 
-				if resp == nil {
-					log.Debug().Type("request", q.Data).Msg("direct proxy")
-					// we expect to have only fast nodes, so timeout is short
-					ctx, cancel := context.WithTimeout(ctx, 7*time.Second)
-
-					lsTm := time.Now()
-					err := s.backendBalancer.GetClient().QueryLiteserver(ctx, q.Data, &resp)
-					cancel()
-					if err != nil {
-						if ls, ok := err.(ton.LSError); ok {
-							resp = ls
-						} else if strings.HasSuffix(err.Error(), "context canceled") {
-							resp = ton.LSError{
-								Code: 400,
-								Text: "canceled",
-							}
-						} else {
-							log.Warn().Err(err).Type("request", q.Data).Dur("took", time.Since(lsTm)).Msg("query failed")
-
-							resp = ton.LSError{
-								Code: 502,
-								Text: "backend node timeout",
-							}
-						}
-					} else if s.gpCache != nil {
-						s.gpCache.Add(gpKey, resp)
-					}
+			wt, ok := v[0].(ton.WaitMasterchainSeqno)
+			if !ok {
+				return ton.LSError{
+					Code: 400,
+					Text: "unexpected first query type",
 				}
+			}
 
-				_ = sc.Send(adnl.MessageAnswer{ID: m.ID, Data: resp})
-			}()
+			// timeout is in milliseconds, the same as timeout_ms of liteserver, so proxies can be chained,
+			// it was read as seconds before, see MaxWaitMasterMs in config
+			wt = s.capMasterWait(keyName, wt)
+			tmWait := time.Now()
+			if err := s.cache.WaitMasterBlock(ctx, uint32(wt.Seqno), time.Duration(wt.Timeout)*time.Millisecond); err != nil {
+				if ls, ok := err.(ton.LSError); ok {
+					return ls
+				}
+				return nil
+			}
+			log.Ctx(ctx).Debug().Dur("took", time.Since(tmWait)).Msg("master block wait finished")
+			ctx = withMasterWait(ctx, wt)
+			// data of client is the whole list, wrapped queries are sent to backends one by one
+			ctx = withClientQuery(ctx, nil)
+			if len(v) > 2 {
+				return s.processWrapped(ctx, keyName, v[1:])
+			}
+			query = v[1]
+
+			// reset time to not track waiting time
+			tm = time.Now()
+		}
+
+		switch v := query.(type) {
+		case ton.GetVersion:
+			hitType = HitTypeEmulated
+			resp = ton.Version{
+				Mode:         0,
+				Version:      0x101,
+				Capabilities: 7,
+				Now:          uint32(time.Now().Unix()),
+			}
+		case ton.GetTime:
+			hitType = HitTypeEmulated
+			resp = ton.CurrentTime{
+				Now: uint32(time.Now().Unix()),
+			}
+		case ton.GetMasterchainInfoExt:
+			resp, hitType = s.handleGetMasterchainInfoExt(ctx, &v)
+		case ton.GetMasterchainInf:
+			resp, hitType = s.handleGetMasterchainInfo(ctx)
+		case ton.GetLibraries:
+			resp, hitType = s.handleGetLibraries(ctx, &v)
+		case ton.GetOneTransaction:
+			resp, hitType = s.handleGetTransaction(ctx, &v)
+		case ton.GetTransactions:
+			resp, hitType = s.handleGetTransactions(ctx, &v)
+		case ton.GetBlockData:
+			resp, hitType = s.handleGetBlock(ctx, &v)
+		case ton.GetAccountState:
+			resp, hitType = s.handleGetAccount(ctx, &v)
+		case ton.RunSmcMethod:
+			resp, hitType = s.handleRunSmcMethod(ctx, &v)
+		case ton.LookupBlock:
+			resp, hitType = s.handleLookupBlock(ctx, &v)
+		case ton.GetBlockHeader:
+		case ton.GetConfigAll:
+		case ton.GetBlockProof:
+		case ton.GetConfigParams:
+		case ton.GetAllShardsInfo:
+		case ton.ListBlockTransactions:
+		case ton.ListBlockTransactionsExt:
+			// TODO: cache all of this
+		}
+
+		if s.canary != nil && (hitType == HitTypeEmulated || hitType == HitTypeCache || hitType == HitTypePrecomputed) {
+			s.canary.check(query, resp)
+		}
+	}
+
+	defer func() {
+		if ls, ok := resp.(ton.LSError); ok {
+			metrics.Global.LSErrors.WithLabelValues(keyName, metrics.Global.TypeLabel(query), fmt.Sprint(ls.Code)).Add(1)
+		}
+
+		snc := time.Since(tm)
+		metrics.Global.Queries.WithLabelValues(keyName, metrics.Global.TypeLabel(query), hitType).Observe(snc.Seconds())
+		if s.analytics != nil {
+			s.analytics.record(keyName, query, hitType, resp)
+		}
+		log.Ctx(ctx).Debug().Type("request", query).Dur("took", snc).Msg("query finished")
+	}()
 
+	var gpKey uint64
+	if resp == nil && (s.gpCache != nil || s.negCache != nil) {
+		rqData, err := tl.Serialize(query, true)
+		if err != nil {
+			log.Ctx(ctx).Warn().Type("request", query).Msg("serialization for hash failed")
+
+			resp = ton.LSError{
+				Code: 400,
+				Text: "request serialization failed",
+			}
+		}
+		gpKey = crc64.Checksum(rqData, crcTable)
+
+		if s.negCache != nil {
+			if ls, ok := s.negCache.Get(strconv.FormatUint(gpKey, 16)); ok {
+				log.Ctx(ctx).Debug().Type("request", query).Int32("code", ls.Code).Msg("fetched from negative cache")
+				resp = ls
+				hitType = HitTypeNegativeCache
+			}
+		}
+
+		if hitType != HitTypeNegativeCache && s.gpCache != nil {
+			resp, _ = s.gpCache.Get(gpKey)
+			if resp != nil {
+				log.Ctx(ctx).Debug().Type("request", query).Type("response", resp).Msg("fetched from gp cache")
+				hitType = HitTypeGPCache
+			}
+		}
+	}
+
+	if resp == nil {
+		log.Ctx(ctx).Debug().Type("request", query).Msg("direct proxy")
+
+		var ok bool
+		resp, ok = s.queryBackend(ctx, query)
+		if ok && s.gpCache != nil {
+			s.gpCache.Add(gpKey, resp)
+		}
+
+		if ls, ok := resp.(ton.LSError); ok && s.negCache != nil {
+			s.negCache.Observe(strconv.FormatUint(gpKey, 16), ls)
+		}
+	}
+
+	return resp
+}
+
+// capMasterWait lowers wait timeout of client to the maximum allowed for key
+func (s *ProxyBalancer) capMasterWait(keyName string, wt ton.WaitMasterchainSeqno) ton.WaitMasterchainSeqno {
+	max, ok := s.keyMaxWaitMaster[keyName]
+	if !ok {
+		max = s.maxWaitMaster
+	}
+
+	if max > 0 && time.Duration(wt.Timeout)*time.Millisecond > max {
+		metrics.Global.CappedMasterWaits.WithLabelValues(keyName).Add(1)
+		wt.Timeout = int32(max / time.Millisecond)
+	}
+	return wt
+}
+
+// processWrapped serves queries wrapped by one wait master in order, answers are concatenated the same way
+// as queries, failed query gets its error in place of answer, so positions of others are kept
+func (s *ProxyBalancer) processWrapped(ctx context.Context, keyName string, queries []tl.Serializable) tl.Serializable {
+	var data []byte
+	for _, q := range queries {
+		resp := s.processQuery(ctx, keyName, q)
+		if resp == nil {
 			return nil
 		}
-	case liteclient.TCPPing:
-		return sc.Send(liteclient.TCPPong{RandomID: m.RandomID})
+
+		answer, err := tl.Serialize(resp, true)
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Type("request", q).Msg("failed to serialize wrapped answer")
+			return ton.LSError{
+				Code: 500,
+				Text: "failed to serialize answer",
+			}
+		}
+		data = append(data, answer...)
 	}
+	return tl.Raw(data)
+}
 
-	return fmt.Errorf("something unknown: %s", reflect.TypeOf(msg).String())
+// isRawProxied reports if query goes to backend without local processing, when it is never answered locally,
+// general and negative caches are skipped, which saves serialization and hashing of query for their keys
+func (s *ProxyBalancer) isRawProxied(query any, onlyProxy bool) bool {
+	if overrides := s.methodOverrides.Load(); overrides != nil {
+		switch (*overrides)[reflect.TypeOf(query).String()] {
+		case "raw":
+			return true
+		case "local":
+			return false
+		}
+	}
+	return s.rawPassthrough && (onlyProxy || !servedLocally(query))
 }
 
-func (s *ProxyBalancer) handleRunSmcMethod(ctx context.Context, v *ton.RunSmcMethod) (tl.Serializable, string) {
-	if v.ID.Workchain != -1 {
-		// TODO: account state on shard block level
-		return nil, HitTypeBackend
+// SetCapture enables recording of sampled queries with responses, it should be set before start
+func (s *ProxyBalancer) SetCapture(capture *QueryCapture) {
+	s.capture = capture
+}
+
+// SetAnalytics enables aggregation of query statistics for export, it should be set before start
+func (s *ProxyBalancer) SetAnalytics(analytics *QueryAnalytics) {
+	s.analytics = analytics
+}
+
+// SetBilling enables export of usage of keys, it should be set before start
+func (s *ProxyBalancer) SetBilling(billing *BillingExporter) {
+	s.billing = billing
+}
+
+// SetMethodOverrides replaces handling of query types by their names, "raw" sends query to backend as is,
+// to work around broken local handler, "local" processes it as usual even in raw passthrough mode
+func (s *ProxyBalancer) SetMethodOverrides(overrides map[string]string) {
+	known := map[string]bool{}
+	for _, name := range DefaultMetricsTypeLabels {
+		known[name] = true
 	}
 
-	block, cachedBlock, err := s.cache.CacheBlockIfNeeded(ctx, v.ID)
-	if err != nil {
-		if ls, ok := err.(ton.LSError); ok {
-			return ls, HitTypeFailedValidate
+	list := map[string]string{}
+	for method, mode := range overrides {
+		if !known[method] {
+			log.Warn().Str("method", method).Msg("override is set for unknown query type")
 		}
-		if ctx.Err() != nil {
-			return ErrTimeout, HitTypeFailedValidate
+		list[method] = mode
+	}
+	s.methodOverrides.Store(&list)
+}
+
+// servedLocally reports if query can be answered by emulation or caches, others always go to backends
+func servedLocally(query any) bool {
+	switch query.(type) {
+	case []tl.Serializable, ton.GetVersion, ton.GetTime, ton.GetMasterchainInfoExt, ton.GetMasterchainInf,
+		ton.GetLibraries, ton.GetOneTransaction, ton.GetTransactions, ton.GetBlockData, ton.GetAccountState,
+		ton.RunSmcMethod, ton.LookupBlock:
+		return true
+	}
+	return false
+}
+
+// passthrough sends query to backend and returns its answer without caches and emulation,
+// query is still validated, so size limit and checks of malformed queries apply the same way.
+// Bytes of adnl client query are sent to backend as is, without serializing decoded query again.
+func (s *ProxyBalancer) passthrough(ctx context.Context, keyName string, query any) tl.Serializable {
+	tm := time.Now()
+
+	var resp tl.Serializable
+	hitType := HitTypeRawProxy
+	if err := s.validator.Validate(query, clientQuerySize(ctx)); err != nil {
+		log.Ctx(ctx).Debug().Err(err).Type("request", query).Msg("invalid query")
+		resp = ton.LSError{
+			Code: 400,
+			Text: "invalid query: " + err.Error(),
+		}
+		hitType = HitTypeFailedValidate
+	} else {
+		if data := clientQueryFrom(ctx); data != nil {
+			ctx = withRawPayload(ctx, data)
 		}
+		resp, _ = s.queryBackend(ctx, query)
+	}
 
-		log.Warn().Err(err).Type("request", v).Msg("failed to get block")
-		return ton.LSError{
-			Code: 500,
-			Text: "failed to resolve block",
-		}, HitTypeFailedInternal
+	if ls, ok := resp.(ton.LSError); ok {
+		metrics.Global.LSErrors.WithLabelValues(keyName, metrics.Global.TypeLabel(query), fmt.Sprint(ls.Code)).Add(1)
+	}
+	snc := time.Since(tm)
+	metrics.Global.Queries.WithLabelValues(keyName, metrics.Global.TypeLabel(query), hitType).Observe(snc.Seconds())
+	if s.analytics != nil {
+		s.analytics.record(keyName, query, hitType, resp)
 	}
+	log.Ctx(ctx).Debug().Type("request", query).Dur("took", snc).Msg("query passed through")
 
-	masterBlock, cachedMasterBlock, err := s.cache.GetMasterBlock(ctx, block.MasterID)
-	if err != nil {
+	return resp
+}
+
+// queryBackend sends query to backends, failures are converted to liteserver errors for client,
+// false is returned when answer is not received from backend
+func (s *ProxyBalancer) queryBackend(ctx context.Context, query any) (tl.Serializable, bool) {
+	// we expect to have only fast nodes, so timeout is short
+	qctx, cancel := context.WithTimeout(ctx, 7*time.Second)
+	defer cancel()
+
+	var resp tl.Serializable
+	lsTm := time.Now()
+	if err := s.backendBalancer.Query(qctx, query, &resp); err != nil {
 		if ls, ok := err.(ton.LSError); ok {
-			return ls, HitTypeFailedValidate
+			return ls, false
 		}
-		if ctx.Err() != nil {
-			return ErrTimeout, HitTypeFailedValidate
+		if strings.HasSuffix(err.Error(), "context canceled") {
+			return ton.LSError{
+				Code: 400,
+				Text: "canceled",
+			}, false
 		}
 
-		log.Warn().Err(err).Type("request", v).Msg("failed to get master block")
+		log.Ctx(ctx).Warn().Err(err).Type("request", query).Dur("took", time.Since(lsTm)).Msg("query failed")
 		return ton.LSError{
-			Code: 500,
-			Text: "failed to resolve master block",
-		}, HitTypeFailedInternal
+			Code: 502,
+			Text: "backend node timeout",
+		}, false
 	}
+	return resp, true
+}
 
-	if block == nil {
-		// block is too old for cache, for now we proxy it to backend,
-		// but maybe it is reasonable to throw an error
-		return nil, HitTypeBackend
+// admit applies rate limits, bandwidth quota and in-flight limit of key to query, error for client is returned
+// when it is rejected, otherwise query is counted in flight until caller decrements inFlight of key
+func (s *ProxyBalancer) admit(lim *KeyConfig, ip string, cost int64, exempt bool) *ton.LSError {
+	if ls := s.limitRate(lim, ip, cost, exempt); ls != nil {
+		return ls
 	}
+	return s.reserve(lim, exempt)
+}
 
-	addr := address.NewAddress(0, byte(v.Account.Workchain), v.Account.ID)
-	state, cachedState, err := s.cache.GetAccountStateInBlock(ctx, block, addr)
+// limitRate charges cost to rate limits of key, shared limits wait for redis,
+// so it should not be called on read path of connection
+func (s *ProxyBalancer) limitRate(lim *KeyConfig, ip string, cost int64, exempt bool) *ton.LSError {
+	if exempt {
+		return nil
+	}
+	if wait, ok := s.takeLimits(lim, ip, cost); !ok {
+		metrics.Global.RetryAfter.WithLabelValues(lim.name).Observe(wait.Seconds())
+		return &ton.LSError{
+			Code: 429,
+			Text: fmt.Sprintf("too many requests, retry after %dms", wait.Milliseconds()),
+		}
+	}
+	return nil
+}
+
+// reserve checks bandwidth quota and counts query in flight of key
+func (s *ProxyBalancer) reserve(lim *KeyConfig, exempt bool) *ton.LSError {
+	if lim.bandwidth != nil && !exempt {
+		if wait, ok := lim.bandwidth.available(); !ok {
+			metrics.Global.RetryAfter.WithLabelValues(lim.name).Observe(wait.Seconds())
+			return &ton.LSError{
+				Code: 429,
+				Text: fmt.Sprintf("bandwidth quota is exceeded, retry after %dms", wait.Milliseconds()),
+			}
+		}
+	}
+
+	if n := atomic.AddInt64(&lim.inFlight, 1); !exempt && lim.maxInFlight > 0 && n > lim.maxInFlight {
+		atomic.AddInt64(&lim.inFlight, -1)
+		return &ton.LSError{
+			Code: 429,
+			Text: "too many queries in flight for key",
+		}
+	}
+	return nil
+}
+
+func (s *ProxyBalancer) sendAnswer(sc *ServerClient, lim *KeyConfig, queryID []byte, reqID string, req any, resp tl.Serializable) error {
+	data, _, err := s.encodeAnswer(lim, reqID, req, resp)
 	if err != nil {
-		if ls, ok := err.(ton.LSError); ok {
-			return ls, HitTypeFailedValidate
+		return err
+	}
+
+	if len(queryID) != 32 {
+		return sc.Send(adnl.MessageAnswer{ID: queryID, Data: tl.Raw(data)})
+	}
+
+	buf := serializeAnswer(queryID, data)
+	defer releaseBuffer(buf)
+
+	return sc.Send(tl.Raw(*buf))
+}
+
+// encodeAnswer serializes answer to key, too big one is replaced with error, size of the result is accounted
+// in metrics, billing and bandwidth quota of key, answer which is actually sent is returned with its data
+func (s *ProxyBalancer) encodeAnswer(lim *KeyConfig, reqID string, req any, resp tl.Serializable) ([]byte, tl.Serializable, error) {
+	if ls, ok := resp.(ton.LSError); ok && s.exposeRequestID {
+		ls.Text += ", request id: " + reqID
+		resp = ls
+	}
+
+	// serialized once here to know the size, then passed as is
+	data, err := tl.Serialize(resp, true)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to serialize answer: %w", err)
+	}
+	sent := resp
+
+	if s.maxResponseSize > 0 && len(data) > s.maxResponseSize {
+		log.Warn().Str("request_id", reqID).Type("request", req).Type("response", resp).Int("size", len(data)).Msg("response is too big, not sent")
+		metrics.Global.OversizedResponses.WithLabelValues(metrics.Global.TypeLabel(req)).Add(1)
+
+		ls := ErrResponseTooBig
+		if s.exposeRequestID {
+			ls.Text += ", request id: " + reqID
 		}
-		if ctx.Err() != nil {
-			return ErrTimeout, HitTypeFailedValidate
+		if data, err = tl.Serialize(ls, true); err != nil {
+			return nil, nil, fmt.Errorf("failed to serialize answer: %w", err)
 		}
+		sent = ls
+	}
+	metrics.Global.ResponseBytes.WithLabelValues(metrics.Global.TypeLabel(req)).Observe(float64(len(data)))
+	metrics.Global.KeyResponseBytes.WithLabelValues(lim.name).Add(float64(len(data)))
+	if s.billing != nil {
+		s.billing.record(lim.name, req, resp, len(data))
+	}
+	if lim.bandwidth != nil {
+		lim.bandwidth.add(len(data))
+	}
+	return data, sent, nil
+}
 
-		log.Warn().Err(err).Type("request", v).Msg("failed to get account")
+// queryCost returns units taken from rate limits of key for query, each query wrapped after wait master prefix
+// is a unit, so client cannot get several answers for the price of one
+func queryCost(query any) int64 {
+	if list, ok := query.([]tl.Serializable); ok && len(list) > 1 {
+		return int64(len(list) - 1)
+	}
+	return 1
+}
 
-		return ton.LSError{
-			Code: 500,
-			Text: "failed to get account state",
-		}, HitTypeFailedInternal
+func typeNames(values ...any) []string {
+	names := make([]string, 0, len(values))
+	for _, v := range values {
+		names = append(names, reflect.TypeOf(v).String())
+	}
+	return names
+}
+
+func newRequestID() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+func (s *ProxyBalancer) handleRunSmcMethod(ctx context.Context, v *ton.RunSmcMethod) (tl.Serializable, string) {
+	if v.ID.Workchain != -1 {
+		// TODO: account state on shard block level
+		return nil, HitTypeBackend
+	}
+
+	keyName := keyNameFrom(ctx)
+	gas, ls := s.takeGasBudget(ctx)
+	if ls != nil {
+		return *ls, HitTypeGasLimited
+	}
+
+	addr := address.NewAddress(0, byte(v.Account.Workchain), v.Account.ID)
+	block, masterBlock, state, hit, errResp := s.runSmcState(ctx, v, addr)
+	if errResp != nil && s.cache.IsStale() {
+		// backends are down, method is emulated only if state of the requested block is still in memory
+		if staleState, ok := s.cache.GetStaleAccountState(v.ID, addr); ok {
+			if mb, _, err := s.cache.GetMasterBlock(ctx, v.ID); err == nil {
+				block, masterBlock, state, hit, errResp = &mb.Block, mb, staleState, HitTypeStale, nil
+			}
+		}
+	}
+	if errResp != nil || block == nil {
+		return errResp, hit
 	}
 
 	if state.State == nil {
@@ -449,17 +1180,112 @@ func (s *ProxyBalancer) handleRunSmcMethod(ctx context.Context, v *ton.RunSmcMet
 		}, HitTypeFailedValidate
 	}
 
+	var sysKey string
+	if s.systemGetters != nil {
+		sysKey = s.systemGetters.key(masterBlock, block, addr, v)
+	}
+
 	var st tlb.AccountState
-	if err = st.LoadFromCell(state.State.BeginParse()); err != nil {
-		log.Warn().Err(err).Type("request", v).Msg("failed to parse account")
-		return ton.LSError{
-			Code: 500,
-			Text: "failed to parse account state: " + err.Error(),
-		}, HitTypeFailedInternal
+	var res *emulate.RunResult
+	var seed []byte
+	cachedLibs := true
+	if pre := s.precomputedResult(addr, state.State, v); pre != nil {
+		res = pre
+		if hit != HitTypeStale {
+			hit = HitTypePrecomputed
+		}
+	} else if sys := s.systemGetters.get(sysKey); sys != nil {
+		res = sys
+		metrics.Global.SystemGetters.WithLabelValues("hit").Add(1)
+	} else {
+		if err := st.LoadFromCell(state.State.BeginParse()); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Type("request", v).Msg("failed to parse account")
+			return ton.LSError{
+				Code: 500,
+				Text: "failed to parse account state: " + err.Error(),
+			}, HitTypeFailedInternal
+		}
+
+		var errResp tl.Serializable
+		var errHit string
+		if res = walletSeqno(&st, v); res != nil {
+			metrics.Global.WalletSeqnoFastPath.Add(1)
+		} else if res, seed, cachedLibs, errResp, errHit = s.runGetMethod(ctx, block, masterBlock, addr, &st, v.Params, v.MethodID); errResp != nil {
+			return errResp, errHit
+		}
+
+		if sysKey != "" {
+			s.systemGetters.add(sysKey, res)
+			metrics.Global.SystemGetters.WithLabelValues("miss").Add(1)
+		}
+	}
+
+	metrics.Global.EmulatedGas.WithLabelValues(keyName).Add(float64(res.GasUsed))
+	if s.billing != nil {
+		s.billing.addGas(keyName, res.GasUsed)
+	}
+	if gas != nil {
+		gas.charge(res.GasUsed)
+	}
+
+	var stateProof, c7 *cell.Cell
+
+	if v.Mode&2 != 0 {
+		var err error
+		stateProof, err = state.State.CreateProof(cell.CreateProofSkeleton())
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Type("request", v).Msg("failed to prepare state proof args")
+
+			return ton.LSError{
+				Code: 500,
+				Text: "failed to prepare state proof args: " + err.Error(),
+			}, HitTypeFailedInternal
+		}
+	}
+
+	if v.Mode&8 != 0 {
+		// short c7 for response
+		c7t, err := emulate.PrepareC7(addr, time.Now(), seed, st.Balance.Nano(), nil, nil)
+		if err != nil {
+			return ton.LSError{
+				Code: 500,
+				Text: "failed to prepare c7: " + err.Error(),
+			}, HitTypeFailedInternal
+		}
+
+		b := cell.BeginCell()
+		if err = tlb.SerializeStackValue(b, c7t); err != nil {
+			return ton.LSError{
+				Code: 500,
+				Text: "failed to build c7 tuple: " + err.Error(),
+			}, HitTypeFailedInternal
+		}
+		c7 = b.EndCell()
+	}
+
+	if !cachedLibs && hit != HitTypeStale {
+		hit = HitTypeBackend
 	}
 
+	return ton.RunMethodResult{
+		Mode:       v.Mode,
+		ID:         block.ID,
+		ShardBlock: state.Shard,
+		ShardProof: state.ShardProof,
+		Proof:      state.Proof,
+		StateProof: stateProof,
+		InitC7:     c7,
+		LibExtras:  nil,
+		ExitCode:   res.ExitCode,
+		Result:     res.Stack,
+	}, hit
+}
+
+// runGetMethod emulates method on parsed account state with config of master block,
+// liteserver error with hit type is returned when it cannot be run
+func (s *ProxyBalancer) runGetMethod(ctx context.Context, block *Block, masterBlock *MasterBlock, addr *address.Address, st *tlb.AccountState, params *cell.Cell, methodID uint64) (*emulate.RunResult, []byte, bool, tl.Serializable, string) {
 	if st.StateInit == nil || st.StateInit.Code == nil {
-		return ton.LSError{
+		return nil, nil, false, ton.LSError{
 			Code: ton.ErrCodeContractNotInitialized,
 			Text: "contract is not initialized",
 		}, HitTypeFailedValidate
@@ -468,13 +1294,13 @@ func (s *ProxyBalancer) handleRunSmcMethod(ctx context.Context, v *ton.RunSmcMet
 	libsCodes, cachedLibs, err := s.cache.GetLibraries(ctx, findLibs(st.StateInit.Code))
 	if err != nil {
 		if ls, ok := err.(ton.LSError); ok {
-			return ls, HitTypeFailedValidate
+			return nil, nil, false, ls, HitTypeFailedValidate
 		}
 		if ctx.Err() != nil {
-			return ErrTimeout, HitTypeFailedValidate
+			return nil, nil, false, ErrTimeout, HitTypeFailedValidate
 		}
 
-		return ton.LSError{
+		return nil, nil, false, ton.LSError{
 			Code: 500,
 			Text: "failed resolve libraries: " + err.Error(),
 		}, HitTypeFailedInternal
@@ -492,7 +1318,7 @@ func (s *ProxyBalancer) handleRunSmcMethod(ctx context.Context, v *ton.RunSmcMet
 
 	c7tuple, err := emulate.PrepareC7(addr, time.Now(), seed, st.Balance.Nano(), masterBlock.Config, st.StateInit.Code)
 	if err != nil {
-		return ton.LSError{
+		return nil, nil, false, ton.LSError{
 			Code: 500,
 			Text: "failed to prepare c7: " + err.Error(),
 		}, HitTypeFailedInternal
@@ -502,87 +1328,110 @@ func (s *ProxyBalancer) handleRunSmcMethod(ctx context.Context, v *ton.RunSmcMet
 	stack.Push(c7tuple)
 	c7cell, err := stack.ToCell()
 	if err != nil {
-		return ton.LSError{
+		return nil, nil, false, ton.LSError{
 			Code: 500,
 			Text: "failed to build c7 stack: " + err.Error(),
 		}, HitTypeFailedInternal
 	}
 
+	metrics.Global.EmulationsInFlight.Inc()
 	etm := time.Now()
 	res, err := emulate.RunGetMethod(emulate.RunMethodParams{
 		Code:  st.StateInit.Code,
 		Data:  st.StateInit.Data,
-		Stack: v.Params,
+		Stack: params,
 		Params: emulate.MethodConfig{
 			C7:   c7cell,
 			Libs: libsCell,
 		},
-		MethodID: int32(v.MethodID),
+		MethodID: int32(methodID),
 	}, 1_000_000)
+	metrics.Global.EmulationsInFlight.Dec()
 	if err != nil {
-		log.Warn().Err(err).Type("request", v).Msg("failed to emulate get method")
-
-		return ton.LSError{
+		log.Ctx(ctx).Warn().Err(err).Str("account", addr.String()).Uint64("method_id", methodID).Msg("failed to emulate get method")
+		reportError(ReportKindEmulationFailed, err.Error(), map[string]string{
+			"key_name":  keyNameFrom(ctx),
+			"account":   addr.String(),
+			"method_id": fmt.Sprint(methodID),
+			"block":     fmt.Sprint(block.ID.SeqNo),
+		}, "")
+
+		return nil, nil, false, ton.LSError{
 			Code: 500,
 			Text: "failed to emulate run method: " + err.Error(),
 		}, HitTypeFailedInternal
 	}
-	log.Debug().Dur("took", time.Since(etm)).Msg("get method emulation finished")
+	log.Ctx(ctx).Debug().Dur("took", time.Since(etm)).Int64("gas", res.GasUsed).Msg("get method emulation finished")
 
-	var stateProof, c7 *cell.Cell
+	return res, seed, cachedLibs, nil, ""
+}
 
-	if v.Mode&2 != 0 {
-		stateProof, err = state.State.CreateProof(cell.CreateProofSkeleton())
-		if err != nil {
-			log.Warn().Err(err).Type("request", v).Msg("failed to prepare state proof args")
+// runSmcState resolves block, its master block and account state to emulate get method on,
+// liteserver error is returned when they cannot be resolved, all nils when query should be proxied
+func (s *ProxyBalancer) runSmcState(ctx context.Context, v *ton.RunSmcMethod, addr *address.Address) (*Block, *MasterBlock, *ton.AccountState, string, tl.Serializable) {
+	block, cachedBlock, err := s.cache.CacheBlockIfNeeded(ctx, v.ID)
+	if err != nil {
+		if ls, ok := err.(ton.LSError); ok {
+			return nil, nil, nil, HitTypeFailedValidate, ls
+		}
+		if ctx.Err() != nil {
+			return nil, nil, nil, HitTypeFailedValidate, ErrTimeout
+		}
 
-			return ton.LSError{
-				Code: 500,
-				Text: "failed to prepare state proof args: " + err.Error(),
-			}, HitTypeFailedInternal
+		log.Ctx(ctx).Warn().Err(err).Type("request", v).Msg("failed to get block")
+		return nil, nil, nil, HitTypeFailedInternal, ton.LSError{
+			Code: 500,
+			Text: "failed to resolve block",
 		}
 	}
 
-	if v.Mode&8 != 0 {
-		// short c7 for response
-		c7t, err := emulate.PrepareC7(addr, time.Now(), seed, st.Balance.Nano(), nil, nil)
-		if err != nil {
-			return ton.LSError{
-				Code: 500,
-				Text: "failed to prepare c7: " + err.Error(),
-			}, HitTypeFailedInternal
+	masterBlock, cachedMasterBlock, err := s.cache.GetMasterBlock(ctx, block.MasterID)
+	if err != nil {
+		if ls, ok := err.(ton.LSError); ok {
+			return nil, nil, nil, HitTypeFailedValidate, ls
+		}
+		if ctx.Err() != nil {
+			return nil, nil, nil, HitTypeFailedValidate, ErrTimeout
 		}
 
-		b := cell.BeginCell()
-		if err = tlb.SerializeStackValue(b, c7t); err != nil {
-			return ton.LSError{
-				Code: 500,
-				Text: "failed to build c7 tuple: " + err.Error(),
-			}, HitTypeFailedInternal
+		log.Ctx(ctx).Warn().Err(err).Type("request", v).Msg("failed to get master block")
+		return nil, nil, nil, HitTypeFailedInternal, ton.LSError{
+			Code: 500,
+			Text: "failed to resolve master block",
+		}
+	}
+
+	if block == nil {
+		// block is too old for cache, for now we proxy it to backend,
+		// but maybe it is reasonable to throw an error
+		return nil, nil, nil, HitTypeBackend, nil
+	}
+
+	state, cachedState, err := s.cache.GetAccountStateInBlock(ctx, block, addr)
+	if err != nil {
+		if ls, ok := err.(ton.LSError); ok {
+			return nil, nil, nil, HitTypeFailedValidate, ls
+		}
+		if ctx.Err() != nil {
+			return nil, nil, nil, HitTypeFailedValidate, ErrTimeout
+		}
+
+		log.Ctx(ctx).Warn().Err(err).Type("request", v).Msg("failed to get account")
+
+		return nil, nil, nil, HitTypeFailedInternal, ton.LSError{
+			Code: 500,
+			Text: "failed to get account state",
 		}
-		c7 = b.EndCell()
 	}
 
 	hit := HitTypeBackend
-	if cachedBlock && cachedMasterBlock && cachedLibs {
+	if cachedBlock && cachedMasterBlock {
 		hit = HitTypeEmulated
 		if cachedState {
 			hit = HitTypeCache
 		}
 	}
-
-	return ton.RunMethodResult{
-		Mode:       v.Mode,
-		ID:         v.ID,
-		ShardBlock: state.Shard,
-		ShardProof: state.ShardProof,
-		Proof:      state.Proof,
-		StateProof: stateProof,
-		InitC7:     c7,
-		LibExtras:  nil,
-		ExitCode:   res.ExitCode,
-		Result:     res.Stack,
-	}, hit
+	return block, masterBlock, state, hit, nil
 }
 
 func (s *ProxyBalancer) handleGetMasterchainInfoExt(ctx context.Context, v *ton.GetMasterchainInfoExt) (tl.Serializable, string) {
@@ -602,7 +1451,7 @@ func (s *ProxyBalancer) handleGetMasterchainInfoExt(ctx context.Context, v *ton.
 			return ErrTimeout, HitTypeFailedValidate
 		}
 
-		log.Warn().Err(err).Type("request", v).Msg("failed to get last master")
+		log.Ctx(ctx).Warn().Err(err).Type("request", v).Msg("failed to get last master")
 		return ton.LSError{
 			Code: 500,
 			Text: "failed to resolve master block",
@@ -611,7 +1460,7 @@ func (s *ProxyBalancer) handleGetMasterchainInfoExt(ctx context.Context, v *ton.
 
 	zero, err := s.cache.GetZeroState()
 	if err != nil {
-		log.Warn().Err(err).Type("request", v).Msg("failed to get zero state")
+		log.Ctx(ctx).Warn().Err(err).Type("request", v).Msg("failed to get zero state")
 
 		return ton.LSError{
 			Code: 500,
@@ -622,6 +1471,9 @@ func (s *ProxyBalancer) handleGetMasterchainInfoExt(ctx context.Context, v *ton.
 	hit := HitTypeBackend
 	if cached {
 		hit = HitTypeCache
+		if s.cache.IsStale() {
+			hit = HitTypeStale
+		}
 	}
 
 	return ton.MasterchainInfoExt{
@@ -646,7 +1498,7 @@ func (s *ProxyBalancer) handleGetMasterchainInfo(ctx context.Context) (tl.Serial
 			return ErrTimeout, HitTypeFailedValidate
 		}
 
-		log.Warn().Err(err).Type("request", ton.GetMasterchainInf{}).Msg("failed to get last master")
+		log.Ctx(ctx).Warn().Err(err).Type("request", ton.GetMasterchainInf{}).Msg("failed to get last master")
 		return ton.LSError{
 			Code: 500,
 			Text: "failed to resolve master block",
@@ -655,7 +1507,7 @@ func (s *ProxyBalancer) handleGetMasterchainInfo(ctx context.Context) (tl.Serial
 
 	zero, err := s.cache.GetZeroState()
 	if err != nil {
-		log.Warn().Err(err).Type("request", ton.GetMasterchainInf{}).Msg("failed to get zero state")
+		log.Ctx(ctx).Warn().Err(err).Type("request", ton.GetMasterchainInf{}).Msg("failed to get zero state")
 
 		return ton.LSError{
 			Code: 500,
@@ -666,6 +1518,9 @@ func (s *ProxyBalancer) handleGetMasterchainInfo(ctx context.Context) (tl.Serial
 	hit := HitTypeBackend
 	if cached {
 		hit = HitTypeCache
+		if s.cache.IsStale() {
+			hit = HitTypeStale
+		}
 	}
 	return ton.MasterchainInfo{
 		Last:          block.Block.ID,
@@ -684,7 +1539,7 @@ func (s *ProxyBalancer) handleGetLibraries(ctx context.Context, v *ton.GetLibrar
 			return ErrTimeout, HitTypeFailedValidate
 		}
 
-		log.Warn().Err(err).Type("request", v).Msg("failed to get libraries")
+		log.Ctx(ctx).Warn().Err(err).Type("request", v).Msg("failed to get libraries")
 		return ton.LSError{
 			Code: 500,
 			Text: "failed to get libraries",
@@ -693,7 +1548,7 @@ func (s *ProxyBalancer) handleGetLibraries(ctx context.Context, v *ton.GetLibrar
 
 	all, err := libs.LoadAll()
 	if err != nil {
-		log.Warn().Err(err).Type("request", v).Msg("failed to load libraries")
+		log.Ctx(ctx).Warn().Err(err).Type("request", v).Msg("failed to load libraries")
 		return ton.LSError{
 			Code: 500,
 			Text: "failed to load libraries",
@@ -728,7 +1583,7 @@ func (s *ProxyBalancer) handleGetBlock(ctx context.Context, v *ton.GetBlockData)
 			return ErrTimeout, HitTypeFailedValidate
 		}
 
-		log.Warn().Err(err).Type("request", v).Msg("failed to get block")
+		log.Ctx(ctx).Warn().Err(err).Type("request", v).Msg("failed to get block")
 		return ton.LSError{
 			Code: 500,
 			Text: "failed to get block",
@@ -751,7 +1606,7 @@ func (s *ProxyBalancer) handleGetTransaction(ctx context.Context, v *ton.GetOneT
 			return ErrTimeout, HitTypeFailedValidate
 		}
 
-		log.Warn().Err(err).Type("request", v).Msg("failed to get transaction")
+		log.Ctx(ctx).Warn().Err(err).Type("request", v).Msg("failed to get transaction")
 		return ton.LSError{
 			Code: 500,
 			Text: "failed to get transaction",
@@ -764,8 +1619,27 @@ func (s *ProxyBalancer) handleGetTransaction(ctx context.Context, v *ton.GetOneT
 	return data, HitTypeBackend
 }
 
+func (s *ProxyBalancer) handleGetTransactions(ctx context.Context, v *ton.GetTransactions) (tl.Serializable, string) {
+	if v.AccID == nil || v.Limit <= 0 {
+		return nil, HitTypeBackend
+	}
+
+	list := s.cache.GetTransactionsInCache(v.AccID, v.LT, v.TxHash, v.Limit)
+	if list == nil {
+		log.Ctx(ctx).Debug().Msg("transactions are not indexed")
+		return nil, HitTypeBackend
+	}
+	return *list, HitTypeCache
+}
+
 func (s *ProxyBalancer) handleGetAccount(ctx context.Context, v *ton.GetAccountState) (tl.Serializable, string) {
-	state, cachedState, err := s.cache.GetAccountState(ctx, v.ID, address.NewAddress(0, byte(v.Account.Workchain), v.Account.ID))
+	addr := address.NewAddress(0, byte(v.Account.Workchain), v.Account.ID)
+	state, cachedState, err := s.cache.GetAccountState(ctx, v.ID, addr)
+	if err != nil && s.cache.IsStale() {
+		if staleState, ok := s.cache.GetStaleAccountState(v.ID, addr); ok {
+			return staleState, HitTypeStale
+		}
+	}
 	if err != nil {
 		if ls, ok := err.(ton.LSError); ok {
 			return ls, HitTypeFailedValidate
@@ -774,7 +1648,7 @@ func (s *ProxyBalancer) handleGetAccount(ctx context.Context, v *ton.GetAccountS
 			return ErrTimeout, HitTypeFailedValidate
 		}
 
-		log.Warn().Err(err).Type("request", v).Msg("failed to get account state")
+		log.Ctx(ctx).Warn().Err(err).Type("request", v).Msg("failed to get account state")
 		return ton.LSError{
 			Code: 500,
 			Text: "failed to get account state",
@@ -788,15 +1662,20 @@ func (s *ProxyBalancer) handleGetAccount(ctx context.Context, v *ton.GetAccountS
 }
 
 func (s *ProxyBalancer) handleLookupBlock(ctx context.Context, v *ton.LookupBlock) (tl.Serializable, string) {
-	if v.Mode != 1 {
-		log.Debug().Msg("requested lookup block with non 1 mode")
-		// TODO: support non zero mode too
+	var hdr *ton.BlockHeader
+	var err error
+	switch v.Mode {
+	case 1:
+		hdr, err = s.cache.LookupBlockInCache(v.ID)
+	case 2:
+		hdr, err = s.cache.LookupBlockByLtInCache(v.ID, v.LT)
+	default:
+		log.Ctx(ctx).Debug().Uint32("mode", v.Mode).Msg("requested lookup block with not supported mode")
+		// TODO: support utime and header flags too
 		return nil, HitTypeBackend
 	}
-
-	hdr, err := s.cache.LookupBlockInCache(v.ID)
 	if err != nil {
-		log.Warn().Err(err).Type("request", v).Msg("failed to get lookup block in cache")
+		log.Ctx(ctx).Warn().Err(err).Type("request", v).Msg("failed to get lookup block in cache")
 
 		return ton.LSError{
 			Code: 500,
@@ -806,7 +1685,7 @@ func (s *ProxyBalancer) handleLookupBlock(ctx context.Context, v *ton.LookupBloc
 
 	if hdr == nil {
 		// not in cache
-		log.Debug().Msg("lookup block cache miss")
+		log.Ctx(ctx).Debug().Msg("lookup block cache miss")
 		return nil, HitTypeBackend
 	}
 	return hdr, HitTypeCache
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"math/rand"
+	"time"
+)
+
+// shadowPool receives copies of sampled read queries, its answers are compared with answers of main backends
+// and never returned to clients, so new backend versions can be checked on live traffic
+type shadowPool struct {
+	pool    *BackendBalancer
+	percent float64
+	timeout time.Duration
+	slots   chan struct{}
+}
+
+// EnableShadow mirrors percent of read queries to pool, pool is closed together with balancer
+func (b *BackendBalancer) EnableShadow(pool *BackendBalancer, cfg config.ShadowConfig) {
+	timeout := time.Duration(cfg.TimeoutSeconds) * time.Second
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	maxInFlight := int(cfg.MaxInFlight)
+	if maxInFlight == 0 {
+		maxInFlight = 64
+	}
+
+	b.shadow = &shadowPool{
+		pool:    pool,
+		percent: cfg.Percent,
+		timeout: timeout,
+		slots:   make(chan struct{}, maxInFlight),
+	}
+}
+
+// mirror sends payload to shadow pool in background and compares its answer with resp of main backends
+func (s *shadowPool) mirror(payload tl.Serializable, resp tl.Serializable) {
+	if !isIdempotent(payload) || rand.Float64()*100 >= s.percent {
+		return
+	}
+
+	typ := metrics.Global.TypeLabel(shadowRequest(payload))
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		// shadow pool is slower than main one, it should not accumulate goroutines
+		metrics.Global.ShadowQueries.WithLabelValues(typ, "dropped").Add(1)
+		return
+	}
+
+	go func() {
+		defer func() { <-s.slots }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+		defer cancel()
+
+		var shadow tl.Serializable
+		if err := s.pool.query(ctx, payload, &shadow); err != nil {
+			metrics.Global.ShadowQueries.WithLabelValues(typ, "failed").Add(1)
+			return
+		}
+
+		result := compareShadow(resp, shadow)
+		metrics.Global.ShadowQueries.WithLabelValues(typ, result).Add(1)
+		if result == "mismatch" {
+			log.Debug().Type("request", payload).Type("answer", resp).Type("shadow_answer", shadow).Msg("shadow backend answer differs")
+		}
+	}()
+}
+
+// compareShadow returns match, mismatch or lagging, answers about different blocks are lagging,
+// because backends are not required to be at the same height
+func compareShadow(resp, shadow tl.Serializable) string {
+	if a, b := digestAnswer(resp), digestAnswer(shadow); a != nil && b != nil {
+		if a.key != b.key {
+			return "lagging"
+		}
+		if !bytes.Equal(a.hash, b.hash) {
+			return "mismatch"
+		}
+		return "match"
+	}
+
+	if a, ok := resp.(ton.LSError); ok {
+		if b, ok := shadow.(ton.LSError); ok && a.Code == b.Code {
+			return "match"
+		}
+		return "mismatch"
+	}
+
+	a, err := tl.Serialize(resp, true)
+	if err != nil {
+		return "mismatch"
+	}
+	b, err := tl.Serialize(shadow, true)
+	if err != nil || !bytes.Equal(a, b) {
+		return "mismatch"
+	}
+	return "match"
+}
+
+// shadowRequest returns wrapped query of wait master
+func shadowRequest(payload tl.Serializable) tl.Serializable {
+	if list, ok := payload.([]tl.Serializable); ok && len(list) > 0 {
+		return list[len(list)-1]
+	}
+	return payload
+}
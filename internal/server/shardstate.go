@@ -0,0 +1,348 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+	"time"
+)
+
+// max number of shard blocks between two master blocks to apply, longer gaps are not tracked
+const maxShardChainLength = 8
+
+// carryShardAccountStates moves remembered account states of basechain shard from block prev to block next.
+// Accounts without transactions in the new blocks keep the same state, only proofs of them are rebuilt
+// by applying state updates of blocks to the previous proofs. Proof of the new block itself is taken
+// from one account which is fetched at the new block, so the whole shard costs one account query.
+func (c *BlockCache) carryShardAccountStates(prev, next *ton.BlockIDExt, states *lru.ARCCache) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tm := time.Now()
+	logger := log.With().Str("shard", getShardKey(next.Workchain, next.Shard)).Uint32("seqno", next.SeqNo).Logger()
+
+	blocks, err := c.shardChain(ctx, prev, next)
+	if err != nil {
+		logger.Debug().Err(err).Msg("shard account states are not carried")
+		return
+	}
+
+	accounts := map[string]*ton.AccountState{}
+	addrs := map[string]*address.Address{}
+	for _, k := range states.Keys() {
+		v, ok := states.Peek(k)
+		if !ok {
+			continue
+		}
+
+		acc := v.(*ton.AccountState)
+		addr, err := address.ParseAddr(k.(string))
+		if err != nil || acc.State == nil || len(acc.Proof) != 2 {
+			// proof of not existing account cannot be rebuilt by its hash
+			continue
+		}
+		accounts[k.(string)] = acc
+		addrs[k.(string)] = addr
+	}
+	if len(accounts) == 0 {
+		return
+	}
+
+	var changed []string
+	for _, block := range blocks {
+		var shardAccounts tlb.ShardAccountBlocks
+		if err = tlb.LoadFromCellAsProof(&shardAccounts, block.Extra.ShardAccountBlocks.BeginParse()); err != nil {
+			logger.Debug().Err(err).Msg("failed to load shard accounts of block")
+			return
+		}
+
+		for k, addr := range addrs {
+			if shardAccounts.Accounts.Get(cell.BeginCell().MustStoreSlice(addr.Data(), 256).EndCell()) != nil {
+				// account has transactions in the block, its state is changed
+				changed = append(changed, k)
+				delete(accounts, k)
+				delete(addrs, k)
+			}
+		}
+
+		newState, err := block.StateUpdate.PeekRef(1)
+		if err != nil {
+			logger.Debug().Err(err).Msg("block without state update")
+			return
+		}
+
+		known := map[string]*cell.Cell{}
+		for _, acc := range accounts {
+			if body, err := acc.Proof[0].PeekRef(0); err == nil {
+				indexProofCells(body, known)
+			}
+		}
+		state := graftPruned(newState, known, map[*cell.Cell]*cell.Cell{})
+
+		for k, acc := range accounts {
+			proof, err := proveCell(state, acc.State.Hash())
+			if err != nil {
+				delete(accounts, k)
+				delete(addrs, k)
+				continue
+			}
+			accounts[k] = &ton.AccountState{
+				ID:    next,
+				Shard: next,
+				Proof: []*cell.Cell{proof, acc.Proof[1]},
+				State: acc.State,
+			}
+		}
+	}
+
+	if len(accounts) == 0 {
+		return
+	}
+
+	// block proof is the same for all accounts, so any account fetched at the new block gives it,
+	// changed one is preferred because it should be fetched anyway
+	fetch := ""
+	for k := range accounts {
+		fetch = k
+		break
+	}
+	if len(changed) > 0 {
+		fetch = changed[0]
+	}
+
+	addr, _ := address.ParseAddr(fetch)
+	fresh, err := c.fetchAccount(ctx, next, addr)
+	if err != nil || len(fresh.Proof) != 2 {
+		logger.Debug().Err(err).Msg("failed to fetch account state for block proof")
+		return
+	}
+
+	carried := map[string]*ton.AccountState{fetch: fresh}
+	for k, acc := range accounts {
+		if k == fetch {
+			continue
+		}
+
+		acc.Proof[1] = fresh.Proof[1]
+		if err = verifyAccountState(next, addrs[k], acc); err != nil {
+			logger.Debug().Err(err).Str("addr", k).Msg("rebuilt account state proof is invalid")
+			continue
+		}
+		carried[k] = acc
+	}
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	si := c.shardBlocks[getShardKey(next.Workchain, next.Shard)]
+	if si == nil || !si.lastBlock.Equals(next) {
+		// shard already advanced further
+		return
+	}
+
+	if si.accountStates == nil {
+		if si.accountStates, err = lru.NewARC(int(c.config.MaxCachedAccountsPerBlock)); err != nil {
+			return
+		}
+	}
+	for k, acc := range carried {
+		// states fetched by clients meanwhile are the same, so they are kept
+		if !si.accountStates.Contains(k) {
+			si.accountStates.Add(k, acc)
+		}
+	}
+
+	logger.Debug().Int("carried", len(carried)).Int("tracked", states.Len()).Int("blocks", len(blocks)).
+		Dur("took", time.Since(tm)).Msg("shard account states carried to new block")
+}
+
+// shardChain returns blocks of shard after prev up to next, in order of creation
+func (c *BlockCache) shardChain(ctx context.Context, prev, next *ton.BlockIDExt) ([]*tlb.Block, error) {
+	var chain []*tlb.Block
+	id := next
+	for i := 0; i < maxShardChainLength; i++ {
+		data, err := c.fetchBlock(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get block %d: %w", id.SeqNo, err)
+		}
+
+		var block tlb.Block
+		if err = tlb.LoadFromCell(&block, data.BeginParse()); err != nil {
+			return nil, fmt.Errorf("failed to parse block %d: %w", id.SeqNo, err)
+		}
+		if block.Extra == nil || block.Extra.ShardAccountBlocks == nil {
+			return nil, fmt.Errorf("not complete block %d", id.SeqNo)
+		}
+		chain = append([]*tlb.Block{&block}, chain...)
+
+		parents, err := block.BlockInfo.GetParentBlocks()
+		if err != nil {
+			return nil, err
+		}
+		if len(parents) != 1 || parents[0].Shard != next.Shard {
+			return nil, fmt.Errorf("shard was split or merged")
+		}
+
+		if parents[0].Equals(prev) {
+			return chain, nil
+		}
+		if parents[0].SeqNo <= prev.SeqNo {
+			return nil, fmt.Errorf("block %d is not a descendant of previous block", next.SeqNo)
+		}
+		id = parents[0]
+	}
+	return nil, fmt.Errorf("too many blocks after previous block")
+}
+
+// indexProofCells remembers all not pruned cells of proof by their hash
+func indexProofCells(c *cell.Cell, known map[string]*cell.Cell) {
+	if c.GetType() == cell.PrunedCellType {
+		return
+	}
+
+	// the same cell can be in several proofs with different branches pruned,
+	// any of them is enough because pruned branches are grafted recursively
+	h := string(c.Hash(0))
+	if _, ok := known[h]; !ok {
+		known[h] = c
+	}
+
+	for i := 0; i < int(c.RefsNum()); i++ {
+		indexProofCells(c.MustPeekRef(i), known)
+	}
+}
+
+// graftPruned replaces pruned branches of the new state from block state update with cells known from previous proofs,
+// unchanged parts of state are pruned in the update, so paths to remembered accounts are restored this way
+func graftPruned(c *cell.Cell, known map[string]*cell.Cell, done map[*cell.Cell]*cell.Cell) *cell.Cell {
+	if v, ok := done[c]; ok {
+		return v
+	}
+
+	res := c
+	if c.GetType() == cell.PrunedCellType {
+		if k, ok := known[string(c.Hash(0))]; ok {
+			res = graftPruned(k, known, done)
+		}
+	} else if c.GetType() == cell.OrdinaryCellType && c.RefsNum() > 0 {
+		refs := make([]*cell.Cell, c.RefsNum())
+		changed := false
+		for i := range refs {
+			refs[i] = graftPruned(c.MustPeekRef(i), known, done)
+			changed = changed || refs[i] != c.MustPeekRef(i)
+		}
+		if changed {
+			res = rebuildCell(c, refs)
+		}
+	}
+
+	done[c] = res
+	return res
+}
+
+// proveCell creates merkle proof of state with path to the cell with hash target, other branches are pruned
+func proveCell(state *cell.Cell, target []byte) (*cell.Cell, error) {
+	body, found, err := provePath(state, string(target), map[*cell.Cell]bool{})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("cell is not in state")
+	}
+
+	b := cell.BeginCell().MustStoreUInt(uint64(cell.MerkleProofCellType), 8).
+		MustStoreSlice(body.Hash(0), 256).MustStoreUInt(uint64(body.Depth(0)), 16).MustStoreRef(body)
+	proof := b.EndCell()
+	proof.UnsafeModify(cell.LevelMask{Mask: levelMaskOf(body) >> 1}, true)
+	return proof, nil
+}
+
+func provePath(c *cell.Cell, target string, visited map[*cell.Cell]bool) (*cell.Cell, bool, error) {
+	if string(c.Hash(0)) == target {
+		// state is answered separately, so only its hash is needed
+		pruned, err := pruneCell(c)
+		return pruned, err == nil, err
+	}
+	if c.GetType() != cell.OrdinaryCellType || c.RefsNum() == 0 || visited[c] {
+		return c, false, nil
+	}
+	visited[c] = true
+
+	refs := make([]*cell.Cell, c.RefsNum())
+	found := false
+	for i := range refs {
+		ref := c.MustPeekRef(i)
+		if !found {
+			r, ok, err := provePath(ref, target, visited)
+			if err != nil {
+				return nil, false, err
+			}
+			if ok {
+				refs[i], found = r, true
+				continue
+			}
+		}
+
+		pruned, err := pruneCell(ref)
+		if err != nil {
+			return nil, false, err
+		}
+		refs[i] = pruned
+	}
+
+	if !found {
+		return c, false, nil
+	}
+	return rebuildCell(c, refs), true, nil
+}
+
+// pruneCell replaces cell with pruned branch of the first merkle level, the same as in proofs of liteservers
+func pruneCell(c *cell.Cell) (*cell.Cell, error) {
+	if c.RefsNum() == 0 {
+		// pruned branches and cells without refs are kept as is
+		return c, nil
+	}
+
+	mask := levelMaskOf(c)
+	if mask > 1 {
+		return nil, fmt.Errorf("level of cell is too big to prune")
+	}
+
+	pruned := cell.BeginCell().MustStoreUInt(uint64(cell.PrunedCellType), 8).MustStoreUInt(uint64(mask|1), 8).
+		MustStoreSlice(c.Hash(0), 256).MustStoreUInt(uint64(c.Depth(0)), 16).EndCell()
+	pruned.UnsafeModify(cell.LevelMask{Mask: mask | 1}, true)
+	return pruned, nil
+}
+
+func rebuildCell(c *cell.Cell, refs []*cell.Cell) *cell.Cell {
+	b := cell.BeginCell().MustStoreSlice(c.BeginParse().MustLoadSlice(c.BitsSize()), c.BitsSize())
+	var mask byte
+	for _, ref := range refs {
+		b.MustStoreRef(ref)
+		mask |= levelMaskOf(ref)
+	}
+
+	res := b.EndCell()
+	if mask > 0 {
+		res.UnsafeModify(cell.LevelMask{Mask: mask}, false)
+	}
+	return res
+}
+
+// levelMaskOf returns level mask of cell, it is not exported by cell package,
+// but hashes of cell are different only for significant levels
+func levelMaskOf(c *cell.Cell) byte {
+	var mask byte
+	for lvl := 0; lvl < 3; lvl++ {
+		if !bytes.Equal(c.Hash(lvl), c.Hash(lvl+1)) {
+			mask |= 1 << lvl
+		}
+	}
+	return mask
+}
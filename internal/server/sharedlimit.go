@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"time"
+)
+
+// gcraScript is generic cell rate algorithm, only theoretical arrival time of the next request is stored,
+// clock of redis is used, so instances with drifted clocks share limit fairly. All keys are checked in one call
+// and cost is taken from them only when each of them allows it. ARGV has cost and then interval and tolerance
+// of each key. Returns 0 when cost is taken, otherwise microseconds until it can be taken.
+// Script writes after reading time, so it is replicated by commands, which needs redis 3.2 or newer,
+// since redis 5 it is the default and the call is a no op.
+var gcraScript = redis.NewScript(`
+redis.replicate_commands()
+
+local t = redis.call("time")
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local cost = tonumber(ARGV[1])
+
+local tats = {}
+local wait = 0
+for i, key in ipairs(KEYS) do
+	local interval = tonumber(ARGV[i * 2])
+	local tolerance = tonumber(ARGV[i * 2 + 1])
+
+	local tat = tonumber(redis.call("get", key) or now)
+	if tat < now then
+		tat = now
+	end
+
+	tats[i] = tat + cost * interval
+	local allowAt = tats[i] - tolerance
+	if allowAt - now > wait then
+		wait = allowAt - now
+	end
+end
+
+if wait > 0 then
+	return math.ceil(wait)
+end
+
+for i, key in ipairs(KEYS) do
+	redis.call("set", key, string.format("%d", math.floor(tats[i])), "px", math.max(1, math.ceil((tats[i] - now) / 1000)))
+end
+return 0
+`)
+
+// sharedLimiter applies the same per key and per ip limits as local buckets, but keeps them in redis,
+// so client balanced over several instances cannot multiply its quota. When redis fails, local buckets are used.
+type sharedLimiter struct {
+	client  *redis.Client
+	prefix  string
+	timeout time.Duration
+}
+
+// EnableSharedLimits makes rate limits of keys shared by all instances using the same redis and prefix
+func (s *ProxyBalancer) EnableSharedLimits(cfg config.SharedLimitsConfig) error {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := gcraScript.Load(ctx, client).Err(); err != nil {
+		_ = client.Close()
+		return fmt.Errorf("failed to load limits script: %w", err)
+	}
+
+	l := &sharedLimiter{
+		client:  client,
+		prefix:  cfg.KeyPrefix,
+		timeout: time.Duration(cfg.TimeoutMs) * time.Millisecond,
+	}
+	if l.timeout == 0 {
+		l.timeout = 50 * time.Millisecond
+	}
+	s.sharedLimits = l
+
+	log.Info().Str("addr", cfg.Addr).Msg("rate limits are shared through redis")
+	return nil
+}
+
+// takeLimits charges cost to shared limits of key and ip in one call, error means that redis is not available
+func (l *sharedLimiter) takeLimits(lim *KeyConfig, ipKey string, cost int64) (time.Duration, bool, error) {
+	var keys []string
+	args := []any{cost}
+	if lim.limiterPerIP != nil {
+		keys = append(keys, l.prefix+"ip:"+lim.name+":"+ipKey)
+		args = append(args, gcraArgs(lim.limiterPerIP.Rate(), lim.limiterPerIP.Capacity())...)
+	}
+	if lim.limiterPerKey != nil {
+		keys = append(keys, l.prefix+"key:"+lim.name)
+		args = append(args, gcraArgs(lim.limiterPerKey.Rate(), lim.limiterPerKey.Capacity())...)
+	}
+	if len(keys) == 0 {
+		return 0, true, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+	defer cancel()
+
+	wait, err := gcraScript.Run(ctx, l.client, keys, args...).Int64()
+	if err != nil {
+		log.Debug().Err(err).Msg("failed to check shared limit, local one is used")
+		metrics.Global.SharedLimits.WithLabelValues("failed").Add(1)
+		return 0, false, err
+	}
+
+	if wait > 0 {
+		metrics.Global.SharedLimits.WithLabelValues("rejected").Add(1)
+		// rounded up to milliseconds, the same as local limits
+		return (time.Duration(wait)*time.Microsecond + time.Millisecond - 1).Truncate(time.Millisecond), false, nil
+	}
+	metrics.Global.SharedLimits.WithLabelValues("allowed").Add(1)
+	return 0, true, nil
+}
+
+// gcraArgs returns interval between requests and burst tolerance in microseconds for bucket
+func gcraArgs(rate float64, capacity int64) []any {
+	interval := float64(time.Second/time.Microsecond) / rate
+	return []any{interval, interval * float64(capacity)}
+}
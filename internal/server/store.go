@@ -0,0 +1,455 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"math/rand"
+	"sync/atomic"
+	"time"
+)
+
+type CacheClass string
+
+const (
+	CacheClassMasterBlocks CacheClass = "master_blocks"
+	CacheClassShardBlocks  CacheClass = "shard_blocks"
+	CacheClassAccounts     CacheClass = "accounts"
+	CacheClassLibraries    CacheClass = "libraries"
+	CacheClassTransactions CacheClass = "transactions"
+)
+
+var cacheClasses = []CacheClass{
+	CacheClassMasterBlocks, CacheClassShardBlocks, CacheClassAccounts, CacheClassLibraries, CacheClassTransactions,
+}
+
+// CacheStore keeps serialized immutable objects, it is used as a second cache level
+// behind in-memory structures of BlockCache and can be shared between proxy instances
+type CacheStore interface {
+	// Get returns false when there is no such key
+	Get(ctx context.Context, class CacheClass, key string) ([]byte, bool, error)
+	// Set stores data, when ttl is 0 default of the store is used
+	Set(ctx context.Context, class CacheClass, key string, data []byte, ttl time.Duration) error
+	Delete(ctx context.Context, class CacheClass, key string) error
+	// Purge removes all objects of class, or all objects when class is empty
+	Purge(ctx context.Context, class CacheClass) error
+	Close() error
+}
+
+type memoryEntry struct {
+	data     []byte
+	ttl      time.Duration
+	expireAt time.Time
+}
+
+type memoryClass struct {
+	cache    *lru.Cache
+	maxBytes uint64
+	bytes    int64
+}
+
+// MemoryStore is a CacheStore which keeps objects in process memory, least recently used are evicted,
+// each class has its own limits of entries and bytes
+type MemoryStore struct {
+	classes map[CacheClass]*memoryClass
+	ttl     time.Duration
+
+	jitter uint32
+	stale  time.Duration
+}
+
+// LayeredStore reads from the first layer which has the object and fills faster layers with it,
+// writes go to all layers, layers should be ordered from the fastest
+type LayeredStore struct {
+	layers []CacheStore
+}
+
+// NewCacheStore creates store of type selected in config, nil is returned when store is disabled,
+// sibling proxies and s3 tier are added below it when configured
+func NewCacheStore(cfg config.CacheConfig) (CacheStore, error) {
+	store, err := newCacheStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var layers []CacheStore
+	if store != nil {
+		layers = append(layers, store)
+	}
+	if len(cfg.PeerCache.Peers) > 0 {
+		layers = append(layers, NewPeerStore(cfg.PeerCache))
+	}
+	if cfg.S3.Bucket != "" {
+		s3, err := NewS3Store(cfg.S3)
+		if err != nil {
+			for _, layer := range layers {
+				_ = layer.Close()
+			}
+			return nil, err
+		}
+		layers = append(layers, s3)
+	}
+
+	switch len(layers) {
+	case 0:
+		return nil, nil
+	case 1:
+		return layers[0], nil
+	}
+	return NewLayeredStore(layers...), nil
+}
+
+func newCacheStore(cfg config.CacheConfig) (CacheStore, error) {
+	stale := time.Duration(cfg.StaleSeconds) * time.Second
+
+	switch cfg.Store {
+	case "", "none":
+		return nil, nil
+	case "memory":
+		mem, err := NewMemoryStore(cfg.Memory, StoreClassLimits(cfg))
+		if err != nil {
+			return nil, err
+		}
+		mem.SetExpiration(cfg.TTLJitterPercent, stale)
+		return mem, nil
+	case "redis":
+		r, err := NewRedisStore(cfg.Redis)
+		if err != nil {
+			return nil, err
+		}
+		r.SetTTLJitter(cfg.TTLJitterPercent)
+		return r, nil
+	case "disk":
+		disk, err := NewDiskStore(cfg.Disk)
+		if err != nil {
+			return nil, err
+		}
+		disk.SetExpiration(cfg.TTLJitterPercent, stale)
+		return disk, nil
+	case "layered":
+		mem, err := NewMemoryStore(cfg.Memory, StoreClassLimits(cfg))
+		if err != nil {
+			return nil, err
+		}
+		mem.SetExpiration(cfg.TTLJitterPercent, stale)
+
+		disk, err := NewDiskStore(cfg.Disk)
+		if err != nil {
+			return nil, err
+		}
+		disk.SetExpiration(cfg.TTLJitterPercent, stale)
+		return NewLayeredStore(mem, disk), nil
+	}
+	return nil, fmt.Errorf("unknown cache store type %s", cfg.Store)
+}
+
+// jitterTTL randomly shifts ttl by up to percent in both directions,
+// so objects cached at the same time are not expiring in the same second
+func jitterTTL(ttl time.Duration, percent uint32) time.Duration {
+	if ttl <= 0 || percent == 0 {
+		return ttl
+	}
+	if percent > 100 {
+		percent = 100
+	}
+
+	max := int64(ttl) * int64(percent) / 100
+	if max <= 0 {
+		return ttl
+	}
+	return ttl + time.Duration(rand.Int63n(2*max+1)-max)
+}
+
+func NewMemoryStore(cfg config.MemoryCacheConfig, limits map[CacheClass]config.CacheStoreClassConfig) (*MemoryStore, error) {
+	m := &MemoryStore{
+		classes: map[CacheClass]*memoryClass{},
+		ttl:     time.Duration(cfg.TTLSeconds) * time.Second,
+	}
+
+	for _, class := range cacheClasses {
+		entries := limits[class].MaxEntries
+		if entries == 0 {
+			entries = cfg.MaxEntries
+		}
+		if entries == 0 {
+			return nil, fmt.Errorf("max entries of memory store should be set")
+		}
+
+		mc := &memoryClass{
+			maxBytes: limits[class].MaxBytes,
+		}
+
+		class := class
+		cache, err := lru.NewWithEvict(int(entries), func(_ interface{}, value interface{}) {
+			size := atomic.AddInt64(&mc.bytes, -int64(len(value.(*memoryEntry).data)))
+			metrics.Global.CacheBytes.WithLabelValues(string(class), "memory").Set(float64(size))
+			metrics.Global.CacheEvictions.WithLabelValues(string(class), "memory").Add(1)
+		})
+		if err != nil {
+			return nil, err
+		}
+		mc.cache = cache
+		m.classes[class] = mc
+	}
+	return m, nil
+}
+
+// SetExpiration sets max random deviation of ttl in percents, and how long expired objects
+// are still served, each read during this window renews object for one more ttl
+func (m *MemoryStore) SetExpiration(jitterPercent uint32, stale time.Duration) {
+	m.jitter = jitterPercent
+	m.stale = stale
+}
+
+func (m *MemoryStore) Get(_ context.Context, class CacheClass, key string) ([]byte, bool, error) {
+	mc := m.classes[class]
+	if mc == nil {
+		return nil, false, nil
+	}
+
+	v, ok := mc.cache.Get(key)
+	if !ok {
+		return nil, false, nil
+	}
+
+	e := v.(*memoryEntry)
+	if now := time.Now(); !e.expireAt.IsZero() && now.After(e.expireAt) {
+		if now.After(e.expireAt.Add(m.stale)) {
+			mc.cache.Remove(key)
+			return nil, false, nil
+		}
+
+		// object is still requested after expiration, so it is hot, objects are immutable
+		// so it is served and kept for one more ttl without going to backend
+		mc.cache.Add(key, &memoryEntry{data: e.data, ttl: e.ttl, expireAt: now.Add(jitterTTL(e.ttl, m.jitter))})
+		metrics.Global.CacheStaleRenewals.WithLabelValues(string(class), "memory").Add(1)
+	}
+	return e.data, true, nil
+}
+
+func (m *MemoryStore) Set(_ context.Context, class CacheClass, key string, data []byte, ttl time.Duration) error {
+	mc := m.classes[class]
+	if mc == nil {
+		return fmt.Errorf("unknown cache class %s", class)
+	}
+
+	if mc.maxBytes > 0 && uint64(len(data)) > mc.maxBytes {
+		// will never fit
+		return nil
+	}
+
+	if ttl == 0 {
+		ttl = m.ttl
+	}
+
+	e := &memoryEntry{data: data, ttl: ttl}
+	if ttl > 0 {
+		e.expireAt = time.Now().Add(jitterTTL(ttl, m.jitter))
+	}
+
+	// lru does not call evict callback on replace, so we account size of old value explicitly
+	size := int64(len(data))
+	if old, ok := mc.cache.Peek(key); ok {
+		size -= int64(len(old.(*memoryEntry).data))
+	}
+	size = atomic.AddInt64(&mc.bytes, size)
+	mc.cache.Add(key, e)
+	metrics.Global.CacheBytes.WithLabelValues(string(class), "memory").Set(float64(size))
+
+	for mc.maxBytes > 0 && uint64(atomic.LoadInt64(&mc.bytes)) > mc.maxBytes {
+		if _, _, ok := mc.cache.RemoveOldest(); !ok {
+			break
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) Delete(_ context.Context, class CacheClass, key string) error {
+	if mc := m.classes[class]; mc != nil {
+		mc.cache.Remove(key)
+	}
+	return nil
+}
+
+func (m *MemoryStore) Purge(_ context.Context, class CacheClass) error {
+	for cl, mc := range m.classes {
+		if class == "" || class == cl {
+			mc.cache.Purge()
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) MemoryUsage() map[CacheClass]int64 {
+	res := make(map[CacheClass]int64, len(m.classes))
+	for class, mc := range m.classes {
+		res[class] = atomic.LoadInt64(&mc.bytes)
+	}
+	return res
+}
+
+func (m *MemoryStore) Evict(class CacheClass, n int64) int64 {
+	mc := m.classes[class]
+	if mc == nil {
+		return 0
+	}
+
+	// evict callback decreases size of class
+	start := atomic.LoadInt64(&mc.bytes)
+	for start-atomic.LoadInt64(&mc.bytes) < n {
+		if _, _, ok := mc.cache.RemoveOldest(); !ok {
+			break
+		}
+	}
+	return start - atomic.LoadInt64(&mc.bytes)
+}
+
+func (m *MemoryStore) Close() error {
+	for _, mc := range m.classes {
+		mc.cache.Purge()
+	}
+	return nil
+}
+
+func NewLayeredStore(layers ...CacheStore) *LayeredStore {
+	return &LayeredStore{
+		layers: layers,
+	}
+}
+
+func (l *LayeredStore) Get(ctx context.Context, class CacheClass, key string) ([]byte, bool, error) {
+	for i, layer := range l.layers {
+		data, ok, err := layer.Get(ctx, class, key)
+		if err != nil {
+			return nil, false, err
+		}
+		if !ok {
+			continue
+		}
+
+		for j := 0; j < i; j++ {
+			// faster layers are filled with their default ttl
+			if err = l.layers[j].Set(ctx, class, key, data, 0); err != nil {
+				return nil, false, err
+			}
+		}
+		return data, true, nil
+	}
+	return nil, false, nil
+}
+
+func (l *LayeredStore) Set(ctx context.Context, class CacheClass, key string, data []byte, ttl time.Duration) error {
+	for _, layer := range l.layers {
+		if err := layer.Set(ctx, class, key, data, ttl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *LayeredStore) Delete(ctx context.Context, class CacheClass, key string) error {
+	for _, layer := range l.layers {
+		if err := layer.Delete(ctx, class, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *LayeredStore) Purge(ctx context.Context, class CacheClass) error {
+	for _, layer := range l.layers {
+		if err := layer.Purge(ctx, class); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MemoryUsage sums usage of layers which keep objects in memory
+func (l *LayeredStore) MemoryUsage() map[CacheClass]int64 {
+	res := map[CacheClass]int64{}
+	for _, layer := range l.layers {
+		if mc, ok := layer.(MemoryConsumer); ok {
+			for class, n := range mc.MemoryUsage() {
+				res[class] += n
+			}
+		}
+	}
+	return res
+}
+
+func (l *LayeredStore) Evict(class CacheClass, n int64) int64 {
+	var freed int64
+	for _, layer := range l.layers {
+		if mc, ok := layer.(MemoryConsumer); ok && freed < n {
+			freed += mc.Evict(class, n-freed)
+		}
+	}
+	return freed
+}
+
+func (l *LayeredStore) Close() error {
+	var firstErr error
+	for _, layer := range l.layers {
+		if err := layer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// StoreClassLimits maps per class config of the store to classes
+func StoreClassLimits(cfg config.CacheConfig) map[CacheClass]config.CacheStoreClassConfig {
+	return map[CacheClass]config.CacheStoreClassConfig{
+		CacheClassMasterBlocks: cfg.StoreLimits.MasterBlocks,
+		CacheClassShardBlocks:  cfg.StoreLimits.ShardBlocks,
+		CacheClassAccounts:     cfg.StoreLimits.AccountStates,
+		CacheClassLibraries:    cfg.StoreLimits.Libraries,
+		CacheClassTransactions: cfg.StoreLimits.Transactions,
+	}
+}
+
+func blockStoreKey(id *ton.BlockIDExt) string {
+	return hex.EncodeToString(id.RootHash)
+}
+
+func accountStoreKey(id *ton.BlockIDExt, wc int32, addr []byte) string {
+	return hex.EncodeToString(id.RootHash) + ":" + fmt.Sprint(wc) + ":" + hex.EncodeToString(addr)
+}
+
+func transactionStoreKey(id *ton.BlockIDExt, acc *ton.AccountID, lt int64) string {
+	return accountStoreKey(id, acc.Workchain, acc.ID) + ":" + fmt.Sprint(lt)
+}
+
+// loadStoredCell returns cell only if its hash matches the key, so corrupted store cannot poison the cache
+func loadStoredCell(data []byte, hash []byte) (*cell.Cell, error) {
+	cl, err := cell.FromBOC(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(cl.Hash(), hash) {
+		return nil, fmt.Errorf("stored cell hash mismatch")
+	}
+	return cl, nil
+}
+
+func loadStoredTL[T any](data []byte) (*T, error) {
+	var resp tl.Serializable
+	if _, err := tl.Parse(&resp, data, true); err != nil {
+		return nil, err
+	}
+
+	v, ok := resp.(T)
+	if !ok {
+		return nil, fmt.Errorf("unexpected stored type %T", resp)
+	}
+	return &v, nil
+}
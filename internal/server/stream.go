@@ -0,0 +1,290 @@
+package server
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"strconv"
+	"time"
+)
+
+// max number of missed master blocks or shard parents to recover, older are skipped
+const maxStreamBacktrack = 64
+
+// EventSink delivers serialized events to external message broker
+type EventSink interface {
+	// Publish sends event, key is used for partitioning and ordering
+	Publish(ctx context.Context, key string, data []byte) error
+	Close() error
+}
+
+// NewEventSink creates sink of type selected in config, nil is returned when stream is disabled
+func NewEventSink(cfg config.EventStreamConfig) (EventSink, error) {
+	switch cfg.Type {
+	case "", "none":
+		return nil, nil
+	case "kafka":
+		return NewKafkaSink(cfg.Kafka)
+	case "nats":
+		return NewNATSSink(cfg.NATS)
+	}
+	return nil, fmt.Errorf("unknown event stream type %s", cfg.Type)
+}
+
+type BlockStreamEvent struct {
+	Type        string `json:"type"` // master or shard
+	Workchain   int32  `json:"workchain"`
+	Shard       string `json:"shard"`
+	SeqNo       uint32 `json:"seqno"`
+	RootHash    string `json:"root_hash"`
+	FileHash    string `json:"file_hash"`
+	GenUtime    uint32 `json:"gen_utime"`
+	TxCount     int    `json:"tx_count"`
+	MasterSeqNo uint32 `json:"master_seqno"`
+	BOC         []byte `json:"boc,omitempty"`
+}
+
+// BlockStreamer publishes every block ingested by cache to event sink, master blocks missed by feed
+// are looked up, and shard blocks not referenced by master directly are found by walking back parents
+type BlockStreamer struct {
+	cache    *BlockCache
+	sink     EventSink
+	withData bool
+
+	lastMaster uint32
+	// last published seqno of each shard
+	lastShards map[string]uint32
+
+	closed chan struct{}
+	done   chan struct{}
+}
+
+func NewBlockStreamer(cache *BlockCache, sink EventSink, withData bool) *BlockStreamer {
+	return &BlockStreamer{
+		cache:      cache,
+		sink:       sink,
+		withData:   withData,
+		lastShards: map[string]uint32{},
+		closed:     make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+func (s *BlockStreamer) Start() {
+	go func() {
+		defer close(s.done)
+
+		for {
+			events, unsubscribe := s.cache.SubscribeBlocks(256)
+			if !s.consume(events) {
+				unsubscribe()
+				return
+			}
+			unsubscribe()
+
+			// channel is closed when we were too slow, missed blocks are recovered on next event
+			log.Warn().Msg("block stream is behind the chain, resubscribing")
+		}
+	}()
+}
+
+// Stop stops started streaming and closes sink, event which is being published is finished first
+func (s *BlockStreamer) Stop() error {
+	close(s.closed)
+	<-s.done
+	return s.sink.Close()
+}
+
+// consume streams events until channel is closed, false is returned when streamer is stopped
+func (s *BlockStreamer) consume(events <-chan *BlockEvent) bool {
+	for {
+		select {
+		case <-s.closed:
+			return false
+		case ev, ok := <-events:
+			if !ok {
+				select {
+				case <-s.closed:
+					return false
+				default:
+					return true
+				}
+			}
+			s.streamMaster(ev.Master)
+		}
+	}
+}
+
+func (s *BlockStreamer) streamMaster(id *ton.BlockIDExt) {
+	if s.lastMaster > 0 && id.SeqNo > s.lastMaster+1 {
+		from := s.lastMaster + 1
+		if id.SeqNo-from > maxStreamBacktrack {
+			log.Warn().Uint32("from", from).Uint32("to", id.SeqNo-maxStreamBacktrack).Msg("block stream skipped master blocks")
+			from = id.SeqNo - maxStreamBacktrack
+		}
+
+		for seqno := from; seqno < id.SeqNo; seqno++ {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			missed, err := lookupBlock(ctx, s.cache.balancer.GetClient(), &ton.BlockInfoShort{
+				Workchain: -1,
+				Shard:     -0x8000000000000000,
+				Seqno:     int32(seqno),
+			})
+			cancel()
+			if err != nil {
+				log.Warn().Err(err).Uint32("seqno", seqno).Msg("block stream failed to lookup missed master block")
+				continue
+			}
+			s.publishMaster(missed)
+		}
+	}
+	s.publishMaster(id)
+}
+
+func (s *BlockStreamer) publishMaster(id *ton.BlockIDExt) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	master, _, err := s.cache.GetMasterBlock(ctx, id)
+	if err != nil {
+		log.Warn().Err(err).Uint32("seqno", id.SeqNo).Msg("block stream failed to get master block")
+		return
+	}
+
+	// shard blocks are published before master block which commits them
+	for _, shard := range master.Shards {
+		s.publishShard(ctx, shard, id.SeqNo)
+	}
+
+	s.publish(ctx, "master", id, master.Block.Data, id.SeqNo)
+	s.lastMaster = id.SeqNo
+}
+
+func (s *BlockStreamer) publishShard(ctx context.Context, top *ton.BlockIDExt, masterSeqno uint32) {
+	type shardBlock struct {
+		id   *ton.BlockIDExt
+		data *cell.Cell
+	}
+
+	var chain []shardBlock
+	for id := top; len(chain) < maxStreamBacktrack; {
+		last, known := s.lastShards[getShardKey(id.Workchain, id.Shard)]
+		if known && id.SeqNo <= last {
+			break
+		}
+
+		data, _, err := s.cache.GetBlock(ctx, id)
+		if err != nil {
+			log.Warn().Err(err).Int32("workchain", id.Workchain).Uint32("seqno", id.SeqNo).Msg("block stream failed to get shard block")
+			break
+		}
+		chain = append(chain, shardBlock{id: id, data: data.Payload})
+
+		if !known {
+			// shard is new for us, so there is nothing to recover
+			break
+		}
+
+		var block tlb.Block
+		if err = tlb.LoadFromCell(&block, data.Payload.BeginParse()); err != nil {
+			break
+		}
+
+		parents, err := block.BlockInfo.GetParentBlocks()
+		if err != nil || len(parents) != 1 {
+			// after merge both parents were already published as tops of their shards
+			break
+		}
+		id = &ton.BlockIDExt{
+			Workchain: parents[0].Workchain,
+			Shard:     parents[0].Shard,
+			SeqNo:     parents[0].SeqNo,
+			RootHash:  parents[0].RootHash,
+			FileHash:  parents[0].FileHash,
+		}
+	}
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		s.publish(ctx, "shard", chain[i].id, chain[i].data, masterSeqno)
+	}
+	s.lastShards[getShardKey(top.Workchain, top.Shard)] = top.SeqNo
+}
+
+func (s *BlockStreamer) publish(ctx context.Context, typ string, id *ton.BlockIDExt, data *cell.Cell, masterSeqno uint32) {
+	ev := &BlockStreamEvent{
+		Type:        typ,
+		Workchain:   id.Workchain,
+		Shard:       fmt.Sprintf("%016x", uint64(id.Shard)),
+		SeqNo:       id.SeqNo,
+		RootHash:    hex.EncodeToString(id.RootHash),
+		FileHash:    hex.EncodeToString(id.FileHash),
+		MasterSeqNo: masterSeqno,
+	}
+
+	var block tlb.Block
+	if err := tlb.LoadFromCell(&block, data.BeginParse()); err != nil {
+		log.Warn().Err(err).Str("type", typ).Uint32("seqno", id.SeqNo).Msg("block stream failed to parse block")
+	} else {
+		ev.GenUtime = block.BlockInfo.GenUtime
+		ev.TxCount = countBlockTransactions(&block)
+	}
+
+	if s.withData {
+		ev.BOC = data.ToBOC()
+	}
+
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+
+	// master block and its shard blocks share the key, so they keep order in one partition
+	if err = s.sink.Publish(ctx, strconv.FormatUint(uint64(masterSeqno), 10), payload); err != nil {
+		log.Warn().Err(err).Str("type", typ).Uint32("seqno", id.SeqNo).Msg("block stream failed to publish block")
+		metrics.Global.StreamEvents.WithLabelValues("failed").Add(1)
+		return
+	}
+	metrics.Global.StreamEvents.WithLabelValues("ok").Add(1)
+}
+
+func countBlockTransactions(block *tlb.Block) int {
+	if block.Extra == nil || block.Extra.ShardAccountBlocks == nil {
+		return 0
+	}
+
+	var shardAccounts tlb.ShardAccountBlocks
+	if err := tlb.LoadFromCell(&shardAccounts, block.Extra.ShardAccountBlocks.BeginParse()); err != nil || shardAccounts.Accounts.IsEmpty() {
+		return 0
+	}
+
+	accounts, err := shardAccounts.Accounts.LoadAll()
+	if err != nil {
+		return 0
+	}
+
+	var num int
+	for _, kv := range accounts {
+		if err = tlb.LoadFromCell(new(tlb.CurrencyCollection), kv.Value); err != nil {
+			continue
+		}
+
+		var accBlock tlb.AccountBlock
+		if err = tlb.LoadFromCell(&accBlock, kv.Value); err != nil || accBlock.Transactions.IsEmpty() {
+			continue
+		}
+
+		txs, err := accBlock.Transactions.LoadAll()
+		if err != nil {
+			continue
+		}
+		num += len(txs)
+	}
+	return num
+}
@@ -0,0 +1,375 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"golang.org/x/net/websocket"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	subscriptionWriteTimeout   = 10 * time.Second
+	maxAccountsPerSubscription = 256
+	// parallel account state checks per subscription on new block
+	accountChecksParallel = 8
+)
+
+type SubscriptionsAPI struct {
+	cache *BlockCache
+	token string
+	mux   *http.ServeMux
+}
+
+type BlockNotification struct {
+	Type        string `json:"type"` // master or shard
+	Workchain   int32  `json:"workchain"`
+	Shard       string `json:"shard"`
+	SeqNo       uint32 `json:"seqno"`
+	RootHash    string `json:"root_hash"`
+	FileHash    string `json:"file_hash"`
+	GenUtime    uint32 `json:"gen_utime,omitempty"`
+	MasterSeqNo uint32 `json:"master_seqno"`
+}
+
+type AccountNotification struct {
+	Type        string `json:"type"` // account
+	Address     string `json:"address"`
+	MasterSeqNo uint32 `json:"master_seqno"`
+	Status      string `json:"status"`
+	Balance     string `json:"balance"`
+	LastTxLT    uint64 `json:"last_tx_lt"`
+	StateHash   string `json:"state_hash"` // hash of account cell, empty when account not exists
+}
+
+type ErrorNotification struct {
+	Type  string `json:"type"` // error
+	Error string `json:"error"`
+}
+
+type accountsCommand struct {
+	Op        string   `json:"op"` // subscribe or unsubscribe
+	Addresses []string `json:"addresses"`
+}
+
+type watchedAccount struct {
+	name      string
+	addr      *address.Address
+	stateHash string
+	// current state was sent to client at least once
+	checked bool
+	// last check failed, so it is checked on next block even if its shard was not changed
+	stale bool
+}
+
+// NewSubscriptionsAPI creates http handler with websocket subscriptions on data ingested by cache,
+// when token is not empty it is required to be passed as bearer authorization
+func NewSubscriptionsAPI(cache *BlockCache, token string) *SubscriptionsAPI {
+	a := &SubscriptionsAPI{
+		cache: cache,
+		token: token,
+		mux:   http.NewServeMux(),
+	}
+	a.mux.Handle("/blocks", a.websocket(a.handleBlocks))
+	a.mux.Handle("/accounts", a.websocket(a.handleAccounts))
+	a.mux.Handle("/downstream", a.websocket(a.handleDownstream))
+
+	return a
+}
+
+func (a *SubscriptionsAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if a.token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+a.token)) != 1 {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return
+	}
+	a.mux.ServeHTTP(w, r)
+}
+
+func (a *SubscriptionsAPI) websocket(handler websocket.Handler) http.Handler {
+	return websocket.Server{
+		// access is controlled by token, so clients without origin, like indexers, are allowed too
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler:   handler,
+	}
+}
+
+// handleBlocks streams new blocks, shard blocks are sent before master block which commits them,
+// shards=false query parameter leaves only master blocks
+func (a *SubscriptionsAPI) handleBlocks(ws *websocket.Conn) {
+	defer ws.Close()
+
+	withShards := ws.Request().URL.Query().Get("shards") != "false"
+
+	events, unsubscribe := a.cache.SubscribeBlocks(64)
+	defer unsubscribe()
+
+	metrics.Global.Subscriptions.WithLabelValues("blocks").Add(1)
+	defer metrics.Global.Subscriptions.WithLabelValues("blocks").Sub(1)
+
+	closed := watchClose(ws)
+	for {
+		select {
+		case <-closed:
+			return
+		case ev, ok := <-events:
+			if !ok {
+				log.Debug().Str("addr", ws.Request().RemoteAddr).Msg("blocks subscriber is too slow, disconnecting")
+				return
+			}
+
+			if withShards {
+				for _, shard := range ev.Shards {
+					if err := sendNotification(ws, newBlockNotification("shard", shard, 0, ev.Master.SeqNo)); err != nil {
+						return
+					}
+				}
+			}
+			if err := sendNotification(ws, newBlockNotification("master", ev.Master, ev.GenTime, ev.Master.SeqNo)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// handleAccounts pushes account state when it is changed in new block, client manages set of accounts
+// with {"op":"subscribe","addresses":[...]} and {"op":"unsubscribe","addresses":[...]} messages,
+// current state is sent right after subscribe
+func (a *SubscriptionsAPI) handleAccounts(ws *websocket.Conn) {
+	defer ws.Close()
+
+	events, unsubscribe := a.cache.SubscribeBlocks(64)
+	defer unsubscribe()
+
+	metrics.Global.Subscriptions.WithLabelValues("accounts").Add(1)
+	defer metrics.Global.Subscriptions.WithLabelValues("accounts").Sub(1)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	commands := make(chan *accountsCommand)
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+
+		for {
+			var cmd accountsCommand
+			if err := websocket.JSON.Receive(ws, &cmd); err != nil {
+				return
+			}
+
+			select {
+			case commands <- &cmd:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	watched := map[string]*watchedAccount{}
+	for {
+		select {
+		case <-closed:
+			return
+		case cmd := <-commands:
+			var added []*watchedAccount
+			var err error
+
+			switch cmd.Op {
+			case "subscribe":
+				added, err = addWatchedAccounts(watched, cmd.Addresses)
+			case "unsubscribe":
+				for _, addr := range cmd.Addresses {
+					delete(watched, addr)
+				}
+			default:
+				err = fmt.Errorf("unknown op")
+			}
+
+			if err != nil {
+				if err = sendNotification(ws, &ErrorNotification{Type: "error", Error: err.Error()}); err != nil {
+					return
+				}
+			}
+
+			if len(added) > 0 {
+				master, _, err := a.cache.GetLastMasterBlock(context.Background())
+				if err != nil {
+					for _, acc := range added {
+						acc.stale = true
+					}
+					continue
+				}
+
+				if err = a.checkAccounts(ws, master.Block.ID, added); err != nil {
+					return
+				}
+			}
+		case ev, ok := <-events:
+			if !ok {
+				log.Debug().Str("addr", ws.Request().RemoteAddr).Msg("accounts subscriber is too slow, disconnecting")
+				return
+			}
+
+			var changed []*watchedAccount
+			for _, acc := range watched {
+				if acc.stale || accountInShards(acc.addr, ev.Shards) {
+					changed = append(changed, acc)
+				}
+			}
+
+			if err := a.checkAccounts(ws, ev.Master, changed); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// checkAccounts fetches accounts state at master block and sends ones which were changed since last check
+func (a *SubscriptionsAPI) checkAccounts(ws *websocket.Conn, master *ton.BlockIDExt, list []*watchedAccount) error {
+	notifications := make([]*AccountNotification, len(list))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, accountChecksParallel)
+	for i, acc := range list {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, acc *watchedAccount) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			state, _, err := a.cache.GetAccountState(ctx, master, acc.addr)
+			cancel()
+			if err != nil {
+				log.Debug().Err(err).Str("account", acc.name).Uint32("seqno", master.SeqNo).Msg("failed to check subscribed account")
+				acc.stale = true
+				return
+			}
+			acc.stale = false
+
+			n := &AccountNotification{
+				Type:        "account",
+				Address:     acc.name,
+				MasterSeqNo: master.SeqNo,
+				Status:      string(tlb.AccountStatusNonExist),
+				Balance:     "0",
+			}
+
+			if state.State != nil {
+				n.StateHash = hex.EncodeToString(state.State.Hash())
+
+				var st tlb.AccountState
+				if err = st.LoadFromCell(state.State.BeginParse()); err == nil && st.IsValid {
+					n.Status = string(st.Status)
+					n.Balance = st.Balance.Nano().String()
+					n.LastTxLT = st.LastTransactionLT
+				}
+			}
+
+			if !acc.checked || n.StateHash != acc.stateHash {
+				acc.checked = true
+				acc.stateHash = n.StateHash
+				notifications[i] = n
+			}
+		}(i, acc)
+	}
+	wg.Wait()
+
+	for _, n := range notifications {
+		if n == nil {
+			continue
+		}
+		if err := sendNotification(ws, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addWatchedAccounts(watched map[string]*watchedAccount, list []string) ([]*watchedAccount, error) {
+	var added []*watchedAccount
+	for _, str := range list {
+		if watched[str] != nil {
+			continue
+		}
+
+		addr, err := address.ParseAddr(str)
+		if err != nil {
+			return added, fmt.Errorf("invalid address %s", str)
+		}
+		// cache keys are built from addresses without flags
+		addr = address.NewAddress(0, byte(addr.Workchain()), addr.Data())
+
+		if len(watched) >= maxAccountsPerSubscription {
+			return added, fmt.Errorf("too many accounts, max %d", maxAccountsPerSubscription)
+		}
+
+		acc := &watchedAccount{
+			name: str,
+			addr: addr,
+		}
+		watched[str] = acc
+		added = append(added, acc)
+	}
+	return added, nil
+}
+
+// accountInShards checks if account could be changed in new master block, masterchain accounts are always checked
+func accountInShards(addr *address.Address, shards []*ton.BlockIDExt) bool {
+	if addr.Workchain() == -1 {
+		return true
+	}
+
+	for _, shard := range shards {
+		if shard.Workchain == addr.Workchain() && shardContainsAccount(shard.Shard, addr.Data()) {
+			return true
+		}
+	}
+	return false
+}
+
+// watchClose reads and drops client frames, returned channel is closed when connection is closed
+func watchClose(ws *websocket.Conn) <-chan struct{} {
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+
+		var msg []byte
+		for {
+			if err := websocket.Message.Receive(ws, &msg); err != nil {
+				return
+			}
+		}
+	}()
+	return closed
+}
+
+func sendNotification(ws *websocket.Conn, v any) error {
+	if err := ws.SetWriteDeadline(time.Now().Add(subscriptionWriteTimeout)); err != nil {
+		return err
+	}
+	return websocket.JSON.Send(ws, v)
+}
+
+func newBlockNotification(typ string, id *ton.BlockIDExt, genTime, masterSeqno uint32) *BlockNotification {
+	return &BlockNotification{
+		Type:        typ,
+		Workchain:   id.Workchain,
+		Shard:       fmt.Sprintf("%016x", uint64(id.Shard)),
+		SeqNo:       id.SeqNo,
+		RootHash:    hex.EncodeToString(id.RootHash),
+		FileHash:    hex.EncodeToString(id.FileHash),
+		GenUtime:    genTime,
+		MasterSeqNo: masterSeqno,
+	}
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-liteserver-proxy/internal/emulate"
+	"math/big"
+)
+
+// config params with addresses of system contracts
+const (
+	configParamConfigAddr  = 0
+	configParamElectorAddr = 1
+)
+
+// systemGetters keeps results of get methods of elector and config contracts by master block,
+// validator tooling polls participant lists, stakes and proposals constantly, and these contracts
+// have big states which are expensive to parse and emulate for every query
+type systemGetters struct {
+	results *lru.Cache
+}
+
+func newSystemGetters(size int) (*systemGetters, error) {
+	results, err := lru.New(size)
+	if err != nil {
+		return nil, err
+	}
+	return &systemGetters{results: results}, nil
+}
+
+// key returns cache key of query in block, empty when account is not a system contract,
+// results are bound to block, so they are invalidated with every new block
+func (g *systemGetters) key(masterBlock *MasterBlock, block *Block, addr *address.Address, v *ton.RunSmcMethod) string {
+	if addr.Workchain() != -1 || v.Mode&8 != 0 || v.Params == nil || !isSystemContract(masterBlock, addr) {
+		return ""
+	}
+	method := make([]byte, 8)
+	binary.BigEndian.PutUint64(method, v.MethodID)
+	return string(block.ID.RootHash) + string(addr.Data()) + string(method) + string(v.Params.Hash())
+}
+
+func (g *systemGetters) get(key string) *emulate.RunResult {
+	if key == "" {
+		return nil
+	}
+	if v, ok := g.results.Get(key); ok {
+		return v.(*emulate.RunResult)
+	}
+	return nil
+}
+
+func (g *systemGetters) add(key string, res *emulate.RunResult) {
+	g.results.Add(key, res)
+}
+
+// isSystemContract reports if account is config or elector contract by config of master block
+func isSystemContract(masterBlock *MasterBlock, addr *address.Address) bool {
+	if masterBlock.Config == nil {
+		return false
+	}
+
+	for _, param := range []int64{configParamConfigAddr, configParamElectorAddr} {
+		v := masterBlock.Config.GetByIntKey(big.NewInt(param))
+		if v == nil {
+			continue
+		}
+
+		ref, err := v.BeginParse().LoadRef()
+		if err != nil {
+			continue
+		}
+		data, err := ref.LoadSlice(256)
+		if err != nil {
+			continue
+		}
+		if bytes.Equal(data, addr.Data()) {
+			return true
+		}
+	}
+	return false
+}
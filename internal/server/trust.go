@@ -0,0 +1,202 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"sync"
+)
+
+// TrustVerifier establishes trust in master blocks like a light client does: starting from the trusted init block
+// it follows proof links, through key blocks with validator set changes, and checks validator signatures of each link
+type TrustVerifier struct {
+	balancer Balancer
+
+	trusted      *ton.BlockIDExt
+	lastKeyBlock *ton.BlockIDExt
+	mx           sync.Mutex
+}
+
+// NewTrustVerifier creates verifier, when init block is not configured the first verified block is trusted as is
+func NewTrustVerifier(balancer Balancer, init config.TrustedBlockConfig) (*TrustVerifier, error) {
+	v := &TrustVerifier{
+		balancer: balancer,
+	}
+
+	if len(init.RootHash) == 0 {
+		return v, nil
+	}
+
+	if init.Workchain != -1 {
+		return nil, fmt.Errorf("trusted block should be from masterchain")
+	}
+
+	if len(init.RootHash) != 32 || len(init.FileHash) != 32 {
+		return nil, fmt.Errorf("trusted block hashes should be 32 bytes")
+	}
+
+	v.trusted = &ton.BlockIDExt{
+		Workchain: init.Workchain,
+		Shard:     init.Shard,
+		SeqNo:     init.SeqNo,
+		RootHash:  init.RootHash,
+		FileHash:  init.FileHash,
+	}
+	v.lastKeyBlock = v.trusted
+	return v, nil
+}
+
+// Verify checks validator signatures on the way from the last trusted master block to the new one,
+// on success new block becomes trusted, so usually only one forward link is checked per block
+func (v *TrustVerifier) Verify(ctx context.Context, id *ton.BlockIDExt) error {
+	v.mx.Lock()
+	defer v.mx.Unlock()
+
+	from := v.trusted
+	if from == nil {
+		log.Warn().Uint32("seqno", id.SeqNo).Msg("no trusted master block configured, trusting the first fetched one")
+		v.trusted = id
+		return nil
+	}
+
+	if id.SeqNo <= from.SeqNo {
+		if id.SeqNo == from.SeqNo && !id.Equals(from) {
+			return fmt.Errorf("block is not equal to trusted block with the same seqno")
+		}
+		return nil
+	}
+
+	client := v.balancer.GetClient()
+	for !v.trusted.Equals(id) {
+		if v.trusted.SeqNo >= id.SeqNo {
+			return fmt.Errorf("proof chain passed target block")
+		}
+
+		// trust is advanced by each verified part, so long chains are not restarted after failures
+		next, keyBlock, err := verifyBlockProofPart(ctx, client, v.trusted, id)
+		if err != nil {
+			return err
+		}
+
+		if keyBlock != nil {
+			log.Info().Uint32("seqno", keyBlock.SeqNo).Msg("trusted key block advanced")
+			v.lastKeyBlock = keyBlock
+		}
+		v.trusted = next
+	}
+	return nil
+}
+
+// Trusted returns the last verified master block and the last verified key block
+func (v *TrustVerifier) Trusted() (block, keyBlock *ton.BlockIDExt) {
+	v.mx.Lock()
+	defer v.mx.Unlock()
+
+	return v.trusted, v.lastKeyBlock
+}
+
+// verifyBlockProofPart verifies one part of proof chain returned by backend,
+// the last block of the part and the last key block passed by it, if any, are returned
+func verifyBlockProofPart(ctx context.Context, client ton.LiteClient, from, to *ton.BlockIDExt) (*ton.BlockIDExt, *ton.BlockIDExt, error) {
+	part, err := getBlockProof(ctx, client, from, to)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get block proof from %d to %d: %w", from.SeqNo, to.SeqNo, err)
+	}
+
+	if !part.From.Equals(from) {
+		return nil, nil, fmt.Errorf("unexpected from block %d in proof, want %d", part.From.SeqNo, from.SeqNo)
+	}
+
+	var keyBlock *ton.BlockIDExt
+	cur := from
+	for _, step := range part.Steps {
+		switch s := step.(type) {
+		case ton.BlockLinkForward:
+			if !s.From.Equals(cur) {
+				return nil, nil, fmt.Errorf("broken proof chain at block %d", cur.SeqNo)
+			}
+
+			destProof, err := cell.FromBOC(s.DestProof)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to parse dest proof: %w", err)
+			}
+
+			configProof, err := cell.FromBOC(s.ConfigProof)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to parse config proof: %w", err)
+			}
+
+			if s.SignatureSet == nil {
+				return nil, nil, fmt.Errorf("no signatures in forward link to %d", s.To.SeqNo)
+			}
+
+			if err = ton.CheckForwardBlockProof(s.From, s.To, s.ToKeyBlock, configProof, destProof, s.SignatureSet); err != nil {
+				return nil, nil, fmt.Errorf("invalid forward link from %d to %d: %w", s.From.SeqNo, s.To.SeqNo, err)
+			}
+			if s.ToKeyBlock {
+				keyBlock = s.To
+			}
+			cur = s.To
+		case ton.BlockLinkBackward:
+			// link back to the key block with validator set for the next forward links
+			if !s.From.Equals(cur) {
+				return nil, nil, fmt.Errorf("broken proof chain at block %d", cur.SeqNo)
+			}
+
+			destProof, err := cell.FromBOC(s.DestProof)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to parse dest proof: %w", err)
+			}
+
+			stateProof, err := cell.FromBOC(s.StateProof)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to parse state proof: %w", err)
+			}
+
+			proof, err := cell.FromBOC(s.Proof)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to parse proof: %w", err)
+			}
+
+			if err = ton.CheckBackwardBlockProof(s.From, s.To, s.ToKeyBlock, stateProof, destProof, proof); err != nil {
+				return nil, nil, fmt.Errorf("invalid backward link from %d to %d: %w", s.From.SeqNo, s.To.SeqNo, err)
+			}
+			cur = s.To
+		default:
+			return nil, nil, fmt.Errorf("unexpected proof link type %T", step)
+		}
+	}
+
+	if !cur.Equals(part.To) {
+		return nil, nil, fmt.Errorf("proof chain not ends at declared block %d", part.To.SeqNo)
+	}
+
+	if cur.SeqNo <= from.SeqNo {
+		return nil, nil, fmt.Errorf("proof chain is not advancing from %d", from.SeqNo)
+	}
+	return cur, keyBlock, nil
+}
+
+func getBlockProof(ctx context.Context, client ton.LiteClient, known, target *ton.BlockIDExt) (*ton.PartialBlockProof, error) {
+	var resp tl.Serializable
+	err := client.QueryLiteserver(ctx, ton.GetBlockProof{
+		Mode:        1,
+		KnownBlock:  known,
+		TargetBlock: target,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := resp.(type) {
+	case ton.PartialBlockProof:
+		return &t, nil
+	case ton.LSError:
+		return nil, t
+	}
+	return nil, fmt.Errorf("unexpected response")
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"testing"
+)
+
+// proofBalancer gives client which answers block proof queries with unexpected answer
+type proofBalancer struct {
+	Balancer
+}
+
+func (b *proofBalancer) GetClient() ton.LiteClient {
+	return &answerClient{}
+}
+
+func trustBlock(seqno uint32, hash byte) *ton.BlockIDExt {
+	root := make([]byte, 32)
+	root[0] = hash
+	return &ton.BlockIDExt{Workchain: -1, Shard: -0x8000000000000000, SeqNo: seqno, RootHash: root, FileHash: make([]byte, 32)}
+}
+
+func TestNewTrustVerifier(t *testing.T) {
+	tests := []struct {
+		name        string
+		init        config.TrustedBlockConfig
+		wantErr     bool
+		wantTrusted bool
+	}{
+		{name: "not configured", init: config.TrustedBlockConfig{}},
+		{name: "configured", init: config.TrustedBlockConfig{Workchain: -1, SeqNo: 5, RootHash: make([]byte, 32), FileHash: make([]byte, 32)}, wantTrusted: true},
+		{name: "not masterchain", init: config.TrustedBlockConfig{Workchain: 0, RootHash: make([]byte, 32), FileHash: make([]byte, 32)}, wantErr: true},
+		{name: "short hash", init: config.TrustedBlockConfig{Workchain: -1, RootHash: make([]byte, 32), FileHash: make([]byte, 31)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewTrustVerifier(&proofBalancer{}, tt.init)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			block, keyBlock := v.Trusted()
+			if (block != nil) != tt.wantTrusted || (keyBlock != nil) != tt.wantTrusted {
+				t.Fatalf("expected trusted %v, got %v and key block %v", tt.wantTrusted, block, keyBlock)
+			}
+			if block != nil && block.SeqNo != tt.init.SeqNo {
+				t.Fatalf("expected trusted seqno %d, got %d", tt.init.SeqNo, block.SeqNo)
+			}
+		})
+	}
+}
+
+func TestTrustOnFirstUse(t *testing.T) {
+	first := trustBlock(10, 1)
+
+	tests := []struct {
+		name    string
+		block   *ton.BlockIDExt
+		wantErr bool
+	}{
+		{name: "the same block", block: trustBlock(10, 1)},
+		{name: "older block is not checked", block: trustBlock(9, 2)},
+		{name: "fork of trusted block", block: trustBlock(10, 2), wantErr: true},
+		// newer block needs proof chain, backend does not give it
+		{name: "newer block without proof", block: trustBlock(11, 1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewTrustVerifier(&proofBalancer{}, config.TrustedBlockConfig{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// without configured init block the first one is trusted as is
+			if err = v.Verify(context.Background(), first); err != nil {
+				t.Fatal(err)
+			}
+			if block, keyBlock := v.Trusted(); block != first || keyBlock != nil {
+				t.Fatalf("expected the first block to be trusted without key block, got %v and %v", block, keyBlock)
+			}
+
+			if err = v.Verify(context.Background(), tt.block); (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if block, _ := v.Trusted(); block != first {
+				t.Fatalf("trusted block is expected to stay at the first one, got %v", block)
+			}
+		})
+	}
+}
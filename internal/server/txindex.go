@@ -0,0 +1,206 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"sort"
+	"sync"
+)
+
+// TxIndex keeps recent transactions of each account from ingested blocks, ordered by lt,
+// so getTransactions for the indexed window is answered without backends
+type TxIndex struct {
+	accounts map[string][]*indexedTx
+	// accounts of transactions in order of indexing, to evict the oldest ones
+	order    []string
+	maxTotal int
+
+	mx sync.RWMutex
+}
+
+type indexedTx struct {
+	lt       uint64
+	hash     []byte
+	prevLt   uint64
+	prevHash []byte
+	block    *ton.BlockIDExt
+	cell     *cell.Cell
+}
+
+func NewTxIndex(maxTransactions int) *TxIndex {
+	return &TxIndex{
+		accounts: map[string][]*indexedTx{},
+		maxTotal: maxTransactions,
+	}
+}
+
+func txIndexKey(wc int32, addr []byte) string {
+	return fmt.Sprintf("%d:%x", wc, addr)
+}
+
+func (idx *TxIndex) add(wc int32, addr []byte, tx *indexedTx) {
+	key := txIndexKey(wc, addr)
+
+	idx.mx.Lock()
+	defer idx.mx.Unlock()
+
+	list := idx.accounts[key]
+	i := sort.Search(len(list), func(i int) bool {
+		return list[i].lt >= tx.lt
+	})
+	if i < len(list) && list[i].lt == tx.lt {
+		return
+	}
+	list = append(list, nil)
+	copy(list[i+1:], list[i:])
+	list[i] = tx
+	idx.accounts[key] = list
+	idx.order = append(idx.order, key)
+
+	for len(idx.order) > idx.maxTotal {
+		old := idx.order[0]
+		idx.order = idx.order[1:]
+
+		// transactions are indexed mostly in lt order, so the first one of account is the oldest
+		if list := idx.accounts[old]; len(list) > 1 {
+			idx.accounts[old] = list[1:]
+		} else {
+			delete(idx.accounts, old)
+		}
+	}
+}
+
+// Get returns up to limit transactions of account starting from lt and hash and going to older ones,
+// nil is returned when chain is not fully indexed
+func (idx *TxIndex) Get(acc *ton.AccountID, lt uint64, hash []byte, limit int) *ton.TransactionList {
+	idx.mx.RLock()
+	defer idx.mx.RUnlock()
+
+	list := idx.accounts[txIndexKey(acc.Workchain, acc.ID)]
+	i := sort.Search(len(list), func(i int) bool {
+		return list[i].lt >= lt
+	})
+	if i >= len(list) || list[i].lt != lt || !bytes.Equal(list[i].hash, hash) {
+		return nil
+	}
+
+	res := &ton.TransactionList{}
+	var cells []*cell.Cell
+	for {
+		tx := list[i]
+		res.IDs = append(res.IDs, tx.block)
+		cells = append(cells, tx.cell)
+		if len(cells) >= limit || tx.prevLt == 0 {
+			// limit is reached or it is the first transaction of account
+			break
+		}
+
+		if i == 0 || list[i-1].lt != tx.prevLt || !bytes.Equal(list[i-1].hash, tx.prevHash) {
+			// previous transaction is older than indexed window
+			return nil
+		}
+		i--
+	}
+	res.Transactions = cell.ToBOCWithFlags(cells, false)
+	return res
+}
+
+// EnableTxIndex makes cache to index transactions of every new block, to answer getTransactions locally
+func (c *BlockCache) EnableTxIndex(idx *TxIndex) {
+	c.txIndex = idx
+	c.addBlockConsumer(c.indexTransactions)
+}
+
+func (c *BlockCache) indexTransactions(blk *Block, _ *tlb.BlockHeader) {
+	var block tlb.Block
+	if err := tlb.LoadFromCell(&block, blk.Data.BeginParse()); err != nil {
+		log.Debug().Err(err).Uint32("seqno", blk.ID.SeqNo).Msg("failed to parse block for transactions index")
+		return
+	}
+	if block.Extra == nil || block.Extra.ShardAccountBlocks == nil {
+		return
+	}
+
+	var shardAccounts tlb.ShardAccountBlocks
+	if err := tlb.LoadFromCell(&shardAccounts, block.Extra.ShardAccountBlocks.BeginParse()); err != nil || shardAccounts.Accounts.IsEmpty() {
+		return
+	}
+
+	accounts, err := shardAccounts.Accounts.LoadAll()
+	if err != nil {
+		return
+	}
+
+	num := 0
+	for _, kv := range accounts {
+		if err = tlb.LoadFromCell(new(tlb.CurrencyCollection), kv.Value); err != nil {
+			continue
+		}
+
+		var accBlock tlb.AccountBlock
+		if err = tlb.LoadFromCell(&accBlock, kv.Value); err != nil || accBlock.Transactions.IsEmpty() {
+			continue
+		}
+
+		txs, err := accBlock.Transactions.LoadAll()
+		if err != nil {
+			continue
+		}
+
+		for _, txKV := range txs {
+			if err = tlb.LoadFromCell(new(tlb.CurrencyCollection), txKV.Value); err != nil {
+				continue
+			}
+			txCell, err := txKV.Value.LoadRefCell()
+			if err != nil {
+				continue
+			}
+
+			tx, err := parseIndexedTx(txCell)
+			if err != nil {
+				continue
+			}
+			tx.block = blk.ID
+			c.txIndex.add(blk.ID.Workchain, accBlock.Addr, tx)
+			num++
+		}
+	}
+	metrics.Global.IndexedTransactions.WithLabelValues(fmt.Sprint(blk.ID.Workchain)).Add(float64(num))
+}
+
+// parseIndexedTx loads only the beginning of transaction, which links it to the previous one
+func parseIndexedTx(c *cell.Cell) (*indexedTx, error) {
+	s := c.BeginParse()
+	if magic, err := s.LoadUInt(4); err != nil || magic != 0b0111 {
+		return nil, fmt.Errorf("not a transaction")
+	}
+	if _, err := s.LoadSlice(256); err != nil {
+		return nil, err
+	}
+
+	lt, err := s.LoadUInt(64)
+	if err != nil {
+		return nil, err
+	}
+	prevHash, err := s.LoadSlice(256)
+	if err != nil {
+		return nil, err
+	}
+	prevLt, err := s.LoadUInt(64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &indexedTx{
+		lt:       lt,
+		hash:     c.Hash(),
+		prevLt:   prevLt,
+		prevHash: prevHash,
+		cell:     c,
+	}, nil
+}
@@ -0,0 +1,275 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"golang.org/x/net/websocket"
+	"sync"
+	"time"
+)
+
+// PushedBlock is sent by upstream proxy to downstream ones for every new master block, together with
+// shard blocks committed by it. Config is sent only in the first message of connection,
+// downstream takes config of the next blocks from previous ones, the same as for polled blocks.
+type PushedBlock struct {
+	ID     *ton.BlockIDExt    `json:"id"`
+	Data   []byte             `json:"data"`
+	Config []byte             `json:"config,omitempty"`
+	Shards []PushedShardBlock `json:"shards,omitempty"`
+}
+
+type PushedShardBlock struct {
+	ID   *ton.BlockIDExt `json:"id"`
+	Data []byte          `json:"data"`
+}
+
+func pushedConfigKey(id *ton.BlockIDExt) string {
+	return "config:" + blockStoreKey(id)
+}
+
+// handleDownstream pushes new master blocks with their data to downstream proxy, the latest block is sent right away
+func (a *SubscriptionsAPI) handleDownstream(ws *websocket.Conn) {
+	defer ws.Close()
+
+	events, unsubscribe := a.cache.SubscribeBlocks(64)
+	defer unsubscribe()
+
+	metrics.Global.Subscriptions.WithLabelValues("downstream").Add(1)
+	defer metrics.Global.Subscriptions.WithLabelValues("downstream").Sub(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	master, _, err := a.cache.GetLastMasterBlock(ctx)
+	if err == nil {
+		err = a.pushBlock(ctx, ws, master, nil, true)
+	}
+	cancel()
+	if err != nil {
+		log.Debug().Err(err).Str("addr", ws.Request().RemoteAddr).Msg("failed to push the latest block to downstream")
+		return
+	}
+
+	closed := watchClose(ws)
+	for {
+		select {
+		case <-closed:
+			return
+		case ev, ok := <-events:
+			if !ok {
+				log.Debug().Str("addr", ws.Request().RemoteAddr).Msg("downstream proxy is too slow, disconnecting")
+				return
+			}
+
+			ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+			master, _, err = a.cache.GetMasterBlock(ctx, ev.Master)
+			if err == nil {
+				err = a.pushBlock(ctx, ws, master, ev.Shards, false)
+			}
+			cancel()
+			if err != nil {
+				log.Debug().Err(err).Str("addr", ws.Request().RemoteAddr).Msg("failed to push block to downstream")
+				return
+			}
+		}
+	}
+}
+
+func (a *SubscriptionsAPI) pushBlock(ctx context.Context, ws *websocket.Conn, master *MasterBlock, shards []*ton.BlockIDExt, withConfig bool) error {
+	master.mx.RLock()
+	msg := &PushedBlock{
+		ID:   master.Block.ID,
+		Data: master.Block.Data.ToBOCWithFlags(false),
+	}
+	if withConfig && master.Config != nil {
+		msg.Config = master.Config.AsCell().ToBOCWithFlags(false)
+	}
+	master.mx.RUnlock()
+
+	for _, shard := range shards {
+		blk, _, err := a.cache.CacheBlockIfNeeded(ctx, shard)
+		if err != nil {
+			// downstream fetches it by itself
+			log.Debug().Err(err).Uint32("seqno", shard.SeqNo).Msg("shard block is not pushed to downstream")
+			continue
+		}
+		msg.Shards = append(msg.Shards, PushedShardBlock{
+			ID:   shard,
+			Data: blk.Data.ToBOCWithFlags(false),
+		})
+	}
+
+	if err := sendNotification(ws, msg); err != nil {
+		metrics.Global.DownstreamBlocks.WithLabelValues("out", "failed").Add(1)
+		return err
+	}
+	metrics.Global.DownstreamBlocks.WithLabelValues("out", "ok").Add(1)
+	return nil
+}
+
+// Upstream receives new blocks from upstream proxy over persistent websocket, they are applied
+// without queries to backends. Upstream is trusted the same as backends, block data is checked
+// by hash, but ids are verified only when signature verification is enabled.
+type Upstream struct {
+	cache   *BlockCache
+	url     string
+	token   string
+	timeout time.Duration
+
+	conn   *websocket.Conn
+	mx     sync.Mutex
+	closed chan struct{}
+}
+
+// EnableUpstream connects to upstream proxy, master blocks are polled from backends only while it is silent
+func (c *BlockCache) EnableUpstream(cfg config.UpstreamConfig) {
+	u := &Upstream{
+		cache:   c,
+		url:     cfg.URL,
+		token:   cfg.Token,
+		timeout: time.Duration(cfg.TimeoutSeconds) * time.Second,
+		closed:  make(chan struct{}),
+	}
+	if u.timeout == 0 {
+		u.timeout = 15 * time.Second
+	}
+	c.upstream.Store(u)
+
+	go u.run()
+}
+
+func (u *Upstream) run() {
+	for {
+		err := u.session()
+
+		select {
+		case <-u.closed:
+			return
+		default:
+		}
+		log.Warn().Err(err).Str("url", u.url).Msg("upstream connection lost, we will reconnect in 3s")
+
+		select {
+		case <-u.closed:
+			return
+		case <-time.After(3 * time.Second):
+		}
+	}
+}
+
+func (u *Upstream) session() error {
+	cfg, err := websocket.NewConfig(u.url, "http://localhost/")
+	if err != nil {
+		return err
+	}
+	if u.token != "" {
+		cfg.Header.Set("Authorization", "Bearer "+u.token)
+	}
+
+	ws, err := websocket.DialConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer ws.Close()
+
+	u.mx.Lock()
+	select {
+	case <-u.closed:
+		u.mx.Unlock()
+		return nil
+	default:
+	}
+	u.conn = ws
+	u.mx.Unlock()
+
+	log.Info().Str("url", u.url).Msg("connected to upstream proxy")
+	for {
+		if err = ws.SetReadDeadline(time.Now().Add(u.timeout)); err != nil {
+			return err
+		}
+
+		var msg PushedBlock
+		if err = websocket.JSON.Receive(ws, &msg); err != nil {
+			return err
+		}
+
+		if err = u.apply(&msg); err != nil {
+			log.Warn().Err(err).Msg("failed to apply block pushed by upstream")
+			metrics.Global.DownstreamBlocks.WithLabelValues("in", "failed").Add(1)
+			continue
+		}
+		metrics.Global.DownstreamBlocks.WithLabelValues("in", "ok").Add(1)
+	}
+}
+
+func (u *Upstream) apply(msg *PushedBlock) error {
+	if msg.ID == nil || msg.ID.Workchain != -1 || len(msg.ID.RootHash) != 32 || len(msg.ID.FileHash) != 32 {
+		return fmt.Errorf("invalid master block id")
+	}
+
+	data, err := loadStoredCell(msg.Data, msg.ID.RootHash)
+	if err != nil {
+		return fmt.Errorf("invalid master block data: %w", err)
+	}
+	u.cache.pushed.Add(blockStoreKey(msg.ID), data)
+
+	if len(msg.Config) > 0 {
+		cfg, err := cell.FromBOC(msg.Config)
+		if err != nil {
+			return fmt.Errorf("invalid config: %w", err)
+		}
+		u.cache.pushed.Add(pushedConfigKey(msg.ID), cfg.AsDict(32))
+	}
+
+	var shards []*ton.BlockIDExt
+	for _, shard := range msg.Shards {
+		if shard.ID == nil || len(shard.ID.RootHash) != 32 {
+			continue
+		}
+		if data, err := loadStoredCell(shard.Data, shard.ID.RootHash); err == nil {
+			u.cache.pushed.Add(blockStoreKey(shard.ID), data)
+			shards = append(shards, shard.ID)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, _, err = u.cache.GetMasterBlock(ctx, msg.ID); err != nil {
+		return err
+	}
+	u.cache.balancer.ObserveMasterSeqno(msg.ID.SeqNo)
+
+	for _, shard := range shards {
+		// shard blocks are moved to memory while they are in pushed cache
+		_, _, _ = u.cache.CacheBlockIfNeeded(ctx, shard)
+	}
+	return nil
+}
+
+// awaitPush waits until master block seqno is pushed by upstream, false is returned when it was silent
+// for timeout, then block should be fetched from backends
+func (u *Upstream) awaitPush(seqno uint32) (uint32, bool) {
+	if err := u.cache.WaitMasterBlock(context.Background(), seqno, u.timeout); err != nil {
+		log.Debug().Uint32("seqno", seqno).Msg("no master block from upstream, fetching it from backends")
+		return 0, false
+	}
+
+	u.cache.mx.RLock()
+	last := u.cache.lastBlock.SeqNo
+	u.cache.mx.RUnlock()
+	return last, true
+}
+
+func (u *Upstream) close() {
+	u.mx.Lock()
+	defer u.mx.Unlock()
+
+	close(u.closed)
+	if u.conn != nil {
+		_ = u.conn.Close()
+	}
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"fmt"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+)
+
+// limits are the same as liteserver applies, larger values are not served by nodes anyway
+const (
+	maxQueryLibraries    = 16
+	maxQueryConfigParams = 256
+	maxQueryTransactions = 256
+	maxQueryTxHistory    = 16
+	maxQueryStackItems   = 255
+	maxQueryCellDepth    = 512
+	// queries wrapped by one wait master, they are processed one by one
+	maxWrappedQueries = 16
+)
+
+// QueryValidator checks decoded client queries before processing, so malformed
+// but parsable structures are rejected instead of reaching cache, emulator or backends
+type QueryValidator struct {
+	maxSize int
+}
+
+// NewQueryValidator creates validator, maxSize is maximum size of query in bytes as client sent it, 0 is unlimited
+func NewQueryValidator(maxSize uint32) *QueryValidator {
+	return &QueryValidator{
+		maxSize: int(maxSize),
+	}
+}
+
+// Validate checks query, size is its length as received from client, it is not measured again,
+// 0 is passed for queries of proxy itself
+func (v *QueryValidator) Validate(query any, size int) (err error) {
+	defer func() {
+		// validation is applied to untrusted data, so its own failures are treated as invalid query
+		if r := recover(); r != nil {
+			err = fmt.Errorf("malformed query")
+		}
+	}()
+
+	if v.maxSize > 0 && size > v.maxSize {
+		return fmt.Errorf("query is too big: %d bytes, max %d", size, v.maxSize)
+	}
+	return validateQuery(query, true)
+}
+
+func validateQuery(query any, root bool) error {
+	switch q := query.(type) {
+	case []tl.Serializable:
+		if !root || len(q) < 2 || len(q) > maxWrappedQueries+1 {
+			return fmt.Errorf("unexpected len of queries")
+		}
+
+		wt, ok := q[0].(ton.WaitMasterchainSeqno)
+		if !ok {
+			return fmt.Errorf("unexpected first query type")
+		}
+		if wt.Seqno < 0 || wt.Timeout < 0 {
+			return fmt.Errorf("invalid wait master params")
+		}
+		for _, wrapped := range q[1:] {
+			if err := validateQuery(wrapped, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	case ton.WaitMasterchainSeqno:
+		return fmt.Errorf("wait master without query")
+	case ton.GetLibraries:
+		if len(q.LibraryList) == 0 || len(q.LibraryList) > maxQueryLibraries {
+			return fmt.Errorf("libraries number should be from 1 to %d", maxQueryLibraries)
+		}
+		for _, h := range q.LibraryList {
+			if len(h) != 32 {
+				return fmt.Errorf("invalid library hash")
+			}
+		}
+	case ton.GetOneTransaction:
+		if q.AccID == nil {
+			return fmt.Errorf("no account")
+		}
+		return validateBlockID(q.ID)
+	case ton.GetTransactions:
+		if q.AccID == nil {
+			return fmt.Errorf("no account")
+		}
+		if q.Limit <= 0 || q.Limit > maxQueryTxHistory {
+			return fmt.Errorf("limit should be from 1 to %d", maxQueryTxHistory)
+		}
+	case ton.GetBlockData:
+		return validateBlockID(q.ID)
+	case ton.GetState:
+		return validateBlockID(q.ID)
+	case ton.GetBlockHeader:
+		if q.ID == nil {
+			return fmt.Errorf("no block")
+		}
+	case ton.LookupBlock:
+		if q.ID == nil {
+			return fmt.Errorf("no block")
+		}
+		if q.Mode&7 == 0 {
+			return fmt.Errorf("lookup mode is not set")
+		}
+	case ton.GetAccountState:
+		return validateBlockID(q.ID)
+	case ton.GetAccountStatePruned:
+		return validateBlockID(q.ID)
+	case ton.RunSmcMethod:
+		if err := validateBlockID(q.ID); err != nil {
+			return err
+		}
+		return validateStack(q.Params)
+	case ton.GetConfigAll:
+		return validateBlockID(q.BlockID)
+	case ton.GetConfigParams:
+		if len(q.Params) > maxQueryConfigParams {
+			return fmt.Errorf("too many config params, max %d", maxQueryConfigParams)
+		}
+		return validateBlockID(q.BlockID)
+	case ton.GetAllShardsInfo:
+		return validateBlockID(q.ID)
+	case ton.GetShardInfo:
+		return validateBlockID(q.ID)
+	case ton.GetShardBlockProof:
+		return validateBlockID(q.ID)
+	case ton.GetBlockProof:
+		if err := validateBlockID(q.KnownBlock); err != nil {
+			return err
+		}
+		if q.Mode&1 != 0 {
+			return validateBlockID(q.TargetBlock)
+		}
+	case ton.ListBlockTransactions:
+		if q.Count == 0 || q.Count > maxQueryTransactions {
+			return fmt.Errorf("count should be from 1 to %d", maxQueryTransactions)
+		}
+		return validateBlockID(q.ID)
+	case ton.ListBlockTransactionsExt:
+		if q.Count == 0 || q.Count > maxQueryTransactions {
+			return fmt.Errorf("count should be from 1 to %d", maxQueryTransactions)
+		}
+		return validateBlockID(q.ID)
+	case ton.SendMessage:
+		if len(q.Body) == 0 {
+			return fmt.Errorf("empty message")
+		}
+	}
+	return nil
+}
+
+func validateBlockID(id *ton.BlockIDExt) error {
+	if id == nil {
+		return fmt.Errorf("no block")
+	}
+	if len(id.RootHash) != 32 || len(id.FileHash) != 32 {
+		return fmt.Errorf("invalid block hashes")
+	}
+	return nil
+}
+
+func validateStack(params *cell.Cell) error {
+	if params == nil {
+		return fmt.Errorf("no stack")
+	}
+
+	if params.Depth() > maxQueryCellDepth {
+		return fmt.Errorf("stack is too deep")
+	}
+
+	var stack tlb.Stack
+	if err := stack.LoadFromCell(params.BeginParse()); err != nil {
+		return fmt.Errorf("invalid stack: %w", err)
+	}
+
+	if stack.Depth() > maxQueryStackItems {
+		return fmt.Errorf("too many stack items, max %d", maxQueryStackItems)
+	}
+	return nil
+}
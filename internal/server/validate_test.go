@@ -0,0 +1,60 @@
+package server
+
+import (
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+	"testing"
+)
+
+func TestValidateQuery(t *testing.T) {
+	blk := &ton.BlockIDExt{Workchain: -1, RootHash: make([]byte, 32), FileHash: make([]byte, 32)}
+	acc := ton.AccountID{ID: make([]byte, 32)}
+
+	stack, err := tlb.NewStack().ToCell()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		query   any
+		size    int
+		wantErr bool
+	}{
+		{name: "time", query: ton.GetTime{}},
+		{name: "account state", query: ton.GetAccountState{ID: blk, Account: acc}},
+		{name: "run method", query: ton.RunSmcMethod{ID: blk, Account: acc, Params: stack}},
+		{name: "wait master with queries", query: []tl.Serializable{ton.WaitMasterchainSeqno{Seqno: 1}, ton.GetTime{}, ton.GetVersion{}}},
+		{name: "size at limit", query: ton.GetTime{}, size: 64},
+		{name: "unknown size is not checked", query: ton.GetTime{}, size: 0},
+		{name: "too big", query: ton.GetTime{}, size: 65, wantErr: true},
+		{name: "no block", query: ton.GetBlockData{}, wantErr: true},
+		{name: "short block hash", query: ton.GetBlockData{ID: &ton.BlockIDExt{RootHash: make([]byte, 31), FileHash: make([]byte, 32)}}, wantErr: true},
+		{name: "run method without stack", query: ton.RunSmcMethod{ID: blk, Account: acc}, wantErr: true},
+		{name: "run method with invalid stack", query: ton.RunSmcMethod{ID: blk, Account: acc, Params: cell.BeginCell().MustStoreUInt(1, 8).EndCell()}, wantErr: true},
+		{name: "no libraries", query: ton.GetLibraries{}, wantErr: true},
+		{name: "invalid library hash", query: ton.GetLibraries{LibraryList: [][]byte{make([]byte, 31)}}, wantErr: true},
+		{name: "transactions over limit", query: ton.GetTransactions{AccID: &acc, Limit: maxQueryTxHistory + 1}, wantErr: true},
+		{name: "lookup without mode", query: ton.LookupBlock{ID: &ton.BlockInfoShort{}}, wantErr: true},
+		{name: "block proof without target", query: ton.GetBlockProof{Mode: 1, KnownBlock: blk}, wantErr: true},
+		{name: "list transactions without count", query: ton.ListBlockTransactions{ID: blk}, wantErr: true},
+		{name: "empty message", query: ton.SendMessage{}, wantErr: true},
+		{name: "wait master alone", query: ton.WaitMasterchainSeqno{}, wantErr: true},
+		{name: "wait master without query", query: []tl.Serializable{ton.WaitMasterchainSeqno{}}, wantErr: true},
+		{name: "query instead of wait master", query: []tl.Serializable{ton.GetTime{}, ton.GetTime{}}, wantErr: true},
+		{name: "negative wait", query: []tl.Serializable{ton.WaitMasterchainSeqno{Seqno: -1}, ton.GetTime{}}, wantErr: true},
+		{name: "nested wait master", query: []tl.Serializable{ton.WaitMasterchainSeqno{}, []tl.Serializable{ton.WaitMasterchainSeqno{}, ton.GetTime{}}}, wantErr: true},
+		{name: "invalid wrapped query", query: []tl.Serializable{ton.WaitMasterchainSeqno{}, ton.GetBlockData{}}, wantErr: true},
+	}
+
+	v := NewQueryValidator(64)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := v.Validate(tt.query, tt.size); (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
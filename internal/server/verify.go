@@ -0,0 +1,244 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+	"math/bits"
+)
+
+// verifyAccountState checks merkle proofs of account state against the root hash of requested block,
+// when block is master and account is not, shard block is checked to be in master first
+func verifyAccountState(id *ton.BlockIDExt, addr *address.Address, acc *ton.AccountState) error {
+	var shardHash []byte
+	if id.Workchain == address.MasterchainID && addr.Workchain() != address.MasterchainID {
+		if len(acc.ShardProof) == 0 || acc.Shard == nil || len(acc.Shard.RootHash) != 32 {
+			return fmt.Errorf("no shard proof")
+		}
+		shardHash = acc.Shard.RootHash
+	}
+
+	if acc.State == nil {
+		// absence is proven too, otherwise backend could make account disappear for all clients
+		return verifyAccountAbsent(id, addr, acc, shardHash)
+	}
+
+	shardAcc, balance, err := ton.CheckAccountStateProof(addr, id, acc.Proof, acc.ShardProof, shardHash, false)
+	if err != nil {
+		return fmt.Errorf("incorrect account state proof: %w", err)
+	}
+
+	if !bytes.Equal(shardAcc.Account.Hash(0), acc.State.Hash()) {
+		return fmt.Errorf("proof hash not match state hash")
+	}
+
+	var st tlb.AccountState
+	if err = st.LoadFromCell(acc.State.BeginParse()); err != nil {
+		return fmt.Errorf("failed to load account state: %w", err)
+	}
+
+	if st.IsValid && st.Balance.Nano().Cmp(balance.Currencies.Coins.Nano()) != 0 {
+		return fmt.Errorf("proof balance not match state balance")
+	}
+	return nil
+}
+
+// verifyAccountAbsent checks that proof of shard state of the block has no account. Pruned branches are skipped
+// by tlb when proof is parsed, so accounts dictionary is walked here and pruned cell on the path is an error
+func verifyAccountAbsent(id *ton.BlockIDExt, addr *address.Address, acc *ton.AccountState, shardHash []byte) error {
+	blockHash := id.RootHash
+	if len(shardHash) > 0 {
+		if err := ton.CheckShardInMasterProof(id, acc.ShardProof, addr.Workchain(), shardHash); err != nil {
+			return fmt.Errorf("incorrect shard proof: %w", err)
+		}
+		blockHash = shardHash
+	}
+
+	if len(acc.Proof) != 2 {
+		return fmt.Errorf("proof should have 2 roots")
+	}
+
+	block, err := ton.CheckBlockProof(acc.Proof[1], blockHash)
+	if err != nil {
+		return fmt.Errorf("incorrect block proof: %w", err)
+	}
+	if block.StateUpdate == nil {
+		return fmt.Errorf("block proof without state update")
+	}
+	upd, err := block.StateUpdate.PeekRef(1)
+	if err != nil {
+		return fmt.Errorf("failed to load state update ref: %w", err)
+	}
+
+	state, err := cell.UnwrapProof(acc.Proof[0], upd.Hash(0))
+	if err != nil {
+		return fmt.Errorf("incorrect shard state proof: %w", err)
+	}
+
+	// accounts are the second ref of ShardStateUnsplit
+	accounts, err := state.PeekRef(1)
+	if err != nil {
+		return fmt.Errorf("no accounts in shard state proof: %w", err)
+	}
+	if accounts.GetType() == cell.PrunedCellType {
+		return fmt.Errorf("accounts are pruned in shard state proof")
+	}
+
+	// HashmapAugE, empty dictionary has no root
+	s := accounts.BeginParse()
+	hasRoot, err := s.LoadBoolBit()
+	if err != nil {
+		return fmt.Errorf("failed to load accounts: %w", err)
+	}
+	if !hasRoot {
+		return nil
+	}
+	root, err := s.LoadRefCell()
+	if err != nil {
+		return fmt.Errorf("failed to load accounts root: %w", err)
+	}
+
+	if err = checkDictKeyAbsent(root, addr.Data(), 256); err != nil {
+		return fmt.Errorf("account absence is not proven: %w", err)
+	}
+	return nil
+}
+
+// checkDictKeyAbsent walks dictionary by key, absence is proven when label on the path differs from key
+func checkDictKeyAbsent(branch *cell.Cell, key []byte, keySz uint) error {
+	k := cell.BeginCell().MustStoreSlice(key, keySz).EndCell().BeginParse()
+	for {
+		if branch.GetType() == cell.PrunedCellType {
+			return fmt.Errorf("key path is pruned")
+		}
+
+		s := branch.BeginParse()
+		n, label, err := loadDictLabel(s, k.BitsLeft())
+		if err != nil {
+			return err
+		}
+
+		pfx, err := k.LoadSlice(n)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(pfx, label) {
+			return nil
+		}
+		if k.BitsLeft() == 0 {
+			return fmt.Errorf("key is present")
+		}
+
+		idx, err := k.LoadUInt(1)
+		if err != nil {
+			return err
+		}
+		if branch, err = branch.PeekRef(int(idx)); err != nil {
+			return err
+		}
+	}
+}
+
+// loadDictLabel loads label of dictionary node, max is number of key bits left
+func loadDictLabel(s *cell.Slice, max uint) (uint, []byte, error) {
+	short, err := s.LoadBoolBit()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if !short {
+		// hml_short$0, length in unary
+		var n uint
+		for {
+			bit, err := s.LoadBoolBit()
+			if err != nil {
+				return 0, nil, err
+			}
+			if !bit {
+				break
+			}
+			n++
+		}
+		label, err := s.LoadSlice(n)
+		return n, label, err
+	}
+
+	same, err := s.LoadBoolBit()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	lenBits := uint(bits.Len(max))
+	if !same {
+		// hml_long$10
+		n, err := s.LoadUInt(lenBits)
+		if err != nil {
+			return 0, nil, err
+		}
+		label, err := s.LoadSlice(uint(n))
+		return uint(n), label, err
+	}
+
+	// hml_same$11, n bits of v
+	v, err := s.LoadUInt(1)
+	if err != nil {
+		return 0, nil, err
+	}
+	n, err := s.LoadUInt(lenBits)
+	if err != nil {
+		return 0, nil, err
+	}
+	if n > uint64(max) {
+		return 0, nil, fmt.Errorf("too long label")
+	}
+
+	b := cell.BeginCell()
+	for i := uint64(0); i < n; i++ {
+		b.MustStoreUInt(v, 1)
+	}
+	label, err := b.EndCell().BeginParse().LoadSlice(uint(n))
+	return uint(n), label, err
+}
+
+// verifyTransaction checks that transaction is in the block using block proof
+func verifyTransaction(id *ton.BlockIDExt, acc *ton.AccountID, lt int64, tx *ton.TransactionInfo) error {
+	txCell, err := cell.FromBOC(tx.Transaction)
+	if err != nil {
+		return fmt.Errorf("failed to parse transaction: %w", err)
+	}
+
+	proof, err := cell.FromBOC(tx.Proof)
+	if err != nil {
+		return fmt.Errorf("failed to parse proof: %w", err)
+	}
+
+	block, err := ton.CheckBlockProof(proof, id.RootHash)
+	if err != nil {
+		return fmt.Errorf("incorrect block proof: %w", err)
+	}
+
+	if block.Extra == nil || block.Extra.ShardAccountBlocks == nil {
+		return fmt.Errorf("block proof without shard accounts")
+	}
+
+	var shardAccounts tlb.ShardAccountBlocks
+	if err = tlb.LoadFromCellAsProof(&shardAccounts, block.Extra.ShardAccountBlocks.BeginParse()); err != nil {
+		return fmt.Errorf("failed to load shard accounts from proof: %w", err)
+	}
+
+	if err = ton.CheckTransactionProof(txCell.Hash(), uint64(lt), acc.ID, &shardAccounts); err != nil {
+		return fmt.Errorf("incorrect transaction proof: %w", err)
+	}
+	return nil
+}
+
+// verifyShardProof checks that shard block is in the master block
+func verifyShardProof(masterID, shard *ton.BlockIDExt, proof []*cell.Cell) error {
+	if err := ton.CheckShardInMasterProof(masterID, proof, shard.Workchain, shard.RootHash); err != nil {
+		return fmt.Errorf("incorrect shard proof: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+	"testing"
+)
+
+// testMasterBlock is master block with shard state which has accounts, with proofs built the same way as liteserver does
+type testMasterBlock struct {
+	id    *ton.BlockIDExt
+	block *cell.Cell
+	state *cell.Cell
+}
+
+func testAccountKey(b byte) []byte {
+	key := make([]byte, 32)
+	key[0] = b
+	return key
+}
+
+// account_none$0, enough for proofs, balance is not checked for it
+func testAccountCell(b byte) *cell.Cell {
+	return cell.BeginCell().MustStoreUInt(0, 1).MustStoreUInt(uint64(b), 8).EndCell()
+}
+
+// cells without refs are never pruned in proofs, so parts of block which are not parsed have a ref
+func testOpaqueCell(b byte) *cell.Cell {
+	return cell.BeginCell().MustStoreUInt(uint64(b), 8).MustStoreRef(cell.BeginCell().EndCell()).EndCell()
+}
+
+func newTestMasterBlock(t *testing.T, accounts ...byte) *testMasterBlock {
+	dict := cell.NewDict(256)
+	for _, b := range accounts {
+		// DepthBalanceInfo and ShardAccount
+		value := cell.BeginCell().
+			MustStoreUInt(0, 5).MustStoreCoins(0).MustStoreUInt(0, 1).
+			MustStoreRef(testAccountCell(b)).MustStoreSlice(make([]byte, 32), 256).MustStoreUInt(0, 64).
+			EndCell()
+		if err := dict.Set(cell.BeginCell().MustStoreSlice(testAccountKey(b), 256).EndCell(), value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	empty := cell.BeginCell().EndCell()
+	state := cell.BeginCell().
+		MustStoreUInt(0x9023afe2, 32).MustStoreInt(-239, 32).
+		MustStoreUInt(0, 2).MustStoreUInt(0, 6).MustStoreInt(-1, 32).MustStoreUInt(1<<63, 64).
+		MustStoreUInt(1, 32).MustStoreUInt(0, 32).MustStoreUInt(0, 32).MustStoreUInt(0, 64).MustStoreUInt(0, 32).
+		MustStoreRef(empty).MustStoreBoolBit(false).
+		MustStoreRef(cell.BeginCell().MustStoreDict(dict).EndCell()).
+		MustStoreRef(empty).MustStoreMaybeRef(nil).
+		EndCell()
+
+	update := cell.BeginCell().MustStoreRef(cell.BeginCell().MustStoreUInt(1, 8).EndCell()).MustStoreRef(state).EndCell()
+	block := cell.BeginCell().
+		MustStoreUInt(0x11ef55aa, 32).MustStoreInt(-239, 32).
+		MustStoreRef(testOpaqueCell(2)).
+		MustStoreRef(testOpaqueCell(3)).
+		MustStoreRef(update).
+		MustStoreRef(testOpaqueCell(4)).
+		EndCell()
+
+	return &testMasterBlock{
+		id:    &ton.BlockIDExt{Workchain: -1, Shard: -0x8000000000000000, SeqNo: 1, RootHash: block.Hash(), FileHash: make([]byte, 32)},
+		block: block,
+		state: state,
+	}
+}
+
+// proof includes state update of block and accounts of state up to depth of dictionary, all of them when depth is -1
+func (b *testMasterBlock) proof(t *testing.T, depth int) []*cell.Cell {
+	blockSk := cell.CreateProofSkeleton()
+	blockSk.ProofRef(2)
+	blockProof, err := b.block.CreateProof(blockSk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stateSk := cell.CreateProofSkeleton()
+	if depth != 0 {
+		sk := stateSk.ProofRef(1)
+		if depth < 0 {
+			sk.SetRecursive()
+		}
+		for i := 1; i < depth; i++ {
+			sk = sk.ProofRef(0)
+		}
+	}
+	stateProof, err := b.state.CreateProof(stateSk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []*cell.Cell{stateProof, blockProof}
+}
+
+func TestVerifyAccountState(t *testing.T) {
+	blk := newTestMasterBlock(t, 1, 2, 3)
+	other := newTestMasterBlock(t, 1, 3)
+
+	tests := []struct {
+		name    string
+		account byte
+		state   *cell.Cell
+		proof   []*cell.Cell
+		valid   bool
+	}{
+		{name: "present", account: 2, state: testAccountCell(2), proof: blk.proof(t, -1), valid: true},
+		{name: "absent", account: 5, proof: blk.proof(t, -1), valid: true},
+		{name: "forged present state", account: 2, state: testAccountCell(9), proof: blk.proof(t, -1)},
+		{name: "forged present account", account: 5, state: testAccountCell(5), proof: blk.proof(t, -1)},
+		{name: "forged absent, account is in proof", account: 2, proof: blk.proof(t, -1)},
+		{name: "forged absent, accounts are pruned", account: 2, proof: blk.proof(t, 0)},
+		{name: "forged absent, key path is pruned", account: 2, proof: blk.proof(t, 2)},
+		{name: "forged absent, proof of other block", account: 2, proof: other.proof(t, -1)},
+		{name: "absent without proof", account: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := address.NewAddress(0, 255, testAccountKey(tt.account))
+			err := verifyAccountState(blk.id, addr, &ton.AccountState{ID: blk.id, Proof: tt.proof, State: tt.state})
+			if (err == nil) != tt.valid {
+				t.Fatalf("expected valid %v, got %v", tt.valid, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"bytes"
+	"encoding/hex"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-liteserver-proxy/internal/emulate"
+	"math/big"
+)
+
+var seqnoMethodID = tlb.MethodNameHash("seqno")
+
+// bit offsets of seqno in data of standard wallets by hash of their code,
+// seqno method of them just reads it, so there is no need to run tvm
+var walletSeqnoOffsets = map[string]uint{}
+
+// hash of serialized empty stack, the same as sent by clients for methods without arguments
+var emptyStackHash []byte
+
+func init() {
+	for hash, offset := range map[string]uint{
+		"d4902fcc9fad74698fa8e353220a68da0dcf72e32bcb2eb9ee04217c17d3062c": 0, // v1r2
+		"587cc789eff1c84f46ec3797e45fc809a14ff5ae24f1e0c7a6a99cc9dc9061ff": 0, // v1r3
+		"5c9a5e68c108e18721a07c42f9956bfb39ad77ec6d624b60c576ec88eee65329": 0, // v2r1
+		"fe9530d3243853083ef2ef0b4c2908c0abf6fa1c31ea243aacaa5bf8c7d753f1": 0, // v2r2
+		"b61041a58a7980b946e8fb9e198e3c904d24799ffa36574ea4251c41a566f581": 0, // v3r1
+		"84dafa449f98a6987789ba232358072bc0f76dc4524002a5d0918b9a75d2d599": 0, // v3r2
+		"64dd54805522c5be8a9db59cea0105ccf0d08786ca79beb8cb79e880a8d7322d": 0, // v4r1
+		"feb5ff6820e2ff0d9483e7e0d62c817d846789fb4ae580c878866d959dabd5c0": 0, // v4r2
+		"20834b7b72b112147e1b2fb457b84e74d1a30f04f737d4f62a668e9552d2b72f": 1, // v5r1, after is_signature_allowed flag
+	} {
+		h, _ := hex.DecodeString(hash)
+		walletSeqnoOffsets[string(h)] = offset
+	}
+
+	empty, err := tlb.NewStack().ToCell()
+	if err != nil {
+		panic(err)
+	}
+	emptyStackHash = empty.Hash()
+}
+
+// isEmptyStack reports if query has no method arguments
+func isEmptyStack(v *ton.RunSmcMethod) bool {
+	return v.Params != nil && bytes.Equal(v.Params.Hash(), emptyStackHash)
+}
+
+// walletSeqno answers seqno method of known wallet from its data, nil is returned for other contracts and methods
+func walletSeqno(st *tlb.AccountState, v *ton.RunSmcMethod) *emulate.RunResult {
+	if v.MethodID != seqnoMethodID || v.Mode&8 != 0 || st.StateInit == nil || st.StateInit.Code == nil || st.StateInit.Data == nil {
+		return nil
+	}
+
+	offset, ok := walletSeqnoOffsets[string(st.StateInit.Code.Hash())]
+	if !ok || !isEmptyStack(v) {
+		return nil
+	}
+
+	s := st.StateInit.Data.BeginParse()
+	if offset > 0 {
+		if _, err := s.LoadUInt(offset); err != nil {
+			return nil
+		}
+	}
+	seqno, err := s.LoadUInt(32)
+	if err != nil {
+		return nil
+	}
+
+	stack := tlb.NewStack()
+	stack.Push(new(big.Int).SetUint64(seqno))
+	res, err := stack.ToCell()
+	if err != nil {
+		return nil
+	}
+	return &emulate.RunResult{Stack: res}
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"math/bits"
+	"time"
+)
+
+// Warmup prefetches recent master blocks with their configs, last shard blocks
+// and states of watched accounts, so the first requests after start are served from cache
+func (c *BlockCache) Warmup(ctx context.Context, cfg config.CacheWarmupConfig) {
+	tm := time.Now()
+
+	last, _, err := c.GetLastMasterBlock(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msg("warmup: failed to get last master block")
+		return
+	}
+	lastSeqno := last.Block.ID.SeqNo
+
+	num := cfg.MasterBlocks
+	if num > c.config.MaxMasterBlockSeqnoDiffToCache {
+		num = c.config.MaxMasterBlockSeqnoDiffToCache
+	}
+	if num >= lastSeqno {
+		// blocks start from seqno 1, there is nothing before the first one, on new network it can be 0
+		num = 0
+		if lastSeqno > 0 {
+			num = lastSeqno - 1
+		}
+	}
+
+	// from old to new, so config of the previous block can be reused
+	var masters int
+	for seqno := lastSeqno - num; seqno < lastSeqno; seqno++ {
+		id, err := lookupBlock(ctx, c.balancer.GetClient(), &ton.BlockInfoShort{
+			Workchain: -1,
+			Shard:     -0x8000000000000000,
+			Seqno:     int32(seqno),
+		})
+		if err != nil {
+			log.Warn().Err(err).Uint32("seqno", seqno).Msg("warmup: failed to lookup master block")
+			continue
+		}
+
+		if _, _, err = c.GetMasterBlock(ctx, id); err != nil {
+			log.Warn().Err(err).Uint32("seqno", seqno).Msg("warmup: failed to fetch master block")
+			continue
+		}
+		masters++
+	}
+
+	c.mx.RLock()
+	shards := make([]*ton.BlockIDExt, 0, len(c.shardBlocks))
+	for _, si := range c.shardBlocks {
+		shards = append(shards, si.lastBlock)
+	}
+	c.mx.RUnlock()
+
+	shardBlocks := map[*ton.BlockIDExt]*Block{}
+	for _, id := range shards {
+		block, _, err := c.CacheBlockIfNeeded(ctx, id)
+		if err != nil {
+			log.Warn().Err(err).Int32("wc", id.Workchain).Int64("shard", id.Shard).Uint32("seqno", id.SeqNo).Msg("warmup: failed to fetch shard block")
+			continue
+		}
+		shardBlocks[id] = block
+	}
+
+	var accounts int
+	for _, str := range cfg.Accounts {
+		addr, err := address.ParseAddr(str)
+		if err != nil {
+			log.Warn().Err(err).Str("addr", str).Msg("warmup: invalid account address")
+			continue
+		}
+		// cache keys are built from addresses without flags
+		addr = address.NewAddress(0, byte(addr.Workchain()), addr.Data())
+
+		block := &last.Block
+		if addr.Workchain() != -1 {
+			block = nil
+			for id, sb := range shardBlocks {
+				if sb != nil && id.Workchain == addr.Workchain() && shardContainsAccount(id.Shard, addr.Data()) {
+					block = sb
+					break
+				}
+			}
+		}
+
+		if block == nil {
+			log.Warn().Str("addr", str).Msg("warmup: shard block of account is not cached")
+			continue
+		}
+
+		if _, _, err = c.GetAccountStateInBlock(ctx, block, addr); err != nil {
+			log.Warn().Err(err).Str("addr", str).Msg("warmup: failed to fetch account state")
+			continue
+		}
+		accounts++
+	}
+
+	log.Info().Int("master_blocks", masters).Int("shard_blocks", len(shardBlocks)).Int("accounts", accounts).
+		Dur("took", time.Since(tm).Round(time.Millisecond)).Msg("cache warmup finished")
+}
+
+func shardContainsAccount(shard int64, addr []byte) bool {
+	// shard is a prefix of account id followed by a single 1 bit
+	prefixLen := 63 - bits.TrailingZeros64(uint64(shard))
+	if prefixLen <= 0 {
+		return true
+	}
+
+	shift := 64 - prefixLen
+	return binary.BigEndian.Uint64(addr[:8])>>shift == uint64(shard)>>shift
+}
+
+func lookupBlock(ctx context.Context, client ton.LiteClient, info *ton.BlockInfoShort) (*ton.BlockIDExt, error) {
+	var resp tl.Serializable
+	err := client.QueryLiteserver(ctx, ton.LookupBlock{
+		Mode: 1,
+		ID:   info,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := resp.(type) {
+	case ton.BlockHeader:
+		if t.ID.SeqNo != uint32(info.Seqno) || t.ID.Workchain != info.Workchain {
+			return nil, fmt.Errorf("response with incorrect block")
+		}
+		return t.ID, nil
+	case ton.LSError:
+		return nil, t
+	}
+	return nil, fmt.Errorf("unexpected response")
+}
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"sort"
+	"sync"
+	"time"
+)
+
+var ErrWatchlistFull = errors.New("watchlist is full")
+
+// watchlistHook is called with every refreshed state of watched account
+type watchlistHook func(ctx context.Context, block *Block, addr *address.Address, state *ton.AccountState)
+
+// Watchlist keeps states of listed accounts always cached, they are fetched at every new master block,
+// which clients query accounts at, so queries of hot contracts never wait for backends.
+// Accounts added with admin api are not saved to config.
+type Watchlist struct {
+	cache *BlockCache
+	max   int
+	slots chan struct{}
+
+	accounts map[string]*address.Address
+	hook     watchlistHook
+	mx       sync.RWMutex
+
+	startOnce sync.Once
+}
+
+// EnableWatchlist creates watchlist with accounts from config, blocks are followed since the first account is added
+func (c *BlockCache) EnableWatchlist(cfg config.WatchlistConfig) {
+	w := &Watchlist{
+		cache:    c,
+		max:      int(cfg.MaxAccounts),
+		slots:    make(chan struct{}, cfg.Concurrency),
+		accounts: map[string]*address.Address{},
+	}
+	if w.max == 0 {
+		w.max = 1000
+	}
+	if cfg.Concurrency == 0 {
+		w.slots = make(chan struct{}, 16)
+	}
+	c.watchlist = w
+
+	for _, str := range cfg.Accounts {
+		if _, err := w.Add(str); err != nil {
+			log.Warn().Err(err).Str("addr", str).Msg("account is not added to watchlist")
+		}
+	}
+}
+
+// Watchlist returns watchlist of cache, nil when it is not enabled
+func (c *BlockCache) Watchlist() *Watchlist {
+	return c.watchlist
+}
+
+func (w *Watchlist) setRefreshHook(hook watchlistHook) {
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	w.hook = hook
+}
+
+// Add starts to keep state of account fresh, false is returned when it is already watched
+func (w *Watchlist) Add(str string) (bool, error) {
+	addr, err := address.ParseAddr(str)
+	if err != nil {
+		return false, err
+	}
+	// cache keys are built from addresses without flags
+	addr = address.NewAddress(0, byte(addr.Workchain()), addr.Data())
+
+	w.mx.Lock()
+	if _, ok := w.accounts[addr.String()]; ok {
+		w.mx.Unlock()
+		return false, nil
+	}
+	if len(w.accounts) >= w.max {
+		w.mx.Unlock()
+		return false, ErrWatchlistFull
+	}
+	w.accounts[addr.String()] = addr
+	w.mx.Unlock()
+
+	w.startOnce.Do(func() {
+		go w.follow()
+	})
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		master, _, err := w.cache.GetLastMasterBlock(ctx)
+		if err != nil {
+			return
+		}
+		w.refresh(ctx, &master.Block, []*address.Address{addr})
+	}()
+	return true, nil
+}
+
+// Remove stops watching account, its cached states are evicted as usual
+func (w *Watchlist) Remove(str string) bool {
+	addr, err := address.ParseAddr(str)
+	if err != nil {
+		return false
+	}
+	addr = address.NewAddress(0, byte(addr.Workchain()), addr.Data())
+
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	if _, ok := w.accounts[addr.String()]; !ok {
+		return false
+	}
+	delete(w.accounts, addr.String())
+	return true
+}
+
+// List returns watched accounts sorted
+func (w *Watchlist) List() []string {
+	w.mx.RLock()
+	list := make([]string, 0, len(w.accounts))
+	for k := range w.accounts {
+		list = append(list, k)
+	}
+	w.mx.RUnlock()
+
+	sort.Strings(list)
+	return list
+}
+
+func (w *Watchlist) follow() {
+	for {
+		events, _ := w.cache.SubscribeBlocks(16)
+		for ev := range events {
+			w.refreshMaster(ev.Master)
+		}
+
+		select {
+		case <-w.cache.closed:
+			return
+		default:
+		}
+		// we were too slow, the next block is refreshed
+	}
+}
+
+func (w *Watchlist) refreshMaster(id *ton.BlockIDExt) {
+	w.mx.RLock()
+	addrs := make([]*address.Address, 0, len(w.accounts))
+	for _, addr := range w.accounts {
+		addrs = append(addrs, addr)
+	}
+	w.mx.RUnlock()
+
+	if len(addrs) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	master, _, err := w.cache.GetMasterBlock(ctx, id)
+	if err != nil {
+		log.Debug().Err(err).Uint32("seqno", id.SeqNo).Msg("failed to get master block for watchlist")
+		return
+	}
+
+	tm := time.Now()
+	w.refresh(ctx, &master.Block, addrs)
+	log.Debug().Uint32("seqno", id.SeqNo).Int("accounts", len(addrs)).Dur("took", time.Since(tm)).Msg("watchlist refreshed")
+}
+
+// refresh caches states of accounts in block, states already in cache are not fetched again
+func (w *Watchlist) refresh(ctx context.Context, block *Block, addrs []*address.Address) {
+	w.mx.RLock()
+	hook := w.hook
+	w.mx.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, addr := range addrs {
+		w.slots <- struct{}{}
+		wg.Add(1)
+
+		go func(addr *address.Address) {
+			defer func() {
+				<-w.slots
+				wg.Done()
+			}()
+
+			state, _, err := w.cache.GetAccountStateInBlock(ctx, block, addr)
+			if err != nil {
+				log.Debug().Err(err).Str("addr", addr.String()).Uint32("seqno", block.ID.SeqNo).Msg("failed to refresh watched account")
+				metrics.Global.WatchlistRefreshes.WithLabelValues("failed").Add(1)
+				return
+			}
+			metrics.Global.WatchlistRefreshes.WithLabelValues("ok").Add(1)
+
+			if hook != nil {
+				hook(ctx, block, addr, state)
+			}
+		}(addr)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"sync/atomic"
+)
+
+// connWorkers executes queries of one client connection with limited number of goroutines,
+// workers are started on demand and exit when queue is empty, so idle connections cost nothing
+type connWorkers struct {
+	queue   chan func()
+	running int32
+	max     int32
+}
+
+func newConnWorkers(workers, queue int) *connWorkers {
+	return &connWorkers{
+		queue: make(chan func(), queue),
+		max:   int32(workers),
+	}
+}
+
+// submit queues task, false is returned when queue is full
+func (w *connWorkers) submit(task func()) bool {
+	select {
+	case w.queue <- task:
+	default:
+		return false
+	}
+
+	if w.reserve() {
+		go w.run()
+	}
+	return true
+}
+
+func (w *connWorkers) run() {
+	for {
+		select {
+		case task := <-w.queue:
+			task()
+		default:
+			atomic.AddInt32(&w.running, -1)
+			// task could be queued right before decrement, when submitter saw all workers running
+			if len(w.queue) == 0 || !w.reserve() {
+				return
+			}
+		}
+	}
+}
+
+func (w *connWorkers) reserve() bool {
+	for {
+		n := atomic.LoadInt32(&w.running)
+		if n >= w.max {
+			return false
+		}
+		if atomic.CompareAndSwapInt32(&w.running, n, n+1) {
+			return true
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package server
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"github.com/xssnick/tonutils-go/adnl"
+	"github.com/xssnick/tonutils-go/tl"
+)
+
+// ids of messages which client queries come in, liteServer.query is parsed by proxy itself, because tl parser
+// keeps only the first query after wait master prefix and drops the rest
+var (
+	adnlQueryID = tl.CRC("adnl.message.query query_id:int256 query:bytes = adnl.Message")
+	liteQueryID = tl.CRC("liteServer.query data:bytes = Object")
+)
+
+// rawLiteServerQuery is liteServer.query with data as received from client, it is not registered in tl,
+// so parsing of liteclient.LiteServerQuery in the rest of process is not affected
+type rawLiteServerQuery struct {
+	Data []byte `tl:"bytes"`
+}
+
+type clientQueryCtx struct{}
+type querySizeCtx struct{}
+type rawPayloadCtx struct{}
+
+// withClientQuery keeps data of liteServer.query as client sent it, raw passthrough forwards it to backend
+func withClientQuery(ctx context.Context, data []byte) context.Context {
+	return context.WithValue(ctx, clientQueryCtx{}, data)
+}
+
+func clientQueryFrom(ctx context.Context) []byte {
+	data, _ := ctx.Value(clientQueryCtx{}).([]byte)
+	return data
+}
+
+// withQuerySize keeps size of query received by other protocol than adnl, like grpc
+func withQuerySize(ctx context.Context, size int) context.Context {
+	return context.WithValue(ctx, querySizeCtx{}, size)
+}
+
+// clientQuerySize returns size of query as client sent it, 0 for queries of proxy itself
+func clientQuerySize(ctx context.Context) int {
+	if data := clientQueryFrom(ctx); data != nil {
+		return len(data)
+	}
+	size, _ := ctx.Value(querySizeCtx{}).(int)
+	return size
+}
+
+// withRawPayload makes backend connection to send data in place of query, decoded query is still
+// used by balancer for routing, hedging and metrics
+func withRawPayload(ctx context.Context, data []byte) context.Context {
+	return context.WithValue(ctx, rawPayloadCtx{}, data)
+}
+
+func rawPayloadFrom(ctx context.Context) []byte {
+	data, _ := ctx.Value(rawPayloadCtx{}).([]byte)
+	return data
+}
+
+// parseClientMessage parses message of client connection, liteserver query in adnl query is returned
+// as rawLiteServerQuery, other messages are parsed by tl as is
+func parseClientMessage(data []byte) (tl.Serializable, error) {
+	if len(data) < 4 || binary.LittleEndian.Uint32(data) != adnlQueryID {
+		var msg tl.Serializable
+		_, err := tl.Parse(&msg, data, true)
+		return msg, err
+	}
+
+	data = data[4:]
+	if len(data) < 32 {
+		return nil, fmt.Errorf("too short query id")
+	}
+	id := data[:32]
+
+	query, _, err := tl.FromBytes(data[32:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read query: %w", err)
+	}
+
+	raw, err := parseRawLiteQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		var v tl.Serializable
+		if _, err = tl.Parse(&v, query, true); err != nil {
+			return nil, err
+		}
+		return adnl.MessageQuery{ID: id, Data: v}, nil
+	}
+	return adnl.MessageQuery{ID: id, Data: *raw}, nil
+}
+
+// parseRawLiteQuery reads data of serialized liteServer.query, nil is returned for other objects
+func parseRawLiteQuery(data []byte) (*rawLiteServerQuery, error) {
+	if len(data) < 4 || binary.LittleEndian.Uint32(data) != liteQueryID {
+		return nil, nil
+	}
+
+	raw, _, err := tl.FromBytes(data[4:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read liteserver query: %w", err)
+	}
+	return &rawLiteServerQuery{Data: raw}, nil
+}
+
+// ParseLiteQuery parses serialized liteServer.query and returns query inside, see decodeLiteQuery
+func ParseLiteQuery(data []byte) (tl.Serializable, error) {
+	q, err := parseRawLiteQuery(data)
+	if err != nil {
+		return nil, err
+	}
+	if q == nil {
+		return nil, fmt.Errorf("not a liteserver query")
+	}
+	return decodeLiteQuery(q.Data)
+}
+
+// decodeLiteQuery parses all boxed objects of query data, single query is returned as is,
+// query with prefixes, like wait master, is returned as list with all wrapped queries
+func decodeLiteQuery(data []byte) (tl.Serializable, error) {
+	var list []tl.Serializable
+	for len(data) > 0 {
+		if len(list) > maxWrappedQueries {
+			return nil, fmt.Errorf("too many wrapped queries")
+		}
+
+		var v tl.Serializable
+		rest, err := tl.Parse(&v, data, true)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+		data = rest
+	}
+
+	switch len(list) {
+	case 0:
+		return nil, fmt.Errorf("empty query")
+	case 1:
+		return list[0], nil
+	}
+	return list, nil
+}
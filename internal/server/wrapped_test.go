@@ -0,0 +1,189 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"github.com/xssnick/tonutils-go/adnl"
+	"github.com/xssnick/tonutils-go/liteclient"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/ton"
+	"reflect"
+	"testing"
+)
+
+func serializeLiteQuery(t *testing.T, queries ...tl.Serializable) []byte {
+	var data []byte
+	for _, q := range queries {
+		part, err := tl.Serialize(q, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data = append(data, part...)
+	}
+
+	res, err := tl.Serialize(liteclient.LiteServerQuery{Data: tl.Raw(data)}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestParseLiteQuery(t *testing.T) {
+	wait := ton.WaitMasterchainSeqno{Seqno: 5, Timeout: 1000}
+
+	tests := []struct {
+		name    string
+		queries []tl.Serializable
+		want    tl.Serializable
+	}{
+		{
+			name:    "single",
+			queries: []tl.Serializable{ton.GetTime{}},
+			want:    ton.GetTime{},
+		},
+		{
+			name:    "wait with one query",
+			queries: []tl.Serializable{wait, ton.GetTime{}},
+			want:    []tl.Serializable{wait, ton.GetTime{}},
+		},
+		{
+			name:    "wait with several queries",
+			queries: []tl.Serializable{wait, ton.GetTime{}, ton.GetVersion{}, ton.GetMasterchainInf{}},
+			want:    []tl.Serializable{wait, ton.GetTime{}, ton.GetVersion{}, ton.GetMasterchainInf{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLiteQuery(serializeLiteQuery(t, tt.queries...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLiteQueryInvalid(t *testing.T) {
+	data, err := tl.Serialize([]tl.Serializable{ton.WaitMasterchainSeqno{Seqno: 5, Timeout: 1000}, ton.GetTime{}}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// unknown object after known ones
+	query, err := tl.Serialize(liteclient.LiteServerQuery{Data: tl.Raw(append(data, 1, 2, 3, 4))}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ParseLiteQuery(query); err == nil {
+		t.Fatal("query with unknown object is parsed")
+	}
+
+	var list []tl.Serializable
+	for i := 0; i < maxWrappedQueries+2; i++ {
+		list = append(list, ton.GetTime{})
+	}
+	if _, err = ParseLiteQuery(serializeLiteQuery(t, list...)); err == nil {
+		t.Fatal("too many queries are parsed")
+	}
+}
+
+func TestParseClientMessage(t *testing.T) {
+	query := serializeLiteQuery(t, ton.WaitMasterchainSeqno{Seqno: 5, Timeout: 1000}, ton.GetTime{})
+	id := make([]byte, 32)
+	id[0] = 7
+
+	data, err := tl.Serialize(adnl.MessageQuery{ID: id, Data: tl.Raw(query)}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := parseClientMessage(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, ok := msg.(adnl.MessageQuery)
+	if !ok || !bytes.Equal(m.ID, id) {
+		t.Fatalf("unexpected message %#v", msg)
+	}
+	raw, ok := m.Data.(rawLiteServerQuery)
+	if !ok {
+		t.Fatalf("expected raw liteserver query, got %T", m.Data)
+	}
+	if got, err := decodeLiteQuery(raw.Data); err != nil || len(got.([]tl.Serializable)) != 2 {
+		t.Fatalf("unexpected wrapped queries %v, %v", got, err)
+	}
+
+	// parsing of liteserver query by tl is not replaced for the rest of process
+	var v tl.Serializable
+	if _, err = tl.Parse(&v, query, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok = v.(liteclient.LiteServerQuery); !ok {
+		t.Fatalf("expected liteclient query from tl, got %T", v)
+	}
+}
+
+func TestProcessWrappedQueries(t *testing.T) {
+	s := newTestProxy(&testCache{lastSeqno: 10})
+
+	tests := []struct {
+		name  string
+		query []tl.Serializable
+		want  []reflect.Type
+		code  int32
+	}{
+		{
+			name:  "several queries",
+			query: []tl.Serializable{ton.WaitMasterchainSeqno{Seqno: 5, Timeout: 1000}, ton.GetTime{}, ton.GetVersion{}},
+			want:  []reflect.Type{reflect.TypeOf(ton.CurrentTime{}), reflect.TypeOf(ton.Version{})},
+		},
+		{
+			name:  "invalid wrapped query",
+			query: []tl.Serializable{ton.WaitMasterchainSeqno{Seqno: 5, Timeout: 1000}, ton.GetLibraries{}, ton.GetTime{}},
+			code:  400,
+		},
+		{
+			name:  "block is not reached",
+			query: []tl.Serializable{ton.WaitMasterchainSeqno{Seqno: 20, Timeout: 1000}, ton.GetTime{}, ton.GetVersion{}},
+			code:  652,
+		},
+		{
+			name:  "nested wait",
+			query: []tl.Serializable{ton.WaitMasterchainSeqno{Seqno: 5, Timeout: 1000}, ton.GetTime{}, ton.WaitMasterchainSeqno{}},
+			code:  400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := s.processQuery(context.Background(), "test", tt.query)
+			if tt.code != 0 {
+				if ls, ok := resp.(ton.LSError); !ok || ls.Code != tt.code {
+					t.Fatalf("got %#v, want error %d", resp, tt.code)
+				}
+				return
+			}
+
+			raw, ok := resp.(tl.Raw)
+			if !ok {
+				t.Fatalf("got %T, want combined answer", resp)
+			}
+			for i, typ := range tt.want {
+				var v tl.Serializable
+				rest, err := tl.Parse(&v, raw, true)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if reflect.TypeOf(v) != typ {
+					t.Fatalf("answer %d is %T, want %s", i, v, typ)
+				}
+				raw = rest
+			}
+			if len(raw) != 0 {
+				t.Fatalf("%d bytes left after answers", len(raw))
+			}
+		})
+	}
+}
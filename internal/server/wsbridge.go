@@ -0,0 +1,51 @@
+package server
+
+import (
+	"github.com/rs/zerolog/log"
+	"golang.org/x/net/websocket"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ListenWebSocket accepts liteserver protocol tunneled over websocket binary frames, frames are parts
+// of the same stream as adnl tcp, so websocket connection is served by adnl server as is
+func (s *ProxyBalancer) ListenWebSocket(addr string, trustForwardedFor bool) error {
+	return http.ListenAndServe(addr, s.WebSocketHandler(trustForwardedFor))
+}
+
+// WebSocketHandler returns http handler of websocket tunnel, to serve it on own http server
+func (s *ProxyBalancer) WebSocketHandler(trustForwardedFor bool) http.Handler {
+	return websocket.Server{
+		// adnl handshake authenticates server key, so browser clients from any origin are allowed
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			ws.PayloadType = websocket.BinaryFrame
+
+			ip, port := remoteAddr(ws.Request(), trustForwardedFor)
+			// limits are applied to ip of websocket client, connection is closed by server
+			s.srv.ServeConn(ws, ip, port)
+			log.Debug().Str("addr", ip).Msg("websocket client disconnected")
+		},
+	}
+}
+
+// remoteAddr returns ip of client and port of its connection, port only tells connections
+// from the same ip apart, so for forwarded clients it is port of our load balancer connection
+func remoteAddr(r *http.Request, trustForwardedFor bool) (string, uint16) {
+	host, port, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	p, _ := strconv.ParseUint(port, 10, 16)
+
+	if trustForwardedFor {
+		if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+			// last address is added by our load balancer, previous ones could be set by client
+			parts := strings.Split(fwd, ",")
+			return normalizeIP(strings.TrimSpace(parts[len(parts)-1])), uint16(p)
+		}
+	}
+	return normalizeIP(host), uint16(p)
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"golang.org/x/net/websocket"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebSocketClientIP(t *testing.T) {
+	tests := []struct {
+		name              string
+		trustForwardedFor bool
+		wantIP            string
+	}{
+		{name: "connection ip", wantIP: "127.0.0.1"},
+		{name: "forwarded ip", trustForwardedFor: true, wantIP: "10.0.0.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, key, err := ed25519.GenerateKey(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			connected := make(chan *ServerClient, 1)
+			srv := newADNLServer([]ed25519.PrivateKey{key})
+			srv.connectHook = func(client *ServerClient) error {
+				connected <- client
+				return fmt.Errorf("rejected")
+			}
+
+			s := &ProxyBalancer{srv: srv}
+			hs := httptest.NewServer(s.WebSocketHandler(tt.trustForwardedFor))
+			defer hs.Close()
+
+			cfg, err := websocket.NewConfig("ws"+strings.TrimPrefix(hs.URL, "http"), hs.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg.Header.Set("X-Forwarded-For", "10.0.0.1, 10.0.0.2")
+
+			ws, err := websocket.DialConfig(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ws.Close()
+
+			select {
+			case client := <-connected:
+				if client.IP() != tt.wantIP {
+					t.Fatalf("expected ip %s, got %s", tt.wantIP, client.IP())
+				}
+				if client.Port() == 0 {
+					t.Fatal("port of connection is not set")
+				}
+			case <-time.After(time.Second):
+				t.Fatal("websocket client is not passed to adnl server")
+			}
+
+			// rejected client is closed by server
+			_ = ws.SetReadDeadline(time.Now().Add(time.Second))
+			if _, err = ws.Read(make([]byte, 1)); err == nil {
+				t.Fatal("connection is expected to be closed")
+			}
+		})
+	}
+}
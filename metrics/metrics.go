@@ -3,14 +3,71 @@ package metrics
 import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"reflect"
 )
 
+// TypeLabelOther is reported instead of type names which are not in allowlist
+const TypeLabelOther = "other"
+
 type Metrics struct {
 	ActiveADNLConnections prometheus.Gauge
 	Requests              *prometheus.CounterVec
 	LSErrors              *prometheus.CounterVec
 	Queries               *prometheus.HistogramVec
 	BackendQueries        *prometheus.HistogramVec
+	BackendEvictions      *prometheus.CounterVec
+	BackendHealthy        *prometheus.GaugeVec
+	HedgedRequests        *prometheus.CounterVec
+	BackendRetries        *prometheus.CounterVec
+	CacheRequests         *prometheus.CounterVec
+	CacheBytes            *prometheus.GaugeVec
+	CacheEvictions        *prometheus.CounterVec
+	QuorumMismatches      *prometheus.CounterVec
+	RejectedHandshakes    *prometheus.CounterVec
+	Panics                *prometheus.CounterVec
+	ResponseBytes         *prometheus.HistogramVec
+	OversizedResponses    *prometheus.CounterVec
+	Subscriptions         *prometheus.GaugeVec
+	StreamEvents          *prometheus.CounterVec
+	DHTAnnounces          *prometheus.CounterVec
+	CoalescedQueries      *prometheus.CounterVec
+	CacheStaleRenewals    *prometheus.CounterVec
+	ShedQueries           *prometheus.CounterVec
+	ExemptRequests        *prometheus.CounterVec
+	RetryAfter            *prometheus.HistogramVec
+	EmulatedGas           *prometheus.CounterVec
+	KeyResponseBytes      *prometheus.CounterVec
+	LaggingAnswers        *prometheus.CounterVec
+	ShadowQueries         *prometheus.CounterVec
+	CanaryChecks          *prometheus.CounterVec
+	ChaosInjections       *prometheus.CounterVec
+	SelfTestProbes        *prometheus.HistogramVec
+	SelfTestUp            prometheus.Gauge
+	IndexedBlocks         *prometheus.CounterVec
+	IndexedTransactions   *prometheus.CounterVec
+	S3Requests            *prometheus.HistogramVec
+	AnalyticsExports      *prometheus.CounterVec
+	BillingEvents         *prometheus.CounterVec
+	WatchlistRefreshes    *prometheus.CounterVec
+	PrecomputedGetters    *prometheus.CounterVec
+	WalletSeqnoFastPath   prometheus.Counter
+	SystemGetters         *prometheus.CounterVec
+	PubSubMessages        *prometheus.CounterVec
+	PeerCache             *prometheus.CounterVec
+	ClusterLeader         prometheus.Gauge
+	DownstreamBlocks      *prometheus.CounterVec
+	BackendTierFallbacks  *prometheus.CounterVec
+	BackendResolves       *prometheus.CounterVec
+	SharedLimits          *prometheus.CounterVec
+	QueueDepth            *prometheus.GaugeVec
+	PoolUtilization       *prometheus.GaugeVec
+	EmulationsInFlight    prometheus.Gauge
+	BackendInFlight       *prometheus.GaugeVec
+	BackendSaturation     *prometheus.GaugeVec
+	CappedMasterWaits     *prometheus.CounterVec
+
+	allowedTypes map[string]bool
+	allowAll     bool
 }
 
 var Global *Metrics
@@ -47,5 +104,333 @@ func InitMetrics(namespace, subsystem string) {
 			Name:      "backend_queries",
 			Help:      "LS Requests to backend statistics",
 		}, []string{"name", "request_type", "status"}),
+		BackendEvictions: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "backend_evictions",
+			Help:      "Backends removed from rotation by health checks",
+		}, []string{"name", "reason"}),
+		BackendHealthy: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "backend_healthy",
+			Help:      "Backend health status, 1 when backend is in rotation",
+		}, []string{"name"}),
+		HedgedRequests: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "hedged_requests",
+			Help:      "Requests answered while hedged request was in flight, by whether hedged one won",
+		}, []string{"hedge_won"}),
+		BackendRetries: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "backend_retries",
+			Help:      "Retries of failed backend queries, and retries rejected by budget",
+		}, []string{"result"}),
+		CacheRequests: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "cache_requests",
+			Help:      "Cache lookups by object class, cache level and result",
+		}, []string{"class", "level", "result"}),
+		CacheBytes: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "cache_bytes",
+			Help:      "Size of cached objects by object class and cache level",
+		}, []string{"class", "level"}),
+		CacheEvictions: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "cache_evictions",
+			Help:      "Objects removed from cache because of size limits or expiration",
+		}, []string{"class", "level"}),
+		QuorumMismatches: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "quorum_mismatches",
+			Help:      "Backend answers which disagreed with majority in quorum mode",
+		}, []string{"name", "request_type"}),
+		RejectedHandshakes: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "rejected_handshakes",
+			Help:      "Client connections closed before ADNL handshake, by reason",
+		}, []string{"reason"}),
+		Panics: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "panics",
+			Help:      "Recovered panics during query processing",
+		}, []string{"request_type"}),
+		ResponseBytes: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "response_bytes",
+			Help:      "Size of serialized responses sent to clients",
+			Buckets:   prometheus.ExponentialBuckets(64, 4, 10),
+		}, []string{"request_type"}),
+		OversizedResponses: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "oversized_responses",
+			Help:      "Responses replaced with error because of size limit",
+		}, []string{"request_type"}),
+		Subscriptions: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "subscriptions",
+			Help:      "Active websocket subscriptions",
+		}, []string{"topic"}),
+		StreamEvents: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "stream_events",
+			Help:      "Block events published to event stream",
+		}, []string{"result"}),
+		DHTAnnounces: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "dht_announces",
+			Help:      "Announces of proxy address in dht by client key",
+		}, []string{"key_name", "result"}),
+		CoalescedQueries: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "coalesced_queries",
+			Help:      "Callers of backend queries which answer was shared with identical concurrent queries",
+		}, []string{"request_type"}),
+		CacheStaleRenewals: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "cache_stale_renewals",
+			Help:      "Expired objects which were served and kept because they were still requested",
+		}, []string{"class", "level"}),
+		ShedQueries: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "shed_queries",
+			Help:      "Queries rejected under load by priority of client key",
+		}, []string{"key_name", "priority"}),
+		ExemptRequests: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "exempt_requests",
+			Help:      "Requests of clients exempt from rate and concurrency limits",
+		}, []string{"key_name", "request_type"}),
+		RetryAfter: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "retry_after",
+			Help:      "Seconds until rate limited client can send query again, sent with rejection",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 4, 10),
+		}, []string{"key_name"}),
+		EmulatedGas: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "emulated_gas",
+			Help:      "Gas used by get methods emulated for client key",
+		}, []string{"key_name"}),
+		KeyResponseBytes: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "key_response_bytes",
+			Help:      "Bytes of responses sent to client key",
+		}, []string{"key_name"}),
+		LaggingAnswers: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "lagging_answers",
+			Help:      "Master info answers older than client has already got, which were requeried",
+		}, []string{"request_type"}),
+		ShadowQueries: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "shadow_queries",
+			Help:      "Queries mirrored to shadow backends by result of comparison with main backends answer",
+		}, []string{"request_type", "result"}),
+		CanaryChecks: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "canary_checks",
+			Help:      "Locally answered queries checked against backend by result of comparison, mismatch is divergence of emulation",
+		}, []string{"request_type", "result"}),
+		ChaosInjections: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "chaos_injections",
+			Help:      "Queries with latency or faults injected by chaos rules",
+		}, []string{"key_name", "request_type", "fault"}),
+		SelfTestProbes: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "self_test_probes",
+			Help:      "Steps of self test through public endpoint: connect, master and account",
+		}, []string{"step", "status"}),
+		SelfTestUp: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "self_test_up",
+			Help:      "1 when the last self test through public endpoint succeeded",
+		}),
+		IndexedBlocks: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "indexed_blocks",
+			Help:      "Blocks added to persistent block index for lookups",
+		}, []string{"workchain"}),
+		IndexedTransactions: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "indexed_transactions",
+			Help:      "Transactions added to index of account transactions",
+		}, []string{"workchain"}),
+		S3Requests: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "s3_requests",
+			Help:      "Requests to s3 tier of cache store",
+		}, []string{"op", "status"}),
+		AnalyticsExports: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "analytics_exports",
+			Help:      "Exports of aggregated query statistics",
+		}, []string{"sink", "status"}),
+		BillingEvents: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "billing_events",
+			Help:      "Usage events of client keys by send status",
+		}, []string{"status"}),
+		WatchlistRefreshes: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "watchlist_refreshes",
+			Help:      "States of watched accounts fetched at new master blocks",
+		}, []string{"status"}),
+		PrecomputedGetters: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "precomputed_getters",
+			Help:      "Get methods run for new states of watched accounts",
+		}, []string{"status"}),
+		WalletSeqnoFastPath: promauto.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "wallet_seqno_fast_path",
+			Help:      "Seqno methods of known wallets answered from data without tvm",
+		}),
+		SystemGetters: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "system_getters",
+			Help:      "Get methods of elector and config contracts by cache result",
+		}, []string{"result"}),
+		PubSubMessages: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "pubsub_messages",
+			Help:      "Messages exchanged with other proxy instances by type and direction",
+		}, []string{"type", "direction"}),
+		PeerCache: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "peer_cache",
+			Help:      "Lookups of cached objects in sibling proxies, asked by us and answered to them",
+		}, []string{"op", "class", "result"}),
+		ClusterLeader: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "cluster_leader",
+			Help:      "1 when instance holds leader lock and fetches new master blocks for cluster",
+		}),
+		DownstreamBlocks: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "downstream_blocks",
+			Help:      "Master blocks pushed to downstream proxies and received from upstream, by status",
+		}, []string{"direction", "status"}),
+		BackendTierFallbacks: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "backend_tier_fallbacks",
+			Help:      "Queries sent to less preferred tier because backends of better ones are unhealthy or saturated",
+		}, []string{"tier"}),
+		BackendResolves: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "backend_resolves",
+			Help:      "Repeated resolutions of backend hostnames by result: same, changed or failed",
+		}, []string{"backend", "result"}),
+		SharedLimits: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "shared_limits",
+			Help:      "Checks of rate limits shared through redis by result: allowed, rejected or failed, when local limits are used",
+		}, []string{"result"}),
+		QueueDepth: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "queue_depth",
+			Help:      "Queries waiting for processing by queue: connections, qos or backend_fair",
+		}, []string{"queue"}),
+		PoolUtilization: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "pool_utilization",
+			Help:      "Share of busy slots from 0 to 1 by pool: connection_workers, qos or backend_fair",
+		}, []string{"pool"}),
+		EmulationsInFlight: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "emulations_in_flight",
+			Help:      "Get methods being emulated at the moment",
+		}),
+		BackendInFlight: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "backend_in_flight",
+			Help:      "Queries sent to backend and not answered yet",
+		}, []string{"backend"}),
+		BackendSaturation: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "backend_saturation",
+			Help:      "Queries in flight of backend divided by its MaxInFlight, only for backends with limit",
+		}, []string{"backend"}),
+		CappedMasterWaits: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "capped_master_waits",
+			Help:      "Queries which asked to wait for master block longer than allowed for key, wait was shortened",
+		}, []string{"key"}),
+	}
+}
+
+// SetTypeLabelsAllowlist limits possible values of type labels to keep cardinality bounded,
+// "*" in list allows any type
+func (m *Metrics) SetTypeLabelsAllowlist(types []string) {
+	m.allowedTypes = map[string]bool{}
+	m.allowAll = false
+	for _, t := range types {
+		if t == "*" {
+			m.allowAll = true
+		}
+		m.allowedTypes[t] = true
+	}
+}
+
+// TypeLabel returns value's type name when it is allowed, and TypeLabelOther otherwise
+func (m *Metrics) TypeLabel(v any) string {
+	if v == nil {
+		return "nil"
+	}
+
+	name := reflect.TypeOf(v).String()
+	if m.allowAll || m.allowedTypes == nil || m.allowedTypes[name] {
+		return name
 	}
+	return TypeLabelOther
 }
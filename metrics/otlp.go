@@ -0,0 +1,297 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/rs/zerolog/log"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// OTLPExporter periodically pushes all registered prometheus metrics
+// to OTLP collector using OTLP/HTTP protocol with JSON encoding
+type OTLPExporter struct {
+	endpoint    string
+	headers     map[string]string
+	interval    time.Duration
+	serviceName string
+
+	gatherer  prometheus.Gatherer
+	client    *http.Client
+	startedAt time.Time
+}
+
+type otlpKeyValue struct {
+	Key   string        `json:"key"`
+	Value otlpAnyString `json:"value"`
+}
+
+type otlpAnyString struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpNumberPoint struct {
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	TimeUnixNano      string         `json:"timeUnixNano"`
+	AsDouble          float64        `json:"asDouble"`
+}
+
+type otlpHistogramPoint struct {
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	TimeUnixNano      string         `json:"timeUnixNano"`
+	Count             string         `json:"count"`
+	Sum               float64        `json:"sum"`
+	BucketCounts      []string       `json:"bucketCounts"`
+	ExplicitBounds    []float64      `json:"explicitBounds"`
+}
+
+type otlpQuantile struct {
+	Quantile float64 `json:"quantile"`
+	Value    float64 `json:"value"`
+}
+
+type otlpSummaryPoint struct {
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	TimeUnixNano      string         `json:"timeUnixNano"`
+	Count             string         `json:"count"`
+	Sum               float64        `json:"sum"`
+	QuantileValues    []otlpQuantile `json:"quantileValues"`
+}
+
+type otlpSum struct {
+	DataPoints             []otlpNumberPoint `json:"dataPoints"`
+	AggregationTemporality int               `json:"aggregationTemporality"`
+	IsMonotonic            bool              `json:"isMonotonic"`
+}
+
+type otlpGauge struct {
+	DataPoints []otlpNumberPoint `json:"dataPoints"`
+}
+
+type otlpHistogram struct {
+	DataPoints             []otlpHistogramPoint `json:"dataPoints"`
+	AggregationTemporality int                  `json:"aggregationTemporality"`
+}
+
+type otlpSummary struct {
+	DataPoints []otlpSummaryPoint `json:"dataPoints"`
+}
+
+type otlpMetric struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Sum         *otlpSum       `json:"sum,omitempty"`
+	Gauge       *otlpGauge     `json:"gauge,omitempty"`
+	Histogram   *otlpHistogram `json:"histogram,omitempty"`
+	Summary     *otlpSummary   `json:"summary,omitempty"`
+}
+
+type otlpScopeMetrics struct {
+	Scope struct {
+		Name string `json:"name"`
+	} `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	} `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpExportRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+const otlpTemporalityCumulative = 2
+
+// NewOTLPExporter creates exporter, endpoint is a full url of collector's metrics handler,
+// usually http://collector:4318/v1/metrics
+func NewOTLPExporter(endpoint string, headers map[string]string, interval time.Duration, serviceName string) *OTLPExporter {
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+
+	return &OTLPExporter{
+		endpoint:    endpoint,
+		headers:     headers,
+		interval:    interval,
+		serviceName: serviceName,
+		gatherer:    prometheus.DefaultGatherer,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		startedAt:   time.Now(),
+	}
+}
+
+func (e *OTLPExporter) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			pushCtx, cancel := context.WithTimeout(ctx, e.interval)
+			err := e.Push(pushCtx)
+			cancel()
+			if err != nil {
+				log.Warn().Err(err).Str("endpoint", e.endpoint).Msg("failed to push otlp metrics")
+			}
+		}
+	}()
+}
+
+func (e *OTLPExporter) Push(ctx context.Context) error {
+	families, err := e.gatherer.Gather()
+	if err != nil {
+		return fmt.Errorf("failed to gather metrics: %w", err)
+	}
+
+	data, err := json.Marshal(e.convert(families, time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to serialize metrics: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("collector responded with status %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+func (e *OTLPExporter) convert(families []*dto.MetricFamily, now time.Time) otlpExportRequest {
+	start := strconv.FormatInt(e.startedAt.UnixNano(), 10)
+	ts := strconv.FormatInt(now.UnixNano(), 10)
+
+	var metrics []otlpMetric
+	for _, f := range families {
+		m := otlpMetric{
+			Name:        f.GetName(),
+			Description: f.GetHelp(),
+		}
+
+		switch f.GetType() {
+		case dto.MetricType_COUNTER:
+			m.Sum = &otlpSum{AggregationTemporality: otlpTemporalityCumulative, IsMonotonic: true}
+			for _, pm := range f.GetMetric() {
+				m.Sum.DataPoints = append(m.Sum.DataPoints, otlpNumberPoint{
+					Attributes:        otlpAttributes(pm.GetLabel()),
+					StartTimeUnixNano: start,
+					TimeUnixNano:      ts,
+					AsDouble:          pm.GetCounter().GetValue(),
+				})
+			}
+		case dto.MetricType_GAUGE, dto.MetricType_UNTYPED:
+			m.Gauge = &otlpGauge{}
+			for _, pm := range f.GetMetric() {
+				val := pm.GetGauge().GetValue()
+				if f.GetType() == dto.MetricType_UNTYPED {
+					val = pm.GetUntyped().GetValue()
+				}
+
+				m.Gauge.DataPoints = append(m.Gauge.DataPoints, otlpNumberPoint{
+					Attributes:        otlpAttributes(pm.GetLabel()),
+					StartTimeUnixNano: start,
+					TimeUnixNano:      ts,
+					AsDouble:          val,
+				})
+			}
+		case dto.MetricType_HISTOGRAM:
+			m.Histogram = &otlpHistogram{AggregationTemporality: otlpTemporalityCumulative}
+			for _, pm := range f.GetMetric() {
+				h := pm.GetHistogram()
+				point := otlpHistogramPoint{
+					Attributes:        otlpAttributes(pm.GetLabel()),
+					StartTimeUnixNano: start,
+					TimeUnixNano:      ts,
+					Count:             strconv.FormatUint(h.GetSampleCount(), 10),
+					Sum:               h.GetSampleSum(),
+				}
+
+				// prometheus buckets are cumulative, otlp expects count per bucket
+				var prev uint64
+				for _, b := range h.GetBucket() {
+					if math.IsInf(b.GetUpperBound(), 1) {
+						continue
+					}
+					point.ExplicitBounds = append(point.ExplicitBounds, b.GetUpperBound())
+					point.BucketCounts = append(point.BucketCounts, strconv.FormatUint(b.GetCumulativeCount()-prev, 10))
+					prev = b.GetCumulativeCount()
+				}
+				point.BucketCounts = append(point.BucketCounts, strconv.FormatUint(h.GetSampleCount()-prev, 10))
+
+				m.Histogram.DataPoints = append(m.Histogram.DataPoints, point)
+			}
+		case dto.MetricType_SUMMARY:
+			m.Summary = &otlpSummary{}
+			for _, pm := range f.GetMetric() {
+				sm := pm.GetSummary()
+				point := otlpSummaryPoint{
+					Attributes:        otlpAttributes(pm.GetLabel()),
+					StartTimeUnixNano: start,
+					TimeUnixNano:      ts,
+					Count:             strconv.FormatUint(sm.GetSampleCount(), 10),
+					Sum:               sm.GetSampleSum(),
+				}
+				for _, q := range sm.GetQuantile() {
+					point.QuantileValues = append(point.QuantileValues, otlpQuantile{
+						Quantile: q.GetQuantile(),
+						Value:    q.GetValue(),
+					})
+				}
+				m.Summary.DataPoints = append(m.Summary.DataPoints, point)
+			}
+		default:
+			continue
+		}
+		metrics = append(metrics, m)
+	}
+
+	var scope otlpScopeMetrics
+	scope.Scope.Name = e.serviceName
+	scope.Metrics = metrics
+
+	var res otlpResourceMetrics
+	res.Resource.Attributes = []otlpKeyValue{{Key: "service.name", Value: otlpAnyString{StringValue: e.serviceName}}}
+	res.ScopeMetrics = []otlpScopeMetrics{scope}
+
+	return otlpExportRequest{ResourceMetrics: []otlpResourceMetrics{res}}
+}
+
+func otlpAttributes(labels []*dto.LabelPair) []otlpKeyValue {
+	attrs := make([]otlpKeyValue, 0, len(labels))
+	for _, l := range labels {
+		attrs = append(attrs, otlpKeyValue{Key: l.GetName(), Value: otlpAnyString{StringValue: l.GetValue()}})
+	}
+	return attrs
+}
@@ -0,0 +1,227 @@
+package proxy
+
+import (
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// HandoffEnv passes addresses of listening sockets to new process on upgrade, sockets are passed
+// as files starting from fd 3 in the same order, next fd is a pipe to report that new process is started
+const HandoffEnv = "LS_PROXY_HANDOFF"
+
+// Upgrade starts new process of the same executable with the same arguments and passes listening sockets to it,
+// so clients can connect during upgrade. When new process is started, this one stops accepting clients and serves
+// connected ones until they disconnect or DrainSeconds pass, then it is stopped and Wait returns.
+// Proxy continues to work as before when upgrade fails.
+func (p *Proxy) Upgrade() error {
+	p.mx.Lock()
+	if !p.started || p.stopped || p.upgrading {
+		p.mx.Unlock()
+		return fmt.Errorf("proxy is not running or already upgrading")
+	}
+	if !p.cfg.Handoff.Enabled {
+		p.mx.Unlock()
+		return fmt.Errorf("handoff is not enabled in config")
+	}
+	p.upgrading = true
+
+	cmd, ready, err := p.startNext()
+	p.mx.Unlock()
+	if err != nil {
+		p.upgradeFailed()
+		return err
+	}
+	defer ready.Close()
+
+	timeout := time.Duration(p.cfg.Handoff.ReadyTimeoutSeconds) * time.Second
+	if timeout == 0 {
+		timeout = 2 * time.Minute
+	}
+
+	started := make(chan error, 1)
+	go func() {
+		// pipe is closed without data when new process exits before start
+		_, err := ready.Read(make([]byte, 1))
+		started <- err
+	}()
+
+	select {
+	case err = <-started:
+		if err != nil {
+			_ = cmd.Wait()
+			p.upgradeFailed()
+			return fmt.Errorf("new process is not started: %w", err)
+		}
+	case <-time.After(timeout):
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		p.upgradeFailed()
+		return fmt.Errorf("new process is not started in %s", timeout)
+	}
+	log.Info().Int("pid", cmd.Process.Pid).Msg("new process started, draining connected clients")
+	_ = cmd.Process.Release()
+
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	if p.stopped {
+		return nil
+	}
+	p.stopAccepting()
+	go p.drain(time.Duration(p.cfg.Handoff.DrainSeconds) * time.Second)
+
+	return nil
+}
+
+// startNext starts new process with duplicates of listening sockets, it returns read end of readiness pipe
+func (p *Proxy) startNext() (*exec.Cmd, *os.File, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get executable: %w", err)
+	}
+
+	var addrs []string
+	var files []*os.File
+	defer func() {
+		// new process has own duplicates
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}()
+
+	for addr, lis := range p.listeners {
+		fl, ok := lis.(interface{ File() (*os.File, error) })
+		if !ok {
+			return nil, nil, fmt.Errorf("socket of %s cannot be passed", addr)
+		}
+		f, err := fl.File()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get socket of %s: %w", addr, err)
+		}
+		addrs = append(addrs, addr)
+		files = append(files, f)
+	}
+
+	ready, readyW, err := os.Pipe()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create pipe: %w", err)
+	}
+	files = append(files, readyW)
+
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.Env = append(os.Environ(), HandoffEnv+"="+strings.Join(addrs, ","))
+	cmd.ExtraFiles = files
+
+	if err = cmd.Start(); err != nil {
+		_ = ready.Close()
+		return nil, nil, fmt.Errorf("failed to start new process: %w", err)
+	}
+	return cmd, ready, nil
+}
+
+func (p *Proxy) upgradeFailed() {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	p.upgrading = false
+}
+
+// stopAccepting closes listening sockets, new process accepts clients on its duplicates,
+// already connected adnl and websocket clients are not affected
+func (p *Proxy) stopAccepting() {
+	for _, srv := range p.httpServers {
+		_ = srv.Close()
+	}
+	if p.grpc != nil {
+		p.grpc.Stop()
+	}
+	for _, lis := range p.listeners {
+		_ = lis.Close()
+	}
+}
+
+// drain stops proxy when all clients are disconnected or timeout passes
+func (p *Proxy) drain(timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if len(p.srv.Connections()) == 0 {
+			break
+		}
+
+		select {
+		case <-p.done:
+			return
+		case <-time.After(time.Second):
+		}
+	}
+
+	log.Info().Int("connections", len(p.srv.Connections())).Msg("drain completed, stopping")
+	p.Stop()
+}
+
+// inheritListeners takes listening sockets passed by previous process on upgrade
+func (p *Proxy) inheritListeners() error {
+	val := os.Getenv(HandoffEnv)
+	if val == "" {
+		return nil
+	}
+	// upgrade of this process passes its own list
+	_ = os.Unsetenv(HandoffEnv)
+
+	addrs := strings.Split(val, ",")
+	p.inherited = map[string]net.Listener{}
+	for i, addr := range addrs {
+		f := os.NewFile(uintptr(3+i), addr)
+		lis, err := net.FileListener(f)
+		_ = f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to inherit socket of %s: %w", addr, err)
+		}
+		p.inherited[addr] = lis
+	}
+	p.ready = os.NewFile(uintptr(3+len(addrs)), "handoff ready")
+
+	log.Info().Strs("addrs", addrs).Msg("listening sockets inherited from previous process")
+	return nil
+}
+
+// listen returns socket inherited from previous process for addr, or creates new one
+func (p *Proxy) listen(addr string) (net.Listener, error) {
+	lis := p.inherited[addr]
+	if lis != nil {
+		delete(p.inherited, addr)
+	} else {
+		var err error
+		if lis, err = net.Listen("tcp", addr); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.listeners == nil {
+		p.listeners = map[string]net.Listener{}
+	}
+	p.listeners[addr] = lis
+	return lis, nil
+}
+
+// handoffDone closes inherited sockets which are not used by config anymore,
+// and reports to previous process that it can stop accepting clients
+func (p *Proxy) handoffDone() {
+	for addr, lis := range p.inherited {
+		log.Warn().Str("addr", addr).Msg("inherited socket is not used by config, closing")
+		_ = lis.Close()
+	}
+	p.inherited = nil
+
+	if p.ready != nil {
+		_, _ = p.ready.Write([]byte{1})
+		_ = p.ready.Close()
+		p.ready = nil
+	}
+}
@@ -0,0 +1,656 @@
+package proxy
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog/log"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-liteserver-proxy/config"
+	"github.com/xssnick/tonutils-liteserver-proxy/internal/server"
+	"github.com/xssnick/tonutils-liteserver-proxy/metrics"
+	"google.golang.org/grpc"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache provides blocks, states and libraries for emulation and cached answers,
+// by default it is BlockCache created from CacheConfig
+type Cache = server.Cache
+
+// Balancer delivers queries to backend liteservers, by default it is BackendBalancer created from Backends
+type Balancer = server.Balancer
+
+type MasterBlock = server.MasterBlock
+type Block = server.Block
+
+// Proxy is a caching liteserver front-end which runs in process of another service,
+// it starts the same listeners and components as standalone proxy, enabled by config
+type Proxy struct {
+	cfg *config.Config
+
+	balancer Balancer
+	cache    Cache
+
+	// built-in components, nil when replaced or disabled
+	backends   *server.BackendBalancer
+	blockCache *server.BlockCache
+	streamer   *server.BlockStreamer
+	dht        *server.DHTPublisher
+	budget     *server.MemoryBudget
+	reporter   *server.ErrorReporter
+	capture    *server.QueryCapture
+	selfTest   *server.SelfTestProbe
+	analytics  *server.QueryAnalytics
+	billing    *server.BillingExporter
+
+	srv         *server.ProxyBalancer
+	grpc        *grpc.Server
+	httpServers []*http.Server
+	stopOTLP    context.CancelFunc
+
+	// listening sockets by address, they are passed to new process on upgrade
+	listeners map[string]net.Listener
+	inherited map[string]net.Listener
+	ready     *os.File
+	// sockets opened with SO_REUSEPORT, they are not passed on upgrade
+	reusePort []net.Listener
+
+	errs      chan error
+	done      chan struct{}
+	started   bool
+	stopped   bool
+	upgrading bool
+	mx        sync.Mutex
+}
+
+// NewProxy creates proxy with config, it is not started until Start is called
+func NewProxy(cfg *config.Config) *Proxy {
+	return &Proxy{
+		cfg:  cfg,
+		errs: make(chan error, 1),
+		done: make(chan struct{}),
+	}
+}
+
+// SetBalancer replaces built-in backend balancer, Backends of config are not used then,
+// should be called before Start
+func (p *Proxy) SetBalancer(balancer Balancer) {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	p.balancer = balancer
+}
+
+// SetCache replaces built-in block cache, it is used only when emulation and cache are not disabled,
+// components which depend on built-in cache (subscriptions and event stream) cannot be enabled with it,
+// should be called before Start
+func (p *Proxy) SetCache(cache Cache) {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	p.cache = cache
+}
+
+// Start initializes components and starts listeners in background, errors of listeners
+// which happen after start are returned by Wait
+func (p *Proxy) Start() error {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	if p.started || p.stopped {
+		return fmt.Errorf("already started")
+	}
+	p.started = true
+
+	if err := p.start(); err != nil {
+		p.stop()
+		return err
+	}
+	return nil
+}
+
+func (p *Proxy) start() error {
+	cfg := p.cfg
+
+	if err := p.validateConfig(); err != nil {
+		return err
+	}
+
+	if err := p.inheritListeners(); err != nil {
+		return err
+	}
+
+	// metrics are global and registered once, so the first proxy in process initializes them
+	if metrics.Global == nil {
+		metrics.InitMetrics(cfg.MetricsNamespace, "tonutils_ls_proxy")
+		if len(cfg.MetricsTypeLabelsAllowlist) > 0 {
+			metrics.Global.SetTypeLabelsAllowlist(cfg.MetricsTypeLabelsAllowlist)
+		} else {
+			metrics.Global.SetTypeLabelsAllowlist(server.DefaultMetricsTypeLabels)
+		}
+	}
+
+	if cfg.OTLPMetrics.Endpoint != "" {
+		var ctx context.Context
+		ctx, p.stopOTLP = context.WithCancel(context.Background())
+		metrics.NewOTLPExporter(cfg.OTLPMetrics.Endpoint, cfg.OTLPMetrics.Headers,
+			time.Duration(cfg.OTLPMetrics.IntervalSeconds)*time.Second, "tonutils-liteserver-proxy").Start(ctx)
+		log.Info().Str("endpoint", cfg.OTLPMetrics.Endpoint).Msg("otlp metrics export enabled")
+	}
+
+	for i, clientConfig := range cfg.Clients {
+		key := ed25519.NewKeyFromSeed(clientConfig.PrivateKey)
+		log.Info().Int("i", i).Str("pub_key", base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))).Msg("liteserver initialized")
+	}
+
+	if cfg.ErrorReporting.SentryDSN != "" || cfg.ErrorReporting.WebhookURL != "" {
+		reporter, err := server.NewErrorReporter(cfg.ErrorReporting)
+		if err != nil {
+			return fmt.Errorf("failed to init error reporting: %w", err)
+		}
+		p.reporter = reporter
+		server.SetErrorReporter(reporter)
+		log.Info().Msg("error reporting enabled")
+	}
+
+	if p.balancer == nil {
+		if err := p.startBackends(); err != nil {
+			return err
+		}
+		p.balancer = p.backends
+	}
+
+	if !cfg.DisableEmulationAndCache && p.cache == nil {
+		store, err := server.NewCacheStore(cfg.CacheConfig)
+		if err != nil {
+			return fmt.Errorf("failed to init cache store: %w", err)
+		}
+		if store != nil {
+			log.Info().Str("type", cfg.CacheConfig.Store).Msg("cache store enabled")
+		}
+		p.blockCache = server.NewBlockCache(cfg.CacheConfig, p.balancer, store)
+		p.cache = p.blockCache
+
+		if cfg.CacheConfig.MemoryBudgetMB > 0 {
+			// answers caches of proxy are registered when it is created
+			p.budget = server.NewMemoryBudget(int64(cfg.CacheConfig.MemoryBudgetMB) << 20)
+			p.budget.Add(p.blockCache)
+			if mc, ok := store.(server.MemoryConsumer); ok {
+				p.budget.Add(mc)
+			}
+		}
+
+		if cfg.CacheConfig.BlockIndexPath != "" {
+			index, err := server.OpenBlockIndex(cfg.CacheConfig.BlockIndexPath, int(cfg.CacheConfig.BlockIndexMaxBlocks))
+			if err != nil {
+				return err
+			}
+			p.blockCache.EnableBlockIndex(index)
+		}
+		if cfg.CacheConfig.TxIndexMaxTransactions > 0 {
+			p.blockCache.EnableTxIndex(server.NewTxIndex(int(cfg.CacheConfig.TxIndexMaxTransactions)))
+		}
+		p.blockCache.EnableWatchlist(cfg.CacheConfig.Watchlist)
+		if cfg.Upstream.URL != "" {
+			p.blockCache.EnableUpstream(cfg.Upstream)
+		}
+		if cfg.CacheConfig.PubSub.Addr != "" {
+			if err := p.blockCache.EnablePubSub(cfg.CacheConfig.PubSub); err != nil {
+				return fmt.Errorf("failed to init cache pub/sub: %w", err)
+			}
+		}
+
+		if cfg.CacheConfig.Warmup.TimeoutSeconds > 0 {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.CacheConfig.Warmup.TimeoutSeconds)*time.Second)
+			p.blockCache.Warmup(ctx, cfg.CacheConfig.Warmup)
+			cancel()
+		}
+	}
+
+	if cfg.EventStream.Type != "" && cfg.EventStream.Type != "none" {
+		if p.blockCache == nil {
+			return fmt.Errorf("event stream requires built-in cache to be enabled")
+		}
+
+		sink, err := server.NewEventSink(cfg.EventStream)
+		if err != nil {
+			return fmt.Errorf("failed to init event stream: %w", err)
+		}
+		p.streamer = server.NewBlockStreamer(p.blockCache, sink, cfg.EventStream.IncludeBlockData)
+		p.streamer.Start()
+		log.Info().Str("type", cfg.EventStream.Type).Msg("block event stream enabled")
+	}
+
+	var cache Cache
+	if !cfg.DisableEmulationAndCache {
+		cache = p.cache
+	}
+	p.srv = server.NewProxyBalancer(cfg, p.balancer, cache)
+	if p.budget != nil {
+		p.budget.Add(p.srv)
+		p.budget.Start(2 * time.Second)
+	}
+	if len(cfg.Chaos) > 0 {
+		log.Warn().Int("rules", len(cfg.Chaos)).Msg("chaos mode is enabled, faults are injected into answers")
+	}
+	if p.blockCache != nil && p.blockCache.Store() != nil {
+		p.srv.SetPeerCacheSource(p.blockCache.Store())
+	}
+	if cache != nil && p.blockCache != nil && len(cfg.CacheConfig.Watchlist.Methods) > 0 {
+		p.srv.EnablePrecomputedGetters(p.blockCache.Watchlist(), cfg.CacheConfig.Watchlist.Methods)
+	}
+
+	if cfg.Capture.Path != "" {
+		capture, err := server.NewQueryCapture(cfg.Capture)
+		if err != nil {
+			return err
+		}
+		p.capture = capture
+		p.srv.SetCapture(capture)
+		log.Info().Str("path", cfg.Capture.Path).Float64("percent", cfg.Capture.SamplePercent).Msg("query capture enabled")
+	}
+
+	if cfg.Analytics.ClickHouseURL != "" || cfg.Analytics.Dir != "" {
+		analytics, err := server.NewQueryAnalytics(cfg.Analytics)
+		if err != nil {
+			return err
+		}
+		p.analytics = analytics
+		p.srv.SetAnalytics(analytics)
+		log.Info().Uint32("interval", cfg.Analytics.IntervalSeconds).Msg("query analytics export enabled")
+	}
+
+	if cfg.LimiterState.Path != "" {
+		if err := p.srv.EnableLimiterState(cfg.LimiterState); err != nil {
+			return err
+		}
+	}
+
+	if cfg.SharedLimits.Addr != "" {
+		if err := p.srv.EnableSharedLimits(cfg.SharedLimits); err != nil {
+			return fmt.Errorf("failed to enable shared limits: %w", err)
+		}
+	}
+
+	billingSink, err := server.NewBillingSink(cfg.Billing)
+	if err != nil {
+		return fmt.Errorf("failed to init billing export: %w", err)
+	}
+	if billingSink != nil {
+		p.billing = server.NewBillingExporter(billingSink, cfg.Billing)
+		p.srv.SetBilling(p.billing)
+		log.Info().Str("type", cfg.Billing.Type).Msg("billing export enabled")
+	}
+
+	if cfg.MetricsAddr != "" {
+		health := server.NewHealthChecker(p.balancer, p.blockCache, time.Duration(cfg.ReadinessMaxMasterLagSeconds)*time.Second)
+
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.Handler())
+		mux.HandleFunc("/healthz", health.HandleHealthz)
+		mux.HandleFunc("/readyz", health.HandleReadyz)
+		if err := p.serveHTTP("metrics", cfg.MetricsAddr, mux); err != nil {
+			return err
+		}
+	}
+
+	if cfg.AdminAddr != "" {
+		if err := p.serveHTTP("admin api", cfg.AdminAddr, server.NewAdminAPI(p.srv, p.blockCache, cfg)); err != nil {
+			return err
+		}
+	}
+
+	if cfg.GRPCAddr != "" {
+		lis, err := p.listen(cfg.GRPCAddr)
+		if err != nil {
+			return fmt.Errorf("listen grpc failed: %w", err)
+		}
+
+		if p.grpc, err = server.NewGRPCServer(p.srv, cfg.GRPCToken, cfg.GRPCClient); err != nil {
+			_ = lis.Close()
+			return fmt.Errorf("failed to init grpc api: %w", err)
+		}
+		go func() {
+			log.Info().Str("addr", cfg.GRPCAddr).Msg("listening grpc api")
+			if err := p.grpc.Serve(lis); err != nil {
+				p.fail(fmt.Errorf("serve grpc failed: %w", err))
+			}
+		}()
+	}
+
+	if cfg.SubscriptionsAddr != "" {
+		if p.blockCache == nil {
+			return fmt.Errorf("subscriptions api requires built-in cache to be enabled")
+		}
+
+		if err := p.serveHTTP("subscriptions api", cfg.SubscriptionsAddr, server.NewSubscriptionsAPI(p.blockCache, cfg.SubscriptionsToken)); err != nil {
+			return err
+		}
+	}
+
+	if cfg.HelperAddr != "" {
+		if err := p.serveHTTP("helper api", cfg.HelperAddr, server.NewHelperAPI(p.srv, cfg.HelperToken)); err != nil {
+			return err
+		}
+	}
+
+	if cfg.WebSocketListenAddr != "" {
+		if err := p.serveHTTP("websocket", cfg.WebSocketListenAddr, p.srv.WebSocketHandler(cfg.WebSocketTrustForwardedFor)); err != nil {
+			return err
+		}
+	}
+
+	if cfg.DHT.Enabled {
+		pub, err := server.NewDHTPublisher(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to init dht publisher: %w", err)
+		}
+		p.dht = pub
+		p.dht.Start()
+		log.Info().Msg("dht announces enabled")
+	}
+
+	if cfg.SelfTest.IntervalSeconds > 0 {
+		probe, err := server.NewSelfTestProbe(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to init self test: %w", err)
+		}
+		p.selfTest = probe
+		p.selfTest.Start()
+		log.Info().Uint32("interval", cfg.SelfTest.IntervalSeconds).Msg("self test through public endpoint enabled")
+	}
+
+	if err := p.listenADNL(cfg.ListenAddr, nil, true); err != nil {
+		return err
+	}
+	for _, l := range cfg.Listeners {
+		if err := p.listenADNL(l.Addr, l.Clients, false); err != nil {
+			return err
+		}
+	}
+
+	p.handoffDone()
+	return nil
+}
+
+// listenADNL starts liteserver listener, main one accepts all client keys. With handoff or SO_REUSEPORT, sockets are
+// owned by proxy, to pass them to the next process or to have several of them, each socket is served directly.
+func (p *Proxy) listenADNL(addr string, clients []string, main bool) error {
+	var sockets []net.Listener
+	switch {
+	case p.cfg.ReusePortListeners > 1:
+		list, err := listenReusePort(addr, int(p.cfg.ReusePortListeners))
+		if err != nil {
+			return fmt.Errorf("listen %s with SO_REUSEPORT failed: %w", addr, err)
+		}
+		p.reusePort = append(p.reusePort, list...)
+		sockets = list
+	case p.cfg.Handoff.Enabled:
+		lis, err := p.listen(addr)
+		if err != nil {
+			return fmt.Errorf("listen %s failed: %w", addr, err)
+		}
+		sockets = []net.Listener{lis}
+	default:
+		lis, err := net.Listen("tcp", addr)
+		if err != nil {
+			return fmt.Errorf("listen %s failed: %w", addr, err)
+		}
+		sockets = []net.Listener{lis}
+	}
+
+	log.Info().Str("addr", addr).Int("sockets", len(sockets)).Strs("clients", clients).Msg("listening tcp")
+	for _, lis := range sockets {
+		lis := lis
+		// each socket has its own accept loop
+		go func() {
+			serve := p.srv.Serve
+			if !main {
+				serve = func(lis net.Listener) error {
+					return p.srv.ServeClients(lis, clients)
+				}
+			}
+			if err := serve(lis); err != nil {
+				p.fail(fmt.Errorf("serve %s failed: %w", addr, err))
+			}
+		}()
+	}
+	return nil
+}
+
+// validateConfig checks config, settings of built-in balancer are not checked when it is replaced
+func (p *Proxy) validateConfig() error {
+	err := p.cfg.Validate()
+
+	verr, ok := err.(*config.ValidationError)
+	if !ok || p.balancer == nil {
+		return err
+	}
+
+	var fields []config.FieldError
+	for _, f := range verr.Fields {
+		if !strings.HasPrefix(f.Field, "Backends") && f.Field != "BalancerType" && f.Field != "Quorum.Size" {
+			fields = append(fields, f)
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return &config.ValidationError{Fields: fields}
+}
+
+func (p *Proxy) startBackends() error {
+	cfg := p.cfg
+	if len(cfg.Backends) == 0 && cfg.GlobalConfigURL == "" {
+		return fmt.Errorf("no backends specified")
+	}
+
+	blc, err := server.NewBackendBalancer(cfg.Backends, server.BalancerType(cfg.BalancerType))
+	if err != nil {
+		return fmt.Errorf("failed to init backend balancer: %w", err)
+	}
+	p.backends = blc
+
+	blc.SetRouter(server.NewRequestRouter(cfg.Routing))
+	if cfg.Routing.AccountHashing {
+		blc.EnableAccountHashing()
+	}
+	if cfg.GlobalConfigURL != "" {
+		if err = blc.StartDiscovery(cfg.GlobalConfigURL, time.Duration(cfg.GlobalConfigRefreshSeconds)*time.Second, cfg.GlobalConfigBackendTags); err != nil {
+			return fmt.Errorf("failed to discover backends from global config: %w", err)
+		}
+	}
+
+	zeroState, err := server.NetworkZeroState(cfg.Network, cfg.ZeroState)
+	if err != nil {
+		return fmt.Errorf("invalid network config: %w", err)
+	}
+	if err = blc.CheckZeroState(context.Background(), zeroState, 10*time.Second); err != nil {
+		return fmt.Errorf("backends are not from the same network: %w", err)
+	}
+
+	blc.StartHealthChecks(cfg.BackendHealthCheck)
+	if cfg.BackendDNSRefreshSeconds > 0 {
+		blc.StartDNSRefresh(time.Duration(cfg.BackendDNSRefreshSeconds) * time.Second)
+	}
+	blc.EnableHedging(time.Duration(cfg.HedgeDelayMs) * time.Millisecond)
+	blc.EnableQuorum(cfg.Quorum)
+	if cfg.CoalesceBackendQueries {
+		blc.EnableCoalescing()
+	}
+	if cfg.FairBackendSlots > 0 {
+		blc.EnableFairQueuing(int(cfg.FairBackendSlots))
+	}
+	blc.StartSaturationGauges()
+	if cfg.Retry.BudgetRatio > 0 {
+		blc.EnableRetries(server.NewRetryBudget(cfg.Retry.BudgetRatio, cfg.Retry.MinRetriesPerSec),
+			time.Duration(cfg.Retry.AttemptTimeoutMs)*time.Millisecond)
+	}
+
+	if len(cfg.Shadow.Backends) > 0 {
+		shadow, err := server.NewBackendBalancer(cfg.Shadow.Backends, server.BalancerType(cfg.BalancerType))
+		if err != nil {
+			return fmt.Errorf("failed to init shadow backends: %w", err)
+		}
+		blc.EnableShadow(shadow, cfg.Shadow)
+		log.Info().Int("backends", len(cfg.Shadow.Backends)).Float64("percent", cfg.Shadow.Percent).Msg("shadow traffic mirroring enabled")
+	}
+	return nil
+}
+
+func (p *Proxy) serveHTTP(name, addr string, handler http.Handler) error {
+	lis, err := p.listen(addr)
+	if err != nil {
+		return fmt.Errorf("listen %s failed: %w", name, err)
+	}
+
+	srv := &http.Server{Handler: handler}
+	p.httpServers = append(p.httpServers, srv)
+
+	go func() {
+		log.Info().Str("addr", addr).Msg("listening " + name)
+		if err := srv.Serve(lis); err != nil && err != http.ErrServerClosed {
+			p.fail(fmt.Errorf("serve %s failed: %w", name, err))
+		}
+	}()
+	return nil
+}
+
+// fail reports listener error to Wait, only the first one is kept
+func (p *Proxy) fail(err error) {
+	select {
+	case p.errs <- err:
+	default:
+	}
+}
+
+// Wait blocks until one of listeners fails or proxy is stopped, nil is returned after Stop
+func (p *Proxy) Wait() error {
+	select {
+	case err := <-p.errs:
+		return err
+	case <-p.done:
+		return nil
+	}
+}
+
+// Stop closes listeners, disconnects clients and stops built-in components,
+// replaced balancer and cache are not closed, they are owned by caller
+func (p *Proxy) Stop() {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	p.stop()
+}
+
+func (p *Proxy) stop() {
+	if p.stopped {
+		return
+	}
+	p.stopped = true
+
+	if p.dht != nil {
+		p.dht.Close()
+	}
+	if p.selfTest != nil {
+		p.selfTest.Close()
+	}
+	if p.srv != nil {
+		_ = p.srv.Close()
+	}
+	if p.grpc != nil {
+		p.grpc.Stop()
+	}
+	for _, srv := range p.httpServers {
+		_ = srv.Close()
+	}
+	for _, lis := range p.listeners {
+		_ = lis.Close()
+	}
+	for _, lis := range p.inherited {
+		_ = lis.Close()
+	}
+	for _, lis := range p.reusePort {
+		_ = lis.Close()
+	}
+	if p.ready != nil {
+		// previous process sees closed pipe and continues to serve
+		_ = p.ready.Close()
+	}
+	if p.streamer != nil {
+		if err := p.streamer.Stop(); err != nil {
+			log.Warn().Err(err).Msg("failed to close event stream")
+		}
+	}
+	if p.budget != nil {
+		p.budget.Close()
+	}
+	if p.analytics != nil {
+		p.analytics.Close()
+	}
+	if p.billing != nil {
+		if err := p.billing.Close(); err != nil {
+			log.Warn().Err(err).Msg("failed to close billing export")
+		}
+	}
+	if p.capture != nil {
+		if err := p.capture.Close(); err != nil {
+			log.Warn().Err(err).Msg("failed to close capture file")
+		}
+	}
+	if p.blockCache != nil {
+		if err := p.blockCache.Close(); err != nil {
+			log.Warn().Err(err).Msg("failed to close cache store")
+		}
+	}
+	if p.backends != nil {
+		p.backends.Close()
+	}
+	if p.stopOTLP != nil {
+		p.stopOTLP()
+	}
+	if p.reporter != nil {
+		server.SetErrorReporter(nil)
+		p.reporter.Close()
+	}
+	close(p.done)
+}
+
+// Client returns client which queries proxy in process, to use it with tonutils api client,
+// queries are served like queries of clients with key of keyName. Proxy should be started.
+func (p *Proxy) Client(keyName string) ton.LiteClient {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	return p.srv.LocalClient(keyName)
+}
+
+// SetMethodOverrides replaces handling overrides of query types, see config.MethodOverrides
+func (p *Proxy) SetMethodOverrides(overrides map[string]string) error {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	if p.srv == nil {
+		return fmt.Errorf("proxy is not started")
+	}
+	p.srv.SetMethodOverrides(overrides)
+	return nil
+}
+
+// ReloadBackends replaces backends of built-in balancer with new list
+func (p *Proxy) ReloadBackends(list []config.BackendLiteserver) error {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	if p.backends == nil {
+		return fmt.Errorf("backends are managed by custom balancer")
+	}
+	return p.backends.ReloadBackends(list)
+}
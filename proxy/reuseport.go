@@ -0,0 +1,44 @@
+//go:build linux || darwin
+
+package proxy
+
+import (
+	"context"
+	"golang.org/x/sys/unix"
+	"net"
+	"syscall"
+)
+
+// listenReusePort opens n sockets on the same address with SO_REUSEPORT,
+// kernel distributes new connections between them, so each one can have its own accept loop
+func listenReusePort(addr string, n int) ([]net.Listener, error) {
+	lc := net.ListenConfig{
+		Control: func(_, _ string, c syscall.RawConn) error {
+			var err error
+			if cErr := c.Control(func(fd uintptr) {
+				err = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEPORT, 1)
+			}); cErr != nil {
+				return cErr
+			}
+			return err
+		},
+	}
+
+	list := make([]net.Listener, 0, n)
+	for i := 0; i < n; i++ {
+		lis, err := lc.Listen(context.Background(), "tcp", addr)
+		if err != nil {
+			for _, l := range list {
+				_ = l.Close()
+			}
+			return nil, err
+		}
+		list = append(list, lis)
+
+		if i == 0 {
+			// port could be random, others should share the same one
+			addr = lis.Addr().String()
+		}
+	}
+	return list, nil
+}
@@ -0,0 +1,12 @@
+//go:build !linux && !darwin
+
+package proxy
+
+import (
+	"fmt"
+	"net"
+)
+
+func listenReusePort(addr string, n int) ([]net.Listener, error) {
+	return nil, fmt.Errorf("SO_REUSEPORT is not supported on this system")
+}